@@ -5,6 +5,7 @@ import (
 	"io"
 	"net/http"
 	"strconv"
+	"strings"
 	"web/config"
 	"web/middleware"
 	"web/services"
@@ -14,17 +15,23 @@ import (
 
 // AttachmentHandler handles HTTP requests for attachments
 type AttachmentHandler struct {
-	app         *config.AppConfig
-	service     *services.AttachmentService
-	authService *services.AuthService
+	app               *config.AppConfig
+	service           *services.AttachmentService
+	enrollmentService *services.EnrollmentService
+	authService       *services.AuthService
+	policyService     *services.PolicyService
+	auditService      *services.AuditService
 }
 
 // NewAttachmentHandler creates a new attachment handler
-func NewAttachmentHandler(app *config.AppConfig, service *services.AttachmentService, authService *services.AuthService) *AttachmentHandler {
+func NewAttachmentHandler(app *config.AppConfig, service *services.AttachmentService, enrollmentService *services.EnrollmentService, authService *services.AuthService, policyService *services.PolicyService, auditService *services.AuditService) *AttachmentHandler {
 	return &AttachmentHandler{
-		app:         app,
-		service:     service,
-		authService: authService,
+		app:               app,
+		service:           service,
+		enrollmentService: enrollmentService,
+		authService:       authService,
+		policyService:     policyService,
+		auditService:      auditService,
 	}
 }
 
@@ -40,25 +47,17 @@ func (h *AttachmentHandler) RegisterRoutes(router *gin.Engine) {
 				attachmentGroup := lessonGroup.Group("/:lessonId/attachments")
 				attachmentGroup.Use(middleware.AuthMiddleware(h.authService))
 				{
-					// GET all attachments for a lesson
-					attachmentGroup.GET("", h.GetAttachmentsByLessonID)
+					// GET all attachments for a lesson - only users with access to the course
+					attachmentGroup.GET("", middleware.RequireCourseAccess(h.authService, h.enrollmentService), h.GetAttachmentsByLessonID)
 
-					// Upload endpoint - only admin and teacher can upload
-					uploadGroup := attachmentGroup.Group("")
-					uploadGroup.Use(h.requireAdminOrTeacher())
-					{
-						uploadGroup.POST("", h.UploadFile)
-					}
+					// Upload endpoint - only admin, the course owner and co-instructors can upload
+					attachmentGroup.POST("", middleware.AuthorizeCourse(h.authService, h.policyService, services.ActionCreate, services.ResourceAttachment), middleware.Audit(h.auditService, services.ActionCreate, services.ResourceAttachment, middleware.AuditCreated), h.UploadFile)
 
 					// Download endpoint - any authenticated user with access to the lesson can download
 					attachmentGroup.GET("/:attachmentId", h.DownloadFile)
 
-					// Delete attachment - only admin and teacher can delete
-					deleteGroup := attachmentGroup.Group("/:attachmentId")
-					deleteGroup.Use(h.requireAdminOrTeacher())
-					{
-						deleteGroup.DELETE("", h.DeleteAttachment)
-					}
+					// Delete attachment - only admin, the course owner and co-instructors can delete
+					attachmentGroup.DELETE("/:attachmentId", middleware.AuthorizeCourse(h.authService, h.policyService, services.ActionDelete, services.ResourceAttachment), middleware.Audit(h.auditService, services.ActionDelete, services.ResourceAttachment, middleware.AuditParam("attachmentId")), h.DeleteAttachment)
 				}
 			}
 		}
@@ -68,12 +67,8 @@ func (h *AttachmentHandler) RegisterRoutes(router *gin.Engine) {
 	oldAttachmentGroup := router.Group("/api/v1/attachments")
 	oldAttachmentGroup.Use(middleware.AuthMiddleware(h.authService))
 	{
-		// Upload endpoint - only admin and teacher can upload
-		uploadGroup := oldAttachmentGroup.Group("/upload")
-		uploadGroup.Use(h.requireAdminOrTeacher())
-		{
-			uploadGroup.POST("/:lessonId", h.UploadFile)
-		}
+		// Upload endpoint - only admin, the owner and co-instructors of the lesson's course can upload
+		oldAttachmentGroup.POST("/upload/:lessonId", middleware.AuthorizeLesson(h.authService, h.policyService, services.ActionCreate, services.ResourceAttachment), middleware.Audit(h.auditService, services.ActionCreate, services.ResourceAttachment, middleware.AuditCreated), h.UploadFile)
 
 		// Download endpoint - any authenticated user with access to the lesson can download
 		oldAttachmentGroup.GET("/download/:id", h.DownloadFile)
@@ -81,42 +76,39 @@ func (h *AttachmentHandler) RegisterRoutes(router *gin.Engine) {
 		// Get attachments for a lesson
 		oldAttachmentGroup.GET("/lesson/:lessonId", h.GetAttachmentsByLessonID)
 
-		// Delete attachment - only admin and teacher can delete
-		deleteGroup := oldAttachmentGroup.Group("/delete")
-		deleteGroup.Use(h.requireAdminOrTeacher())
-		{
-			deleteGroup.DELETE("/:id", h.DeleteAttachment)
-		}
+		// Delete attachment - the route does not name the course, so only admins can delete
+		oldAttachmentGroup.DELETE("/delete/:id", middleware.Authorize(h.authService, h.policyService, services.ActionDelete, services.ResourceAttachment), middleware.Audit(h.auditService, services.ActionDelete, services.ResourceAttachment, middleware.AuditParam("id")), h.DeleteAttachment)
 	}
 }
 
-// requireAdminOrTeacher is a middleware that checks if the user has admin or teacher
-func (h *AttachmentHandler) requireAdminOrTeacher() gin.HandlerFunc {
-	return func(c *gin.Context) {
-		// Get the claims from the context
-		claims, exists := c.Get("claims")
-		if !exists {
-			middleware.RespondWithError(c, http.StatusUnauthorized, "Authentication required")
-			c.Abort()
-			return
-		}
-
-		// Check if the user has the required role
-		keycloakClaims, ok := claims.(*services.KeycloakClaims)
-		if !ok {
-			middleware.RespondWithError(c, http.StatusInternalServerError, "Invalid claims type")
-			c.Abort()
-			return
-		}
-
-		if !h.authService.HasRole(keycloakClaims, "admin") && !h.authService.HasRole(keycloakClaims, "teacher") {
-			middleware.RespondWithError(c, http.StatusForbidden, "Insufficient permissions")
-			c.Abort()
-			return
+// hasAccessToLesson checks that the actor may read the lesson and writes the error
+// response when access is denied
+func (h *AttachmentHandler) hasAccessToLesson(c *gin.Context, actor services.Actor, lessonID uint) bool {
+	hasAccess, err := h.service.HasAccessToLesson(actor, lessonID)
+	if err != nil {
+		if err.Error() == "lesson not found" {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error":   true,
+				"message": err.Error(),
+			})
+			return false
 		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   true,
+			"message": err.Error(),
+		})
+		return false
+	}
 
-		c.Next()
+	if !hasAccess {
+		c.JSON(http.StatusForbidden, gin.H{
+			"error":   true,
+			"message": "You don't have access to this lesson",
+		})
+		return false
 	}
+
+	return true
 }
 
 // UploadFile handles POST /api/v1/courses/:id/chapters/:chapterId/lessons/:lessonId/attachments
@@ -137,6 +129,11 @@ func (h *AttachmentHandler) requireAdminOrTeacher() gin.HandlerFunc {
 // @Failure 500 {object} map[string]interface{} "Internal server error"
 // @Router /courses/{id}/chapters/{chapterId}/lessons/{lessonId}/attachments [post]
 func (h *AttachmentHandler) UploadFile(c *gin.Context) {
+	actor, ok := middleware.CurrentActor(c, h.authService)
+	if !ok {
+		return
+	}
+
 	// Parse course ID, chapter ID, and lesson ID
 	courseIdStr := c.Param("id")
 	chapterIdStr := c.Param("chapterId")
@@ -173,7 +170,7 @@ func (h *AttachmentHandler) UploadFile(c *gin.Context) {
 		}
 
 		// Upload the file
-		uploadResponse, err := h.service.UploadFile(file, 0, 0, uint(lessonId))
+		uploadResponse, err := h.service.UploadFile(file, 0, 0, uint(lessonId), actor.TenantFilter())
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{
 				"error":   true,
@@ -182,6 +179,7 @@ func (h *AttachmentHandler) UploadFile(c *gin.Context) {
 			return
 		}
 
+		middleware.SetAuditResourceID(c, uploadResponse.ID)
 		c.JSON(http.StatusCreated, gin.H{
 			"error":   false,
 			"data":    uploadResponse,
@@ -229,7 +227,7 @@ func (h *AttachmentHandler) UploadFile(c *gin.Context) {
 	}
 
 	// Upload the file
-	uploadResponse, err := h.service.UploadFile(file, uint(courseId), uint(chapterId), uint(lessonId))
+	uploadResponse, err := h.service.UploadFile(file, uint(courseId), uint(chapterId), uint(lessonId), actor.TenantFilter())
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error":   true,
@@ -238,6 +236,7 @@ func (h *AttachmentHandler) UploadFile(c *gin.Context) {
 		return
 	}
 
+	middleware.SetAuditResourceID(c, uploadResponse.ID)
 	c.JSON(http.StatusCreated, gin.H{
 		"error":   false,
 		"data":    uploadResponse,
@@ -263,8 +262,16 @@ func (h *AttachmentHandler) UploadFile(c *gin.Context) {
 // @Failure 500 {object} map[string]interface{} "Internal server error"
 // @Router /courses/{id}/chapters/{chapterId}/lessons/{lessonId}/attachments/{attachmentId} [get]
 func (h *AttachmentHandler) DownloadFile(c *gin.Context) {
-	// Parse attachment ID
+	actor, ok := middleware.CurrentActor(c, h.authService)
+	if !ok {
+		return
+	}
+
+	// Parse attachment ID, the legacy route passes it as :id
 	idStr := c.Param("attachmentId")
+	if idStr == "" {
+		idStr = c.Param("id")
+	}
 	id, err := strconv.ParseUint(idStr, 10, 32)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
@@ -274,11 +281,10 @@ func (h *AttachmentHandler) DownloadFile(c *gin.Context) {
 		return
 	}
 
-	// Get the attachment and MinIO object
-	attachment, object, err := h.service.DownloadFile(uint(id))
+	attachment, err := h.service.GetAttachmentByID(uint(id), actor.TenantFilter())
 	if err != nil {
 		status := http.StatusInternalServerError
-		if err.Error() == "attachment not found" || err.Error() == "file not found in MinIO" {
+		if err.Error() == "attachment not found" {
 			status = http.StatusNotFound
 		}
 		c.JSON(status, gin.H{
@@ -287,35 +293,38 @@ func (h *AttachmentHandler) DownloadFile(c *gin.Context) {
 		})
 		return
 	}
-	defer object.Close()
 
-	// Get the user ID from the context
-	userID, exists := c.Get("user_id")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{
-			"error":   true,
-			"message": "User not authenticated",
-		})
-		return
+	// On the hierarchical route the attachment has to belong to the lesson in the path
+	if lessonIdStr := c.Param("lessonId"); lessonIdStr != "" {
+		lessonId, err := strconv.ParseUint(lessonIdStr, 10, 32)
+		if err != nil || attachment.LessonID != uint(lessonId) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error":   true,
+				"message": "attachment not found",
+			})
+			return
+		}
 	}
 
-	// Check if the user has access to the lesson
-	hasAccess, err := h.service.HasAccessToLesson(userID.(uint), attachment.LessonID)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   true,
-			"message": err.Error(),
-		})
+	// Check if the user has access to the lesson before touching the file
+	if !h.hasAccessToLesson(c, actor, attachment.LessonID) {
 		return
 	}
 
-	if !hasAccess {
-		c.JSON(http.StatusForbidden, gin.H{
+	// Get the attachment and MinIO object
+	attachment, object, err := h.service.DownloadFile(uint(id), actor.TenantFilter())
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err.Error() == "attachment not found" || err.Error() == "file not found in MinIO" {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, gin.H{
 			"error":   true,
-			"message": "You don't have access to this lesson",
+			"message": err.Error(),
 		})
 		return
 	}
+	defer object.Close()
 
 	// Set the appropriate headers
 	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", attachment.Name))
@@ -343,9 +352,16 @@ func (h *AttachmentHandler) DownloadFile(c *gin.Context) {
 // @Success 200 {object} map[string]interface{} "Returns a list of attachments"
 // @Failure 400 {object} map[string]interface{} "Invalid course, chapter, or lesson ID"
 // @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Forbidden"
+// @Failure 404 {object} map[string]interface{} "Lesson not found"
 // @Failure 500 {object} map[string]interface{} "Internal server error"
 // @Router /courses/{id}/chapters/{chapterId}/lessons/{lessonId}/attachments [get]
 func (h *AttachmentHandler) GetAttachmentsByLessonID(c *gin.Context) {
+	actor, ok := middleware.CurrentActor(c, h.authService)
+	if !ok {
+		return
+	}
+
 	// Parse course ID, chapter ID, and lesson ID
 	courseIdStr := c.Param("id")
 	chapterIdStr := c.Param("chapterId")
@@ -371,8 +387,13 @@ func (h *AttachmentHandler) GetAttachmentsByLessonID(c *gin.Context) {
 			return
 		}
 
+		// Legacy route has no course in the path, so check access through the lesson
+		if !h.hasAccessToLesson(c, actor, uint(lessonId)) {
+			return
+		}
+
 		// Get the attachments
-		attachments, err := h.service.GetAttachmentsByLessonID(0, 0, uint(lessonId))
+		attachments, err := h.service.GetAttachmentsByLessonID(0, 0, uint(lessonId), actor.TenantFilter())
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{
 				"error":   true,
@@ -417,7 +438,7 @@ func (h *AttachmentHandler) GetAttachmentsByLessonID(c *gin.Context) {
 	}
 
 	// Get the attachments
-	attachments, err := h.service.GetAttachmentsByLessonID(uint(courseId), uint(chapterId), uint(lessonId))
+	attachments, err := h.service.GetAttachmentsByLessonID(uint(courseId), uint(chapterId), uint(lessonId), actor.TenantFilter())
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error":   true,
@@ -450,8 +471,16 @@ func (h *AttachmentHandler) GetAttachmentsByLessonID(c *gin.Context) {
 // @Failure 500 {object} map[string]interface{} "Internal server error"
 // @Router /courses/{id}/chapters/{chapterId}/lessons/{lessonId}/attachments/{attachmentId} [delete]
 func (h *AttachmentHandler) DeleteAttachment(c *gin.Context) {
-	// Parse attachment ID
+	actor, ok := middleware.CurrentActor(c, h.authService)
+	if !ok {
+		return
+	}
+
+	// Parse attachment ID, the legacy route passes it as :id
 	idStr := c.Param("attachmentId")
+	if idStr == "" {
+		idStr = c.Param("id")
+	}
 	id, err := strconv.ParseUint(idStr, 10, 32)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
@@ -461,8 +490,35 @@ func (h *AttachmentHandler) DeleteAttachment(c *gin.Context) {
 		return
 	}
 
+	// The route authorizes the course in the path, the attachment may belong to another
+	// course of the organization
+	attachment, err := h.service.GetAttachmentByID(uint(id), actor.TenantFilter())
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err.Error() == "attachment not found" {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, gin.H{
+			"error":   true,
+			"message": err.Error(),
+		})
+		return
+	}
+	err = h.policyService.AuthorizeLesson(actor, services.ActionDelete, services.ResourceAttachment, attachment.LessonID)
+	if err != nil {
+		status, message := http.StatusNotFound, "attachment not found"
+		if strings.HasPrefix(err.Error(), "insufficient permissions") {
+			status, message = http.StatusForbidden, err.Error()
+		}
+		c.JSON(status, gin.H{
+			"error":   true,
+			"message": message,
+		})
+		return
+	}
+
 	// Delete the attachment
-	err = h.service.DeleteAttachment(uint(id))
+	err = h.service.DeleteAttachment(uint(id), actor.TenantFilter())
 	if err != nil {
 		status := http.StatusInternalServerError
 		if err.Error() == "attachment not found" {
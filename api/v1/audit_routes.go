@@ -0,0 +1,138 @@
+package v1
+
+import (
+	"strconv"
+	"strings"
+	"time"
+	"web/config"
+	"web/middleware"
+	"web/schemas"
+	"web/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AuditHandler handles HTTP requests for the audit log
+type AuditHandler struct {
+	app           *config.AppConfig
+	service       *services.AuditService
+	authService   *services.AuthService
+	policyService *services.PolicyService
+}
+
+// NewAuditHandler creates a new audit handler
+func NewAuditHandler(app *config.AppConfig, service *services.AuditService, authService *services.AuthService, policyService *services.PolicyService) *AuditHandler {
+	return &AuditHandler{
+		app:           app,
+		service:       service,
+		authService:   authService,
+		policyService: policyService,
+	}
+}
+
+// RegisterRoutes registers audit api to the router
+func (h *AuditHandler) RegisterRoutes(router *gin.Engine) {
+	auditGroup := router.Group("/api/v1/audit-logs")
+	auditGroup.Use(middleware.AuthMiddleware(h.authService))
+	{
+		auditGroup.GET("", middleware.Authorize(h.authService, h.policyService, services.ActionRead, services.ResourceAuditLog), h.GetAuditLogs)
+	}
+}
+
+// GetAuditLogs handles GET /api/v1/audit-logs
+// @Summary Query the audit log
+// @Description Get the creates, updates and deletes of courses, chapters, lessons, attachments and users, newest first (admins only).
+// @Description Every entry has the user who made the change, the request ID returned in the X-Request-ID header, and the JSON state of the resource before and after the change.
+// @Description Admins of an organization only see the entries of its users.
+// @Tags audit
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param actor_id query int false "Only entries of this user"
+// @Param action query string false "Only this action, such as create, update, delete or change_status"
+// @Param resource_type query string false "Only this resource type: course, chapter, lesson, attachment, user, chapter_order or lesson_order"
+// @Param resource_id query int false "Only entries of this resource"
+// @Param request_id query string false "Only entries of this request"
+// @Param from query string false "Only entries from this time (RFC 3339 or YYYY-MM-DD)"
+// @Param to query string false "Only entries until this time (RFC 3339 or YYYY-MM-DD, inclusive)"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Page size (max 100)" default(20)
+// @Success 200 {object} map[string]interface{} "Returns the audit log entries"
+// @Failure 400 {object} map[string]interface{} "Invalid query parameters"
+// @Failure 403 {object} map[string]interface{} "Insufficient permissions"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /audit-logs [get]
+func (h *AuditHandler) GetAuditLogs(c *gin.Context) {
+	page, limit, ok := parsePageParams(c)
+	if !ok {
+		return
+	}
+
+	actor, ok := middleware.CurrentActor(c, h.authService)
+	if !ok {
+		return
+	}
+
+	params := schemas.AuditLogParams{
+		Action:       strings.TrimSpace(c.Query("action")),
+		ResourceType: strings.TrimSpace(c.Query("resource_type")),
+		RequestID:    strings.TrimSpace(c.Query("request_id")),
+		Tenant:       actor.TenantFilter(),
+		Page:         page,
+		Limit:        limit,
+	}
+
+	if actorIDStr := c.Query("actor_id"); actorIDStr != "" {
+		actorID, err := strconv.ParseUint(actorIDStr, 10, 32)
+		if err != nil {
+			middleware.RespondWithBadRequest(c, "Invalid actor_id")
+			return
+		}
+		id := uint(actorID)
+		params.ActorID = &id
+	}
+
+	if resourceIDStr := c.Query("resource_id"); resourceIDStr != "" {
+		resourceID, err := strconv.ParseUint(resourceIDStr, 10, 32)
+		if err != nil {
+			middleware.RespondWithBadRequest(c, "Invalid resource_id")
+			return
+		}
+		id := uint(resourceID)
+		params.ResourceID = &id
+	}
+
+	if fromStr := c.Query("from"); fromStr != "" {
+		from, _, err := parseDateParam(fromStr)
+		if err != nil {
+			middleware.RespondWithBadRequest(c, "Invalid from, use RFC 3339 or YYYY-MM-DD")
+			return
+		}
+		params.From = &from
+	}
+
+	if toStr := c.Query("to"); toStr != "" {
+		to, dateOnly, err := parseDateParam(toStr)
+		if err != nil {
+			middleware.RespondWithBadRequest(c, "Invalid to, use RFC 3339 or YYYY-MM-DD")
+			return
+		}
+		if dateOnly {
+			// Include the whole day
+			to = to.Add(24*time.Hour - time.Nanosecond)
+		}
+		params.To = &to
+	}
+
+	entries, meta, err := h.service.GetAll(params)
+	if err != nil {
+		if err.Error() == "invalid time range" {
+			middleware.RespondWithBadRequest(c, "from must not be after to")
+		} else {
+			middleware.RespondWithInternalServerError(c, err.Error())
+		}
+		return
+	}
+
+	middleware.RespondWithSuccessAndMeta(c, entries, meta, "")
+}
@@ -0,0 +1,233 @@
+package v1
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"web/config"
+	"web/middleware"
+	"web/schemas"
+	"web/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CertificateHandler handles HTTP requests for course completion certificates
+type CertificateHandler struct {
+	app               *config.AppConfig
+	service           *services.CertificateService
+	enrollmentService *services.EnrollmentService
+	authService       *services.AuthService
+	policyService     *services.PolicyService
+}
+
+// NewCertificateHandler creates a new certificate handler
+func NewCertificateHandler(app *config.AppConfig, service *services.CertificateService, enrollmentService *services.EnrollmentService, authService *services.AuthService, policyService *services.PolicyService) *CertificateHandler {
+	return &CertificateHandler{
+		app:               app,
+		service:           service,
+		enrollmentService: enrollmentService,
+		authService:       authService,
+		policyService:     policyService,
+	}
+}
+
+// RegisterRoutes registers certificate api to the router
+func (h *CertificateHandler) RegisterRoutes(router *gin.Engine) {
+	courseGroup := router.Group("/api/v1/courses/:id/certificates")
+	courseGroup.Use(middleware.AuthMiddleware(h.authService))
+	{
+		courseGroup.POST("", middleware.AuthorizeCourse(h.authService, h.policyService, services.ActionCreate, services.ResourceCertificate), h.IssueCertificate)
+		courseGroup.POST("/claim", middleware.RequireCourseAccess(h.authService, h.enrollmentService), h.ClaimCertificate)
+	}
+
+	meGroup := router.Group("/api/v1/users/me")
+	meGroup.Use(middleware.AuthMiddleware(h.authService))
+	{
+		meGroup.GET("/certificates", h.GetMyCertificates)
+	}
+
+	certificateGroup := router.Group("/api/v1/certificates/:code")
+	{
+		// Verification is public so that anyone holding a certificate code can check it
+		certificateGroup.GET("/verify", h.VerifyCertificate)
+		certificateGroup.GET("/download", middleware.AuthMiddleware(h.authService), h.DownloadCertificate)
+	}
+}
+
+// certificateErrorStatus maps certificate service errors to HTTP status codes
+func certificateErrorStatus(err error) int {
+	switch err.Error() {
+	case "course not found", "certificate not found":
+		return http.StatusNotFound
+	case "certificate already issued":
+		return http.StatusConflict
+	case "insufficient permissions to download this certificate":
+		return http.StatusForbidden
+	case "user ID is required", "user is not enrolled in this course", "course completion requirements not met":
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
+
+// IssueCertificate handles POST /api/v1/courses/:id/certificates
+// @Summary Issue a certificate
+// @Description Mark a learner as having completed the course and issue a certificate (admins, the course owner, co-instructors and teaching assistants only)
+// @Tags certificates
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Course ID"
+// @Param certificate body schemas.IssueCertificateRequest true "Learner to certify"
+// @Success 201 {object} map[string]interface{} "Certificate issued successfully"
+// @Failure 400 {object} map[string]interface{} "Invalid request body or learner not enrolled"
+// @Failure 403 {object} map[string]interface{} "Forbidden"
+// @Failure 404 {object} map[string]interface{} "Course not found"
+// @Failure 409 {object} map[string]interface{} "Certificate already issued"
+// @Router /courses/{id}/certificates [post]
+// @example request - example payload
+//
+//	{
+//	  "user_id": 1
+//	}
+func (h *CertificateHandler) IssueCertificate(c *gin.Context) {
+	courseID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		middleware.RespondWithBadRequest(c, "Invalid course ID")
+		return
+	}
+
+	var certificateRequest schemas.IssueCertificateRequest
+	if err := c.ShouldBindJSON(&certificateRequest); err != nil {
+		middleware.RespondWithBadRequest(c, "Invalid request body")
+		return
+	}
+
+	issuer, ok := currentUser(c)
+	if !ok {
+		return
+	}
+	issuedBy := issuer.ID
+
+	certificate, err := h.service.IssueCertificate(uint(courseID), certificateRequest.UserID, &issuedBy)
+	if err != nil {
+		middleware.RespondWithError(c, certificateErrorStatus(err), err.Error())
+		return
+	}
+
+	middleware.RespondWithCreated(c, certificate, "Certificate issued successfully")
+}
+
+// ClaimCertificate handles POST /api/v1/courses/:id/certificates/claim
+// @Summary Claim a certificate
+// @Description Issue a certificate to the current user once the course completion rule is met
+// @Tags certificates
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Course ID"
+// @Success 201 {object} map[string]interface{} "Certificate issued successfully"
+// @Failure 400 {object} map[string]interface{} "Course completion requirements not met"
+// @Failure 403 {object} map[string]interface{} "Not enrolled in this course"
+// @Failure 404 {object} map[string]interface{} "Course not found"
+// @Failure 409 {object} map[string]interface{} "Certificate already issued"
+// @Router /courses/{id}/certificates/claim [post]
+func (h *CertificateHandler) ClaimCertificate(c *gin.Context) {
+	courseID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		middleware.RespondWithBadRequest(c, "Invalid course ID")
+		return
+	}
+
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	certificate, err := h.service.ClaimCertificate(uint(courseID), user.ID)
+	if err != nil {
+		middleware.RespondWithError(c, certificateErrorStatus(err), err.Error())
+		return
+	}
+
+	middleware.RespondWithCreated(c, certificate, "Certificate issued successfully")
+}
+
+// GetMyCertificates handles GET /api/v1/users/me/certificates
+// @Summary Get my certificates
+// @Description Get the certificates issued to the current user
+// @Tags certificates
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{} "Returns a list of certificates"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /users/me/certificates [get]
+func (h *CertificateHandler) GetMyCertificates(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	certificates, err := h.service.GetUserCertificates(user.ID)
+	if err != nil {
+		middleware.RespondWithInternalServerError(c, err.Error())
+		return
+	}
+
+	middleware.RespondWithSuccess(c, certificates, "")
+}
+
+// DownloadCertificate handles GET /api/v1/certificates/:code/download
+// @Summary Download a certificate
+// @Description Download the certificate PDF. Only the certificate holder and the admins, owner, co-instructors and teaching assistants of its course may download it.
+// @Tags certificates
+// @Produce application/pdf
+// @Security BearerAuth
+// @Param code path string true "Verification code"
+// @Success 200 {file} binary "Certificate PDF"
+// @Failure 403 {object} map[string]interface{} "Forbidden"
+// @Failure 404 {object} map[string]interface{} "Certificate not found"
+// @Router /certificates/{code}/download [get]
+func (h *CertificateHandler) DownloadCertificate(c *gin.Context) {
+	actor, ok := middleware.CurrentActor(c, h.authService)
+	if !ok {
+		return
+	}
+
+	certificate, object, err := h.service.DownloadCertificate(actor, c.Param("code"))
+	if err != nil {
+		middleware.RespondWithError(c, certificateErrorStatus(err), err.Error())
+		return
+	}
+	defer object.Close()
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=\"certificate-%s.pdf\"", certificate.Code))
+	c.Header("Content-Type", "application/pdf")
+
+	if _, err := io.Copy(c.Writer, object); err != nil {
+		middleware.RespondWithInternalServerError(c, "Failed to stream file: "+err.Error())
+		return
+	}
+}
+
+// VerifyCertificate handles GET /api/v1/certificates/:code/verify
+// @Summary Verify a certificate
+// @Description Check whether a certificate code is valid. This endpoint is public.
+// @Tags certificates
+// @Produce json
+// @Param code path string true "Verification code"
+// @Success 200 {object} map[string]interface{} "Returns the verification result"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /certificates/{code}/verify [get]
+func (h *CertificateHandler) VerifyCertificate(c *gin.Context) {
+	verification, err := h.service.VerifyCertificate(c.Param("code"))
+	if err != nil {
+		middleware.RespondWithInternalServerError(c, err.Error())
+		return
+	}
+
+	middleware.RespondWithSuccess(c, verification, "")
+}
@@ -14,16 +14,24 @@ import (
 )
 
 type ChapterHandler struct {
-	app         *config.AppConfig
-	service     *services.ChapterService
-	authService *services.AuthService
+	app               *config.AppConfig
+	service           *services.ChapterService
+	enrollmentService *services.EnrollmentService
+	progressService   *services.ProgressService
+	authService       *services.AuthService
+	policyService     *services.PolicyService
+	auditService      *services.AuditService
 }
 
-func NewChapterHandler(app *config.AppConfig, service *services.ChapterService, authService *services.AuthService) *ChapterHandler {
+func NewChapterHandler(app *config.AppConfig, service *services.ChapterService, enrollmentService *services.EnrollmentService, progressService *services.ProgressService, authService *services.AuthService, policyService *services.PolicyService, auditService *services.AuditService) *ChapterHandler {
 	return &ChapterHandler{
-		app:         app,
-		service:     service,
-		authService: authService,
+		app:               app,
+		service:           service,
+		enrollmentService: enrollmentService,
+		progressService:   progressService,
+		authService:       authService,
+		policyService:     policyService,
+		auditService:      auditService,
 	}
 }
 
@@ -33,25 +41,33 @@ func (h *ChapterHandler) RegisterRoutes(router *gin.Engine) {
 		chapterGroup := courseGroup.Group("/:id/chapters")
 		chapterGroup.Use(middleware.AuthMiddleware(h.authService))
 		{
-			chapterGroup.GET("", h.GetAllChapters)
-			chapterGroup.GET("/:chapterId", h.GetChapterByID)
-			chapterGroup.POST("", h.CreateChapter)
-			chapterGroup.PUT("/:chapterId", h.UpdateChapter)
-			chapterGroup.DELETE("/:chapterId", h.DeleteChapter)
+			chapterGroup.GET("", middleware.RequireCourseAccess(h.authService, h.enrollmentService), h.GetAllChapters)
+			chapterGroup.GET("/:chapterId", middleware.RequireCourseAccess(h.authService, h.enrollmentService), h.GetChapterByID)
+			chapterGroup.POST("", middleware.AuthorizeCourse(h.authService, h.policyService, services.ActionCreate, services.ResourceChapter), middleware.Audit(h.auditService, services.ActionCreate, services.ResourceChapter, middleware.AuditCreated), h.CreateChapter)
+			chapterGroup.PUT("/:chapterId", middleware.AuthorizeCourse(h.authService, h.policyService, services.ActionUpdate, services.ResourceChapter), middleware.Audit(h.auditService, services.ActionUpdate, services.ResourceChapter, middleware.AuditParam("chapterId")), h.UpdateChapter)
+			chapterGroup.DELETE("/:chapterId", middleware.AuthorizeCourse(h.authService, h.policyService, services.ActionDelete, services.ResourceChapter), middleware.Audit(h.auditService, services.ActionDelete, services.ResourceChapter, middleware.AuditParam("chapterId")), h.DeleteChapter)
 		}
 	}
 }
 
 // GetAllChapters handles GET /api/v1/courses/:id/chapters
 // @Summary Get all chapters for a course
-// @Description Get a list of all chapters for a specific course
+// @Description Get a paginated list of chapters for a specific course with the current user's progress
 // @Tags chapters
 // @Accept json
 // @Produce json
 // @Security BearerAuth
 // @Param id path int true "Course ID"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Page size (max 100)" default(20)
+// @Param sort query string false "Sort key: order, name or created_at, prefix with - for descending" default(order)
+// @Param created_by query int false "Only chapters created by this user"
+// @Param name query string false "Only chapters whose name contains this text"
+// @Param created_from query string false "Only chapters created at or after this date (RFC 3339 or YYYY-MM-DD)"
+// @Param created_to query string false "Only chapters created at or before this date (RFC 3339 or YYYY-MM-DD)"
 // @Success 200 {object} map[string]interface{} "Returns a list of chapters"
 // @Failure 400 {object} map[string]interface{} "Invalid course ID"
+// @Failure 403 {object} map[string]interface{} "Not enrolled in this course"
 // @Failure 500 {object} map[string]interface{} "Internal server error"
 // @Router /courses/{id}/chapters [get]
 func (h *ChapterHandler) GetAllChapters(c *gin.Context) {
@@ -66,7 +82,18 @@ func (h *ChapterHandler) GetAllChapters(c *gin.Context) {
 		return
 	}
 
-	chapterResponses, err := h.service.GetChaptersByCourseID(uint(courseId))
+	params, ok := parseListParams(c, schemas.ChapterSortKeys, "order")
+	if !ok {
+		return
+	}
+
+	actor, ok := middleware.CurrentActor(c, h.authService)
+	if !ok {
+		return
+	}
+	params.Tenant = actor.TenantFilter()
+
+	chapterResponses, meta, err := h.service.GetChaptersByCourseID(uint(courseId), params)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error":   true,
@@ -75,15 +102,26 @@ func (h *ChapterHandler) GetAllChapters(c *gin.Context) {
 		return
 	}
 
+	if user, ok := c.Get("user"); ok {
+		if err := h.progressService.AttachChapterProgress(user.(models.User).ID, chapterResponses); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   true,
+				"message": err.Error(),
+			})
+			return
+		}
+	}
+
 	c.JSON(http.StatusOK, gin.H{
 		"error": false,
 		"data":  chapterResponses,
+		"meta":  meta,
 	})
 }
 
 // GetChapterByID handles GET /api/v1/courses/:id/chapters/:chapterId
 // @Summary Get a chapter by ID
-// @Description Get a chapter by its ID
+// @Description Get a chapter by its ID with the current user's progress
 // @Tags chapters
 // @Accept json
 // @Produce json
@@ -92,6 +130,7 @@ func (h *ChapterHandler) GetAllChapters(c *gin.Context) {
 // @Param chapterId path int true "Chapter ID"
 // @Success 200 {object} map[string]interface{} "Returns the chapter"
 // @Failure 400 {object} map[string]interface{} "Invalid chapter ID"
+// @Failure 403 {object} map[string]interface{} "Not enrolled in this course"
 // @Failure 404 {object} map[string]interface{} "Chapter not found"
 // @Failure 500 {object} map[string]interface{} "Internal server error"
 // @Router /courses/{id}/chapters/{chapterId} [get]
@@ -116,7 +155,12 @@ func (h *ChapterHandler) GetChapterByID(c *gin.Context) {
 		return
 	}
 
-	chapter, err := h.service.GetChapterByIDWithLessonsCount(uint(id), uint(courseID))
+	actor, ok := middleware.CurrentActor(c, h.authService)
+	if !ok {
+		return
+	}
+
+	chapter, err := h.service.GetChapterByIDWithLessonsCount(uint(id), uint(courseID), actor.TenantFilter())
 	if err != nil {
 		status := http.StatusInternalServerError
 		if err.Error() == "chapter not found" {
@@ -129,6 +173,18 @@ func (h *ChapterHandler) GetChapterByID(c *gin.Context) {
 		return
 	}
 
+	if user, ok := c.Get("user"); ok {
+		chapters := []schemas.ChapterResponseWithLessonsCount{chapter}
+		if err := h.progressService.AttachChapterProgress(user.(models.User).ID, chapters); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   true,
+				"message": err.Error(),
+			})
+			return
+		}
+		chapter = chapters[0]
+	}
+
 	c.JSON(http.StatusOK, gin.H{
 		"error": false,
 		"data":  chapter,
@@ -138,6 +194,7 @@ func (h *ChapterHandler) GetChapterByID(c *gin.Context) {
 // CreateChapter handles POST /api/v1/courses/:id/chapters
 // @Summary Create a new chapter
 // @Description Create a new chapter with the provided data
+// @Description Only admins, the course owner and co-instructors can add chapters.
 // @Tags chapters
 // @Accept json
 // @Produce json
@@ -146,6 +203,7 @@ func (h *ChapterHandler) GetChapterByID(c *gin.Context) {
 // @Param chapter body schemas.ChapterRequest true "Chapter data"
 // @Success 201 {object} map[string]interface{} "Chapter created successfully"
 // @Failure 400 {object} map[string]interface{} "Invalid request body or validation error"
+// @Failure 403 {object} map[string]interface{} "Insufficient permissions"
 // @Router /courses/{id}/chapters [post]
 // @example request - example payload
 //
@@ -205,6 +263,7 @@ func (h *ChapterHandler) CreateChapter(c *gin.Context) {
 		return
 	}
 
+	middleware.SetAuditResourceID(c, id)
 	c.JSON(http.StatusCreated, gin.H{
 		"error": false,
 		"data": gin.H{
@@ -217,6 +276,7 @@ func (h *ChapterHandler) CreateChapter(c *gin.Context) {
 // UpdateChapter handles PUT /api/v1/courses/:id/chapters/:chapterId
 // @Summary Update a chapter
 // @Description Update a chapter with the provided data
+// @Description Only admins, the course owner and co-instructors can update chapters.
 // @Tags chapters
 // @Accept json
 // @Produce json
@@ -226,6 +286,7 @@ func (h *ChapterHandler) CreateChapter(c *gin.Context) {
 // @Param chapter body schemas.ChapterRequest true "Chapter data"
 // @Success 200 {object} map[string]interface{} "Chapter updated successfully"
 // @Failure 400 {object} map[string]interface{} "Invalid request body or validation error"
+// @Failure 403 {object} map[string]interface{} "Insufficient permissions"
 // @Failure 404 {object} map[string]interface{} "Chapter not found"
 // @Router /courses/{id}/chapters/{chapterId} [put]
 func (h *ChapterHandler) UpdateChapter(c *gin.Context) {
@@ -287,6 +348,7 @@ func (h *ChapterHandler) UpdateChapter(c *gin.Context) {
 // DeleteChapter handles DELETE /api/v1/courses/:id/chapters/:chapterId
 // @Summary Delete a chapter
 // @Description Delete a chapter by its ID
+// @Description Only admins, the course owner and co-instructors can delete chapters.
 // @Tags chapters
 // @Accept json
 // @Produce json
@@ -295,6 +357,7 @@ func (h *ChapterHandler) UpdateChapter(c *gin.Context) {
 // @Param chapterId path int true "Chapter ID"
 // @Success 200 {object} map[string]interface{} "Chapter deleted successfully"
 // @Failure 400 {object} map[string]interface{} "Invalid chapter ID"
+// @Failure 403 {object} map[string]interface{} "Insufficient permissions"
 // @Failure 404 {object} map[string]interface{} "Chapter not found"
 // @Failure 500 {object} map[string]interface{} "Internal server error"
 // @Router /courses/{id}/chapters/{chapterId} [delete]
@@ -319,7 +382,12 @@ func (h *ChapterHandler) DeleteChapter(c *gin.Context) {
 		return
 	}
 
-	chapter, err := h.service.GetChapterByID(uint(id), uint(courseId))
+	actor, ok := middleware.CurrentActor(c, h.authService)
+	if !ok {
+		return
+	}
+
+	chapter, err := h.service.GetChapterByID(uint(id), uint(courseId), actor.TenantFilter())
 	if err != nil {
 		status := http.StatusInternalServerError
 		if err.Error() == "chapter not found" {
@@ -0,0 +1,170 @@
+package v1
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"web/config"
+	"web/middleware"
+	"web/schemas"
+	"web/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxCourseArchiveUploadSize limits the size of an uploaded course archive
+const maxCourseArchiveUploadSize = 1 << 30
+
+// CourseArchiveHandler handles HTTP requests for exporting and importing courses
+type CourseArchiveHandler struct {
+	app           *config.AppConfig
+	service       *services.CourseArchiveService
+	authService   *services.AuthService
+	policyService *services.PolicyService
+	auditService  *services.AuditService
+}
+
+// NewCourseArchiveHandler creates a new course archive handler
+func NewCourseArchiveHandler(app *config.AppConfig, service *services.CourseArchiveService, authService *services.AuthService, policyService *services.PolicyService, auditService *services.AuditService) *CourseArchiveHandler {
+	return &CourseArchiveHandler{
+		app:           app,
+		service:       service,
+		authService:   authService,
+		policyService: policyService,
+		auditService:  auditService,
+	}
+}
+
+// RegisterRoutes registers course export and import api to the router
+func (h *CourseArchiveHandler) RegisterRoutes(router *gin.Engine) {
+	courseGroup := router.Group("/api/v1/courses")
+	courseGroup.Use(middleware.AuthMiddleware(h.authService))
+	// Archives move whole courses between environments
+	{
+		courseGroup.GET("/:id/export", middleware.AuthorizeCourse(h.authService, h.policyService, services.ActionCopy, services.ResourceCourse), h.ExportCourse)
+		courseGroup.POST("/import", middleware.Authorize(h.authService, h.policyService, services.ActionImport, services.ResourceCourse), middleware.Audit(h.auditService, services.ActionImport, services.ResourceCourse, middleware.AuditCreated), h.ImportCourse)
+	}
+}
+
+// ExportCourse handles GET /api/v1/courses/:id/export
+// @Summary Export a course
+// @Description Download a zip archive with a JSON manifest of the course, its chapters, lessons and attachments, and the attachment files. Teachers can export their own and published courses.
+// @Tags courses
+// @Produce application/zip
+// @Security BearerAuth
+// @Param id path int true "Course ID"
+// @Success 200 {file} binary "Course archive"
+// @Failure 400 {object} map[string]interface{} "Invalid course ID"
+// @Failure 403 {object} map[string]interface{} "Insufficient permissions"
+// @Failure 404 {object} map[string]interface{} "Course not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /courses/{id}/export [get]
+func (h *CourseArchiveHandler) ExportCourse(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		middleware.RespondWithBadRequest(c, "Invalid course ID")
+		return
+	}
+
+	actor, ok := middleware.CurrentActor(c, h.authService)
+	if !ok {
+		return
+	}
+
+	course, err := h.service.GetExportableCourse(uint(id), actor)
+	if err != nil {
+		switch err.Error() {
+		case "course not found":
+			middleware.RespondWithNotFound(c, err.Error())
+		case "insufficient permissions to export this course":
+			middleware.RespondWithError(c, http.StatusForbidden, err.Error())
+		default:
+			middleware.RespondWithInternalServerError(c, err.Error())
+		}
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=\"course-%d.zip\"", course.ID))
+	c.Header("Content-Type", "application/zip")
+
+	if err := h.service.WriteCourseArchive(c.Writer, course); err != nil {
+		middleware.RespondWithInternalServerError(c, "Failed to stream course archive: "+err.Error())
+		return
+	}
+}
+
+// ImportCourse handles POST /api/v1/courses/import
+// @Summary Import a course
+// @Description Recreate a course from an archive written by the export endpoint as a new draft course owned by the caller. Conflicts such as an existing course name or missing attachment files are reported; with on_conflict=fail (default) the import is rejected, with on_conflict=resolve the reported resolutions are applied.
+// @Tags courses
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param archive formData file true "Course archive"
+// @Param name formData string false "Name of the imported course, defaults to the name in the archive"
+// @Param on_conflict formData string false "fail or resolve"
+// @Success 201 {object} map[string]interface{} "Returns the imported course tree, the ID map and the resolved conflicts"
+// @Failure 400 {object} map[string]interface{} "Invalid archive or request"
+// @Failure 403 {object} map[string]interface{} "Insufficient permissions"
+// @Failure 409 {object} map[string]interface{} "Archive has conflicts, the conflicts are returned in data"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /courses/import [post]
+func (h *CourseArchiveHandler) ImportCourse(c *gin.Context) {
+	var importRequest schemas.ImportCourseRequest
+	if err := c.ShouldBind(&importRequest); err != nil {
+		middleware.RespondWithBadRequest(c, "Invalid request")
+		return
+	}
+
+	file, err := c.FormFile("archive")
+	if err != nil {
+		middleware.RespondWithBadRequest(c, "No course archive uploaded")
+		return
+	}
+	if file.Size > maxCourseArchiveUploadSize {
+		middleware.RespondWithBadRequest(c, "Course archive is too large")
+		return
+	}
+
+	actor, ok := middleware.CurrentActor(c, h.authService)
+	if !ok {
+		return
+	}
+
+	archive, err := file.Open()
+	if err != nil {
+		middleware.RespondWithInternalServerError(c, "Failed to open course archive")
+		return
+	}
+	defer archive.Close()
+
+	importResponse, err := h.service.ImportCourse(archive, file.Size, actor, importRequest)
+	if err != nil {
+		switch {
+		case err.Error() == "course archive has conflicts":
+			c.JSON(http.StatusConflict, gin.H{
+				"error":   true,
+				"data":    importResponse,
+				"message": err.Error(),
+			})
+		case err.Error() == "insufficient permissions to import courses":
+			middleware.RespondWithError(c, http.StatusForbidden, err.Error())
+		case strings.HasPrefix(err.Error(), "failed to import attachment"):
+			middleware.RespondWithInternalServerError(c, err.Error())
+		case strings.Contains(err.Error(), "archive"), strings.Contains(err.Error(), "manifest"),
+			strings.HasPrefix(err.Error(), "chapter "), strings.HasPrefix(err.Error(), "lesson "),
+			strings.Contains(err.Error(), "too large"), strings.Contains(err.Error(), "course name"),
+			strings.HasPrefix(err.Error(), "invalid conflict mode"):
+			middleware.RespondWithBadRequest(c, err.Error())
+		default:
+			middleware.RespondWithInternalServerError(c, err.Error())
+		}
+		return
+	}
+
+	if importResponse.Course != nil {
+		middleware.SetAuditResourceID(c, importResponse.Course.ID)
+	}
+	middleware.RespondWithCreated(c, importResponse, "Course imported successfully")
+}
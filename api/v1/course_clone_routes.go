@@ -0,0 +1,101 @@
+package v1
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"web/config"
+	"web/middleware"
+	"web/schemas"
+	"web/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CourseCloneHandler handles HTTP requests for cloning courses
+type CourseCloneHandler struct {
+	app           *config.AppConfig
+	service       *services.CourseCloneService
+	authService   *services.AuthService
+	policyService *services.PolicyService
+	auditService  *services.AuditService
+}
+
+// NewCourseCloneHandler creates a new course clone handler
+func NewCourseCloneHandler(app *config.AppConfig, service *services.CourseCloneService, authService *services.AuthService, policyService *services.PolicyService, auditService *services.AuditService) *CourseCloneHandler {
+	return &CourseCloneHandler{
+		app:           app,
+		service:       service,
+		authService:   authService,
+		policyService: policyService,
+		auditService:  auditService,
+	}
+}
+
+// RegisterRoutes registers course clone api to the router
+func (h *CourseCloneHandler) RegisterRoutes(router *gin.Engine) {
+	courseGroup := router.Group("/api/v1/courses")
+	courseGroup.Use(middleware.AuthMiddleware(h.authService))
+	{
+		courseGroup.POST("/:id/clone", middleware.AuthorizeCourse(h.authService, h.policyService, services.ActionCopy, services.ResourceCourse), middleware.Audit(h.auditService, services.ActionCopy, services.ResourceCourse, middleware.AuditCreated), h.CloneCourse)
+	}
+}
+
+// CloneCourse handles POST /api/v1/courses/:id/clone
+// @Summary Clone a course
+// @Description Copy a course with all of its chapters, lessons and attachments into a new draft course owned by the caller. Teachers can clone their own and published courses.
+// @Tags courses
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Course ID"
+// @Param clone body schemas.CloneCourseRequest false "Name of the new course, defaults to the source name with a (copy) suffix"
+// @Success 201 {object} map[string]interface{} "Returns the tree of the new course"
+// @Failure 400 {object} map[string]interface{} "Invalid course ID or request body"
+// @Failure 403 {object} map[string]interface{} "Insufficient permissions"
+// @Failure 404 {object} map[string]interface{} "Course not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /courses/{id}/clone [post]
+// @example request - example payload
+//
+//	{
+//	  "name": "Introduction to Go Programming (Spring)"
+//	}
+func (h *CourseCloneHandler) CloneCourse(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		middleware.RespondWithBadRequest(c, "Invalid course ID")
+		return
+	}
+
+	// The body is optional
+	var cloneRequest schemas.CloneCourseRequest
+	if err := c.ShouldBindJSON(&cloneRequest); err != nil && !errors.Is(err, io.EOF) {
+		middleware.RespondWithBadRequest(c, "Invalid request body")
+		return
+	}
+
+	actor, ok := middleware.CurrentActor(c, h.authService)
+	if !ok {
+		return
+	}
+
+	tree, err := h.service.CloneCourse(uint(id), actor, cloneRequest)
+	if err != nil {
+		switch err.Error() {
+		case "course not found":
+			middleware.RespondWithNotFound(c, err.Error())
+		case "insufficient permissions to clone this course":
+			middleware.RespondWithError(c, http.StatusForbidden, err.Error())
+		case "course ID is required", "course name must not exceed 255 characters":
+			middleware.RespondWithBadRequest(c, err.Error())
+		default:
+			middleware.RespondWithInternalServerError(c, err.Error())
+		}
+		return
+	}
+
+	middleware.SetAuditResourceID(c, tree.ID)
+	middleware.RespondWithCreated(c, tree, "Course cloned successfully")
+}
@@ -0,0 +1,215 @@
+package v1
+
+import (
+	"net/http"
+	"strconv"
+	"web/config"
+	"web/middleware"
+	"web/schemas"
+	"web/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CourseMemberHandler handles HTTP requests for the collaborators of a course
+type CourseMemberHandler struct {
+	app           *config.AppConfig
+	service       *services.CourseMemberService
+	authService   *services.AuthService
+	policyService *services.PolicyService
+}
+
+// NewCourseMemberHandler creates a new course member handler
+func NewCourseMemberHandler(app *config.AppConfig, service *services.CourseMemberService, authService *services.AuthService, policyService *services.PolicyService) *CourseMemberHandler {
+	return &CourseMemberHandler{
+		app:           app,
+		service:       service,
+		authService:   authService,
+		policyService: policyService,
+	}
+}
+
+// RegisterRoutes registers course member api to the router
+func (h *CourseMemberHandler) RegisterRoutes(router *gin.Engine) {
+	memberGroup := router.Group("/api/v1/courses/:id/members")
+	memberGroup.Use(middleware.AuthMiddleware(h.authService))
+	{
+		memberGroup.GET("", middleware.AuthorizeCourse(h.authService, h.policyService, services.ActionRead, services.ResourceCourseMember), h.GetMembers)
+		memberGroup.POST("", middleware.AuthorizeCourse(h.authService, h.policyService, services.ActionCreate, services.ResourceCourseMember), h.InviteMember)
+		memberGroup.PUT("/:userId", middleware.AuthorizeCourse(h.authService, h.policyService, services.ActionUpdate, services.ResourceCourseMember), h.UpdateMember)
+		memberGroup.DELETE("/:userId", middleware.AuthorizeCourse(h.authService, h.policyService, services.ActionDelete, services.ResourceCourseMember), h.RemoveMember)
+	}
+}
+
+// memberErrorStatus maps course member service errors to HTTP status codes
+func memberErrorStatus(err error) int {
+	switch err.Error() {
+	case "course not found", "user not found", "course member not found":
+		return http.StatusNotFound
+	case "invalid member role", "user ID or email is required", "user does not belong to the organization of this course":
+		return http.StatusBadRequest
+	case "user is already a member of this course":
+		return http.StatusConflict
+	case "the course owner cannot be changed", "the course owner cannot be removed":
+		return http.StatusForbidden
+	}
+	return http.StatusInternalServerError
+}
+
+// GetMembers handles GET /api/v1/courses/:id/members
+// @Summary List course members
+// @Description Get the owner and the collaborators of a course with their roles (admins and course members only)
+// @Tags course members
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Course ID"
+// @Success 200 {object} map[string]interface{} "Returns the course members"
+// @Failure 403 {object} map[string]interface{} "Insufficient permissions"
+// @Failure 404 {object} map[string]interface{} "Course not found"
+// @Router /courses/{id}/members [get]
+func (h *CourseMemberHandler) GetMembers(c *gin.Context) {
+	courseID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		middleware.RespondWithBadRequest(c, "Invalid course ID")
+		return
+	}
+
+	members, err := h.service.ListMembers(uint(courseID))
+	if err != nil {
+		middleware.RespondWithError(c, memberErrorStatus(err), err.Error())
+		return
+	}
+
+	middleware.RespondWithSuccess(c, members, "")
+}
+
+// InviteMember handles POST /api/v1/courses/:id/members
+// @Summary Invite a course member
+// @Description Add a user, identified by ID or email, to a course as co_instructor, teaching_assistant or viewer (admins and the course owner only). Co-instructors edit the course content, teaching assistants read quiz answers and issue certificates, viewers read drafts and history.
+// @Tags course members
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Course ID"
+// @Param member body schemas.InviteCourseMemberRequest true "User and role"
+// @Success 201 {object} map[string]interface{} "Member added successfully"
+// @Failure 400 {object} map[string]interface{} "Invalid request body or role"
+// @Failure 403 {object} map[string]interface{} "Insufficient permissions"
+// @Failure 404 {object} map[string]interface{} "Course or user not found"
+// @Failure 409 {object} map[string]interface{} "User is already a member"
+// @Router /courses/{id}/members [post]
+// @example request - example payload
+//
+//	{
+//	  "email": "jane.doe@example.com",
+//	  "role": "co_instructor"
+//	}
+func (h *CourseMemberHandler) InviteMember(c *gin.Context) {
+	courseID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		middleware.RespondWithBadRequest(c, "Invalid course ID")
+		return
+	}
+
+	var inviteRequest schemas.InviteCourseMemberRequest
+	if err := c.ShouldBindJSON(&inviteRequest); err != nil {
+		middleware.RespondWithBadRequest(c, "Invalid request body")
+		return
+	}
+
+	actor, ok := middleware.CurrentActor(c, h.authService)
+	if !ok {
+		return
+	}
+
+	member, err := h.service.InviteMember(uint(courseID), actor, inviteRequest)
+	if err != nil {
+		middleware.RespondWithError(c, memberErrorStatus(err), err.Error())
+		return
+	}
+
+	middleware.RespondWithCreated(c, member, "Member added successfully")
+}
+
+// UpdateMember handles PUT /api/v1/courses/:id/members/:userId
+// @Summary Change the role of a course member
+// @Description Change the role of a collaborator (admins and the course owner only). The role of the owner cannot be changed.
+// @Tags course members
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Course ID"
+// @Param userId path int true "User ID"
+// @Param member body schemas.UpdateCourseMemberRequest true "New role"
+// @Success 200 {object} map[string]interface{} "Member updated successfully"
+// @Failure 400 {object} map[string]interface{} "Invalid request body or role"
+// @Failure 403 {object} map[string]interface{} "Insufficient permissions"
+// @Failure 404 {object} map[string]interface{} "Course member not found"
+// @Router /courses/{id}/members/{userId} [put]
+// @example request - example payload
+//
+//	{
+//	  "role": "viewer"
+//	}
+func (h *CourseMemberHandler) UpdateMember(c *gin.Context) {
+	courseID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		middleware.RespondWithBadRequest(c, "Invalid course ID")
+		return
+	}
+
+	userID, err := strconv.ParseUint(c.Param("userId"), 10, 32)
+	if err != nil {
+		middleware.RespondWithBadRequest(c, "Invalid user ID")
+		return
+	}
+
+	var updateRequest schemas.UpdateCourseMemberRequest
+	if err := c.ShouldBindJSON(&updateRequest); err != nil {
+		middleware.RespondWithBadRequest(c, "Invalid request body")
+		return
+	}
+
+	member, err := h.service.UpdateMemberRole(uint(courseID), uint(userID), updateRequest)
+	if err != nil {
+		middleware.RespondWithError(c, memberErrorStatus(err), err.Error())
+		return
+	}
+
+	middleware.RespondWithSuccess(c, member, "Member updated successfully")
+}
+
+// RemoveMember handles DELETE /api/v1/courses/:id/members/:userId
+// @Summary Remove a course member
+// @Description Remove a collaborator from a course (admins and the course owner only). The owner cannot be removed.
+// @Tags course members
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Course ID"
+// @Param userId path int true "User ID"
+// @Success 200 {object} map[string]interface{} "Member removed successfully"
+// @Failure 403 {object} map[string]interface{} "Insufficient permissions"
+// @Failure 404 {object} map[string]interface{} "Course member not found"
+// @Router /courses/{id}/members/{userId} [delete]
+func (h *CourseMemberHandler) RemoveMember(c *gin.Context) {
+	courseID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		middleware.RespondWithBadRequest(c, "Invalid course ID")
+		return
+	}
+
+	userID, err := strconv.ParseUint(c.Param("userId"), 10, 32)
+	if err != nil {
+		middleware.RespondWithBadRequest(c, "Invalid user ID")
+		return
+	}
+
+	if err := h.service.RemoveMember(uint(courseID), uint(userID)); err != nil {
+		middleware.RespondWithError(c, memberErrorStatus(err), err.Error())
+		return
+	}
+
+	middleware.RespondWithSuccess(c, nil, "Member removed successfully")
+}
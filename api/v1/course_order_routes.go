@@ -0,0 +1,258 @@
+package v1
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"web/config"
+	"web/middleware"
+	"web/schemas"
+	"web/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CourseOrderHandler handles HTTP requests for reordering chapters and lessons
+type CourseOrderHandler struct {
+	app           *config.AppConfig
+	service       *services.CourseOrderService
+	authService   *services.AuthService
+	policyService *services.PolicyService
+	auditService  *services.AuditService
+}
+
+// NewCourseOrderHandler creates a new course order handler
+func NewCourseOrderHandler(app *config.AppConfig, service *services.CourseOrderService, authService *services.AuthService, policyService *services.PolicyService, auditService *services.AuditService) *CourseOrderHandler {
+	return &CourseOrderHandler{
+		app:           app,
+		service:       service,
+		authService:   authService,
+		policyService: policyService,
+		auditService:  auditService,
+	}
+}
+
+// RegisterRoutes registers chapter and lesson ordering api to the router
+func (h *CourseOrderHandler) RegisterRoutes(router *gin.Engine) {
+	chapterGroup := router.Group("/api/v1/courses/:id/chapters")
+	chapterGroup.Use(middleware.AuthMiddleware(h.authService))
+	{
+		chapterGroup.PUT("/order", middleware.AuthorizeCourse(h.authService, h.policyService, services.ActionUpdate, services.ResourceChapter), middleware.Audit(h.auditService, services.ActionUpdate, services.AuditResourceChapterOrder, middleware.AuditParam("id")), h.ReorderChapters)
+		chapterGroup.PUT("/:chapterId/lessons/order", middleware.AuthorizeCourse(h.authService, h.policyService, services.ActionUpdate, services.ResourceLesson), middleware.Audit(h.auditService, services.ActionUpdate, services.AuditResourceLessonOrder, middleware.AuditParam("chapterId")), h.ReorderLessons)
+		chapterGroup.POST("/:chapterId/move", middleware.AuthorizeCourse(h.authService, h.policyService, services.ActionUpdate, services.ResourceChapter), middleware.Audit(h.auditService, services.ActionUpdate, services.ResourceChapter, middleware.AuditParam("chapterId")), h.MoveChapter)
+		chapterGroup.POST("/:chapterId/lessons/:lessonId/move", middleware.AuthorizeCourse(h.authService, h.policyService, services.ActionUpdate, services.ResourceLesson), middleware.Audit(h.auditService, services.ActionUpdate, services.ResourceLesson, middleware.AuditParam("lessonId")), h.MoveLesson)
+	}
+}
+
+// orderErrorStatus maps course order service errors to HTTP status codes
+func orderErrorStatus(err error) int {
+	switch {
+	case err.Error() == "course not found", err.Error() == "chapter not found", err.Error() == "lesson not found",
+		err.Error() == "target chapter not found in this course", err.Error() == "target course not found":
+		return http.StatusNotFound
+	case strings.Contains(err.Error(), " order "), strings.HasSuffix(err.Error(), " position"),
+		strings.HasSuffix(err.Error(), " ID is required"):
+		return http.StatusBadRequest
+	case err.Error() == "insufficient permissions to move content between these courses":
+		return http.StatusForbidden
+	}
+	return http.StatusInternalServerError
+}
+
+// ReorderChapters handles PUT /api/v1/courses/:id/chapters/order
+// @Summary Reorder the chapters of a course
+// @Description Set the order of all chapters of a course at once. The list must contain every chapter of the course exactly once; the chapters get the positions 1..n in one transaction.
+// @Tags chapters
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Course ID"
+// @Param order body schemas.ReorderChaptersRequest true "Chapter IDs in their new order"
+// @Success 200 {object} map[string]interface{} "Returns the chapters in their new order"
+// @Failure 400 {object} map[string]interface{} "Invalid request or the list does not match the chapters of the course"
+// @Failure 403 {object} map[string]interface{} "Insufficient permissions"
+// @Failure 404 {object} map[string]interface{} "Course not found"
+// @Router /courses/{id}/chapters/order [put]
+// @example request - example payload
+//
+//	{
+//	  "chapter_ids": [3, 1, 2]
+//	}
+func (h *CourseOrderHandler) ReorderChapters(c *gin.Context) {
+	courseID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		middleware.RespondWithBadRequest(c, "Invalid course ID")
+		return
+	}
+
+	var reorderRequest schemas.ReorderChaptersRequest
+	if err := c.ShouldBindJSON(&reorderRequest); err != nil {
+		middleware.RespondWithBadRequest(c, "Invalid request body")
+		return
+	}
+
+	chapters, err := h.service.ReorderChapters(uint(courseID), reorderRequest)
+	if err != nil {
+		middleware.RespondWithError(c, orderErrorStatus(err), err.Error())
+		return
+	}
+
+	middleware.RespondWithSuccess(c, chapters, "Chapters reordered successfully")
+}
+
+// ReorderLessons handles PUT /api/v1/courses/:id/chapters/:chapterId/lessons/order
+// @Summary Reorder the lessons of a chapter
+// @Description Set the order of all lessons of a chapter at once. The list must contain every lesson of the chapter exactly once; the lessons get the positions 1..n in one transaction.
+// @Tags lessons
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Course ID"
+// @Param chapterId path int true "Chapter ID"
+// @Param order body schemas.ReorderLessonsRequest true "Lesson IDs in their new order"
+// @Success 200 {object} map[string]interface{} "Returns the lessons in their new order"
+// @Failure 400 {object} map[string]interface{} "Invalid request or the list does not match the lessons of the chapter"
+// @Failure 403 {object} map[string]interface{} "Insufficient permissions"
+// @Failure 404 {object} map[string]interface{} "Chapter not found"
+// @Router /courses/{id}/chapters/{chapterId}/lessons/order [put]
+// @example request - example payload
+//
+//	{
+//	  "lesson_ids": [3, 1, 2]
+//	}
+func (h *CourseOrderHandler) ReorderLessons(c *gin.Context) {
+	courseID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		middleware.RespondWithBadRequest(c, "Invalid course ID")
+		return
+	}
+
+	chapterID, err := strconv.ParseUint(c.Param("chapterId"), 10, 32)
+	if err != nil {
+		middleware.RespondWithBadRequest(c, "Invalid chapter ID")
+		return
+	}
+
+	var reorderRequest schemas.ReorderLessonsRequest
+	if err := c.ShouldBindJSON(&reorderRequest); err != nil {
+		middleware.RespondWithBadRequest(c, "Invalid request body")
+		return
+	}
+
+	actor, ok := middleware.CurrentActor(c, h.authService)
+	if !ok {
+		return
+	}
+
+	lessons, err := h.service.ReorderLessons(uint(courseID), uint(chapterID), reorderRequest, actor.TenantFilter())
+	if err != nil {
+		middleware.RespondWithError(c, orderErrorStatus(err), err.Error())
+		return
+	}
+
+	middleware.RespondWithSuccess(c, lessons, "Lessons reordered successfully")
+}
+
+// MoveChapter handles POST /api/v1/courses/:id/chapters/:chapterId/move
+// @Summary Move a chapter to another course
+// @Description Move a chapter with its lessons to a position in another course, or to another position in its course. Both courses are renumbered in one transaction.
+// @Description Moving to another course requires being an admin or the creator of both courses. Attachments keep their storage objects.
+// @Tags chapters
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Course ID"
+// @Param chapterId path int true "Chapter ID"
+// @Param move body schemas.MoveChapterRequest true "Target course and position"
+// @Success 200 {object} map[string]interface{} "Returns the chapters of the target course in their new order"
+// @Failure 400 {object} map[string]interface{} "Invalid request or position"
+// @Failure 403 {object} map[string]interface{} "Insufficient permissions"
+// @Failure 404 {object} map[string]interface{} "Chapter or target course not found"
+// @Router /courses/{id}/chapters/{chapterId}/move [post]
+// @example request - example payload
+//
+//	{
+//	  "course_id": 2,
+//	  "position": 1
+//	}
+func (h *CourseOrderHandler) MoveChapter(c *gin.Context) {
+	courseID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		middleware.RespondWithBadRequest(c, "Invalid course ID")
+		return
+	}
+	chapterID, err := strconv.ParseUint(c.Param("chapterId"), 10, 32)
+	if err != nil {
+		middleware.RespondWithBadRequest(c, "Invalid chapter ID")
+		return
+	}
+
+	var moveRequest schemas.MoveChapterRequest
+	if err := c.ShouldBindJSON(&moveRequest); err != nil {
+		middleware.RespondWithBadRequest(c, "Invalid request body")
+		return
+	}
+
+	actor, ok := middleware.CurrentActor(c, h.authService)
+	if !ok {
+		return
+	}
+
+	chapters, err := h.service.MoveChapter(uint(courseID), uint(chapterID), actor, moveRequest)
+	if err != nil {
+		middleware.RespondWithError(c, orderErrorStatus(err), err.Error())
+		return
+	}
+
+	middleware.RespondWithSuccess(c, chapters, "Chapter moved successfully")
+}
+
+// MoveLesson handles POST /api/v1/courses/:id/chapters/:chapterId/lessons/:lessonId/move
+// @Summary Move a lesson to another chapter
+// @Description Move a lesson to a position in another chapter, or to another position in its chapter. Both chapters are renumbered in one transaction.
+// @Description Moving to a chapter of another course (course_id) requires being an admin or the creator of both courses. Attachments keep their storage objects.
+// @Tags lessons
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Course ID"
+// @Param chapterId path int true "Chapter ID"
+// @Param lessonId path int true "Lesson ID"
+// @Param move body schemas.MoveLessonRequest true "Target chapter and position"
+// @Success 200 {object} map[string]interface{} "Returns the lessons of the target chapter in their new order"
+// @Failure 400 {object} map[string]interface{} "Invalid request or position"
+// @Failure 403 {object} map[string]interface{} "Insufficient permissions"
+// @Failure 404 {object} map[string]interface{} "Lesson or target chapter not found"
+// @Router /courses/{id}/chapters/{chapterId}/lessons/{lessonId}/move [post]
+// @example request - example payload
+//
+//	{
+//	  "course_id": 1,
+//	  "chapter_id": 2,
+//	  "position": 1
+//	}
+func (h *CourseOrderHandler) MoveLesson(c *gin.Context) {
+	courseID, chapterID, lessonID, ok := parseLessonPath(c)
+	if !ok {
+		return
+	}
+
+	var moveRequest schemas.MoveLessonRequest
+	if err := c.ShouldBindJSON(&moveRequest); err != nil {
+		middleware.RespondWithBadRequest(c, "Invalid request body")
+		return
+	}
+
+	actor, ok := middleware.CurrentActor(c, h.authService)
+	if !ok {
+		return
+	}
+
+	lessons, err := h.service.MoveLesson(courseID, chapterID, lessonID, actor, moveRequest)
+	if err != nil {
+		middleware.RespondWithError(c, orderErrorStatus(err), err.Error())
+		return
+	}
+
+	middleware.RespondWithSuccess(c, lessons, "Lesson moved successfully")
+}
@@ -2,28 +2,39 @@ package v1
 
 import (
 	"github.com/gin-gonic/gin"
+	"net/http"
 	"strconv"
+	"strings"
 	"web/config"
 	"web/middleware"
+	"web/models"
 	"web/schemas"
 	"web/services"
 )
 
 // CourseHandler handles HTTP requests for courses
 type CourseHandler struct {
-	app            *config.AppConfig
-	service        *services.CourseService
-	chapterService *services.ChapterService
-	authService    *services.AuthService
+	app               *config.AppConfig
+	service           *services.CourseService
+	chapterService    *services.ChapterService
+	enrollmentService *services.EnrollmentService
+	progressService   *services.ProgressService
+	authService       *services.AuthService
+	policyService     *services.PolicyService
+	auditService      *services.AuditService
 }
 
 // NewCourseHandler creates a new course handler
-func NewCourseHandler(app *config.AppConfig, service *services.CourseService, chapterService *services.ChapterService, authService *services.AuthService) *CourseHandler {
+func NewCourseHandler(app *config.AppConfig, service *services.CourseService, chapterService *services.ChapterService, enrollmentService *services.EnrollmentService, progressService *services.ProgressService, authService *services.AuthService, policyService *services.PolicyService, auditService *services.AuditService) *CourseHandler {
 	return &CourseHandler{
-		app:            app,
-		service:        service,
-		chapterService: chapterService,
-		authService:    authService,
+		app:               app,
+		service:           service,
+		chapterService:    chapterService,
+		enrollmentService: enrollmentService,
+		progressService:   progressService,
+		authService:       authService,
+		policyService:     policyService,
+		auditService:      auditService,
 	}
 }
 
@@ -33,36 +44,79 @@ func (h *CourseHandler) RegisterRoutes(router *gin.Engine) {
 	courseGroup.Use(middleware.AuthMiddleware(h.authService))
 	{
 		courseGroup.GET("", h.GetAllCourses)
-		courseGroup.GET("/:id", h.GetCourseByID)
-		courseGroup.POST("", h.CreateCourse)
-		courseGroup.PUT("/:id", h.UpdateCourse)
-		courseGroup.DELETE("/:id", h.DeleteCourse)
+		courseGroup.GET("/:id", middleware.RequireCourseAccess(h.authService, h.enrollmentService), h.GetCourseByID)
+		courseGroup.POST("", middleware.Authorize(h.authService, h.policyService, services.ActionCreate, services.ResourceCourse), middleware.Audit(h.auditService, services.ActionCreate, services.ResourceCourse, middleware.AuditCreated), h.CreateCourse)
+		courseGroup.PUT("/:id", middleware.AuthorizeCourse(h.authService, h.policyService, services.ActionUpdate, services.ResourceCourse), middleware.Audit(h.auditService, services.ActionUpdate, services.ResourceCourse, middleware.AuditParam("id")), h.UpdateCourse)
+		courseGroup.DELETE("/:id", middleware.AuthorizeCourse(h.authService, h.policyService, services.ActionDelete, services.ResourceCourse), middleware.Audit(h.auditService, services.ActionDelete, services.ResourceCourse, middleware.AuditParam("id")), h.DeleteCourse)
+		courseGroup.POST("/:id/status", middleware.AuthorizeCourse(h.authService, h.policyService, services.ActionChangeStatus, services.ResourceCourse), middleware.Audit(h.auditService, services.ActionChangeStatus, services.ResourceCourse, middleware.AuditParam("id")), h.ChangeCourseStatus)
+		courseGroup.GET("/:id/status-history", middleware.AuthorizeCourse(h.authService, h.policyService, services.ActionReadHistory, services.ResourceCourse), h.GetCourseStatusHistory)
 	}
 }
 
 // GetAllCourses handles GET /api/courses
 // @Summary Get all courses
-// @Description Get a list of all courses
+// @Description Get a paginated list of courses with the current user's progress.
+// @Description Learners only see published courses and the drafts of courses they own or collaborate on, admins and teachers see every course.
 // @Tags courses
 // @Security BearerAuth
 // @Accept json
 // @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Page size (max 100)" default(20)
+// @Param sort query string false "Sort key: name or created_at, prefix with - for descending" default(-created_at)
+// @Param created_by query int false "Only courses created by this user"
+// @Param name query string false "Only courses whose name contains this text"
+// @Param created_from query string false "Only courses created at or after this date (RFC 3339 or YYYY-MM-DD)"
+// @Param created_to query string false "Only courses created at or before this date (RFC 3339 or YYYY-MM-DD)"
+// @Param status query string false "Only courses with this status: draft, in_review, published or archived"
 // @Success 200 {object} map[string]interface{} "Returns a list of courses"
+// @Failure 400 {object} map[string]interface{} "Invalid query parameters"
 // @Failure 500 {object} map[string]interface{} "Internal server error"
 // @Router /courses [get]
 func (h *CourseHandler) GetAllCourses(c *gin.Context) {
-	courseResponses, err := h.service.GetAllCourses()
+	params, ok := parseListParams(c, schemas.CourseSortKeys, "-created_at")
+	if !ok {
+		return
+	}
+
+	params.Status = c.Query("status")
+	switch params.Status {
+	case "", models.CourseStatusDraft, models.CourseStatusInReview, models.CourseStatusPublished, models.CourseStatusArchived:
+	default:
+		middleware.RespondWithBadRequest(c, "Invalid course status")
+		return
+	}
+
+	actor, ok := middleware.CurrentActor(c, h.authService)
+	if !ok {
+		return
+	}
+
+	// Learners only see published courses and the drafts of the courses they are members of
+	if !h.policyService.Can(actor, services.ActionReadUnpublished, services.ResourceCourse, nil) {
+		params.VisibleTo = &actor.UserID
+	}
+	params.Tenant = actor.TenantFilter()
+
+	courseResponses, meta, err := h.service.GetAllCourses(params)
 	if err != nil {
 		middleware.RespondWithInternalServerError(c, err.Error())
 		return
 	}
 
-	middleware.RespondWithSuccess(c, courseResponses, "")
+	if user, ok := c.Get("user"); ok {
+		if err := h.progressService.AttachCourseProgress(user.(models.User).ID, courseResponses); err != nil {
+			middleware.RespondWithInternalServerError(c, err.Error())
+			return
+		}
+	}
+
+	middleware.RespondWithSuccessAndMeta(c, courseResponses, meta, "")
 }
 
 // GetCourseByID handles GET /api/courses/:id
 // @Summary Get a course by ID
-// @Description Get a course by its ID
+// @Description Get a course by its ID with the current user's progress
 // @Tags courses
 // @Accept json
 // @Produce json
@@ -70,6 +124,7 @@ func (h *CourseHandler) GetAllCourses(c *gin.Context) {
 // @Param id path int true "Course ID"
 // @Success 200 {object} map[string]interface{} "Returns the course"
 // @Failure 400 {object} map[string]interface{} "Invalid course ID"
+// @Failure 403 {object} map[string]interface{} "Not enrolled in this course"
 // @Failure 404 {object} map[string]interface{} "Course not found"
 // @Failure 500 {object} map[string]interface{} "Internal server error"
 // @Router /courses/{id} [get]
@@ -91,12 +146,22 @@ func (h *CourseHandler) GetCourseByID(c *gin.Context) {
 		return
 	}
 
+	if user, ok := c.Get("user"); ok {
+		courseResponses := []schemas.CourseResponseWithChaptersCount{courseResponse}
+		if err := h.progressService.AttachCourseProgress(user.(models.User).ID, courseResponses); err != nil {
+			middleware.RespondWithInternalServerError(c, err.Error())
+			return
+		}
+		courseResponse = courseResponses[0]
+	}
+
 	middleware.RespondWithSuccess(c, courseResponse, "")
 }
 
 // CreateCourse handles POST /api/courses
 // @Summary Create a new course
 // @Description Create a new course with the provided data
+// @Description Only admins and teachers can create courses.
 // @Tags courses
 // @Accept json
 // @Security BearerAuth
@@ -104,6 +169,7 @@ func (h *CourseHandler) GetCourseByID(c *gin.Context) {
 // @Param course body schemas.CreateCourseRequest true "Course data"
 // @Success 201 {object} map[string]interface{} "Course created successfully"
 // @Failure 400 {object} map[string]interface{} "Invalid request body or validation error"
+// @Failure 403 {object} map[string]interface{} "Insufficient permissions"
 // @Router /courses [post]
 // @example request - example payload
 //
@@ -131,9 +197,10 @@ func (h *CourseHandler) CreateCourse(c *gin.Context) {
 		return
 	}
 
-	// Set the created_by field
+	// Set the created_by field, the course belongs to the organization of its creator
 	userID := user.ID
 	courseRequest.CreatedBy = &userID
+	courseRequest.OrganizationID = user.OrganizationID
 
 	courseResponse, err := h.service.CreateCourse(courseRequest)
 	if err != nil {
@@ -141,12 +208,14 @@ func (h *CourseHandler) CreateCourse(c *gin.Context) {
 		return
 	}
 
+	middleware.SetAuditResourceID(c, courseResponse.ID)
 	middleware.RespondWithCreated(c, courseResponse, "Course created successfully")
 }
 
 // UpdateCourse handles PUT /api/courses/:id
 // @Summary Update a course
 // @Description Update a course with the provided data
+// @Description Only admins, the course owner and co-instructors can update it.
 // @Tags courses
 // @Accept json
 // @Produce json
@@ -155,6 +224,7 @@ func (h *CourseHandler) CreateCourse(c *gin.Context) {
 // @Param course body schemas.UpdateCourseRequest true "Course data"
 // @Success 200 {object} map[string]interface{} "Course updated successfully"
 // @Failure 400 {object} map[string]interface{} "Invalid request body or validation error"
+// @Failure 403 {object} map[string]interface{} "Insufficient permissions"
 // @Failure 404 {object} map[string]interface{} "Course not found"
 // @Router /courses/{id} [put]
 func (h *CourseHandler) UpdateCourse(c *gin.Context) {
@@ -198,6 +268,7 @@ func (h *CourseHandler) UpdateCourse(c *gin.Context) {
 // DeleteCourse handles DELETE /api/courses/:id
 // @Summary Delete a course
 // @Description Delete a course by its ID
+// @Description Only admins and the course owner can delete it.
 // @Tags courses
 // @Accept json
 // @Produce json
@@ -205,6 +276,7 @@ func (h *CourseHandler) UpdateCourse(c *gin.Context) {
 // @Param id path int true "Course ID"
 // @Success 200 {object} map[string]interface{} "Course deleted successfully"
 // @Failure 400 {object} map[string]interface{} "Invalid course ID"
+// @Failure 403 {object} map[string]interface{} "Insufficient permissions"
 // @Failure 404 {object} map[string]interface{} "Course not found"
 // @Failure 500 {object} map[string]interface{} "Internal server error"
 // @Router /courses/{id} [delete]
@@ -228,3 +300,107 @@ func (h *CourseHandler) DeleteCourse(c *gin.Context) {
 
 	middleware.RespondWithSuccess(c, nil, "Course deleted successfully")
 }
+
+// ChangeCourseStatus handles POST /api/courses/:id/status
+// @Summary Change the publishing status of a course
+// @Description Move a course through the publishing workflow. Teachers submit their own drafts for review
+// @Description (draft -> in_review) and may withdraw them (in_review -> draft). Admins approve (in_review -> published),
+// @Description reject (in_review -> draft), archive (published -> archived) and restore (archived -> draft).
+// @Tags courses
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Course ID"
+// @Param status body schemas.ChangeCourseStatusRequest true "Target status"
+// @Success 200 {object} map[string]interface{} "Course status changed successfully"
+// @Failure 400 {object} map[string]interface{} "Invalid status or transition"
+// @Failure 403 {object} map[string]interface{} "Insufficient permissions"
+// @Failure 404 {object} map[string]interface{} "Course not found"
+// @Failure 409 {object} map[string]interface{} "Course status changed concurrently"
+// @Router /courses/{id}/status [post]
+// @example request - example payload
+//
+//	{
+//	  "status": "in_review",
+//	  "comment": "Ready for review"
+//	}
+func (h *CourseHandler) ChangeCourseStatus(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		middleware.RespondWithBadRequest(c, "Invalid course ID")
+		return
+	}
+
+	var statusRequest schemas.ChangeCourseStatusRequest
+	if err := c.ShouldBindJSON(&statusRequest); err != nil {
+		middleware.RespondWithBadRequest(c, "Invalid request body")
+		return
+	}
+
+	actor, ok := middleware.CurrentActor(c, h.authService)
+	if !ok {
+		return
+	}
+
+	courseResponse, err := h.service.ChangeCourseStatus(uint(id), actor, statusRequest)
+	if err != nil {
+		switch {
+		case err.Error() == "course not found":
+			middleware.RespondWithNotFound(c, err.Error())
+		case err.Error() == "insufficient permissions to change course status":
+			middleware.RespondWithError(c, http.StatusForbidden, err.Error())
+		case err.Error() == "course status has changed, please reload and try again":
+			middleware.RespondWithError(c, http.StatusConflict, err.Error())
+		case err.Error() == "invalid course status",
+			strings.HasPrefix(err.Error(), "course is already"),
+			strings.HasPrefix(err.Error(), "cannot change course status"):
+			middleware.RespondWithBadRequest(c, err.Error())
+		default:
+			middleware.RespondWithInternalServerError(c, err.Error())
+		}
+		return
+	}
+
+	middleware.RespondWithSuccess(c, courseResponse, "Course status changed successfully")
+}
+
+// GetCourseStatusHistory handles GET /api/courses/:id/status-history
+// @Summary Get the publishing history of a course
+// @Description Get every status transition of a course. Only admins, teachers and course members can see it.
+// @Tags courses
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Course ID"
+// @Success 200 {object} map[string]interface{} "Returns the status transitions"
+// @Failure 400 {object} map[string]interface{} "Invalid course ID"
+// @Failure 403 {object} map[string]interface{} "Insufficient permissions"
+// @Failure 404 {object} map[string]interface{} "Course not found"
+// @Router /courses/{id}/status-history [get]
+func (h *CourseHandler) GetCourseStatusHistory(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		middleware.RespondWithBadRequest(c, "Invalid course ID")
+		return
+	}
+
+	course, err := h.service.GetCourseByID(uint(id))
+	if err != nil {
+		if err.Error() == "course not found" {
+			middleware.RespondWithNotFound(c, err.Error())
+		} else {
+			middleware.RespondWithInternalServerError(c, err.Error())
+		}
+		return
+	}
+
+	history, err := h.service.GetCourseStatusHistory(course.ID)
+	if err != nil {
+		middleware.RespondWithInternalServerError(c, err.Error())
+		return
+	}
+
+	middleware.RespondWithSuccess(c, history, "")
+}
@@ -0,0 +1,177 @@
+package v1
+
+import (
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+	"web/config"
+	"web/middleware"
+	"web/models"
+	"web/services"
+)
+
+// EnrollmentHandler handles HTTP requests for course enrollments
+type EnrollmentHandler struct {
+	app           *config.AppConfig
+	service       *services.EnrollmentService
+	authService   *services.AuthService
+	policyService *services.PolicyService
+}
+
+// NewEnrollmentHandler creates a new enrollment handler
+func NewEnrollmentHandler(app *config.AppConfig, service *services.EnrollmentService, authService *services.AuthService, policyService *services.PolicyService) *EnrollmentHandler {
+	return &EnrollmentHandler{
+		app:           app,
+		service:       service,
+		authService:   authService,
+		policyService: policyService,
+	}
+}
+
+// RegisterRoutes registers enrollment api to the router
+func (h *EnrollmentHandler) RegisterRoutes(router *gin.Engine) {
+	enrollmentGroup := router.Group("/api/v1/courses/:id/enrollment")
+	enrollmentGroup.Use(middleware.AuthMiddleware(h.authService))
+	{
+		// Users only enroll in the courses of their organization
+		enrollmentGroup.POST("", middleware.AuthorizeCourse(h.authService, h.policyService, services.ActionEnroll, services.ResourceCourse), h.Enroll)
+		enrollmentGroup.DELETE("", h.Unenroll)
+	}
+
+	meGroup := router.Group("/api/v1/users/me")
+	meGroup.Use(middleware.AuthMiddleware(h.authService))
+	{
+		meGroup.GET("/enrollments", h.GetMyEnrollments)
+	}
+}
+
+// Enroll handles POST /api/v1/courses/:id/enrollment
+// @Summary Enroll in a course
+// @Description Enroll the current user in a course
+// @Tags enrollments
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Course ID"
+// @Success 201 {object} map[string]interface{} "Enrolled successfully"
+// @Failure 400 {object} map[string]interface{} "Invalid course ID or course not published"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 404 {object} map[string]interface{} "Course not found"
+// @Failure 409 {object} map[string]interface{} "Already enrolled"
+// @Router /courses/{id}/enrollment [post]
+func (h *EnrollmentHandler) Enroll(c *gin.Context) {
+	idStr := c.Param("id")
+	courseID, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		middleware.RespondWithBadRequest(c, "Invalid course ID")
+		return
+	}
+
+	userObj, exists := c.Get("user")
+	if !exists {
+		middleware.RespondWithError(c, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	currentUser, ok := userObj.(models.User)
+	if !ok {
+		middleware.RespondWithError(c, http.StatusInternalServerError, "Invalid user type")
+		return
+	}
+
+	enrollmentResponse, err := h.service.Enroll(currentUser.ID, uint(courseID))
+	if err != nil {
+		switch err.Error() {
+		case "course not found":
+			middleware.RespondWithNotFound(c, err.Error())
+		case "already enrolled in this course":
+			middleware.RespondWithError(c, http.StatusConflict, err.Error())
+		case "course is not published":
+			middleware.RespondWithBadRequest(c, err.Error())
+		default:
+			middleware.RespondWithInternalServerError(c, err.Error())
+		}
+		return
+	}
+
+	middleware.RespondWithCreated(c, enrollmentResponse, "Enrolled successfully")
+}
+
+// Unenroll handles DELETE /api/v1/courses/:id/enrollment
+// @Summary Leave a course
+// @Description Remove the current user's enrollment from a course
+// @Tags enrollments
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Course ID"
+// @Success 200 {object} map[string]interface{} "Unenrolled successfully"
+// @Failure 400 {object} map[string]interface{} "Invalid course ID"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 404 {object} map[string]interface{} "Enrollment not found"
+// @Router /courses/{id}/enrollment [delete]
+func (h *EnrollmentHandler) Unenroll(c *gin.Context) {
+	idStr := c.Param("id")
+	courseID, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		middleware.RespondWithBadRequest(c, "Invalid course ID")
+		return
+	}
+
+	userObj, exists := c.Get("user")
+	if !exists {
+		middleware.RespondWithError(c, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	currentUser, ok := userObj.(models.User)
+	if !ok {
+		middleware.RespondWithError(c, http.StatusInternalServerError, "Invalid user type")
+		return
+	}
+
+	err = h.service.Unenroll(currentUser.ID, uint(courseID))
+	if err != nil {
+		if err.Error() == "enrollment not found" {
+			middleware.RespondWithNotFound(c, err.Error())
+		} else {
+			middleware.RespondWithInternalServerError(c, err.Error())
+		}
+		return
+	}
+
+	middleware.RespondWithSuccess(c, nil, "Unenrolled successfully")
+}
+
+// GetMyEnrollments handles GET /api/v1/users/me/enrollments
+// @Summary Get my enrollments
+// @Description Get the list of courses the current user is enrolled in
+// @Tags enrollments
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{} "Returns a list of enrollments"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /users/me/enrollments [get]
+func (h *EnrollmentHandler) GetMyEnrollments(c *gin.Context) {
+	userObj, exists := c.Get("user")
+	if !exists {
+		middleware.RespondWithError(c, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	currentUser, ok := userObj.(models.User)
+	if !ok {
+		middleware.RespondWithError(c, http.StatusInternalServerError, "Invalid user type")
+		return
+	}
+
+	enrollments, err := h.service.GetUserEnrollments(currentUser.ID)
+	if err != nil {
+		middleware.RespondWithInternalServerError(c, err.Error())
+		return
+	}
+
+	middleware.RespondWithSuccess(c, enrollments, "")
+}
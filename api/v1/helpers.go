@@ -0,0 +1,183 @@
+package v1
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"web/models"
+	"web/schemas"
+
+	"github.com/gin-gonic/gin"
+)
+
+// parseLessonPath parses the course, chapter and lesson IDs of a lesson route
+// and writes the error response when one of them is invalid
+func parseLessonPath(c *gin.Context) (courseID, chapterID, lessonID uint, ok bool) {
+	courseId, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   true,
+			"message": "Invalid course ID",
+		})
+		return 0, 0, 0, false
+	}
+
+	chapterId, err := strconv.ParseUint(c.Param("chapterId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   true,
+			"message": "Invalid chapter ID",
+		})
+		return 0, 0, 0, false
+	}
+
+	lessonId, err := strconv.ParseUint(c.Param("lessonId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   true,
+			"message": "Invalid lesson ID",
+		})
+		return 0, 0, 0, false
+	}
+
+	return uint(courseId), uint(chapterId), uint(lessonId), true
+}
+
+// currentUser returns the user stored by AuthMiddleware and writes the error
+// response when it is missing
+func currentUser(c *gin.Context) (models.User, bool) {
+	userObj, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   true,
+			"message": "Authentication required",
+		})
+		return models.User{}, false
+	}
+
+	user, ok := userObj.(models.User)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   true,
+			"message": "Invalid user type",
+		})
+		return models.User{}, false
+	}
+
+	return user, true
+}
+
+// parseListParams parses the pagination, sorting and filtering query parameters of a
+// listing request and writes the error response when one of them is invalid.
+// defaultSort is used when no sort key is given and may start with "-".
+func parseListParams(c *gin.Context, sortKeys []string, defaultSort string) (schemas.ListParams, bool) {
+	page, limit, ok := parsePageParams(c)
+	if !ok {
+		return schemas.ListParams{}, false
+	}
+	params := schemas.ListParams{
+		Page:  page,
+		Limit: limit,
+	}
+
+	badRequest := func(message string) (schemas.ListParams, bool) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   true,
+			"message": message,
+		})
+		return schemas.ListParams{}, false
+	}
+
+	sort := c.DefaultQuery("sort", defaultSort)
+	params.SortDesc = strings.HasPrefix(sort, "-")
+	params.SortBy = strings.TrimPrefix(sort, "-")
+	validSort := false
+	for _, key := range sortKeys {
+		if key == params.SortBy {
+			validSort = true
+			break
+		}
+	}
+	if !validSort {
+		return badRequest("Invalid sort key, allowed: " + strings.Join(sortKeys, ", "))
+	}
+
+	if createdByStr := c.Query("created_by"); createdByStr != "" {
+		createdBy, err := strconv.ParseUint(createdByStr, 10, 32)
+		if err != nil {
+			return badRequest("Invalid created_by")
+		}
+		userID := uint(createdBy)
+		params.CreatedBy = &userID
+	}
+
+	params.NameContains = strings.TrimSpace(c.Query("name"))
+
+	if fromStr := c.Query("created_from"); fromStr != "" {
+		from, _, err := parseDateParam(fromStr)
+		if err != nil {
+			return badRequest("Invalid created_from, use RFC 3339 or YYYY-MM-DD")
+		}
+		params.CreatedFrom = &from
+	}
+
+	if toStr := c.Query("created_to"); toStr != "" {
+		to, dateOnly, err := parseDateParam(toStr)
+		if err != nil {
+			return badRequest("Invalid created_to, use RFC 3339 or YYYY-MM-DD")
+		}
+		if dateOnly {
+			// Include the whole day
+			to = to.Add(24*time.Hour - time.Nanosecond)
+		}
+		params.CreatedTo = &to
+	}
+
+	if params.CreatedFrom != nil && params.CreatedTo != nil && params.CreatedFrom.After(*params.CreatedTo) {
+		return badRequest("created_from must not be after created_to")
+	}
+
+	return params, true
+}
+
+// parsePageParams parses the page and limit query parameters and writes the error
+// response when one of them is invalid. Limits above MaxPageLimit are capped.
+func parsePageParams(c *gin.Context) (page, limit int, ok bool) {
+	page, limit = 1, schemas.DefaultPageLimit
+
+	if pageStr := c.Query("page"); pageStr != "" {
+		value, err := strconv.Atoi(pageStr)
+		if err != nil || value < 1 {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   true,
+				"message": "Invalid page",
+			})
+			return 0, 0, false
+		}
+		page = value
+	}
+
+	if limitStr := c.Query("limit"); limitStr != "" {
+		value, err := strconv.Atoi(limitStr)
+		if err != nil || value < 1 {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   true,
+				"message": "Invalid limit",
+			})
+			return 0, 0, false
+		}
+		limit = min(value, schemas.MaxPageLimit)
+	}
+
+	return page, limit, true
+}
+
+// parseDateParam parses an RFC 3339 timestamp or a plain date
+func parseDateParam(value string) (t time.Time, dateOnly bool, err error) {
+	if t, err = time.Parse(time.RFC3339, value); err == nil {
+		return t, false, nil
+	}
+	t, err = time.Parse("2006-01-02", value)
+	return t, true, err
+}
@@ -0,0 +1,329 @@
+package v1
+
+import (
+	"net/http"
+	"strconv"
+	"web/config"
+	"web/middleware"
+	"web/schemas"
+	"web/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// LessonBlockHandler handles HTTP requests for lesson content blocks
+type LessonBlockHandler struct {
+	app               *config.AppConfig
+	service           *services.LessonBlockService
+	enrollmentService *services.EnrollmentService
+	authService       *services.AuthService
+	policyService     *services.PolicyService
+	auditService      *services.AuditService
+}
+
+// NewLessonBlockHandler creates a new lesson block handler
+func NewLessonBlockHandler(app *config.AppConfig, service *services.LessonBlockService, enrollmentService *services.EnrollmentService, authService *services.AuthService, policyService *services.PolicyService, auditService *services.AuditService) *LessonBlockHandler {
+	return &LessonBlockHandler{
+		app:               app,
+		service:           service,
+		enrollmentService: enrollmentService,
+		authService:       authService,
+		policyService:     policyService,
+		auditService:      auditService,
+	}
+}
+
+// RegisterRoutes registers lesson block api to the router
+func (h *LessonBlockHandler) RegisterRoutes(router *gin.Engine) {
+	blockGroup := router.Group("/api/v1/courses/:id/chapters/:chapterId/lessons/:lessonId/blocks")
+	blockGroup.Use(middleware.AuthMiddleware(h.authService))
+	{
+		blockGroup.GET("", middleware.RequireCourseAccess(h.authService, h.enrollmentService), h.GetBlocks)
+
+		// Authoring endpoints - blocks are lesson content, changing them updates the lesson
+		authorGroup := blockGroup.Group("")
+		authorGroup.Use(
+			middleware.AuthorizeCourse(h.authService, h.policyService, services.ActionUpdate, services.ResourceLesson),
+			middleware.Audit(h.auditService, services.ActionUpdate, services.ResourceLesson, middleware.AuditParam("lessonId")),
+		)
+		{
+			authorGroup.POST("", h.CreateBlock)
+			authorGroup.PUT("/order", h.ReorderBlocks)
+			authorGroup.PUT("/:blockId", h.UpdateBlock)
+			authorGroup.DELETE("/:blockId", h.DeleteBlock)
+		}
+	}
+}
+
+// parseBlockID parses the :blockId route parameter and writes the error response when it is invalid
+func parseBlockID(c *gin.Context) (uint, bool) {
+	blockId, err := strconv.ParseUint(c.Param("blockId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   true,
+			"message": "Invalid block ID",
+		})
+		return 0, false
+	}
+
+	return uint(blockId), true
+}
+
+// blockErrorStatus maps lesson block service errors to HTTP status codes
+func blockErrorStatus(err error, defaultStatus int) int {
+	switch err.Error() {
+	case "lesson not found", "block not found", "lesson not found or no changes made":
+		return http.StatusNotFound
+	}
+	return defaultStatus
+}
+
+// GetBlocks handles GET /api/v1/courses/:id/chapters/:chapterId/lessons/:lessonId/blocks
+// @Summary Get the content blocks of a lesson
+// @Description Get the ordered content blocks of a lesson
+// @Tags lesson-blocks
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Course ID"
+// @Param chapterId path int true "Chapter ID"
+// @Param lessonId path int true "Lesson ID"
+// @Success 200 {object} map[string]interface{} "Returns a list of blocks"
+// @Failure 400 {object} map[string]interface{} "Invalid ID"
+// @Failure 403 {object} map[string]interface{} "Not enrolled in this course"
+// @Failure 404 {object} map[string]interface{} "Lesson not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /courses/{id}/chapters/{chapterId}/lessons/{lessonId}/blocks [get]
+func (h *LessonBlockHandler) GetBlocks(c *gin.Context) {
+	courseID, chapterID, lessonID, ok := parseLessonPath(c)
+	if !ok {
+		return
+	}
+
+	actor, ok := middleware.CurrentActor(c, h.authService)
+	if !ok {
+		return
+	}
+
+	blocks, err := h.service.GetBlocks(courseID, chapterID, lessonID, actor.TenantFilter())
+	if err != nil {
+		c.JSON(blockErrorStatus(err, http.StatusInternalServerError), gin.H{
+			"error":   true,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"error": false,
+		"data":  blocks,
+	})
+}
+
+// CreateBlock handles POST /api/v1/courses/:id/chapters/:chapterId/lessons/:lessonId/blocks
+// @Summary Add a content block to a lesson
+// @Description Add a markdown, code, attachment, video or callout block. The block is inserted at position, or appended when no position is given.
+// @Tags lesson-blocks
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Course ID"
+// @Param chapterId path int true "Chapter ID"
+// @Param lessonId path int true "Lesson ID"
+// @Param block body schemas.LessonBlockRequest true "Block data"
+// @Success 201 {object} map[string]interface{} "Block created successfully"
+// @Failure 400 {object} map[string]interface{} "Invalid request body or validation error"
+// @Failure 403 {object} map[string]interface{} "Insufficient permissions"
+// @Failure 404 {object} map[string]interface{} "Lesson not found"
+// @Router /courses/{id}/chapters/{chapterId}/lessons/{lessonId}/blocks [post]
+func (h *LessonBlockHandler) CreateBlock(c *gin.Context) {
+	courseID, chapterID, lessonID, ok := parseLessonPath(c)
+	if !ok {
+		return
+	}
+
+	var blockRequest schemas.LessonBlockRequest
+	if err := c.ShouldBindJSON(&blockRequest); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   true,
+			"message": "Invalid request body",
+		})
+		return
+	}
+
+	actor, ok := middleware.CurrentActor(c, h.authService)
+	if !ok {
+		return
+	}
+
+	block, err := h.service.CreateBlock(courseID, chapterID, lessonID, blockRequest, actor.UserID, actor.TenantFilter())
+	if err != nil {
+		c.JSON(blockErrorStatus(err, http.StatusBadRequest), gin.H{
+			"error":   true,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"error":   false,
+		"data":    block,
+		"message": "Block created successfully",
+	})
+}
+
+// UpdateBlock handles PUT /api/v1/courses/:id/chapters/:chapterId/lessons/:lessonId/blocks/:blockId
+// @Summary Update a content block
+// @Description Replace the content of a block. The block keeps its position.
+// @Tags lesson-blocks
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Course ID"
+// @Param chapterId path int true "Chapter ID"
+// @Param lessonId path int true "Lesson ID"
+// @Param blockId path int true "Block ID"
+// @Param block body schemas.LessonBlockRequest true "Block data"
+// @Success 200 {object} map[string]interface{} "Block updated successfully"
+// @Failure 400 {object} map[string]interface{} "Invalid request body or validation error"
+// @Failure 403 {object} map[string]interface{} "Insufficient permissions"
+// @Failure 404 {object} map[string]interface{} "Lesson or block not found"
+// @Router /courses/{id}/chapters/{chapterId}/lessons/{lessonId}/blocks/{blockId} [put]
+func (h *LessonBlockHandler) UpdateBlock(c *gin.Context) {
+	courseID, chapterID, lessonID, ok := parseLessonPath(c)
+	if !ok {
+		return
+	}
+	blockID, ok := parseBlockID(c)
+	if !ok {
+		return
+	}
+
+	var blockRequest schemas.LessonBlockRequest
+	if err := c.ShouldBindJSON(&blockRequest); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   true,
+			"message": "Invalid request body",
+		})
+		return
+	}
+
+	actor, ok := middleware.CurrentActor(c, h.authService)
+	if !ok {
+		return
+	}
+
+	block, err := h.service.UpdateBlock(courseID, chapterID, lessonID, blockID, blockRequest, actor.UserID, actor.TenantFilter())
+	if err != nil {
+		c.JSON(blockErrorStatus(err, http.StatusBadRequest), gin.H{
+			"error":   true,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"error":   false,
+		"data":    block,
+		"message": "Block updated successfully",
+	})
+}
+
+// ReorderBlocks handles PUT /api/v1/courses/:id/chapters/:chapterId/lessons/:lessonId/blocks/order
+// @Summary Reorder the content blocks of a lesson
+// @Description Put the blocks in the given order. The request must list every block of the lesson exactly once.
+// @Tags lesson-blocks
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Course ID"
+// @Param chapterId path int true "Chapter ID"
+// @Param lessonId path int true "Lesson ID"
+// @Param order body schemas.ReorderLessonBlocksRequest true "Block IDs in their new order"
+// @Success 200 {object} map[string]interface{} "Blocks reordered successfully"
+// @Failure 400 {object} map[string]interface{} "Invalid request body or incomplete order"
+// @Failure 403 {object} map[string]interface{} "Insufficient permissions"
+// @Failure 404 {object} map[string]interface{} "Lesson not found"
+// @Router /courses/{id}/chapters/{chapterId}/lessons/{lessonId}/blocks/order [put]
+func (h *LessonBlockHandler) ReorderBlocks(c *gin.Context) {
+	courseID, chapterID, lessonID, ok := parseLessonPath(c)
+	if !ok {
+		return
+	}
+
+	var reorderRequest schemas.ReorderLessonBlocksRequest
+	if err := c.ShouldBindJSON(&reorderRequest); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   true,
+			"message": "Invalid request body",
+		})
+		return
+	}
+
+	actor, ok := middleware.CurrentActor(c, h.authService)
+	if !ok {
+		return
+	}
+
+	blocks, err := h.service.ReorderBlocks(courseID, chapterID, lessonID, reorderRequest, actor.UserID, actor.TenantFilter())
+	if err != nil {
+		c.JSON(blockErrorStatus(err, http.StatusBadRequest), gin.H{
+			"error":   true,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"error":   false,
+		"data":    blocks,
+		"message": "Blocks reordered successfully",
+	})
+}
+
+// DeleteBlock handles DELETE /api/v1/courses/:id/chapters/:chapterId/lessons/:lessonId/blocks/:blockId
+// @Summary Delete a content block
+// @Description Remove a block from a lesson. The following blocks move up.
+// @Tags lesson-blocks
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Course ID"
+// @Param chapterId path int true "Chapter ID"
+// @Param lessonId path int true "Lesson ID"
+// @Param blockId path int true "Block ID"
+// @Success 200 {object} map[string]interface{} "Block deleted successfully"
+// @Failure 400 {object} map[string]interface{} "Invalid ID"
+// @Failure 403 {object} map[string]interface{} "Insufficient permissions"
+// @Failure 404 {object} map[string]interface{} "Lesson or block not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /courses/{id}/chapters/{chapterId}/lessons/{lessonId}/blocks/{blockId} [delete]
+func (h *LessonBlockHandler) DeleteBlock(c *gin.Context) {
+	courseID, chapterID, lessonID, ok := parseLessonPath(c)
+	if !ok {
+		return
+	}
+	blockID, ok := parseBlockID(c)
+	if !ok {
+		return
+	}
+
+	actor, ok := middleware.CurrentActor(c, h.authService)
+	if !ok {
+		return
+	}
+
+	err := h.service.DeleteBlock(courseID, chapterID, lessonID, blockID, actor.UserID, actor.TenantFilter())
+	if err != nil {
+		c.JSON(blockErrorStatus(err, http.StatusInternalServerError), gin.H{
+			"error":   true,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"error":   false,
+		"message": "Block deleted successfully",
+	})
+}
@@ -0,0 +1,270 @@
+package v1
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"web/config"
+	"web/middleware"
+	"web/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// LessonRevisionHandler handles HTTP requests for lesson revisions
+type LessonRevisionHandler struct {
+	app           *config.AppConfig
+	service       *services.LessonRevisionService
+	authService   *services.AuthService
+	policyService *services.PolicyService
+	auditService  *services.AuditService
+}
+
+// NewLessonRevisionHandler creates a new lesson revision handler
+func NewLessonRevisionHandler(app *config.AppConfig, service *services.LessonRevisionService, authService *services.AuthService, policyService *services.PolicyService, auditService *services.AuditService) *LessonRevisionHandler {
+	return &LessonRevisionHandler{
+		app:           app,
+		service:       service,
+		authService:   authService,
+		policyService: policyService,
+		auditService:  auditService,
+	}
+}
+
+// RegisterRoutes registers lesson revision api to the router
+func (h *LessonRevisionHandler) RegisterRoutes(router *gin.Engine) {
+	revisionGroup := router.Group("/api/v1/courses/:id/chapters/:chapterId/lessons/:lessonId/revisions")
+	revisionGroup.Use(middleware.AuthMiddleware(h.authService))
+	{
+		// Revisions are authoring tools, restoring one updates the lesson
+		historyGroup := revisionGroup.Group("")
+		historyGroup.Use(middleware.AuthorizeCourse(h.authService, h.policyService, services.ActionReadHistory, services.ResourceLesson))
+		{
+			historyGroup.GET("", h.GetRevisions)
+			historyGroup.GET("/diff", h.DiffRevisions)
+			historyGroup.GET("/:revision", h.GetRevision)
+		}
+		revisionGroup.POST("/:revision/restore", middleware.AuthorizeCourse(h.authService, h.policyService, services.ActionUpdate, services.ResourceLesson), middleware.Audit(h.auditService, services.ActionUpdate, services.ResourceLesson, middleware.AuditParam("lessonId")), h.RestoreRevision)
+	}
+}
+
+// parseRevisionNumber parses a revision number and writes the error response when it is invalid
+func parseRevisionNumber(c *gin.Context, value, message string) (int, bool) {
+	revision, err := strconv.Atoi(value)
+	if err != nil || revision < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   true,
+			"message": message,
+		})
+		return 0, false
+	}
+
+	return revision, true
+}
+
+// revisionErrorStatus maps lesson revision service errors to HTTP status codes
+func revisionErrorStatus(err error, defaultStatus int) int {
+	switch {
+	case err.Error() == "lesson not found", err.Error() == "revision not found",
+		err.Error() == "lesson not found or no changes made":
+		return http.StatusNotFound
+	case err.Error() == "invalid revision":
+		return http.StatusBadRequest
+	case err.Error() == "revisions differ in too many lines to compare":
+		return http.StatusUnprocessableEntity
+	case strings.HasPrefix(err.Error(), "lesson already matches revision"):
+		return http.StatusConflict
+	}
+	return defaultStatus
+}
+
+// GetRevisions handles GET /api/v1/courses/:id/chapters/:chapterId/lessons/:lessonId/revisions
+// @Summary Get the revisions of a lesson
+// @Description Get all revisions of a lesson, newest first, without their content
+// @Tags lesson-revisions
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Course ID"
+// @Param chapterId path int true "Chapter ID"
+// @Param lessonId path int true "Lesson ID"
+// @Success 200 {object} map[string]interface{} "Returns a list of revisions"
+// @Failure 400 {object} map[string]interface{} "Invalid ID"
+// @Failure 403 {object} map[string]interface{} "Insufficient permissions"
+// @Failure 404 {object} map[string]interface{} "Lesson not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /courses/{id}/chapters/{chapterId}/lessons/{lessonId}/revisions [get]
+func (h *LessonRevisionHandler) GetRevisions(c *gin.Context) {
+	courseID, chapterID, lessonID, ok := parseLessonPath(c)
+	if !ok {
+		return
+	}
+
+	actor, ok := middleware.CurrentActor(c, h.authService)
+	if !ok {
+		return
+	}
+
+	revisions, err := h.service.GetRevisions(courseID, chapterID, lessonID, actor.TenantFilter())
+	if err != nil {
+		c.JSON(revisionErrorStatus(err, http.StatusInternalServerError), gin.H{
+			"error":   true,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"error": false,
+		"data":  revisions,
+	})
+}
+
+// GetRevision handles GET /api/v1/courses/:id/chapters/:chapterId/lessons/:lessonId/revisions/:revision
+// @Summary Get a lesson revision
+// @Description Get the full lesson state stored in a revision
+// @Tags lesson-revisions
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Course ID"
+// @Param chapterId path int true "Chapter ID"
+// @Param lessonId path int true "Lesson ID"
+// @Param revision path int true "Revision number"
+// @Success 200 {object} map[string]interface{} "Returns the revision"
+// @Failure 400 {object} map[string]interface{} "Invalid ID or revision"
+// @Failure 403 {object} map[string]interface{} "Insufficient permissions"
+// @Failure 404 {object} map[string]interface{} "Lesson or revision not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /courses/{id}/chapters/{chapterId}/lessons/{lessonId}/revisions/{revision} [get]
+func (h *LessonRevisionHandler) GetRevision(c *gin.Context) {
+	courseID, chapterID, lessonID, ok := parseLessonPath(c)
+	if !ok {
+		return
+	}
+	revisionNumber, ok := parseRevisionNumber(c, c.Param("revision"), "Invalid revision")
+	if !ok {
+		return
+	}
+
+	actor, ok := middleware.CurrentActor(c, h.authService)
+	if !ok {
+		return
+	}
+
+	revision, err := h.service.GetRevision(courseID, chapterID, lessonID, revisionNumber, actor.TenantFilter())
+	if err != nil {
+		c.JSON(revisionErrorStatus(err, http.StatusInternalServerError), gin.H{
+			"error":   true,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"error": false,
+		"data":  revision,
+	})
+}
+
+// DiffRevisions handles GET /api/v1/courses/:id/chapters/:chapterId/lessons/:lessonId/revisions/diff
+// @Summary Compare two lesson revisions
+// @Description Get a line-level diff of every lesson field that changed between two revisions
+// @Tags lesson-revisions
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Course ID"
+// @Param chapterId path int true "Chapter ID"
+// @Param lessonId path int true "Lesson ID"
+// @Param from query int true "Old revision number"
+// @Param to query int true "New revision number"
+// @Success 200 {object} map[string]interface{} "Returns the diff"
+// @Failure 400 {object} map[string]interface{} "Invalid ID or revision"
+// @Failure 403 {object} map[string]interface{} "Insufficient permissions"
+// @Failure 404 {object} map[string]interface{} "Lesson or revision not found"
+// @Failure 422 {object} map[string]interface{} "Revisions differ in too many lines"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /courses/{id}/chapters/{chapterId}/lessons/{lessonId}/revisions/diff [get]
+func (h *LessonRevisionHandler) DiffRevisions(c *gin.Context) {
+	courseID, chapterID, lessonID, ok := parseLessonPath(c)
+	if !ok {
+		return
+	}
+	from, ok := parseRevisionNumber(c, c.Query("from"), "Invalid from revision")
+	if !ok {
+		return
+	}
+	to, ok := parseRevisionNumber(c, c.Query("to"), "Invalid to revision")
+	if !ok {
+		return
+	}
+
+	actor, ok := middleware.CurrentActor(c, h.authService)
+	if !ok {
+		return
+	}
+
+	diff, err := h.service.DiffRevisions(courseID, chapterID, lessonID, from, to, actor.TenantFilter())
+	if err != nil {
+		c.JSON(revisionErrorStatus(err, http.StatusInternalServerError), gin.H{
+			"error":   true,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"error": false,
+		"data":  diff,
+	})
+}
+
+// RestoreRevision handles POST /api/v1/courses/:id/chapters/:chapterId/lessons/:lessonId/revisions/:revision/restore
+// @Summary Restore a lesson revision
+// @Description Roll the lesson back to an older revision. The rollback is stored as a new revision.
+// @Tags lesson-revisions
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Course ID"
+// @Param chapterId path int true "Chapter ID"
+// @Param lessonId path int true "Lesson ID"
+// @Param revision path int true "Revision number to restore"
+// @Success 200 {object} map[string]interface{} "Lesson restored successfully"
+// @Failure 400 {object} map[string]interface{} "Invalid ID or revision"
+// @Failure 403 {object} map[string]interface{} "Insufficient permissions"
+// @Failure 404 {object} map[string]interface{} "Lesson or revision not found"
+// @Failure 409 {object} map[string]interface{} "Lesson already matches the revision"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /courses/{id}/chapters/{chapterId}/lessons/{lessonId}/revisions/{revision}/restore [post]
+func (h *LessonRevisionHandler) RestoreRevision(c *gin.Context) {
+	courseID, chapterID, lessonID, ok := parseLessonPath(c)
+	if !ok {
+		return
+	}
+	revisionNumber, ok := parseRevisionNumber(c, c.Param("revision"), "Invalid revision")
+	if !ok {
+		return
+	}
+
+	actor, ok := middleware.CurrentActor(c, h.authService)
+	if !ok {
+		return
+	}
+
+	revision, err := h.service.RestoreRevision(courseID, chapterID, lessonID, revisionNumber, actor.UserID, actor.TenantFilter())
+	if err != nil {
+		c.JSON(revisionErrorStatus(err, http.StatusInternalServerError), gin.H{
+			"error":   true,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"error":   false,
+		"data":    revision,
+		"message": "Lesson restored successfully",
+	})
+}
@@ -13,17 +13,23 @@ import (
 
 // LessonHandler handles HTTP requests for lessons
 type LessonHandler struct {
-	app         *config.AppConfig
-	service     *services.LessonService
-	authService *services.AuthService
+	app               *config.AppConfig
+	service           *services.LessonService
+	enrollmentService *services.EnrollmentService
+	authService       *services.AuthService
+	policyService     *services.PolicyService
+	auditService      *services.AuditService
 }
 
 // NewLessonHandler creates a new lesson handler
-func NewLessonHandler(app *config.AppConfig, service *services.LessonService, authService *services.AuthService) *LessonHandler {
+func NewLessonHandler(app *config.AppConfig, service *services.LessonService, enrollmentService *services.EnrollmentService, authService *services.AuthService, policyService *services.PolicyService, auditService *services.AuditService) *LessonHandler {
 	return &LessonHandler{
-		app:         app,
-		service:     service,
-		authService: authService,
+		app:               app,
+		service:           service,
+		enrollmentService: enrollmentService,
+		authService:       authService,
+		policyService:     policyService,
+		auditService:      auditService,
 	}
 }
 
@@ -36,11 +42,11 @@ func (h *LessonHandler) RegisterRoutes(router *gin.Engine) {
 			lessonGroup := chapterGroup.Group("/:chapterId/lessons")
 			lessonGroup.Use(middleware.AuthMiddleware(h.authService))
 			{
-				lessonGroup.GET("", h.GetAllLessons)
-				lessonGroup.GET("/:lessonId", h.GetLessonByID)
-				lessonGroup.POST("", h.CreateLesson)
-				lessonGroup.PUT("/:lessonId", h.UpdateLesson)
-				lessonGroup.DELETE("/:lessonId", h.DeleteLesson)
+				lessonGroup.GET("", middleware.RequireCourseAccess(h.authService, h.enrollmentService), h.GetAllLessons)
+				lessonGroup.GET("/:lessonId", middleware.RequireCourseAccess(h.authService, h.enrollmentService), h.GetLessonByID)
+				lessonGroup.POST("", middleware.AuthorizeCourse(h.authService, h.policyService, services.ActionCreate, services.ResourceLesson), middleware.Audit(h.auditService, services.ActionCreate, services.ResourceLesson, middleware.AuditCreated), h.CreateLesson)
+				lessonGroup.PUT("/:lessonId", middleware.AuthorizeCourse(h.authService, h.policyService, services.ActionUpdate, services.ResourceLesson), middleware.Audit(h.auditService, services.ActionUpdate, services.ResourceLesson, middleware.AuditParam("lessonId")), h.UpdateLesson)
+				lessonGroup.DELETE("/:lessonId", middleware.AuthorizeCourse(h.authService, h.policyService, services.ActionDelete, services.ResourceLesson), middleware.Audit(h.auditService, services.ActionDelete, services.ResourceLesson, middleware.AuditParam("lessonId")), h.DeleteLesson)
 			}
 		}
 	}
@@ -48,15 +54,23 @@ func (h *LessonHandler) RegisterRoutes(router *gin.Engine) {
 
 // GetAllLessons handles GET /api/v1/courses/:id/chapters/:chapterId/lessons
 // @Summary Get all lessons for a chapter
-// @Description Get a list of all lessons for a specific chapter
+// @Description Get a paginated list of lessons for a specific chapter
 // @Tags lessons
 // @Accept json
 // @Produce json
 // @Security BearerAuth
 // @Param id path int true "Course ID"
 // @Param chapterId path int true "Chapter ID"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Page size (max 100)" default(20)
+// @Param sort query string false "Sort key: order, name or created_at, prefix with - for descending" default(order)
+// @Param created_by query int false "Only lessons created by this user"
+// @Param name query string false "Only lessons whose name contains this text"
+// @Param created_from query string false "Only lessons created at or after this date (RFC 3339 or YYYY-MM-DD)"
+// @Param created_to query string false "Only lessons created at or before this date (RFC 3339 or YYYY-MM-DD)"
 // @Success 200 {object} map[string]interface{} "Returns a list of lessons"
 // @Failure 400 {object} map[string]interface{} "Invalid chapter ID"
+// @Failure 403 {object} map[string]interface{} "Not enrolled in this course"
 // @Failure 500 {object} map[string]interface{} "Internal server error"
 // @Router /courses/{id}/chapters/{chapterId}/lessons [get]
 func (h *LessonHandler) GetAllLessons(c *gin.Context) {
@@ -81,7 +95,18 @@ func (h *LessonHandler) GetAllLessons(c *gin.Context) {
 		return
 	}
 
-	lessons, err := h.service.GetLessonsByChapterID(uint(courseID), uint(chapterId))
+	params, ok := parseListParams(c, schemas.LessonSortKeys, "order")
+	if !ok {
+		return
+	}
+
+	actor, ok := middleware.CurrentActor(c, h.authService)
+	if !ok {
+		return
+	}
+	params.Tenant = actor.TenantFilter()
+
+	lessons, meta, err := h.service.GetLessonsByChapterID(uint(courseID), uint(chapterId), params)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error":   true,
@@ -93,12 +118,14 @@ func (h *LessonHandler) GetAllLessons(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{
 		"error": false,
 		"data":  lessons,
+		"meta":  meta,
 	})
 }
 
 // GetLessonByID handles GET /api/v1/courses/:id/chapters/:chapterId/lessons/:lessonId
 // @Summary Get a lesson by ID
-// @Description Get a lesson by its ID
+// @Description Get a lesson by its ID. With format=html the response also contains the content rendered
+// @Description to sanitized HTML, a plain-text excerpt and a table of contents built from the headings.
 // @Tags lessons
 // @Accept json
 // @Produce json
@@ -106,8 +133,10 @@ func (h *LessonHandler) GetAllLessons(c *gin.Context) {
 // @Param id path int true "Course ID"
 // @Param chapterId path int true "Chapter ID"
 // @Param lessonId path int true "Lesson ID"
+// @Param format query string false "Response format: markdown or html" default(markdown)
 // @Success 200 {object} map[string]interface{} "Returns the lesson"
-// @Failure 400 {object} map[string]interface{} "Invalid lesson ID"
+// @Failure 400 {object} map[string]interface{} "Invalid lesson ID or format"
+// @Failure 403 {object} map[string]interface{} "Not enrolled in this course"
 // @Failure 404 {object} map[string]interface{} "Lesson not found"
 // @Failure 500 {object} map[string]interface{} "Internal server error"
 // @Router /courses/{id}/chapters/{chapterId}/lessons/{lessonId} [get]
@@ -141,11 +170,18 @@ func (h *LessonHandler) GetLessonByID(c *gin.Context) {
 		return
 	}
 
-	lesson, err := h.service.GetLessonByID(uint(courseId), uint(chapterId), uint(id))
+	actor, ok := middleware.CurrentActor(c, h.authService)
+	if !ok {
+		return
+	}
+
+	lesson, err := h.service.GetLessonByID(uint(courseId), uint(chapterId), uint(id), c.Query("format"), actor.TenantFilter())
 	if err != nil {
 		status := http.StatusInternalServerError
 		if err.Error() == "lesson not found" {
 			status = http.StatusNotFound
+		} else if err.Error() == "invalid format" {
+			status = http.StatusBadRequest
 		}
 		c.JSON(status, gin.H{
 			"error":   true,
@@ -162,7 +198,8 @@ func (h *LessonHandler) GetLessonByID(c *gin.Context) {
 
 // CreateLesson handles POST /api/v1/courses/:id/chapters/:chapterId/lessons
 // @Summary Create a new lesson
-// @Description Create a new lesson with the provided data
+// @Description Create a new lesson with the provided data. The content becomes the first markdown block of the lesson.
+// @Description Only admins, the course owner and co-instructors can add lessons.
 // @Tags lessons
 // @Accept json
 // @Produce json
@@ -172,6 +209,7 @@ func (h *LessonHandler) GetLessonByID(c *gin.Context) {
 // @Param lesson body schemas.LessonRequest true "Lesson data"
 // @Success 201 {object} map[string]interface{} "Lesson created successfully"
 // @Failure 400 {object} map[string]interface{} "Invalid request body or validation error"
+// @Failure 403 {object} map[string]interface{} "Insufficient permissions"
 // @Router /courses/{id}/chapters/{chapterId}/lessons [post]
 // @example request - example payload
 //
@@ -233,7 +271,12 @@ func (h *LessonHandler) CreateLesson(c *gin.Context) {
 	userID := user.ID
 	lessonRequest.CreatedBy = &userID
 
-	id, err := h.service.CreateLesson(lessonRequest, uint(courseId), uint(chapterId))
+	actor, ok := middleware.CurrentActor(c, h.authService)
+	if !ok {
+		return
+	}
+
+	id, err := h.service.CreateLesson(lessonRequest, uint(courseId), uint(chapterId), actor.TenantFilter())
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
 			"error":   true,
@@ -242,6 +285,7 @@ func (h *LessonHandler) CreateLesson(c *gin.Context) {
 		return
 	}
 
+	middleware.SetAuditResourceID(c, id)
 	c.JSON(http.StatusCreated, gin.H{
 		"error": false,
 		"data": gin.H{
@@ -253,7 +297,9 @@ func (h *LessonHandler) CreateLesson(c *gin.Context) {
 
 // UpdateLesson handles PUT /api/v1/courses/:id/chapters/:chapterId/lessons/:lessonId
 // @Summary Update a lesson
-// @Description Update a lesson with the provided data
+// @Description Update a lesson with the provided data. Every update is stored as a new lesson revision.
+// @Description Content replaces the text of the lesson's markdown block; lessons made of several blocks are edited through the blocks endpoints.
+// @Description Only admins, the course owner and co-instructors can update lessons.
 // @Tags lessons
 // @Accept json
 // @Produce json
@@ -264,6 +310,7 @@ func (h *LessonHandler) CreateLesson(c *gin.Context) {
 // @Param lesson body schemas.LessonRequest true "Lesson data"
 // @Success 200 {object} map[string]interface{} "Lesson updated successfully"
 // @Failure 400 {object} map[string]interface{} "Invalid request body or validation error"
+// @Failure 403 {object} map[string]interface{} "Insufficient permissions"
 // @Failure 404 {object} map[string]interface{} "Lesson not found"
 // @Router /courses/{id}/chapters/{chapterId}/lessons/{lessonId} [put]
 func (h *LessonHandler) UpdateLesson(c *gin.Context) {
@@ -305,7 +352,12 @@ func (h *LessonHandler) UpdateLesson(c *gin.Context) {
 		return
 	}
 
-	err = h.service.UpdateLesson(uint(courseId), uint(chapterId), uint(id), lessonRequest)
+	actor, ok := middleware.CurrentActor(c, h.authService)
+	if !ok {
+		return
+	}
+
+	err = h.service.UpdateLesson(uint(courseId), uint(chapterId), uint(id), lessonRequest, actor.UserID, actor.TenantFilter())
 	if err != nil {
 		status := http.StatusBadRequest
 		if err.Error() == "lesson not found or no changes made" {
@@ -327,6 +379,7 @@ func (h *LessonHandler) UpdateLesson(c *gin.Context) {
 // DeleteLesson handles DELETE /api/v1/courses/:id/chapters/:chapterId/lessons/:lessonId
 // @Summary Delete a lesson
 // @Description Delete a lesson by its ID
+// @Description Only admins, the course owner and co-instructors can delete lessons.
 // @Tags lessons
 // @Accept json
 // @Produce json
@@ -336,6 +389,7 @@ func (h *LessonHandler) UpdateLesson(c *gin.Context) {
 // @Param lessonId path int true "Lesson ID"
 // @Success 200 {object} map[string]interface{} "Lesson deleted successfully"
 // @Failure 400 {object} map[string]interface{} "Invalid lesson ID"
+// @Failure 403 {object} map[string]interface{} "Insufficient permissions"
 // @Failure 404 {object} map[string]interface{} "Lesson not found"
 // @Failure 500 {object} map[string]interface{} "Internal server error"
 // @Router /courses/{id}/chapters/{chapterId}/lessons/{lessonId} [delete]
@@ -369,7 +423,12 @@ func (h *LessonHandler) DeleteLesson(c *gin.Context) {
 		return
 	}
 
-	err = h.service.DeleteLesson(uint(courseId), uint(chapterId), uint(id))
+	actor, ok := middleware.CurrentActor(c, h.authService)
+	if !ok {
+		return
+	}
+
+	err = h.service.DeleteLesson(uint(courseId), uint(chapterId), uint(id), actor.TenantFilter())
 	if err != nil {
 		status := http.StatusInternalServerError
 		if err.Error() == "lesson not found" {
@@ -0,0 +1,279 @@
+package v1
+
+import (
+	"net/http"
+	"strconv"
+	"web/config"
+	"web/middleware"
+	"web/schemas"
+	"web/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// OrganizationHandler handles HTTP requests for the organizations hosted on the deployment
+type OrganizationHandler struct {
+	app           *config.AppConfig
+	service       *services.OrganizationService
+	authService   *services.AuthService
+	policyService *services.PolicyService
+}
+
+// NewOrganizationHandler creates a new organization handler
+func NewOrganizationHandler(app *config.AppConfig, service *services.OrganizationService, authService *services.AuthService, policyService *services.PolicyService) *OrganizationHandler {
+	return &OrganizationHandler{
+		app:           app,
+		service:       service,
+		authService:   authService,
+		policyService: policyService,
+	}
+}
+
+// RegisterRoutes registers organization api to the router
+func (h *OrganizationHandler) RegisterRoutes(router *gin.Engine) {
+	organizationGroup := router.Group("/api/v1/organizations")
+	organizationGroup.Use(middleware.AuthMiddleware(h.authService))
+	{
+		organizationGroup.GET("", middleware.Authorize(h.authService, h.policyService, services.ActionRead, services.ResourceOrganization), h.GetOrganizations)
+		organizationGroup.POST("", middleware.Authorize(h.authService, h.policyService, services.ActionCreate, services.ResourceOrganization), h.CreateOrganization)
+		organizationGroup.GET("/:orgId", middleware.Authorize(h.authService, h.policyService, services.ActionRead, services.ResourceOrganization), h.GetOrganization)
+		organizationGroup.PUT("/:orgId", middleware.Authorize(h.authService, h.policyService, services.ActionUpdate, services.ResourceOrganization), h.UpdateOrganization)
+		organizationGroup.DELETE("/:orgId", middleware.Authorize(h.authService, h.policyService, services.ActionDelete, services.ResourceOrganization), h.DeleteOrganization)
+		organizationGroup.PUT("/:orgId/users/:userId", middleware.Authorize(h.authService, h.policyService, services.ActionUpdate, services.ResourceOrganization), h.AssignUser)
+		organizationGroup.DELETE("/:orgId/users/:userId", middleware.Authorize(h.authService, h.policyService, services.ActionUpdate, services.ResourceOrganization), h.RemoveUser)
+	}
+}
+
+// organizationErrorStatus maps organization service errors to HTTP status codes
+func organizationErrorStatus(err error) int {
+	switch err.Error() {
+	case "organization not found", "user not found":
+		return http.StatusNotFound
+	case "organization name is required", "invalid organization slug", "user does not belong to this organization":
+		return http.StatusBadRequest
+	case "organization slug already exists", "organization still has users or courses":
+		return http.StatusConflict
+	}
+	return http.StatusInternalServerError
+}
+
+// GetOrganizations handles GET /api/v1/organizations
+// @Summary List organizations
+// @Description Get every organization hosted on the deployment (platform admins only)
+// @Tags organizations
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{} "Returns the organizations"
+// @Failure 403 {object} map[string]interface{} "Insufficient permissions"
+// @Router /organizations [get]
+func (h *OrganizationHandler) GetOrganizations(c *gin.Context) {
+	organizations, err := h.service.GetAll()
+	if err != nil {
+		middleware.RespondWithInternalServerError(c, err.Error())
+		return
+	}
+
+	middleware.RespondWithSuccess(c, organizations, "")
+}
+
+// GetOrganization handles GET /api/v1/organizations/:orgId
+// @Summary Get an organization
+// @Description Get an organization by ID (platform admins only)
+// @Tags organizations
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param orgId path int true "Organization ID"
+// @Success 200 {object} map[string]interface{} "Returns the organization"
+// @Failure 403 {object} map[string]interface{} "Insufficient permissions"
+// @Failure 404 {object} map[string]interface{} "Organization not found"
+// @Router /organizations/{orgId} [get]
+func (h *OrganizationHandler) GetOrganization(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("orgId"), 10, 32)
+	if err != nil {
+		middleware.RespondWithBadRequest(c, "Invalid organization ID")
+		return
+	}
+
+	organization, err := h.service.GetByID(uint(id))
+	if err != nil {
+		middleware.RespondWithError(c, organizationErrorStatus(err), err.Error())
+		return
+	}
+
+	middleware.RespondWithSuccess(c, organization, "")
+}
+
+// CreateOrganization handles POST /api/v1/organizations
+// @Summary Create an organization
+// @Description Create an organization (platform admins only). Users are assigned to it by the organization claim of their token, a /organizations/<slug> group, or by a platform admin.
+// @Tags organizations
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param organization body schemas.CreateOrganizationRequest true "Organization"
+// @Success 201 {object} map[string]interface{} "Organization created successfully"
+// @Failure 400 {object} map[string]interface{} "Invalid request body, name or slug"
+// @Failure 403 {object} map[string]interface{} "Insufficient permissions"
+// @Failure 409 {object} map[string]interface{} "Slug already exists"
+// @Router /organizations [post]
+// @example request - example payload
+//
+//	{
+//	  "name": "Springfield High School",
+//	  "slug": "springfield-high"
+//	}
+func (h *OrganizationHandler) CreateOrganization(c *gin.Context) {
+	var createRequest schemas.CreateOrganizationRequest
+	if err := c.ShouldBindJSON(&createRequest); err != nil {
+		middleware.RespondWithBadRequest(c, "Invalid request body")
+		return
+	}
+
+	organization, err := h.service.Create(createRequest)
+	if err != nil {
+		middleware.RespondWithError(c, organizationErrorStatus(err), err.Error())
+		return
+	}
+
+	middleware.RespondWithCreated(c, organization, "Organization created successfully")
+}
+
+// UpdateOrganization handles PUT /api/v1/organizations/:orgId
+// @Summary Update an organization
+// @Description Rename an organization or change its slug (platform admins only)
+// @Tags organizations
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param orgId path int true "Organization ID"
+// @Param organization body schemas.UpdateOrganizationRequest true "Organization"
+// @Success 200 {object} map[string]interface{} "Organization updated successfully"
+// @Failure 400 {object} map[string]interface{} "Invalid request body, name or slug"
+// @Failure 403 {object} map[string]interface{} "Insufficient permissions"
+// @Failure 404 {object} map[string]interface{} "Organization not found"
+// @Failure 409 {object} map[string]interface{} "Slug already exists"
+// @Router /organizations/{orgId} [put]
+func (h *OrganizationHandler) UpdateOrganization(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("orgId"), 10, 32)
+	if err != nil {
+		middleware.RespondWithBadRequest(c, "Invalid organization ID")
+		return
+	}
+
+	var updateRequest schemas.UpdateOrganizationRequest
+	if err := c.ShouldBindJSON(&updateRequest); err != nil {
+		middleware.RespondWithBadRequest(c, "Invalid request body")
+		return
+	}
+
+	organization, err := h.service.Update(uint(id), updateRequest)
+	if err != nil {
+		middleware.RespondWithError(c, organizationErrorStatus(err), err.Error())
+		return
+	}
+
+	middleware.RespondWithSuccess(c, organization, "Organization updated successfully")
+}
+
+// DeleteOrganization handles DELETE /api/v1/organizations/:orgId
+// @Summary Delete an organization
+// @Description Delete an organization that no user and no course belongs to anymore (platform admins only)
+// @Tags organizations
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param orgId path int true "Organization ID"
+// @Success 200 {object} map[string]interface{} "Organization deleted successfully"
+// @Failure 403 {object} map[string]interface{} "Insufficient permissions"
+// @Failure 404 {object} map[string]interface{} "Organization not found"
+// @Failure 409 {object} map[string]interface{} "Organization still has users or courses"
+// @Router /organizations/{orgId} [delete]
+func (h *OrganizationHandler) DeleteOrganization(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("orgId"), 10, 32)
+	if err != nil {
+		middleware.RespondWithBadRequest(c, "Invalid organization ID")
+		return
+	}
+
+	if err := h.service.Delete(uint(id)); err != nil {
+		middleware.RespondWithError(c, organizationErrorStatus(err), err.Error())
+		return
+	}
+
+	middleware.RespondWithSuccess(c, nil, "Organization deleted successfully")
+}
+
+// AssignUser handles PUT /api/v1/organizations/:orgId/users/:userId
+// @Summary Assign a user to an organization
+// @Description Move a user to an organization (platform admins only). Users whose token carries an organization are moved back to it when they log in.
+// @Tags organizations
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param orgId path int true "Organization ID"
+// @Param userId path int true "User ID"
+// @Success 200 {object} map[string]interface{} "User assigned successfully"
+// @Failure 403 {object} map[string]interface{} "Insufficient permissions"
+// @Failure 404 {object} map[string]interface{} "Organization or user not found"
+// @Router /organizations/{orgId}/users/{userId} [put]
+func (h *OrganizationHandler) AssignUser(c *gin.Context) {
+	organizationID, userID, ok := parseOrganizationUserParams(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.AssignUser(organizationID, userID); err != nil {
+		middleware.RespondWithError(c, organizationErrorStatus(err), err.Error())
+		return
+	}
+
+	middleware.RespondWithSuccess(c, nil, "User assigned successfully")
+}
+
+// RemoveUser handles DELETE /api/v1/organizations/:orgId/users/:userId
+// @Summary Remove a user from an organization
+// @Description Move a user of an organization back to the default tenant (platform admins only). An admin removed from an organization administers the default tenant, only the platform_admin role reaches every organization.
+// @Tags organizations
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param orgId path int true "Organization ID"
+// @Param userId path int true "User ID"
+// @Success 200 {object} map[string]interface{} "User removed successfully"
+// @Failure 400 {object} map[string]interface{} "User does not belong to the organization"
+// @Failure 403 {object} map[string]interface{} "Insufficient permissions"
+// @Failure 404 {object} map[string]interface{} "User not found"
+// @Router /organizations/{orgId}/users/{userId} [delete]
+func (h *OrganizationHandler) RemoveUser(c *gin.Context) {
+	organizationID, userID, ok := parseOrganizationUserParams(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.RemoveUser(organizationID, userID); err != nil {
+		middleware.RespondWithError(c, organizationErrorStatus(err), err.Error())
+		return
+	}
+
+	middleware.RespondWithSuccess(c, nil, "User removed successfully")
+}
+
+// parseOrganizationUserParams parses the organization and user IDs of the path and writes
+// the error response when one of them is invalid
+func parseOrganizationUserParams(c *gin.Context) (uint, uint, bool) {
+	organizationID, err := strconv.ParseUint(c.Param("orgId"), 10, 32)
+	if err != nil {
+		middleware.RespondWithBadRequest(c, "Invalid organization ID")
+		return 0, 0, false
+	}
+
+	userID, err := strconv.ParseUint(c.Param("userId"), 10, 32)
+	if err != nil {
+		middleware.RespondWithBadRequest(c, "Invalid user ID")
+		return 0, 0, false
+	}
+
+	return uint(organizationID), uint(userID), true
+}
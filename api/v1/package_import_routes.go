@@ -0,0 +1,138 @@
+package v1
+
+import (
+	"net/http"
+	"strconv"
+	"web/config"
+	"web/middleware"
+	"web/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PackageImportHandler handles HTTP requests for SCORM and Common Cartridge imports
+type PackageImportHandler struct {
+	app           *config.AppConfig
+	service       *services.PackageImportService
+	authService   *services.AuthService
+	policyService *services.PolicyService
+}
+
+// NewPackageImportHandler creates a new package import handler
+func NewPackageImportHandler(app *config.AppConfig, service *services.PackageImportService, authService *services.AuthService, policyService *services.PolicyService) *PackageImportHandler {
+	return &PackageImportHandler{
+		app:           app,
+		service:       service,
+		authService:   authService,
+		policyService: policyService,
+	}
+}
+
+// RegisterRoutes registers package import api to the router
+func (h *PackageImportHandler) RegisterRoutes(router *gin.Engine) {
+	importGroup := router.Group("/api/v1/imports/packages")
+	importGroup.Use(middleware.AuthMiddleware(h.authService))
+	importGroup.Use(middleware.Authorize(h.authService, h.policyService, services.ActionImport, services.ResourceCourse))
+	{
+		importGroup.POST("", h.StartImport)
+		importGroup.GET("/:jobId", h.GetImportJob)
+	}
+}
+
+// StartImport handles POST /api/v1/imports/packages
+// @Summary Import a SCORM or Common Cartridge package
+// @Description Upload a SCORM 1.2 or IMS Common Cartridge zip. The package is imported as a new draft course in the background; poll the returned job for the result and the list of skipped items. The created course is recorded in the audit log when the job completes.
+// @Tags imports
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param package formData file true "SCORM 1.2 or IMS Common Cartridge package"
+// @Success 202 {object} map[string]interface{} "Returns the pending import job"
+// @Failure 400 {object} map[string]interface{} "No package uploaded or not a zip file"
+// @Failure 403 {object} map[string]interface{} "Insufficient permissions"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /imports/packages [post]
+func (h *PackageImportHandler) StartImport(c *gin.Context) {
+	file, err := c.FormFile("package")
+	if err != nil {
+		middleware.RespondWithBadRequest(c, "No package uploaded")
+		return
+	}
+	if file.Size > maxCourseArchiveUploadSize {
+		middleware.RespondWithBadRequest(c, "Package is too large")
+		return
+	}
+
+	actor, ok := middleware.CurrentActor(c, h.authService)
+	if !ok {
+		return
+	}
+
+	upload, err := file.Open()
+	if err != nil {
+		middleware.RespondWithInternalServerError(c, "Failed to open package")
+		return
+	}
+	defer upload.Close()
+
+	// The course is created in the background, the job records it in the audit log
+	auditEntry := middleware.NewAuditEntry(c, services.ActionImport, services.ResourceCourse)
+	job, err := h.service.StartImport(upload, file.Filename, actor, auditEntry)
+	if err != nil {
+		switch err.Error() {
+		case "insufficient permissions to import packages":
+			middleware.RespondWithError(c, http.StatusForbidden, err.Error())
+		case "package must be a zip file":
+			middleware.RespondWithBadRequest(c, err.Error())
+		default:
+			middleware.RespondWithInternalServerError(c, err.Error())
+		}
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"error":   false,
+		"data":    job,
+		"message": "Package import started",
+	})
+}
+
+// GetImportJob handles GET /api/v1/imports/packages/:jobId
+// @Summary Get a package import job
+// @Description Get the status of a package import. Completed jobs link the created course, warnings list the items that were skipped or only partly imported.
+// @Tags imports
+// @Produce json
+// @Security BearerAuth
+// @Param jobId path int true "Import job ID"
+// @Success 200 {object} map[string]interface{} "Returns the import job"
+// @Failure 400 {object} map[string]interface{} "Invalid import job ID"
+// @Failure 403 {object} map[string]interface{} "Insufficient permissions"
+// @Failure 404 {object} map[string]interface{} "Import job not found"
+// @Router /imports/packages/{jobId} [get]
+func (h *PackageImportHandler) GetImportJob(c *gin.Context) {
+	jobID, err := strconv.ParseUint(c.Param("jobId"), 10, 32)
+	if err != nil {
+		middleware.RespondWithBadRequest(c, "Invalid import job ID")
+		return
+	}
+
+	actor, ok := middleware.CurrentActor(c, h.authService)
+	if !ok {
+		return
+	}
+
+	job, err := h.service.GetJob(uint(jobID), actor)
+	if err != nil {
+		switch err.Error() {
+		case "import job not found":
+			middleware.RespondWithNotFound(c, err.Error())
+		case "insufficient permissions to view this import job":
+			middleware.RespondWithError(c, http.StatusForbidden, err.Error())
+		default:
+			middleware.RespondWithInternalServerError(c, err.Error())
+		}
+		return
+	}
+
+	middleware.RespondWithSuccess(c, job, "")
+}
@@ -0,0 +1,237 @@
+package v1
+
+import (
+	"net/http"
+	"web/config"
+	"web/middleware"
+	"web/schemas"
+	"web/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ProgressHandler handles HTTP requests for lesson progress
+type ProgressHandler struct {
+	app               *config.AppConfig
+	service           *services.ProgressService
+	enrollmentService *services.EnrollmentService
+	authService       *services.AuthService
+}
+
+// NewProgressHandler creates a new progress handler
+func NewProgressHandler(app *config.AppConfig, service *services.ProgressService, enrollmentService *services.EnrollmentService, authService *services.AuthService) *ProgressHandler {
+	return &ProgressHandler{
+		app:               app,
+		service:           service,
+		enrollmentService: enrollmentService,
+		authService:       authService,
+	}
+}
+
+// RegisterRoutes registers progress api to the router
+func (h *ProgressHandler) RegisterRoutes(router *gin.Engine) {
+	progressGroup := router.Group("/api/v1/courses/:id/chapters/:chapterId/lessons/:lessonId/progress")
+	progressGroup.Use(middleware.AuthMiddleware(h.authService))
+	progressGroup.Use(middleware.RequireCourseAccess(h.authService, h.enrollmentService))
+	{
+		progressGroup.GET("", h.GetLessonProgress)
+		progressGroup.PUT("", h.UpdateLessonProgress)
+		progressGroup.POST("/complete", h.CompleteLesson)
+		progressGroup.DELETE("", h.ResetLessonProgress)
+	}
+}
+
+// parseLessonPath parses the lesson route and the current actor from the request
+func (h *ProgressHandler) parseLessonPath(c *gin.Context) (actor services.Actor, courseID, chapterID, lessonID uint, ok bool) {
+	courseID, chapterID, lessonID, ok = parseLessonPath(c)
+	if !ok {
+		return services.Actor{}, 0, 0, 0, false
+	}
+
+	actor, ok = middleware.CurrentActor(c, h.authService)
+	if !ok {
+		return services.Actor{}, 0, 0, 0, false
+	}
+
+	return actor, courseID, chapterID, lessonID, true
+}
+
+// GetLessonProgress handles GET /api/v1/courses/:id/chapters/:chapterId/lessons/:lessonId/progress
+// @Summary Get lesson progress
+// @Description Get the current user's progress for a lesson
+// @Tags progress
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Course ID"
+// @Param chapterId path int true "Chapter ID"
+// @Param lessonId path int true "Lesson ID"
+// @Success 200 {object} map[string]interface{} "Returns the lesson progress"
+// @Failure 400 {object} map[string]interface{} "Invalid ID"
+// @Failure 403 {object} map[string]interface{} "Not enrolled in this course"
+// @Failure 404 {object} map[string]interface{} "Lesson not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /courses/{id}/chapters/{chapterId}/lessons/{lessonId}/progress [get]
+func (h *ProgressHandler) GetLessonProgress(c *gin.Context) {
+	actor, courseID, chapterID, lessonID, ok := h.parseLessonPath(c)
+	if !ok {
+		return
+	}
+
+	progress, err := h.service.GetLessonProgress(actor.UserID, courseID, chapterID, lessonID, actor.TenantFilter())
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err.Error() == "lesson not found" {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, gin.H{
+			"error":   true,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"error": false,
+		"data":  progress,
+	})
+}
+
+// UpdateLessonProgress handles PUT /api/v1/courses/:id/chapters/:chapterId/lessons/:lessonId/progress
+// @Summary Update lesson progress
+// @Description Save the current user's status, percent and last position for a lesson. Without a status a completed lesson stays completed.
+// @Tags progress
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Course ID"
+// @Param chapterId path int true "Chapter ID"
+// @Param lessonId path int true "Lesson ID"
+// @Param progress body schemas.UpdateLessonProgressRequest true "Progress data"
+// @Success 200 {object} map[string]interface{} "Progress updated successfully"
+// @Failure 400 {object} map[string]interface{} "Invalid request body or validation error"
+// @Failure 403 {object} map[string]interface{} "Not enrolled in this course"
+// @Failure 404 {object} map[string]interface{} "Lesson not found"
+// @Router /courses/{id}/chapters/{chapterId}/lessons/{lessonId}/progress [put]
+// @example request - example payload
+//
+//	{
+//	  "status": "in_progress",
+//	  "percent": 40,
+//	  "last_position": 120
+//	}
+func (h *ProgressHandler) UpdateLessonProgress(c *gin.Context) {
+	actor, courseID, chapterID, lessonID, ok := h.parseLessonPath(c)
+	if !ok {
+		return
+	}
+
+	var progressRequest schemas.UpdateLessonProgressRequest
+	if err := c.ShouldBindJSON(&progressRequest); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   true,
+			"message": "Invalid request body",
+		})
+		return
+	}
+
+	progress, err := h.service.UpdateLessonProgress(actor.UserID, courseID, chapterID, lessonID, progressRequest, actor.TenantFilter())
+	if err != nil {
+		status := http.StatusBadRequest
+		if err.Error() == "lesson not found" {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, gin.H{
+			"error":   true,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"error":   false,
+		"data":    progress,
+		"message": "Progress updated successfully",
+	})
+}
+
+// CompleteLesson handles POST /api/v1/courses/:id/chapters/:chapterId/lessons/:lessonId/progress/complete
+// @Summary Mark a lesson as complete
+// @Description Mark a lesson as completed by the current user
+// @Tags progress
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Course ID"
+// @Param chapterId path int true "Chapter ID"
+// @Param lessonId path int true "Lesson ID"
+// @Success 200 {object} map[string]interface{} "Lesson marked as complete"
+// @Failure 400 {object} map[string]interface{} "Invalid ID"
+// @Failure 403 {object} map[string]interface{} "Not enrolled in this course"
+// @Failure 404 {object} map[string]interface{} "Lesson not found"
+// @Router /courses/{id}/chapters/{chapterId}/lessons/{lessonId}/progress/complete [post]
+func (h *ProgressHandler) CompleteLesson(c *gin.Context) {
+	actor, courseID, chapterID, lessonID, ok := h.parseLessonPath(c)
+	if !ok {
+		return
+	}
+
+	progress, err := h.service.CompleteLesson(actor.UserID, courseID, chapterID, lessonID, actor.TenantFilter())
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err.Error() == "lesson not found" {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, gin.H{
+			"error":   true,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"error":   false,
+		"data":    progress,
+		"message": "Lesson marked as complete",
+	})
+}
+
+// ResetLessonProgress handles DELETE /api/v1/courses/:id/chapters/:chapterId/lessons/:lessonId/progress
+// @Summary Reset lesson progress
+// @Description Remove the current user's progress for a lesson
+// @Tags progress
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Course ID"
+// @Param chapterId path int true "Chapter ID"
+// @Param lessonId path int true "Lesson ID"
+// @Success 200 {object} map[string]interface{} "Progress reset successfully"
+// @Failure 400 {object} map[string]interface{} "Invalid ID"
+// @Failure 403 {object} map[string]interface{} "Not enrolled in this course"
+// @Failure 404 {object} map[string]interface{} "Lesson or progress not found"
+// @Router /courses/{id}/chapters/{chapterId}/lessons/{lessonId}/progress [delete]
+func (h *ProgressHandler) ResetLessonProgress(c *gin.Context) {
+	actor, courseID, chapterID, lessonID, ok := h.parseLessonPath(c)
+	if !ok {
+		return
+	}
+
+	err := h.service.ResetLessonProgress(actor.UserID, courseID, chapterID, lessonID, actor.TenantFilter())
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err.Error() == "lesson not found" || err.Error() == "progress not found" {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, gin.H{
+			"error":   true,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"error":   false,
+		"message": "Progress reset successfully",
+	})
+}
@@ -0,0 +1,522 @@
+package v1
+
+import (
+	"net/http"
+	"strconv"
+	"web/config"
+	"web/middleware"
+	"web/schemas"
+	"web/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// QuizHandler handles HTTP requests for lesson quizzes
+type QuizHandler struct {
+	app               *config.AppConfig
+	service           *services.QuizService
+	enrollmentService *services.EnrollmentService
+	authService       *services.AuthService
+	policyService     *services.PolicyService
+}
+
+// NewQuizHandler creates a new quiz handler
+func NewQuizHandler(app *config.AppConfig, service *services.QuizService, enrollmentService *services.EnrollmentService, authService *services.AuthService, policyService *services.PolicyService) *QuizHandler {
+	return &QuizHandler{
+		app:               app,
+		service:           service,
+		enrollmentService: enrollmentService,
+		authService:       authService,
+		policyService:     policyService,
+	}
+}
+
+// RegisterRoutes registers quiz api to the router
+func (h *QuizHandler) RegisterRoutes(router *gin.Engine) {
+	quizGroup := router.Group("/api/v1/courses/:id/chapters/:chapterId/lessons/:lessonId/quizzes")
+	quizGroup.Use(middleware.AuthMiddleware(h.authService))
+	quizGroup.Use(middleware.RequireCourseAccess(h.authService, h.enrollmentService))
+	{
+		quizGroup.GET("", h.GetQuizzes)
+		quizGroup.GET("/:quizId", h.GetQuizByID)
+
+		// Authoring endpoints - only admin, the course owner and co-instructors can manage quizzes
+		quizGroup.POST("", middleware.AuthorizeCourse(h.authService, h.policyService, services.ActionCreate, services.ResourceQuiz), h.CreateQuiz)
+		quizGroup.PUT("/:quizId", middleware.AuthorizeCourse(h.authService, h.policyService, services.ActionUpdate, services.ResourceQuiz), h.UpdateQuiz)
+		quizGroup.DELETE("/:quizId", middleware.AuthorizeCourse(h.authService, h.policyService, services.ActionDelete, services.ResourceQuiz), h.DeleteQuiz)
+
+		// Attempt endpoints - any user with access to the course
+		quizGroup.POST("/:quizId/attempts", h.SubmitAttempt)
+		quizGroup.GET("/:quizId/attempts", h.GetAttempts)
+
+		// Review endpoint - the course staff grades free text answers
+		quizGroup.POST("/:quizId/attempts/:attemptId/review", middleware.AuthorizeCourse(h.authService, h.policyService, services.ActionReview, services.ResourceQuiz), h.ReviewAttempt)
+	}
+}
+
+// parseQuizID parses the :quizId route parameter and writes the error response when it is invalid
+func parseQuizID(c *gin.Context) (uint, bool) {
+	quizId, err := strconv.ParseUint(c.Param("quizId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   true,
+			"message": "Invalid quiz ID",
+		})
+		return 0, false
+	}
+
+	return uint(quizId), true
+}
+
+// quizErrorStatus maps quiz service errors to HTTP status codes
+func quizErrorStatus(err error, defaultStatus int) int {
+	switch err.Error() {
+	case "lesson not found", "quiz not found", "quiz not found or no changes made", "quiz attempt not found":
+		return http.StatusNotFound
+	}
+	return defaultStatus
+}
+
+// GetQuizzes handles GET /api/v1/courses/:id/chapters/:chapterId/lessons/:lessonId/quizzes
+// @Summary Get all quizzes for a lesson
+// @Description Get a list of all quizzes attached to a lesson
+// @Tags quizzes
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Course ID"
+// @Param chapterId path int true "Chapter ID"
+// @Param lessonId path int true "Lesson ID"
+// @Success 200 {object} map[string]interface{} "Returns a list of quizzes"
+// @Failure 400 {object} map[string]interface{} "Invalid ID"
+// @Failure 403 {object} map[string]interface{} "Not enrolled in this course"
+// @Failure 404 {object} map[string]interface{} "Lesson not found"
+// @Router /courses/{id}/chapters/{chapterId}/lessons/{lessonId}/quizzes [get]
+func (h *QuizHandler) GetQuizzes(c *gin.Context) {
+	courseID, chapterID, lessonID, ok := parseLessonPath(c)
+	if !ok {
+		return
+	}
+
+	actor, ok := middleware.CurrentActor(c, h.authService)
+	if !ok {
+		return
+	}
+
+	quizzes, err := h.service.GetQuizzesByLessonID(courseID, chapterID, lessonID, actor.TenantFilter())
+	if err != nil {
+		c.JSON(quizErrorStatus(err, http.StatusInternalServerError), gin.H{
+			"error":   true,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"error": false,
+		"data":  quizzes,
+	})
+}
+
+// GetQuizByID handles GET /api/v1/courses/:id/chapters/:chapterId/lessons/:lessonId/quizzes/:quizId
+// @Summary Get a quiz by ID
+// @Description Get a quiz with its questions. Correct answers are only returned to admins, teachers, the course owner, co-instructors and teaching assistants.
+// @Tags quizzes
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Course ID"
+// @Param chapterId path int true "Chapter ID"
+// @Param lessonId path int true "Lesson ID"
+// @Param quizId path int true "Quiz ID"
+// @Success 200 {object} map[string]interface{} "Returns the quiz"
+// @Failure 400 {object} map[string]interface{} "Invalid ID"
+// @Failure 403 {object} map[string]interface{} "Not enrolled in this course"
+// @Failure 404 {object} map[string]interface{} "Quiz not found"
+// @Router /courses/{id}/chapters/{chapterId}/lessons/{lessonId}/quizzes/{quizId} [get]
+func (h *QuizHandler) GetQuizByID(c *gin.Context) {
+	courseID, chapterID, lessonID, ok := parseLessonPath(c)
+	if !ok {
+		return
+	}
+	quizID, ok := parseQuizID(c)
+	if !ok {
+		return
+	}
+
+	actor, ok := middleware.CurrentActor(c, h.authService)
+	if !ok {
+		return
+	}
+	includeAnswers := h.policyService.AuthorizeCourse(actor, services.ActionReadAnswers, services.ResourceQuiz, courseID) == nil
+
+	quiz, err := h.service.GetQuizByID(courseID, chapterID, lessonID, quizID, includeAnswers, actor.TenantFilter())
+	if err != nil {
+		c.JSON(quizErrorStatus(err, http.StatusInternalServerError), gin.H{
+			"error":   true,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"error": false,
+		"data":  quiz,
+	})
+}
+
+// CreateQuiz handles POST /api/v1/courses/:id/chapters/:chapterId/lessons/:lessonId/quizzes
+// @Summary Create a quiz
+// @Description Create a quiz with its questions (admins, the course owner and co-instructors only)
+// @Tags quizzes
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Course ID"
+// @Param chapterId path int true "Chapter ID"
+// @Param lessonId path int true "Lesson ID"
+// @Param quiz body schemas.QuizRequest true "Quiz data"
+// @Success 201 {object} map[string]interface{} "Quiz created successfully"
+// @Failure 400 {object} map[string]interface{} "Invalid request body or validation error"
+// @Failure 403 {object} map[string]interface{} "Forbidden"
+// @Failure 404 {object} map[string]interface{} "Lesson not found"
+// @Router /courses/{id}/chapters/{chapterId}/lessons/{lessonId}/quizzes [post]
+// @example request - example payload
+//
+//	{
+//	  "title": "Chapter 1 Quiz",
+//	  "passing_score": 70,
+//	  "questions": [
+//	    {
+//	      "type": "single_choice",
+//	      "text": "Which keyword declares a variable?",
+//	      "options": [{"text": "var", "is_correct": true}, {"text": "let"}]
+//	    },
+//	    {"type": "numeric", "text": "6 * 7 = ?", "correct_number": 42}
+//	  ]
+//	}
+func (h *QuizHandler) CreateQuiz(c *gin.Context) {
+	courseID, chapterID, lessonID, ok := parseLessonPath(c)
+	if !ok {
+		return
+	}
+
+	var quizRequest schemas.QuizRequest
+	if err := c.ShouldBindJSON(&quizRequest); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   true,
+			"message": "Invalid request body",
+		})
+		return
+	}
+
+	actor, ok := middleware.CurrentActor(c, h.authService)
+	if !ok {
+		return
+	}
+	userID := actor.UserID
+	quizRequest.CreatedBy = &userID
+
+	id, err := h.service.CreateQuiz(courseID, chapterID, lessonID, quizRequest, actor.TenantFilter())
+	if err != nil {
+		c.JSON(quizErrorStatus(err, http.StatusBadRequest), gin.H{
+			"error":   true,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"error": false,
+		"data": gin.H{
+			"id": id,
+		},
+		"message": "Quiz created successfully",
+	})
+}
+
+// UpdateQuiz handles PUT /api/v1/courses/:id/chapters/:chapterId/lessons/:lessonId/quizzes/:quizId
+// @Summary Update a quiz
+// @Description Update a quiz and replace its questions (admins, the course owner and co-instructors only)
+// @Tags quizzes
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Course ID"
+// @Param chapterId path int true "Chapter ID"
+// @Param lessonId path int true "Lesson ID"
+// @Param quizId path int true "Quiz ID"
+// @Param quiz body schemas.QuizRequest true "Quiz data"
+// @Success 200 {object} map[string]interface{} "Quiz updated successfully"
+// @Failure 400 {object} map[string]interface{} "Invalid request body or validation error"
+// @Failure 403 {object} map[string]interface{} "Forbidden"
+// @Failure 404 {object} map[string]interface{} "Quiz not found"
+// @Router /courses/{id}/chapters/{chapterId}/lessons/{lessonId}/quizzes/{quizId} [put]
+func (h *QuizHandler) UpdateQuiz(c *gin.Context) {
+	courseID, chapterID, lessonID, ok := parseLessonPath(c)
+	if !ok {
+		return
+	}
+	quizID, ok := parseQuizID(c)
+	if !ok {
+		return
+	}
+
+	var quizRequest schemas.QuizRequest
+	if err := c.ShouldBindJSON(&quizRequest); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   true,
+			"message": "Invalid request body",
+		})
+		return
+	}
+
+	actor, ok := middleware.CurrentActor(c, h.authService)
+	if !ok {
+		return
+	}
+
+	err := h.service.UpdateQuiz(courseID, chapterID, lessonID, quizID, quizRequest, actor.TenantFilter())
+	if err != nil {
+		c.JSON(quizErrorStatus(err, http.StatusBadRequest), gin.H{
+			"error":   true,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"error":   false,
+		"message": "Quiz updated successfully",
+	})
+}
+
+// DeleteQuiz handles DELETE /api/v1/courses/:id/chapters/:chapterId/lessons/:lessonId/quizzes/:quizId
+// @Summary Delete a quiz
+// @Description Delete a quiz by its ID (admins, the course owner and co-instructors only)
+// @Tags quizzes
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Course ID"
+// @Param chapterId path int true "Chapter ID"
+// @Param lessonId path int true "Lesson ID"
+// @Param quizId path int true "Quiz ID"
+// @Success 200 {object} map[string]interface{} "Quiz deleted successfully"
+// @Failure 400 {object} map[string]interface{} "Invalid ID"
+// @Failure 403 {object} map[string]interface{} "Forbidden"
+// @Failure 404 {object} map[string]interface{} "Quiz not found"
+// @Router /courses/{id}/chapters/{chapterId}/lessons/{lessonId}/quizzes/{quizId} [delete]
+func (h *QuizHandler) DeleteQuiz(c *gin.Context) {
+	courseID, chapterID, lessonID, ok := parseLessonPath(c)
+	if !ok {
+		return
+	}
+	quizID, ok := parseQuizID(c)
+	if !ok {
+		return
+	}
+
+	actor, ok := middleware.CurrentActor(c, h.authService)
+	if !ok {
+		return
+	}
+
+	err := h.service.DeleteQuiz(courseID, chapterID, lessonID, quizID, actor.TenantFilter())
+	if err != nil {
+		c.JSON(quizErrorStatus(err, http.StatusInternalServerError), gin.H{
+			"error":   true,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"error":   false,
+		"message": "Quiz deleted successfully",
+	})
+}
+
+// SubmitAttempt handles POST /api/v1/courses/:id/chapters/:chapterId/lessons/:lessonId/quizzes/:quizId/attempts
+// @Summary Submit a quiz attempt
+// @Description Submit answers for a quiz. Objective questions are graded immediately, an attempt with free text answers is pending review and not passed until the course staff reviews it.
+// @Tags quizzes
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Course ID"
+// @Param chapterId path int true "Chapter ID"
+// @Param lessonId path int true "Lesson ID"
+// @Param quizId path int true "Quiz ID"
+// @Param attempt body schemas.SubmitQuizAttemptRequest true "Answers"
+// @Success 201 {object} map[string]interface{} "Attempt graded"
+// @Failure 400 {object} map[string]interface{} "Invalid request body or validation error"
+// @Failure 403 {object} map[string]interface{} "Not enrolled in this course"
+// @Failure 404 {object} map[string]interface{} "Quiz not found"
+// @Router /courses/{id}/chapters/{chapterId}/lessons/{lessonId}/quizzes/{quizId}/attempts [post]
+// @example request - example payload
+//
+//	{
+//	  "answers": [
+//	    {"question_id": 1, "option_ids": [2]},
+//	    {"question_id": 2, "number": 42}
+//	  ]
+//	}
+func (h *QuizHandler) SubmitAttempt(c *gin.Context) {
+	courseID, chapterID, lessonID, ok := parseLessonPath(c)
+	if !ok {
+		return
+	}
+	quizID, ok := parseQuizID(c)
+	if !ok {
+		return
+	}
+
+	var attemptRequest schemas.SubmitQuizAttemptRequest
+	if err := c.ShouldBindJSON(&attemptRequest); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   true,
+			"message": "Invalid request body",
+		})
+		return
+	}
+
+	actor, ok := middleware.CurrentActor(c, h.authService)
+	if !ok {
+		return
+	}
+
+	attempt, err := h.service.SubmitAttempt(actor.UserID, courseID, chapterID, lessonID, quizID, attemptRequest, actor.TenantFilter())
+	if err != nil {
+		c.JSON(quizErrorStatus(err, http.StatusBadRequest), gin.H{
+			"error":   true,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"error":   false,
+		"data":    attempt,
+		"message": "Attempt submitted successfully",
+	})
+}
+
+// GetAttempts handles GET /api/v1/courses/:id/chapters/:chapterId/lessons/:lessonId/quizzes/:quizId/attempts
+// @Summary Get my quiz attempts
+// @Description Get the current user's attempts for a quiz
+// @Tags quizzes
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Course ID"
+// @Param chapterId path int true "Chapter ID"
+// @Param lessonId path int true "Lesson ID"
+// @Param quizId path int true "Quiz ID"
+// @Success 200 {object} map[string]interface{} "Returns a list of attempts"
+// @Failure 400 {object} map[string]interface{} "Invalid ID"
+// @Failure 403 {object} map[string]interface{} "Not enrolled in this course"
+// @Failure 404 {object} map[string]interface{} "Quiz not found"
+// @Router /courses/{id}/chapters/{chapterId}/lessons/{lessonId}/quizzes/{quizId}/attempts [get]
+func (h *QuizHandler) GetAttempts(c *gin.Context) {
+	courseID, chapterID, lessonID, ok := parseLessonPath(c)
+	if !ok {
+		return
+	}
+	quizID, ok := parseQuizID(c)
+	if !ok {
+		return
+	}
+
+	actor, ok := middleware.CurrentActor(c, h.authService)
+	if !ok {
+		return
+	}
+
+	attempts, err := h.service.GetAttempts(actor.UserID, courseID, chapterID, lessonID, quizID, actor.TenantFilter())
+	if err != nil {
+		c.JSON(quizErrorStatus(err, http.StatusInternalServerError), gin.H{
+			"error":   true,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"error": false,
+		"data":  attempts,
+	})
+}
+
+// ReviewAttempt handles POST /api/v1/courses/:id/chapters/:chapterId/lessons/:lessonId/quizzes/:quizId/attempts/:attemptId/review
+// @Summary Review a quiz attempt
+// @Description Grade the free text answers of an attempt (admins, the course owner, co-instructors and teaching assistants only). The attempt is passed or failed once every free text answer is reviewed.
+// @Tags quizzes
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Course ID"
+// @Param chapterId path int true "Chapter ID"
+// @Param lessonId path int true "Lesson ID"
+// @Param quizId path int true "Quiz ID"
+// @Param attemptId path int true "Attempt ID"
+// @Param review body schemas.ReviewQuizAttemptRequest true "Points awarded to free text answers"
+// @Success 200 {object} map[string]interface{} "Attempt reviewed"
+// @Failure 400 {object} map[string]interface{} "Invalid request body or validation error"
+// @Failure 403 {object} map[string]interface{} "Forbidden"
+// @Failure 404 {object} map[string]interface{} "Quiz or attempt not found"
+// @Router /courses/{id}/chapters/{chapterId}/lessons/{lessonId}/quizzes/{quizId}/attempts/{attemptId}/review [post]
+// @example request - example payload
+//
+//	{
+//	  "answers": [
+//	    {"question_id": 4, "points_awarded": 2}
+//	  ]
+//	}
+func (h *QuizHandler) ReviewAttempt(c *gin.Context) {
+	courseID, chapterID, lessonID, ok := parseLessonPath(c)
+	if !ok {
+		return
+	}
+	quizID, ok := parseQuizID(c)
+	if !ok {
+		return
+	}
+	attemptID, err := strconv.ParseUint(c.Param("attemptId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   true,
+			"message": "Invalid attempt ID",
+		})
+		return
+	}
+
+	var reviewRequest schemas.ReviewQuizAttemptRequest
+	if err := c.ShouldBindJSON(&reviewRequest); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   true,
+			"message": "Invalid request body",
+		})
+		return
+	}
+
+	actor, ok := middleware.CurrentActor(c, h.authService)
+	if !ok {
+		return
+	}
+
+	attempt, err := h.service.ReviewAttempt(courseID, chapterID, lessonID, quizID, uint(attemptID), reviewRequest, actor.TenantFilter())
+	if err != nil {
+		c.JSON(quizErrorStatus(err, http.StatusBadRequest), gin.H{
+			"error":   true,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"error":   false,
+		"data":    attempt,
+		"message": "Attempt reviewed successfully",
+	})
+}
@@ -0,0 +1,107 @@
+package v1
+
+import (
+	"strconv"
+	"strings"
+	"web/config"
+	"web/middleware"
+	"web/schemas"
+	"web/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SearchHandler handles HTTP requests for full-text search
+type SearchHandler struct {
+	app           *config.AppConfig
+	service       *services.SearchService
+	authService   *services.AuthService
+	policyService *services.PolicyService
+}
+
+// NewSearchHandler creates a new search handler
+func NewSearchHandler(app *config.AppConfig, service *services.SearchService, authService *services.AuthService, policyService *services.PolicyService) *SearchHandler {
+	return &SearchHandler{
+		app:           app,
+		service:       service,
+		authService:   authService,
+		policyService: policyService,
+	}
+}
+
+// RegisterRoutes registers search api to the router
+func (h *SearchHandler) RegisterRoutes(router *gin.Engine) {
+	searchGroup := router.Group("/api/v1/search")
+	searchGroup.Use(middleware.AuthMiddleware(h.authService))
+	{
+		searchGroup.GET("", h.Search)
+	}
+}
+
+// Search handles GET /api/v1/search
+// @Summary Search courses, chapters and lessons
+// @Description Full-text search over course, chapter and lesson names, descriptions and lesson content.
+// @Description Hits are ranked by relevance and include the course/chapter/lesson path and an HTML-escaped snippet with matches wrapped in <mark> tags.
+// @Description Chapter and lesson hits are limited to courses the user owns, collaborates on or is enrolled in, except for admins and teachers.
+// @Tags search
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param q query string true "Search query, supports quoted phrases, OR and -exclusions"
+// @Param type query string false "Comma separated hit types: course, chapter, lesson"
+// @Param course_id query int false "Only search within this course"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Page size (max 100)" default(20)
+// @Success 200 {object} map[string]interface{} "Returns the ranked hits"
+// @Failure 400 {object} map[string]interface{} "Invalid query parameters"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /search [get]
+func (h *SearchHandler) Search(c *gin.Context) {
+	page, limit, ok := parsePageParams(c)
+	if !ok {
+		return
+	}
+
+	actor, ok := middleware.CurrentActor(c, h.authService)
+	if !ok {
+		return
+	}
+
+	params := schemas.SearchParams{
+		Query:        c.Query("q"),
+		UserID:       actor.UserID,
+		Unrestricted: h.policyService.Can(actor, services.ActionReadUnpublished, services.ResourceCourse, nil),
+		Tenant:       actor.TenantFilter(),
+		Page:         page,
+		Limit:        limit,
+	}
+
+	if types := c.Query("type"); types != "" {
+		for _, hitType := range strings.Split(types, ",") {
+			params.Types = append(params.Types, strings.TrimSpace(hitType))
+		}
+	}
+
+	if courseIDStr := c.Query("course_id"); courseIDStr != "" {
+		courseID, err := strconv.ParseUint(courseIDStr, 10, 32)
+		if err != nil {
+			middleware.RespondWithBadRequest(c, "Invalid course ID")
+			return
+		}
+		id := uint(courseID)
+		params.CourseID = &id
+	}
+
+	hits, meta, err := h.service.Search(params)
+	if err != nil {
+		if err.Error() == "search query is required" || strings.HasPrefix(err.Error(), "search query must not exceed") ||
+			strings.HasPrefix(err.Error(), "invalid search type") {
+			middleware.RespondWithBadRequest(c, err.Error())
+		} else {
+			middleware.RespondWithInternalServerError(c, err.Error())
+		}
+		return
+	}
+
+	middleware.RespondWithSuccessAndMeta(c, hits, meta, "")
+}
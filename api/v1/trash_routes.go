@@ -0,0 +1,155 @@
+package v1
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"web/config"
+	"web/middleware"
+	"web/schemas"
+	"web/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TrashHandler handles HTTP requests for deleted courses, chapters, lessons and attachments
+type TrashHandler struct {
+	app           *config.AppConfig
+	service       *services.TrashService
+	authService   *services.AuthService
+	policyService *services.PolicyService
+	auditService  *services.AuditService
+}
+
+// NewTrashHandler creates a new trash handler
+func NewTrashHandler(app *config.AppConfig, service *services.TrashService, authService *services.AuthService, policyService *services.PolicyService, auditService *services.AuditService) *TrashHandler {
+	return &TrashHandler{
+		app:           app,
+		service:       service,
+		authService:   authService,
+		policyService: policyService,
+		auditService:  auditService,
+	}
+}
+
+// RegisterRoutes registers trash api to the router. Every resource type has its own listing
+// and restore route under /api/v1/trash/<resource type>s. Restores are authorized by the
+// service, the course of a deleted item cannot be read by the route middlewares.
+func (h *TrashHandler) RegisterRoutes(router *gin.Engine) {
+	trashGroup := router.Group("/api/v1/trash")
+	trashGroup.Use(middleware.AuthMiddleware(h.authService))
+	{
+		for _, resourceType := range services.TrashResourceTypes {
+			path := "/" + resourceType + "s"
+			trashGroup.GET(path, h.GetTrash(resourceType))
+			trashGroup.POST(path+"/:itemId/restore", middleware.Audit(h.auditService, services.ActionRestore, resourceType, middleware.AuditParam("itemId")), h.RestoreFromTrash(resourceType))
+		}
+	}
+}
+
+// trashErrorStatus maps trash service errors to HTTP status codes
+func trashErrorStatus(err error) int {
+	switch {
+	case strings.HasSuffix(err.Error(), " not found"):
+		return http.StatusNotFound
+	case strings.HasPrefix(err.Error(), "insufficient permissions"):
+		return http.StatusForbidden
+	case err.Error() == "parent is deleted":
+		return http.StatusConflict
+	}
+	return http.StatusInternalServerError
+}
+
+// GetTrash handles GET /api/v1/trash/courses, /trash/chapters, /trash/lessons and /trash/attachments
+// @Summary List deleted items
+// @Description Get the deleted courses, chapters, lessons or attachments that can still be restored, most recently deleted first.
+// @Description Admins see the trash of their organization, other users the trash of the courses they own or co-instruct.
+// @Description Content deleted together with its course, chapter or lesson is not listed, it is restored with it. Items are deleted for good at purge_at.
+// @Tags trash
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Page size (max 100)" default(20)
+// @Success 200 {object} map[string]interface{} "Returns the deleted items"
+// @Failure 400 {object} map[string]interface{} "Invalid query parameters"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /trash/courses [get]
+// @Router /trash/chapters [get]
+// @Router /trash/lessons [get]
+// @Router /trash/attachments [get]
+func (h *TrashHandler) GetTrash(resourceType string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		page, limit, ok := parsePageParams(c)
+		if !ok {
+			return
+		}
+
+		actor, ok := middleware.CurrentActor(c, h.authService)
+		if !ok {
+			return
+		}
+
+		params := schemas.TrashParams{
+			ResourceType: resourceType,
+			Tenant:       actor.TenantFilter(),
+			Page:         page,
+			Limit:        limit,
+		}
+
+		items, meta, err := h.service.GetAll(params, actor)
+		if err != nil {
+			middleware.RespondWithError(c, trashErrorStatus(err), err.Error())
+			return
+		}
+
+		middleware.RespondWithSuccessAndMeta(c, items, meta, "")
+	}
+}
+
+// RestoreFromTrash handles POST /api/v1/trash/courses/:itemId/restore and the restore routes of chapters, lessons and attachments
+// @Summary Restore a deleted item
+// @Description Restore a deleted course, chapter, lesson or attachment together with the content that was deleted with it.
+// @Description Restoring a course restores its chapters, lessons and attachments. Restored chapters and lessons are placed after their current siblings.
+// @Description Content of a course, chapter or lesson that is still deleted cannot be restored before its parent.
+// @Tags trash
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param itemId path int true "ID of the deleted item"
+// @Success 200 {object} map[string]interface{} "Item restored successfully"
+// @Failure 400 {object} map[string]interface{} "Invalid item ID"
+// @Failure 403 {object} map[string]interface{} "Insufficient permissions"
+// @Failure 404 {object} map[string]interface{} "Item not found in the trash"
+// @Failure 409 {object} map[string]interface{} "The parent of the item is deleted"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /trash/courses/{itemId}/restore [post]
+// @Router /trash/chapters/{itemId}/restore [post]
+// @Router /trash/lessons/{itemId}/restore [post]
+// @Router /trash/attachments/{itemId}/restore [post]
+func (h *TrashHandler) RestoreFromTrash(resourceType string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseUint(c.Param("itemId"), 10, 32)
+		if err != nil {
+			middleware.RespondWithBadRequest(c, "Invalid item ID")
+			return
+		}
+
+		actor, ok := middleware.CurrentActor(c, h.authService)
+		if !ok {
+			return
+		}
+
+		err = h.service.Restore(resourceType, uint(id), actor)
+		if err != nil {
+			message := err.Error()
+			if message == "parent is deleted" {
+				message = "The course, chapter or lesson of this " + resourceType + " is deleted, restore it first"
+			}
+			middleware.RespondWithError(c, trashErrorStatus(err), message)
+			return
+		}
+
+		middleware.RespondWithSuccess(c, nil, "Item restored successfully")
+	}
+}
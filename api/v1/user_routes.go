@@ -10,16 +10,20 @@ import (
 )
 
 type UserHandler struct {
-	app         *config.AppConfig
-	service     *services.UserService
-	authService *services.AuthService
+	app           *config.AppConfig
+	service       *services.UserService
+	authService   *services.AuthService
+	policyService *services.PolicyService
+	auditService  *services.AuditService
 }
 
-func NewUserHandler(app *config.AppConfig, service *services.UserService, authService *services.AuthService) *UserHandler {
+func NewUserHandler(app *config.AppConfig, service *services.UserService, authService *services.AuthService, policyService *services.PolicyService, auditService *services.AuditService) *UserHandler {
 	return &UserHandler{
-		app:         app,
-		service:     service,
-		authService: authService,
+		app:           app,
+		service:       service,
+		authService:   authService,
+		policyService: policyService,
+		auditService:  auditService,
 	}
 }
 
@@ -30,6 +34,7 @@ func (h *UserHandler) RegisterRoutes(router *gin.Engine) {
 	{
 		publicGroup.POST("/login", h.Login)
 		publicGroup.POST("/refresh", h.RefreshToken)
+		publicGroup.POST("/logout", h.Logout)
 	}
 
 	// Protected routes (authentication required)
@@ -37,20 +42,20 @@ func (h *UserHandler) RegisterRoutes(router *gin.Engine) {
 	protectedGroup.Use(middleware.AuthMiddleware(h.authService))
 	{
 		// User routes
-		protectedGroup.PUT("/update", h.UpdateUser)
-		protectedGroup.PUT("/change-password", h.UpdatePassword)
+		protectedGroup.PUT("/update", middleware.Audit(h.auditService, services.ActionUpdate, services.ResourceUser, middleware.AuditCurrentUser), h.UpdateUser)
+		protectedGroup.PUT("/change-password", middleware.Audit(h.auditService, services.ActionUpdate, services.ResourceUser, middleware.AuditCurrentUser), h.UpdatePassword)
 
-		// Admin-only routes - permission check handled by Keycloak
+		// Admin-only routes
 		adminGroup := protectedGroup.Group("/admin")
 		{
-			adminGroup.POST("/create", h.AdminCreateUser)
+			adminGroup.POST("/create", middleware.Authorize(h.authService, h.policyService, services.ActionCreate, services.ResourceUser), middleware.Audit(h.auditService, services.ActionCreate, services.ResourceUser, middleware.AuditCreated), h.AdminCreateUser)
 		}
 	}
 }
 
 // RegisterUser handles POST /api/v1/users/login
 // @Summary Register a new user
-// @Description Register a new user using Keycloak token
+// @Description Register a new user using the identity provider token
 // @Tags users
 // @Accept json
 // @Produce json
@@ -67,15 +72,15 @@ func (h *UserHandler) Claim(c *gin.Context) {
 		return
 	}
 
-	// Convert to KeycloakClaims
-	keycloakClaims, ok := claims.(*services.KeycloakClaims)
+	// Convert to IdentityClaims
+	identityClaims, ok := claims.(*services.IdentityClaims)
 	if !ok {
 		middleware.RespondWithError(c, 500, "Invalid claims type")
 		return
 	}
 
 	// Register user using token information
-	userResponse, err := h.service.ClaimUserUserFromToken(keycloakClaims)
+	userResponse, err := h.service.ClaimUserUserFromToken(identityClaims)
 	if err != nil {
 		middleware.RespondWithBadRequest(c, err.Error())
 		return
@@ -86,7 +91,7 @@ func (h *UserHandler) Claim(c *gin.Context) {
 
 // Login handles POST /api/v1/auth/login
 // @Summary Login with username and password
-// @Description Authenticate with Keycloak and get a JWT token
+// @Description Authenticate with the identity provider and get a JWT token
 // @Tags auth
 // @Accept json
 // @Produce json
@@ -102,7 +107,7 @@ func (h *UserHandler) Login(c *gin.Context) {
 		return
 	}
 
-	// Authenticate with Keycloak
+	// Authenticate with the identity provider
 	loginResponse, err := h.authService.Login(loginRequest.Username, loginRequest.Password, *h.service)
 	if err != nil {
 		middleware.RespondWithError(c, 401, "Authentication failed: "+err.Error())
@@ -140,9 +145,34 @@ func (h *UserHandler) RefreshToken(c *gin.Context) {
 	middleware.RespondWithSuccess(c, loginResponse, "Token refreshed successfully")
 }
 
+// Logout handles POST /api/v1/auth/logout
+// @Summary Log out
+// @Description Revoke a refresh token and end its session in the identity provider
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param refresh body schemas.RefreshTokenRequest true "Refresh token"
+// @Success 200 {object} map[string]interface{} "Logged out successfully"
+// @Failure 400 {object} map[string]interface{} "Validation error or token revocation failed"
+// @Router /auth/logout [post]
+func (h *UserHandler) Logout(c *gin.Context) {
+	var refreshRequest schemas.RefreshTokenRequest
+	if err := c.ShouldBindJSON(&refreshRequest); err != nil {
+		middleware.RespondWithBadRequest(c, "Invalid request body: "+err.Error())
+		return
+	}
+
+	if err := h.authService.RevokeToken(refreshRequest.RefreshToken); err != nil {
+		middleware.RespondWithBadRequest(c, "Token revocation failed: "+err.Error())
+		return
+	}
+
+	middleware.RespondWithSuccess(c, nil, "Logged out successfully")
+}
+
 // AdminCreateUser handles POST /api/v1/users/admin/create
 // @Summary Create a new user (Admin only)
-// @Description Create a new user in Keycloak and in the local database (Admin only)
+// @Description Create a new user in the identity provider and in the local database (Admin only)
 // @Tags users
 // @Accept json
 // @Produce json
@@ -167,6 +197,9 @@ func (h *UserHandler) AdminCreateUser(c *gin.Context) {
 		return
 	}
 
+	// The user is stored on first login unless the identity provider is the local one, the
+	// audit log keeps the registration
+	middleware.SetAuditAfter(c, userResponse)
 	middleware.RespondWithCreated(c, userResponse, "User created successfully")
 }
 
@@ -214,7 +247,7 @@ func (h *UserHandler) UpdateUser(c *gin.Context) {
 
 // UpdatePassword handles PUT /api/v1/users/change-password
 // @Summary Update user password
-// @Description Update user's password
+// @Description Update user's password. Only available when IDENTITY_PROVIDER is local, other providers manage the passwords themselves.
 // @Tags users
 // @Accept json
 // @Produce json
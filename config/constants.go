@@ -2,7 +2,10 @@ package config
 
 import (
 	"database/sql"
+	"fmt"
 	"os"
+	"strconv"
+	"time"
 
 	"github.com/joho/godotenv"
 	_ "github.com/lib/pq"
@@ -10,11 +13,36 @@ import (
 	"gorm.io/gorm"
 )
 
+// Token validation strategies. Local validation only checks the signature and expiry of the
+// token. Introspection also asks the identity provider whether the token was revoked, for every request or
+// only for the requests that change data.
+const (
+	TokenValidationLocal               = "local"
+	TokenValidationIntrospect          = "introspect"
+	TokenValidationIntrospectMutations = "introspect_mutations"
+)
+
+// Identity providers. Keycloak supports user provisioning and role assignment, a generic
+// OIDC provider is configured from the discovery document of its issuer. The local provider
+// authenticates against the users table and issues its own tokens.
+const (
+	IdentityProviderKeycloak = "keycloak"
+	IdentityProviderOIDC     = "oidc"
+	IdentityProviderLocal    = "local"
+)
+
+// localAuthSecretMinLength is the minimum length of the key signing the tokens of the local
+// identity provider
+const localAuthSecretMinLength = 32
+
 type AppConfig struct {
 	DB     *sql.DB
 	GormDB *gorm.DB
 	DbUrl  string
 
+	// Identity provider configuration
+	IdentityProvider string
+
 	// Keycloak configuration
 	KeycloakURL           string
 	KeycloakRealm         string
@@ -23,12 +51,37 @@ type AppConfig struct {
 	KeycloakAdminUsername string
 	KeycloakAdminPassword string
 
+	// Generic OIDC provider configuration. OIDCRolesClaim is the dotted path of the
+	// roles in the access token, e.g. "realm_access.roles".
+	OIDCIssuerURL    string
+	OIDCClientID     string
+	OIDCClientSecret string
+	OIDCRolesClaim   string
+
+	// Local identity provider configuration. Access tokens are signed with LocalAuthSecret,
+	// refresh tokens are stored in the database.
+	LocalAuthSecret          string
+	LocalAuthIssuer          string
+	LocalAuthAccessTokenTTL  time.Duration
+	LocalAuthRefreshTokenTTL time.Duration
+
+	// Token validation configuration. Introspection results are cached for
+	// IntrospectionCacheTTL, zero (the default) disables the cache.
+	TokenValidation       string
+	IntrospectionCacheTTL time.Duration
+
 	// MinIO configuration
 	MinioEndpoint  string
 	MinioAccessKey string
 	MinioSecretKey string
 	MinioBucket    string
 	MinioUseSSL    bool
+
+	// Certificate configuration
+	CertificateCompletionPercent int
+
+	// Trash configuration, deleted content is purged after the retention period
+	TrashRetentionDays int
 }
 
 func LoadConfig() (*AppConfig, error) {
@@ -57,6 +110,15 @@ func LoadConfig() (*AppConfig, error) {
 		return nil, err
 	}
 
+	// Load identity provider configuration
+	identityProvider := getEnv("IDENTITY_PROVIDER", IdentityProviderKeycloak)
+	switch identityProvider {
+	case IdentityProviderKeycloak, IdentityProviderOIDC, IdentityProviderLocal:
+	default:
+		return nil, fmt.Errorf("invalid IDENTITY_PROVIDER %q, expected %s, %s or %s", identityProvider,
+			IdentityProviderKeycloak, IdentityProviderOIDC, IdentityProviderLocal)
+	}
+
 	// Load Keycloak configuration
 	keycloakURL := getEnv("KEYCLOAK_URL", "http://localhost:8081")
 	keycloakRealm := getEnv("KEYCLOAK_REALM", "master")
@@ -65,6 +127,53 @@ func LoadConfig() (*AppConfig, error) {
 	keycloakAdminUsername := getEnv("KC_ADMIN", "admin")
 	keycloakAdminPassword := getEnv("KC_ADMIN_PASSWORD", "admin")
 
+	// Load generic OIDC provider configuration
+	oidcIssuerURL := getEnv("OIDC_ISSUER_URL", "")
+	oidcClientID := getEnv("OIDC_CLIENT_ID", "")
+	oidcClientSecret := getEnv("OIDC_CLIENT_SECRET", "")
+	oidcRolesClaim := getEnv("OIDC_ROLES_CLAIM", "roles")
+	if identityProvider == IdentityProviderOIDC && (oidcIssuerURL == "" || oidcClientID == "") {
+		return nil, fmt.Errorf("OIDC_ISSUER_URL and OIDC_CLIENT_ID are required when IDENTITY_PROVIDER is %s", IdentityProviderOIDC)
+	}
+
+	// Load local identity provider configuration
+	localAuthSecret := getEnv("LOCAL_AUTH_SECRET", "")
+	if identityProvider == IdentityProviderLocal && len(localAuthSecret) < localAuthSecretMinLength {
+		return nil, fmt.Errorf("invalid LOCAL_AUTH_SECRET: must be at least %d characters when IDENTITY_PROVIDER is %s",
+			localAuthSecretMinLength, IdentityProviderLocal)
+	}
+	localAuthIssuer := getEnv("LOCAL_AUTH_ISSUER", "course-api")
+	localAuthAccessTokenMinutes, err := strconv.Atoi(getEnv("LOCAL_AUTH_ACCESS_TOKEN_MINUTES", "15"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid LOCAL_AUTH_ACCESS_TOKEN_MINUTES: %w", err)
+	}
+	if localAuthAccessTokenMinutes < 1 {
+		return nil, fmt.Errorf("invalid LOCAL_AUTH_ACCESS_TOKEN_MINUTES: must be at least 1, got %d", localAuthAccessTokenMinutes)
+	}
+	localAuthRefreshTokenDays, err := strconv.Atoi(getEnv("LOCAL_AUTH_REFRESH_TOKEN_DAYS", "30"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid LOCAL_AUTH_REFRESH_TOKEN_DAYS: %w", err)
+	}
+	if localAuthRefreshTokenDays < 1 {
+		return nil, fmt.Errorf("invalid LOCAL_AUTH_REFRESH_TOKEN_DAYS: must be at least 1, got %d", localAuthRefreshTokenDays)
+	}
+
+	// Load token validation configuration
+	tokenValidation := getEnv("TOKEN_VALIDATION", TokenValidationIntrospect)
+	switch tokenValidation {
+	case TokenValidationLocal, TokenValidationIntrospect, TokenValidationIntrospectMutations:
+	default:
+		return nil, fmt.Errorf("invalid TOKEN_VALIDATION %q, expected %s, %s or %s", tokenValidation,
+			TokenValidationLocal, TokenValidationIntrospect, TokenValidationIntrospectMutations)
+	}
+	introspectionCacheSeconds, err := strconv.Atoi(getEnv("INTROSPECTION_CACHE_SECONDS", "0"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid INTROSPECTION_CACHE_SECONDS: %w", err)
+	}
+	if introspectionCacheSeconds < 0 {
+		return nil, fmt.Errorf("invalid INTROSPECTION_CACHE_SECONDS: must not be negative, got %d", introspectionCacheSeconds)
+	}
+
 	// Load MinIO configuration
 	minioEndpoint := getEnv("MINIO_ENDPOINT", "localhost:9000")
 	minioAccessKey := getEnv("MINIO_ACCESS_KEY", "minioadmin")
@@ -72,21 +181,50 @@ func LoadConfig() (*AppConfig, error) {
 	minioBucket := getEnv("MINIO_BUCKET", "attachments")
 	minioUseSSL := getEnv("MINIO_USE_SSL", "false") == "true"
 
+	// Load certificate configuration
+	certificateCompletionPercent, err := strconv.Atoi(getEnv("CERTIFICATE_COMPLETION_PERCENT", "100"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid CERTIFICATE_COMPLETION_PERCENT: %w", err)
+	}
+
+	// Load trash configuration
+	trashRetentionDays, err := strconv.Atoi(getEnv("TRASH_RETENTION_DAYS", "30"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid TRASH_RETENTION_DAYS: %w", err)
+	}
+	if trashRetentionDays < 1 {
+		return nil, fmt.Errorf("invalid TRASH_RETENTION_DAYS: must be at least 1, got %d", trashRetentionDays)
+	}
+
 	return &AppConfig{
 		DB:                    sqlDB,
 		GormDB:                gormDB,
 		DbUrl:                 dbUrl,
+		IdentityProvider:      identityProvider,
 		KeycloakURL:           keycloakURL,
 		KeycloakRealm:         keycloakRealm,
 		KeycloakClientID:      keycloakClientID,
 		KeycloakClientSecret:  keycloakClientSecret,
 		KeycloakAdminUsername: keycloakAdminUsername,
 		KeycloakAdminPassword: keycloakAdminPassword,
+		OIDCIssuerURL:         oidcIssuerURL,
+		OIDCClientID:          oidcClientID,
+		OIDCClientSecret:      oidcClientSecret,
+		OIDCRolesClaim:        oidcRolesClaim,
+		LocalAuthSecret:       localAuthSecret,
+		LocalAuthIssuer:       localAuthIssuer,
+		TokenValidation:       tokenValidation,
+		IntrospectionCacheTTL: time.Duration(introspectionCacheSeconds) * time.Second,
 		MinioEndpoint:         minioEndpoint,
 		MinioAccessKey:        minioAccessKey,
 		MinioSecretKey:        minioSecretKey,
 		MinioBucket:           minioBucket,
 		MinioUseSSL:           minioUseSSL,
+
+		CertificateCompletionPercent: certificateCompletionPercent,
+		TrashRetentionDays:           trashRetentionDays,
+		LocalAuthAccessTokenTTL:      time.Duration(localAuthAccessTokenMinutes) * time.Minute,
+		LocalAuthRefreshTokenTTL:     time.Duration(localAuthRefreshTokenDays) * 24 * time.Hour,
 	}, nil
 }
 
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"github.com/gin-gonic/gin"
 	"github.com/pressly/goose/v3"
@@ -9,6 +10,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 	"web/api/v1"
 
 	"web/config"
@@ -53,31 +55,71 @@ func main() {
 	lessonRepo := repos.NewLessonRepository(appConfig.GormDB)
 	userRepo := repos.NewUserRepository(appConfig.GormDB)
 	attachmentRepo := repos.NewAttachmentRepository(appConfig.GormDB)
+	enrollmentRepo := repos.NewEnrollmentRepository(appConfig.GormDB)
+	progressRepo := repos.NewProgressRepository(appConfig.GormDB)
+	quizRepo := repos.NewQuizRepository(appConfig.GormDB)
+	certificateRepo := repos.NewCertificateRepository(appConfig.GormDB)
+	searchRepo := repos.NewSearchRepository(appConfig.GormDB)
+	importJobRepo := repos.NewImportJobRepository(appConfig.GormDB)
+	courseMemberRepo := repos.NewCourseMemberRepository(appConfig.GormDB)
+	organizationRepo := repos.NewOrganizationRepository(appConfig.GormDB)
+	auditLogRepo := repos.NewAuditLogRepository(appConfig.GormDB)
+	trashRepo := repos.NewTrashRepository(appConfig.GormDB)
+	refreshTokenRepo := repos.NewRefreshTokenRepository(appConfig.GormDB)
 
 	// Initialize services
-	courseService := services.NewCourseService(courseRepo)
+	courseService := services.NewCourseService(courseRepo, courseMemberRepo)
 	chapterService := services.NewChapterService(chapterRepo, courseRepo)
 	lessonService := services.NewLessonService(lessonRepo, chapterRepo, courseRepo)
-	authService := services.NewAuthService(appConfig, userRepo)
-	userService := services.NewUserService(userRepo)
+	lessonRevisionService := services.NewLessonRevisionService(lessonRepo)
+	courseOrderService := services.NewCourseOrderService(courseRepo, chapterRepo, lessonRepo, courseMemberRepo)
+	identityProvider, err := services.NewIdentityProvider(appConfig, userRepo, refreshTokenRepo)
+	if err != nil {
+		log.Fatalf("Failed to initialize identity provider: %v", err)
+	}
+	authService := services.NewAuthService(appConfig, identityProvider, userRepo, organizationRepo)
+	go authService.RunKeyRefresh(context.Background())
+	userService := services.NewUserService(appConfig, userRepo)
+	enrollmentService := services.NewEnrollmentService(enrollmentRepo, courseRepo, courseMemberRepo)
+	progressService := services.NewProgressService(progressRepo, lessonRepo)
+	quizService := services.NewQuizService(quizRepo, lessonRepo)
+	searchService := services.NewSearchService(searchRepo)
+	courseMemberService := services.NewCourseMemberService(courseMemberRepo, courseRepo, userRepo)
+	organizationService := services.NewOrganizationService(organizationRepo, userRepo)
+	auditService := services.NewAuditService(auditLogRepo)
+	policyService := services.NewPolicyService(services.PolicyRules, courseRepo, lessonRepo, courseMemberRepo)
 
 	// Initialize attachment service
-	attachmentService, err := services.NewAttachmentService(appConfig, attachmentRepo, lessonRepo)
+	attachmentService, err := services.NewAttachmentService(appConfig, attachmentRepo, lessonRepo, enrollmentService)
 	if err != nil {
 		log.Fatalf("Failed to initialize attachment service: %v", err)
 	}
+	lessonBlockService := services.NewLessonBlockService(lessonRepo, attachmentRepo)
+	courseCloneService := services.NewCourseCloneService(courseRepo, attachmentService)
+	courseArchiveService := services.NewCourseArchiveService(courseRepo, attachmentService)
+	packageImportService := services.NewPackageImportService(importJobRepo, courseRepo, auditService, attachmentService)
+	if failed, err := packageImportService.FailInterruptedJobs(); err != nil {
+		log.Warnf("Failed to mark interrupted import jobs: %v", err)
+	} else if failed > 0 {
+		log.Infof("Marked %d interrupted import jobs as failed", failed)
+	}
+	certificateService := services.NewCertificateService(appConfig, certificateRepo, courseRepo, enrollmentRepo, progressRepo, userRepo, policyService, attachmentService)
+	trashService := services.NewTrashService(trashRepo, policyService, attachmentService, time.Duration(appConfig.TrashRetentionDays)*24*time.Hour)
+	go runTrashPurge(log, trashService)
 
 	// Initialize router
 	router := gin.Default()
 
 	// Apply middleware
 	router.Use(middleware.ResponseMiddleware())
+	router.Use(middleware.RequestIDMiddleware())
 
 	// Apply auth middleware to all routes except swagger, root, and auth endpoints
 	router.Use(func(c *gin.Context) {
 		if strings.HasPrefix(c.Request.URL.Path, "/swagger") ||
 			c.Request.URL.Path == "/" ||
-			strings.HasPrefix(c.Request.URL.Path, "/api/v1/auth/") {
+			strings.HasPrefix(c.Request.URL.Path, "/api/v1/auth/") ||
+			(strings.HasPrefix(c.Request.URL.Path, "/api/v1/certificates/") && strings.HasSuffix(c.Request.URL.Path, "/verify")) {
 			c.Next()
 			return
 		}
@@ -87,18 +129,48 @@ func main() {
 	})
 
 	// Register api
-	courseHandler := v1.NewCourseHandler(appConfig, courseService, chapterService, authService)
-	chapterHandler := v1.NewChapterHandler(appConfig, chapterService, authService)
-	lessonHandler := v1.NewLessonHandler(appConfig, lessonService, authService)
-	userHandler := v1.NewUserHandler(appConfig, userService, authService)
-	attachmentHandler := v1.NewAttachmentHandler(appConfig, attachmentService, authService)
+	courseHandler := v1.NewCourseHandler(appConfig, courseService, chapterService, enrollmentService, progressService, authService, policyService, auditService)
+	courseCloneHandler := v1.NewCourseCloneHandler(appConfig, courseCloneService, authService, policyService, auditService)
+	courseArchiveHandler := v1.NewCourseArchiveHandler(appConfig, courseArchiveService, authService, policyService, auditService)
+	packageImportHandler := v1.NewPackageImportHandler(appConfig, packageImportService, authService, policyService)
+	chapterHandler := v1.NewChapterHandler(appConfig, chapterService, enrollmentService, progressService, authService, policyService, auditService)
+	lessonHandler := v1.NewLessonHandler(appConfig, lessonService, enrollmentService, authService, policyService, auditService)
+	courseOrderHandler := v1.NewCourseOrderHandler(appConfig, courseOrderService, authService, policyService, auditService)
+	courseMemberHandler := v1.NewCourseMemberHandler(appConfig, courseMemberService, authService, policyService)
+	lessonRevisionHandler := v1.NewLessonRevisionHandler(appConfig, lessonRevisionService, authService, policyService, auditService)
+	lessonBlockHandler := v1.NewLessonBlockHandler(appConfig, lessonBlockService, enrollmentService, authService, policyService, auditService)
+	userHandler := v1.NewUserHandler(appConfig, userService, authService, policyService, auditService)
+	attachmentHandler := v1.NewAttachmentHandler(appConfig, attachmentService, enrollmentService, authService, policyService, auditService)
+	enrollmentHandler := v1.NewEnrollmentHandler(appConfig, enrollmentService, authService, policyService)
+	progressHandler := v1.NewProgressHandler(appConfig, progressService, enrollmentService, authService)
+	quizHandler := v1.NewQuizHandler(appConfig, quizService, enrollmentService, authService, policyService)
+	certificateHandler := v1.NewCertificateHandler(appConfig, certificateService, enrollmentService, authService, policyService)
+	searchHandler := v1.NewSearchHandler(appConfig, searchService, authService, policyService)
+	organizationHandler := v1.NewOrganizationHandler(appConfig, organizationService, authService, policyService)
+	auditHandler := v1.NewAuditHandler(appConfig, auditService, authService, policyService)
+	trashHandler := v1.NewTrashHandler(appConfig, trashService, authService, policyService, auditService)
 
 	// Register routes
 	courseHandler.RegisterRoutes(router)
+	courseCloneHandler.RegisterRoutes(router)
+	courseArchiveHandler.RegisterRoutes(router)
 	chapterHandler.RegisterRoutes(router)
 	lessonHandler.RegisterRoutes(router)
+	courseOrderHandler.RegisterRoutes(router)
+	courseMemberHandler.RegisterRoutes(router)
+	lessonRevisionHandler.RegisterRoutes(router)
+	lessonBlockHandler.RegisterRoutes(router)
 	userHandler.RegisterRoutes(router)
 	attachmentHandler.RegisterRoutes(router)
+	enrollmentHandler.RegisterRoutes(router)
+	progressHandler.RegisterRoutes(router)
+	quizHandler.RegisterRoutes(router)
+	certificateHandler.RegisterRoutes(router)
+	searchHandler.RegisterRoutes(router)
+	packageImportHandler.RegisterRoutes(router)
+	organizationHandler.RegisterRoutes(router)
+	auditHandler.RegisterRoutes(router)
+	trashHandler.RegisterRoutes(router)
 
 	// Default route
 	router.GET("/", func(c *gin.Context) {
@@ -115,6 +187,26 @@ func main() {
 	}
 }
 
+// trashPurgeInterval is how often deleted content past the retention period is purged
+const trashPurgeInterval = time.Hour
+
+// runTrashPurge purges the trash on startup and then every trashPurgeInterval
+func runTrashPurge(log *logrus.Logger, trashService *services.TrashService) {
+	ticker := time.NewTicker(trashPurgeInterval)
+	defer ticker.Stop()
+
+	for {
+		removed, err := trashService.Purge()
+		if err != nil {
+			log.Warnf("Failed to purge the trash: %v", err)
+		}
+		if removed > 0 {
+			log.Infof("Purged %d files of deleted attachments from storage", removed)
+		}
+		<-ticker.C
+	}
+}
+
 func runMigrations() error {
 	dir, err := os.Getwd()
 	if err != nil {
@@ -0,0 +1,132 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"web/models"
+	"web/services"
+
+	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	auditResourceIDKey = "audit_resource_id"
+	auditAfterKey      = "audit_after"
+)
+
+// AuditResourceID returns the ID of the resource a request changes, false when the request
+// creates the resource. Handlers of creations report the new ID with SetAuditResourceID.
+type AuditResourceID func(c *gin.Context) (uint, bool)
+
+// AuditParam identifies the audited resource by a route parameter
+func AuditParam(name string) AuditResourceID {
+	return func(c *gin.Context) (uint, bool) {
+		id, err := strconv.ParseUint(c.Param(name), 10, 32)
+		if err != nil {
+			return 0, false
+		}
+		return uint(id), true
+	}
+}
+
+// AuditCurrentUser identifies the audited resource as the authenticated user
+func AuditCurrentUser(c *gin.Context) (uint, bool) {
+	user, ok := c.Get("user")
+	if !ok {
+		return 0, false
+	}
+	currentUser, ok := user.(models.User)
+	return currentUser.ID, ok
+}
+
+// AuditCreated is the AuditResourceID of requests that create the audited resource
+func AuditCreated(c *gin.Context) (uint, bool) {
+	return 0, false
+}
+
+// SetAuditResourceID reports the ID of the resource created by the request
+func SetAuditResourceID(c *gin.Context, id uint) {
+	c.Set(auditResourceIDKey, id)
+}
+
+// SetAuditAfter reports the state of a created resource that is not stored in the
+// database, such as a user registered in the identity provider
+func SetAuditAfter(c *gin.Context, state interface{}) {
+	c.Set(auditAfterKey, state)
+}
+
+// Audit creates a middleware that records a successful request in the audit log with the
+// authenticated user, the request ID and the state of the resource before and after the
+// request. It runs after the authorization middlewares, denied requests are not recorded.
+func Audit(auditService *services.AuditService, action, resourceType string, resourceID AuditResourceID) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, exists := resourceID(c)
+		var before json.RawMessage
+		if exists {
+			before = auditSnapshot(auditService, resourceType, id)
+		}
+
+		c.Next()
+
+		if c.Writer.Status() >= http.StatusMultipleChoices {
+			return
+		}
+
+		entry := NewAuditEntry(c, action, resourceType)
+		entry.Before = before
+
+		if !exists {
+			if createdID, ok := c.Get(auditResourceIDKey); ok {
+				id, exists = createdID.(uint), true
+			}
+		}
+		if exists {
+			entry.ResourceID = &id
+			entry.After = auditSnapshot(auditService, resourceType, id)
+		} else if state, ok := c.Get(auditAfterKey); ok {
+			entry.After, _ = json.Marshal(state)
+		} else {
+			// The request succeeded without creating anything, such as a dry run
+			return
+		}
+
+		// The operation already succeeded, a failure to record it must not fail the request
+		if err := auditService.Record(entry); err != nil {
+			log.Errorf("Failed to record audit log entry for %s %s: %v", entry.Method, entry.Path, err)
+		}
+	}
+}
+
+// NewAuditEntry returns the audit log entry of a request by the authenticated user, without
+// the resource. Operations that finish after the request, such as package imports, record
+// it themselves once the resource exists.
+func NewAuditEntry(c *gin.Context, action, resourceType string) models.AuditLog {
+	entry := models.AuditLog{
+		Action:       action,
+		ResourceType: resourceType,
+		RequestID:    c.GetString("request_id"),
+		Method:       c.Request.Method,
+		Path:         c.Request.URL.Path,
+	}
+
+	if userObj, ok := c.Get("user"); ok {
+		if user, ok := userObj.(models.User); ok {
+			entry.ActorID = &user.ID
+			entry.ActorUsername = user.Username
+			entry.OrganizationID = user.OrganizationID
+		}
+	}
+	return entry
+}
+
+// auditSnapshot returns the state of the audited resource, nil when it cannot be read
+func auditSnapshot(auditService *services.AuditService, resourceType string, id uint) json.RawMessage {
+	snapshot, err := auditService.Snapshot(resourceType, id)
+	if err != nil {
+		log.Errorf("Failed to read %s %d for the audit log: %v", resourceType, id, err)
+		return nil
+	}
+	return snapshot
+}
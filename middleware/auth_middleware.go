@@ -3,10 +3,13 @@ package middleware
 import (
 	"github.com/gin-gonic/gin"
 	"net/http"
+	"strconv"
+	"strings"
+	"web/models"
 	"web/services"
 )
 
-// AuthMiddleware creates a middleware that validates JWT tokens from Keycloak
+// AuthMiddleware creates a middleware that validates JWT tokens from the identity provider
 func AuthMiddleware(authService *services.AuthService) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// Extract the token from the Authorization header
@@ -25,8 +28,9 @@ func AuthMiddleware(authService *services.AuthService) gin.HandlerFunc {
 			return
 		}
 
-		// Check with Keycloak if the token is still valid (not revoked or blocked)
-		active, err := authService.IntrospectToken(token)
+		// Check with the identity provider if the token is still valid (not revoked or blocked), as often as
+		// the token validation strategy asks for
+		active, err := authService.IsTokenActive(token, claims, c.Request.Method)
 		if err != nil {
 			RespondWithError(c, http.StatusUnauthorized, "Token validation failed: "+err.Error())
 			c.Abort()
@@ -40,14 +44,14 @@ func AuthMiddleware(authService *services.AuthService) gin.HandlerFunc {
 
 		// Validate the session (check if user exists in the database)
 		if c.Request.URL.Path != "/api/v1/users/login" { // Skip session validation for login endpoint
-			valid, err := authService.ValidateSession(claims.Sub)
+			valid, err := authService.ValidateSession(claims.Subject)
 			if err != nil {
 				RespondWithError(c, http.StatusInternalServerError, "Session validation error: "+err.Error())
 				c.Abort()
 				return
 			}
 			if !valid {
-				userService := services.NewUserService(authService.GetUserRepo())
+				userService := services.NewUserService(authService.GetConfig(), authService.GetUserRepo())
 
 				_, createErr := userService.ClaimUserUserFromToken(claims)
 				if createErr != nil {
@@ -56,7 +60,7 @@ func AuthMiddleware(authService *services.AuthService) gin.HandlerFunc {
 					return
 				}
 
-				_, getUserErr := authService.GetUserBySub(claims.Sub)
+				_, getUserErr := authService.GetUserBySub(claims.Subject)
 				if getUserErr != nil {
 					RespondWithError(c, http.StatusInternalServerError, "Failed to get created user: "+getUserErr.Error())
 					c.Abort()
@@ -64,45 +68,63 @@ func AuthMiddleware(authService *services.AuthService) gin.HandlerFunc {
 				}
 			}
 		}
-		user, err := authService.GetUserBySub(claims.Sub)
+		user, err := authService.GetUserBySub(claims.Subject)
 		if err != nil {
 			RespondWithError(c, http.StatusUnauthorized, "Invalid session: user not found")
 			c.Abort()
 			return
 		}
+		user, err = authService.SyncOrganization(user, claims)
+		if err != nil {
+			if err.Error() == "organization not found" {
+				RespondWithError(c, http.StatusForbidden, "Unknown organization")
+			} else {
+				RespondWithError(c, http.StatusInternalServerError, "Organization sync error: "+err.Error())
+			}
+			c.Abort()
+			return
+		}
 		// Store the claims in the context for later use
 		c.Set("claims", claims)
 		c.Set("user_id", claims.Subject)
 		c.Set("user", user)
-		c.Set("username", claims.PreferredUsername)
+		c.Set("username", claims.Username)
 		c.Set("email", claims.Email)
-		c.Set("sub", claims.Sub)
+		c.Set("sub", claims.Subject)
 
 		c.Next()
 	}
 }
 
-// RequireRole creates a middleware that requires a specific role
-func RequireRole(authService *services.AuthService, role string) gin.HandlerFunc {
+// RequireCourseAccess creates a middleware that requires the user to be enrolled in,
+// be a member of or teach the course identified by the :id route parameter
+func RequireCourseAccess(authService *services.AuthService, enrollmentService *services.EnrollmentService) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		// Get the claims from the context
-		claims, exists := c.Get("claims")
-		if !exists {
-			RespondWithError(c, http.StatusUnauthorized, "Authentication required")
+		courseID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+		if err != nil {
+			RespondWithBadRequest(c, "Invalid course ID")
 			c.Abort()
 			return
 		}
 
-		// Check if the user has the required role
-		keycloakClaims, ok := claims.(*services.KeycloakClaims)
+		actor, ok := CurrentActor(c, authService)
 		if !ok {
-			RespondWithError(c, http.StatusInternalServerError, "Invalid claims type")
+			return
+		}
+
+		hasAccess, err := enrollmentService.HasAccessToCourse(actor, uint(courseID))
+		if err != nil {
+			if err.Error() == "course not found" {
+				RespondWithNotFound(c, err.Error())
+			} else {
+				RespondWithInternalServerError(c, err.Error())
+			}
 			c.Abort()
 			return
 		}
 
-		if !authService.HasRole(keycloakClaims, role) {
-			RespondWithError(c, http.StatusForbidden, "Insufficient permissions")
+		if !hasAccess {
+			RespondWithError(c, http.StatusForbidden, "You are not enrolled in this course")
 			c.Abort()
 			return
 		}
@@ -110,3 +132,97 @@ func RequireRole(authService *services.AuthService, role string) gin.HandlerFunc
 		c.Next()
 	}
 }
+
+// Authorize creates a middleware that requires the policy to allow the action on a resource
+// that has no owner, such as a resource being created
+func Authorize(authService *services.AuthService, policyService *services.PolicyService, action, resource string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		actor, ok := CurrentActor(c, authService)
+		if !ok {
+			return
+		}
+
+		authorizeRequest(c, policyService.Authorize(actor, action, resource, nil))
+	}
+}
+
+// AuthorizeCourse creates a middleware that requires the policy to allow the action on the
+// course identified by the :id route parameter or on content of that course
+func AuthorizeCourse(authService *services.AuthService, policyService *services.PolicyService, action, resource string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		courseID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+		if err != nil {
+			RespondWithBadRequest(c, "Invalid course ID")
+			c.Abort()
+			return
+		}
+
+		actor, ok := CurrentActor(c, authService)
+		if !ok {
+			return
+		}
+
+		authorizeRequest(c, policyService.AuthorizeCourse(actor, action, resource, uint(courseID)))
+	}
+}
+
+// AuthorizeLesson creates a middleware that requires the policy to allow the action on
+// content of the lesson identified by the :lessonId route parameter
+func AuthorizeLesson(authService *services.AuthService, policyService *services.PolicyService, action, resource string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		lessonID, err := strconv.ParseUint(c.Param("lessonId"), 10, 32)
+		if err != nil {
+			RespondWithBadRequest(c, "Invalid lesson ID")
+			c.Abort()
+			return
+		}
+
+		actor, ok := CurrentActor(c, authService)
+		if !ok {
+			return
+		}
+
+		authorizeRequest(c, policyService.AuthorizeLesson(actor, action, resource, uint(lessonID)))
+	}
+}
+
+// CurrentActor returns the authenticated user with their roles and aborts the request
+// when the user is missing
+func CurrentActor(c *gin.Context, authService *services.AuthService) (services.Actor, bool) {
+	userObj, exists := c.Get("user")
+	if !exists {
+		RespondWithError(c, http.StatusUnauthorized, "Authentication required")
+		c.Abort()
+		return services.Actor{}, false
+	}
+
+	user, ok := userObj.(models.User)
+	if !ok {
+		RespondWithError(c, http.StatusInternalServerError, "Invalid user type")
+		c.Abort()
+		return services.Actor{}, false
+	}
+
+	claims, _ := c.Get("claims")
+	identityClaims, _ := claims.(*services.IdentityClaims)
+	return authService.NewActor(user, identityClaims), true
+}
+
+// authorizeRequest continues the request when the policy allowed it and writes the error
+// response otherwise
+func authorizeRequest(c *gin.Context, err error) {
+	if err == nil {
+		c.Next()
+		return
+	}
+
+	switch {
+	case strings.HasPrefix(err.Error(), "insufficient permissions"):
+		RespondWithError(c, http.StatusForbidden, err.Error())
+	case err.Error() == "course not found", err.Error() == "lesson not found":
+		RespondWithNotFound(c, err.Error())
+	default:
+		RespondWithInternalServerError(c, err.Error())
+	}
+	c.Abort()
+}
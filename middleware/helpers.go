@@ -8,6 +8,10 @@ func GetSuccessResponse(c *gin.Context) func(*gin.Context, interface{}, string)
 	return c.MustGet("SuccessResponse").(func(*gin.Context, interface{}, string))
 }
 
+func GetSuccessWithMetaResponse(c *gin.Context) func(*gin.Context, interface{}, interface{}, string) {
+	return c.MustGet("SuccessWithMetaResponse").(func(*gin.Context, interface{}, interface{}, string))
+}
+
 func GetErrorResponse(c *gin.Context) func(*gin.Context, int, string) {
 	return c.MustGet("ErrorResponse").(func(*gin.Context, int, string))
 }
@@ -32,6 +36,10 @@ func RespondWithSuccess(c *gin.Context, data interface{}, message string) {
 	GetSuccessResponse(c)(c, data, message)
 }
 
+func RespondWithSuccessAndMeta(c *gin.Context, data interface{}, meta interface{}, message string) {
+	GetSuccessWithMetaResponse(c)(c, data, meta, message)
+}
+
 func RespondWithError(c *gin.Context, statusCode int, message string) {
 	GetErrorResponse(c)(c, statusCode, message)
 }
@@ -9,12 +9,14 @@ type Response struct {
 	Error   bool        `json:"error"`
 	Message string      `json:"message,omitempty"`
 	Data    interface{} `json:"data,omitempty"`
+	Meta    interface{} `json:"meta,omitempty"`
 }
 
 func ResponseMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 
 		c.Set("SuccessResponse", SuccessResponse)
+		c.Set("SuccessWithMetaResponse", SuccessWithMetaResponse)
 		c.Set("ErrorResponse", ErrorResponse)
 		c.Set("CreatedResponse", CreatedResponse)
 		c.Set("NotFoundResponse", NotFoundResponse)
@@ -34,6 +36,16 @@ func SuccessResponse(c *gin.Context, data interface{}, message string) {
 	c.JSON(http.StatusOK, response)
 }
 
+func SuccessWithMetaResponse(c *gin.Context, data interface{}, meta interface{}, message string) {
+	response := Response{
+		Error:   false,
+		Data:    data,
+		Meta:    meta,
+		Message: message,
+	}
+	c.JSON(http.StatusOK, response)
+}
+
 func CreatedResponse(c *gin.Context, data interface{}, message string) {
 	response := Response{
 		Error:   false,
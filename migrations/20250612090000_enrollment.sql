@@ -0,0 +1,31 @@
+-- +goose Up
+
+-- Create Enrollment table
+create table enrollment
+(
+    id         bigserial
+        primary key,
+    user_id    bigint not null
+        constraint fk_enrollment_user
+            references users
+            on delete cascade,
+    course_id  bigint not null
+        constraint fk_enrollment_course
+            references course
+            on delete cascade,
+    created_at timestamp with time zone default CURRENT_TIMESTAMP,
+    deleted_at timestamp with time zone
+);
+
+create unique index idx_enrollment_user_course
+    on enrollment (user_id, course_id)
+    where deleted_at is null;
+
+create index idx_enrollment_course_id
+    on enrollment (course_id);
+
+create index idx_enrollment_deleted_at
+    on enrollment (deleted_at);
+
+-- +goose Down
+DROP TABLE IF EXISTS enrollment;
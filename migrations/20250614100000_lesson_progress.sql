@@ -0,0 +1,31 @@
+-- +goose Up
+
+-- Create Lesson Progress table
+create table lesson_progress
+(
+    id            bigserial
+        primary key,
+    user_id       bigint      not null
+        constraint fk_lesson_progress_user
+            references users
+            on delete cascade,
+    lesson_id     bigint      not null
+        constraint fk_lesson_progress_lesson
+            references lesson
+            on delete cascade,
+    status        varchar(20) not null default 'not_started',
+    percent       integer     not null default 0,
+    last_position integer     not null default 0,
+    completed_at  timestamp with time zone,
+    created_at    timestamp with time zone default CURRENT_TIMESTAMP,
+    updated_at    timestamp with time zone default CURRENT_TIMESTAMP
+);
+
+create unique index idx_lesson_progress_user_lesson
+    on lesson_progress (user_id, lesson_id);
+
+create index idx_lesson_progress_lesson_id
+    on lesson_progress (lesson_id);
+
+-- +goose Down
+DROP TABLE IF EXISTS lesson_progress;
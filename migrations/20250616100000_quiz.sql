@@ -0,0 +1,118 @@
+-- +goose Up
+
+-- Create Quiz tables
+create table quiz
+(
+    id            bigserial
+        primary key,
+    title         varchar(255) not null,
+    description   text,
+    passing_score integer      not null default 0,
+    lesson_id     bigint       not null
+        constraint fk_lesson_quizzes
+            references lesson
+            on delete cascade,
+    created_by    bigint
+        constraint fk_quiz_created_by
+            references users,
+    created_at    timestamp with time zone default CURRENT_TIMESTAMP,
+    updated_at    timestamp with time zone default CURRENT_TIMESTAMP,
+    deleted_at    timestamp with time zone
+);
+
+create index idx_quiz_lesson_id
+    on quiz (lesson_id);
+
+create index idx_quiz_deleted_at
+    on quiz (deleted_at);
+
+create table quiz_question
+(
+    id             bigserial
+        primary key,
+    quiz_id        bigint      not null
+        constraint fk_quiz_questions
+            references quiz
+            on delete cascade,
+    type           varchar(20) not null,
+    text           text        not null,
+    points         integer     not null default 1,
+    "order"        bigint      not null,
+    correct_number double precision,
+    tolerance      double precision not null default 0,
+    created_at     timestamp with time zone default CURRENT_TIMESTAMP,
+    deleted_at     timestamp with time zone
+);
+
+create index idx_quiz_question_quiz_id
+    on quiz_question (quiz_id);
+
+create index idx_quiz_question_deleted_at
+    on quiz_question (deleted_at);
+
+create table quiz_option
+(
+    id          bigserial
+        primary key,
+    question_id bigint  not null
+        constraint fk_quiz_question_options
+            references quiz_question
+            on delete cascade,
+    text        text    not null,
+    is_correct  boolean not null default false,
+    "order"     bigint  not null
+);
+
+create index idx_quiz_option_question_id
+    on quiz_option (question_id);
+
+create table quiz_attempt
+(
+    id             bigserial
+        primary key,
+    quiz_id        bigint  not null
+        constraint fk_quiz_attempts
+            references quiz
+            on delete cascade,
+    user_id        bigint  not null
+        constraint fk_quiz_attempt_user
+            references users
+            on delete cascade,
+    score          integer not null default 0,
+    max_score      integer not null default 0,
+    passed         boolean not null default false,
+    pending_review boolean not null default false,
+    submitted_at   timestamp with time zone default CURRENT_TIMESTAMP
+);
+
+create index idx_quiz_attempt_quiz_user
+    on quiz_attempt (quiz_id, user_id);
+
+create table quiz_answer
+(
+    id                  bigserial
+        primary key,
+    attempt_id          bigint  not null
+        constraint fk_quiz_attempt_answers
+            references quiz_attempt
+            on delete cascade,
+    question_id         bigint  not null
+        constraint fk_quiz_answer_question
+            references quiz_question
+            on delete cascade,
+    selected_option_ids bigint[],
+    text_answer         text,
+    numeric_answer      double precision,
+    is_correct          boolean,
+    points_awarded      integer not null default 0
+);
+
+create index idx_quiz_answer_attempt_id
+    on quiz_answer (attempt_id);
+
+-- +goose Down
+DROP TABLE IF EXISTS quiz_answer;
+DROP TABLE IF EXISTS quiz_attempt;
+DROP TABLE IF EXISTS quiz_option;
+DROP TABLE IF EXISTS quiz_question;
+DROP TABLE IF EXISTS quiz;
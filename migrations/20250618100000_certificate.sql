@@ -0,0 +1,35 @@
+-- +goose Up
+
+-- Create Certificate table
+create table certificate
+(
+    id          bigserial
+        primary key,
+    code        varchar(32) not null,
+    user_id     bigint      not null
+        constraint fk_certificate_user
+            references users
+            on delete cascade,
+    course_id   bigint      not null
+        constraint fk_certificate_course
+            references course
+            on delete cascade,
+    object_name varchar(255) not null,
+    issued_by   bigint
+        constraint fk_certificate_issued_by
+            references users
+            on delete set null,
+    issued_at   timestamp with time zone default CURRENT_TIMESTAMP not null
+);
+
+create unique index idx_certificate_code
+    on certificate (code);
+
+create unique index idx_certificate_user_course
+    on certificate (user_id, course_id);
+
+create index idx_certificate_course_id
+    on certificate (course_id);
+
+-- +goose Down
+DROP TABLE IF EXISTS certificate;
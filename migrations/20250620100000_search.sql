@@ -0,0 +1,38 @@
+-- +goose Up
+
+-- Full-text search columns. Names weigh more than descriptions, which weigh more than lesson content.
+alter table course
+    add column search_vector tsvector generated always as (
+        setweight(to_tsvector('english', coalesce(name, '')), 'A') ||
+        setweight(to_tsvector('english', coalesce(description, '')), 'B')
+    ) stored;
+
+create index idx_course_search_vector
+    on course using gin (search_vector);
+
+alter table chapter
+    add column search_vector tsvector generated always as (
+        setweight(to_tsvector('english', coalesce(name, '')), 'A') ||
+        setweight(to_tsvector('english', coalesce(description, '')), 'B')
+    ) stored;
+
+create index idx_chapter_search_vector
+    on chapter using gin (search_vector);
+
+alter table lesson
+    add column search_vector tsvector generated always as (
+        setweight(to_tsvector('english', coalesce(name, '')), 'A') ||
+        setweight(to_tsvector('english', coalesce(description, '')), 'B') ||
+        setweight(to_tsvector('english', coalesce(content, '')), 'C')
+    ) stored;
+
+create index idx_lesson_search_vector
+    on lesson using gin (search_vector);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_lesson_search_vector;
+ALTER TABLE lesson DROP COLUMN IF EXISTS search_vector;
+DROP INDEX IF EXISTS idx_chapter_search_vector;
+ALTER TABLE chapter DROP COLUMN IF EXISTS search_vector;
+DROP INDEX IF EXISTS idx_course_search_vector;
+ALTER TABLE course DROP COLUMN IF EXISTS search_vector;
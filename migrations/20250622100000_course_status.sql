@@ -0,0 +1,46 @@
+-- +goose Up
+
+-- Publishing workflow. Existing courses were visible to everyone, so they start out published.
+alter table course
+    add column status       varchar(20) not null default 'draft',
+    add column published_at timestamp with time zone;
+
+update course
+set status       = 'published',
+    published_at = created_at;
+
+alter table course
+    add constraint chk_course_status
+        check (status in ('draft', 'in_review', 'published', 'archived'));
+
+create index idx_course_status
+    on course (status);
+
+-- Create Course Status History table
+create table course_status_history
+(
+    id          bigserial
+        primary key,
+    course_id   bigint      not null
+        constraint fk_course_status_history_course
+            references course
+            on delete cascade,
+    from_status varchar(20) not null,
+    to_status   varchar(20) not null,
+    changed_by  bigint
+        constraint fk_course_status_history_changed_by
+            references users
+            on delete set null,
+    comment     text,
+    created_at  timestamp with time zone default CURRENT_TIMESTAMP
+);
+
+create index idx_course_status_history_course_id
+    on course_status_history (course_id);
+
+-- +goose Down
+DROP TABLE IF EXISTS course_status_history;
+DROP INDEX IF EXISTS idx_course_status;
+ALTER TABLE course DROP CONSTRAINT IF EXISTS chk_course_status;
+ALTER TABLE course DROP COLUMN IF EXISTS published_at;
+ALTER TABLE course DROP COLUMN IF EXISTS status;
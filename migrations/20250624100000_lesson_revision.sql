@@ -0,0 +1,34 @@
+-- +goose Up
+
+-- Create Lesson Revision table. Every write to a lesson stores its full state as a new revision.
+create table lesson_revision
+(
+    id            bigserial
+        primary key,
+    lesson_id     bigint       not null
+        constraint fk_lesson_revision_lesson
+            references lesson
+            on delete cascade,
+    revision      integer      not null,
+    name          varchar(255) not null,
+    description   text,
+    content       text,
+    "order"       integer      not null,
+    restored_from integer,
+    created_by    bigint
+        constraint fk_lesson_revision_created_by
+            references users
+            on delete set null,
+    created_at    timestamp with time zone default CURRENT_TIMESTAMP
+);
+
+create unique index idx_lesson_revision_lesson_revision
+    on lesson_revision (lesson_id, revision);
+
+-- Existing lessons start with their current state as revision 1
+insert into lesson_revision (lesson_id, revision, name, description, content, "order", created_by, created_at)
+select id, 1, name, description, content, "order", created_by, updated_at
+from lesson;
+
+-- +goose Down
+DROP TABLE IF EXISTS lesson_revision;
@@ -0,0 +1,50 @@
+-- +goose Up
+
+-- Create Lesson Block table. Lessons are composed of ordered typed blocks; lesson.content
+-- keeps a markdown rendering of the blocks for search and older clients.
+create table lesson_block
+(
+    id            bigserial
+        primary key,
+    lesson_id     bigint      not null
+        constraint fk_lesson_block_lesson
+            references lesson
+            on delete cascade,
+    position      integer     not null,
+    type          varchar(20) not null
+        constraint chk_lesson_block_type
+            check (type in ('markdown', 'code', 'attachment', 'video', 'callout')),
+    text          text,
+    language      varchar(50),
+    variant       varchar(20),
+    title         varchar(255),
+    url           text,
+    attachment_id bigint
+        constraint fk_lesson_block_attachment
+            references attachment
+            on delete set null,
+    created_by    bigint
+        constraint fk_lesson_block_created_by
+            references users
+            on delete set null,
+    created_at    timestamp with time zone default CURRENT_TIMESTAMP,
+    updated_at    timestamp with time zone default CURRENT_TIMESTAMP,
+    -- Deferred so blocks can swap positions inside one transaction
+    constraint uq_lesson_block_position
+        unique (lesson_id, position) deferrable initially deferred
+);
+
+-- Existing lesson content becomes a single markdown block
+insert into lesson_block (lesson_id, position, type, text, created_by, created_at, updated_at)
+select id, 1, 'markdown', content, created_by, created_at, updated_at
+from lesson
+where content is not null
+  and content <> '';
+
+-- Revisions keep a snapshot of the blocks so restoring brings the structure back
+alter table lesson_revision
+    add column blocks jsonb;
+
+-- +goose Down
+ALTER TABLE lesson_revision DROP COLUMN IF EXISTS blocks;
+DROP TABLE IF EXISTS lesson_block;
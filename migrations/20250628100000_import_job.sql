@@ -0,0 +1,32 @@
+-- +goose Up
+
+-- Background imports of SCORM 1.2 and IMS Common Cartridge packages
+create table import_job
+(
+    id          bigserial
+        primary key,
+    kind        varchar(20),
+    status      varchar(20) not null default 'pending',
+    file_name   varchar(255) not null,
+    course_id   bigint
+        constraint fk_import_job_course
+            references course
+            on delete set null,
+    warnings    jsonb       not null default '[]',
+    error       text,
+    created_by  bigint
+        constraint fk_import_job_created_by
+            references users
+            on delete set null,
+    created_at  timestamp with time zone default CURRENT_TIMESTAMP,
+    started_at  timestamp with time zone,
+    finished_at timestamp with time zone,
+    constraint chk_import_job_status
+        check (status in ('pending', 'running', 'completed', 'failed'))
+);
+
+create index idx_import_job_created_by
+    on import_job (created_by);
+
+-- +goose Down
+DROP TABLE IF EXISTS import_job;
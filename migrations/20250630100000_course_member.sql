@@ -0,0 +1,40 @@
+-- +goose Up
+
+-- Collaborators of a course and their role in it
+create table course_member
+(
+    id         bigserial
+        primary key,
+    course_id  bigint      not null
+        constraint fk_course_member_course
+            references course
+            on delete cascade,
+    user_id    bigint      not null
+        constraint fk_course_member_user
+            references users
+            on delete cascade,
+    role       varchar(30) not null,
+    invited_by bigint
+        constraint fk_course_member_invited_by
+            references users
+            on delete set null,
+    created_at timestamp with time zone default CURRENT_TIMESTAMP,
+    updated_at timestamp with time zone default CURRENT_TIMESTAMP,
+    constraint chk_course_member_role
+        check (role in ('owner', 'co_instructor', 'teaching_assistant', 'viewer'))
+);
+
+create unique index idx_course_member_course_user
+    on course_member (course_id, user_id);
+
+create index idx_course_member_user_id
+    on course_member (user_id);
+
+-- The creators of the existing courses become their owners
+insert into course_member (course_id, user_id, role)
+select id, created_by, 'owner'
+from course
+where created_by is not null;
+
+-- +goose Down
+DROP TABLE IF EXISTS course_member;
@@ -0,0 +1,45 @@
+-- +goose Up
+
+-- Schools sharing the deployment. Users and courses without an organization belong to the
+-- default tenant.
+create table organization
+(
+    id         bigserial
+        primary key,
+    name       varchar(255) not null,
+    slug       varchar(100) not null,
+    created_at timestamp with time zone default CURRENT_TIMESTAMP,
+    updated_at timestamp with time zone default CURRENT_TIMESTAMP,
+    deleted_at timestamp with time zone
+);
+
+create unique index idx_organization_slug
+    on organization (slug)
+    where deleted_at is null;
+
+alter table users
+    add column organization_id bigint
+        constraint fk_users_organization
+            references organization;
+
+create index idx_users_organization_id
+    on users (organization_id);
+
+alter table course
+    add column organization_id bigint
+        constraint fk_course_organization
+            references organization;
+
+create index idx_course_organization_id
+    on course (organization_id);
+
+alter table import_job
+    add column organization_id bigint
+        constraint fk_import_job_organization
+            references organization;
+
+-- +goose Down
+ALTER TABLE import_job DROP COLUMN IF EXISTS organization_id;
+ALTER TABLE course DROP COLUMN IF EXISTS organization_id;
+ALTER TABLE users DROP COLUMN IF EXISTS organization_id;
+DROP TABLE IF EXISTS organization;
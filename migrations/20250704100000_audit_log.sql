@@ -0,0 +1,52 @@
+-- +goose Up
+
+-- Append-only record of the mutating API operations. Actors and resources are not foreign
+-- keys so that entries outlive what they describe.
+create table audit_log
+(
+    id              bigserial
+        primary key,
+    actor_id        bigint,
+    actor_username  varchar(255),
+    organization_id bigint,
+    action          varchar(50)  not null,
+    resource_type   varchar(50)  not null,
+    resource_id     bigint,
+    before          jsonb,
+    after           jsonb,
+    request_id      varchar(100),
+    method          varchar(10)  not null,
+    path            varchar(500) not null,
+    created_at      timestamp with time zone default CURRENT_TIMESTAMP
+);
+
+create index idx_audit_log_created_at
+    on audit_log (created_at);
+
+create index idx_audit_log_resource
+    on audit_log (resource_type, resource_id);
+
+create index idx_audit_log_actor_id
+    on audit_log (actor_id);
+
+create index idx_audit_log_request_id
+    on audit_log (request_id);
+
+-- +goose StatementBegin
+create function audit_log_append_only() returns trigger as
+$$
+begin
+    raise exception 'audit_log is append-only';
+end;
+$$ language plpgsql;
+-- +goose StatementEnd
+
+create trigger trg_audit_log_append_only
+    before update or delete
+    on audit_log
+    for each row
+execute function audit_log_append_only();
+
+-- +goose Down
+DROP TABLE IF EXISTS audit_log;
+DROP FUNCTION IF EXISTS audit_log_append_only();
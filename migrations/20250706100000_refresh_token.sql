@@ -0,0 +1,27 @@
+-- +goose Up
+
+-- Sessions of the local identity provider. The refresh token of a session is rotated on
+-- every refresh, only its SHA-256 is stored.
+create table refresh_token
+(
+    id         bigserial
+        primary key,
+    user_id    bigint      not null
+        constraint fk_refresh_token_user
+            references users
+            on delete cascade,
+    token_hash varchar(64) not null,
+    expires_at timestamp with time zone not null,
+    revoked_at timestamp with time zone,
+    created_at timestamp with time zone default CURRENT_TIMESTAMP,
+    updated_at timestamp with time zone default CURRENT_TIMESTAMP
+);
+
+create unique index idx_refresh_token_token_hash
+    on refresh_token (token_hash);
+
+create index idx_refresh_token_user_id
+    on refresh_token (user_id);
+
+-- +goose Down
+DROP TABLE IF EXISTS refresh_token;
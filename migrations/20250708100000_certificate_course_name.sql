@@ -0,0 +1,29 @@
+-- +goose Up
+
+-- Certificates outlive their course. The course name is kept on the certificate and the
+-- course reference is cleared when the course is purged from the trash.
+alter table certificate
+    add column course_name varchar(255) not null default '';
+
+update certificate
+set course_name = course.name
+from course
+where course.id = certificate.course_id;
+
+alter table certificate
+    alter column course_name drop default,
+    alter column course_id drop not null,
+    drop constraint fk_certificate_course,
+    add constraint fk_certificate_course
+        foreign key (course_id) references course
+            on delete set null;
+
+-- +goose Down
+DELETE FROM certificate WHERE course_id IS NULL;
+ALTER TABLE certificate
+    DROP CONSTRAINT fk_certificate_course,
+    ADD CONSTRAINT fk_certificate_course
+        FOREIGN KEY (course_id) REFERENCES course
+            ON DELETE CASCADE,
+    ALTER COLUMN course_id SET NOT NULL,
+    DROP COLUMN course_name;
@@ -61,39 +61,46 @@ func (_m *ChapterRepositoryInterface) Delete(id uint) error {
 	return r0
 }
 
-// GetByCourseID provides a mock function with given fields: courseID
-func (_m *ChapterRepositoryInterface) GetByCourseID(courseID uint) ([]schemas.ChapterResponseWithLessonsCount, error) {
-	ret := _m.Called(courseID)
+// GetByCourseID provides a mock function with given fields: courseID, params
+func (_m *ChapterRepositoryInterface) GetByCourseID(courseID uint, params schemas.ListParams) ([]schemas.ChapterResponseWithLessonsCount, int64, error) {
+	ret := _m.Called(courseID, params)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetByCourseID")
 	}
 
 	var r0 []schemas.ChapterResponseWithLessonsCount
-	var r1 error
-	if rf, ok := ret.Get(0).(func(uint) ([]schemas.ChapterResponseWithLessonsCount, error)); ok {
-		return rf(courseID)
+	var r1 int64
+	var r2 error
+	if rf, ok := ret.Get(0).(func(uint, schemas.ListParams) ([]schemas.ChapterResponseWithLessonsCount, int64, error)); ok {
+		return rf(courseID, params)
 	}
-	if rf, ok := ret.Get(0).(func(uint) []schemas.ChapterResponseWithLessonsCount); ok {
-		r0 = rf(courseID)
+	if rf, ok := ret.Get(0).(func(uint, schemas.ListParams) []schemas.ChapterResponseWithLessonsCount); ok {
+		r0 = rf(courseID, params)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]schemas.ChapterResponseWithLessonsCount)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(uint) error); ok {
-		r1 = rf(courseID)
+	if rf, ok := ret.Get(1).(func(uint, schemas.ListParams) int64); ok {
+		r1 = rf(courseID, params)
 	} else {
-		r1 = ret.Error(1)
+		r1 = ret.Get(1).(int64)
 	}
 
-	return r0, r1
+	if rf, ok := ret.Get(2).(func(uint, schemas.ListParams) error); ok {
+		r2 = rf(courseID, params)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
 }
 
-// GetByID provides a mock function with given fields: id, courseId
-func (_m *ChapterRepositoryInterface) GetByID(id uint, courseId uint) (models.Chapter, error) {
-	ret := _m.Called(id, courseId)
+// GetByID provides a mock function with given fields: id, courseId, tenant
+func (_m *ChapterRepositoryInterface) GetByID(id uint, courseId uint, tenant *schemas.TenantFilter) (models.Chapter, error) {
+	ret := _m.Called(id, courseId, tenant)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetByID")
@@ -101,17 +108,17 @@ func (_m *ChapterRepositoryInterface) GetByID(id uint, courseId uint) (models.Ch
 
 	var r0 models.Chapter
 	var r1 error
-	if rf, ok := ret.Get(0).(func(uint, uint) (models.Chapter, error)); ok {
-		return rf(id, courseId)
+	if rf, ok := ret.Get(0).(func(uint, uint, *schemas.TenantFilter) (models.Chapter, error)); ok {
+		return rf(id, courseId, tenant)
 	}
-	if rf, ok := ret.Get(0).(func(uint, uint) models.Chapter); ok {
-		r0 = rf(id, courseId)
+	if rf, ok := ret.Get(0).(func(uint, uint, *schemas.TenantFilter) models.Chapter); ok {
+		r0 = rf(id, courseId, tenant)
 	} else {
 		r0 = ret.Get(0).(models.Chapter)
 	}
 
-	if rf, ok := ret.Get(1).(func(uint, uint) error); ok {
-		r1 = rf(id, courseId)
+	if rf, ok := ret.Get(1).(func(uint, uint, *schemas.TenantFilter) error); ok {
+		r1 = rf(id, courseId, tenant)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -119,9 +126,9 @@ func (_m *ChapterRepositoryInterface) GetByID(id uint, courseId uint) (models.Ch
 	return r0, r1
 }
 
-// GetByIDWithLessonsCount provides a mock function with given fields: id, courseID
-func (_m *ChapterRepositoryInterface) GetByIDWithLessonsCount(id uint, courseID uint) (schemas.ChapterResponseWithLessonsCount, error) {
-	ret := _m.Called(id, courseID)
+// GetByIDWithLessonsCount provides a mock function with given fields: id, courseID, tenant
+func (_m *ChapterRepositoryInterface) GetByIDWithLessonsCount(id uint, courseID uint, tenant *schemas.TenantFilter) (schemas.ChapterResponseWithLessonsCount, error) {
+	ret := _m.Called(id, courseID, tenant)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetByIDWithLessonsCount")
@@ -129,17 +136,77 @@ func (_m *ChapterRepositoryInterface) GetByIDWithLessonsCount(id uint, courseID
 
 	var r0 schemas.ChapterResponseWithLessonsCount
 	var r1 error
-	if rf, ok := ret.Get(0).(func(uint, uint) (schemas.ChapterResponseWithLessonsCount, error)); ok {
-		return rf(id, courseID)
+	if rf, ok := ret.Get(0).(func(uint, uint, *schemas.TenantFilter) (schemas.ChapterResponseWithLessonsCount, error)); ok {
+		return rf(id, courseID, tenant)
 	}
-	if rf, ok := ret.Get(0).(func(uint, uint) schemas.ChapterResponseWithLessonsCount); ok {
-		r0 = rf(id, courseID)
+	if rf, ok := ret.Get(0).(func(uint, uint, *schemas.TenantFilter) schemas.ChapterResponseWithLessonsCount); ok {
+		r0 = rf(id, courseID, tenant)
 	} else {
 		r0 = ret.Get(0).(schemas.ChapterResponseWithLessonsCount)
 	}
 
-	if rf, ok := ret.Get(1).(func(uint, uint) error); ok {
-		r1 = rf(id, courseID)
+	if rf, ok := ret.Get(1).(func(uint, uint, *schemas.TenantFilter) error); ok {
+		r1 = rf(id, courseID, tenant)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Move provides a mock function with given fields: chapterID, toCourseID, position
+func (_m *ChapterRepositoryInterface) Move(chapterID uint, toCourseID uint, position int) ([]models.Chapter, error) {
+	ret := _m.Called(chapterID, toCourseID, position)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Move")
+	}
+
+	var r0 []models.Chapter
+	var r1 error
+	if rf, ok := ret.Get(0).(func(uint, uint, int) ([]models.Chapter, error)); ok {
+		return rf(chapterID, toCourseID, position)
+	}
+	if rf, ok := ret.Get(0).(func(uint, uint, int) []models.Chapter); ok {
+		r0 = rf(chapterID, toCourseID, position)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Chapter)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(uint, uint, int) error); ok {
+		r1 = rf(chapterID, toCourseID, position)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Reorder provides a mock function with given fields: courseID, chapterIDs
+func (_m *ChapterRepositoryInterface) Reorder(courseID uint, chapterIDs []uint) ([]models.Chapter, error) {
+	ret := _m.Called(courseID, chapterIDs)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Reorder")
+	}
+
+	var r0 []models.Chapter
+	var r1 error
+	if rf, ok := ret.Get(0).(func(uint, []uint) ([]models.Chapter, error)); ok {
+		return rf(courseID, chapterIDs)
+	}
+	if rf, ok := ret.Get(0).(func(uint, []uint) []models.Chapter); ok {
+		r0 = rf(courseID, chapterIDs)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Chapter)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(uint, []uint) error); ok {
+		r1 = rf(courseID, chapterIDs)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -7,6 +7,8 @@ import (
 
 	mock "github.com/stretchr/testify/mock"
 
+	repos "web/repos"
+
 	schemas "web/schemas"
 )
 
@@ -15,6 +17,34 @@ type CourseRepositoryInterface struct {
 	mock.Mock
 }
 
+// Clone provides a mock function with given fields: source, options
+func (_m *CourseRepositoryInterface) Clone(source models.Course, options repos.CloneOptions) (models.Course, error) {
+	ret := _m.Called(source, options)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Clone")
+	}
+
+	var r0 models.Course
+	var r1 error
+	if rf, ok := ret.Get(0).(func(models.Course, repos.CloneOptions) (models.Course, error)); ok {
+		return rf(source, options)
+	}
+	if rf, ok := ret.Get(0).(func(models.Course, repos.CloneOptions) models.Course); ok {
+		r0 = rf(source, options)
+	} else {
+		r0 = ret.Get(0).(models.Course)
+	}
+
+	if rf, ok := ret.Get(1).(func(models.Course, repos.CloneOptions) error); ok {
+		r1 = rf(source, options)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Create provides a mock function with given fields: course
 func (_m *CourseRepositoryInterface) Create(course models.Course) (models.Course, error) {
 	ret := _m.Called(course)
@@ -61,34 +91,69 @@ func (_m *CourseRepositoryInterface) Delete(id uint) error {
 	return r0
 }
 
-// GetAll provides a mock function with no fields
-func (_m *CourseRepositoryInterface) GetAll() ([]schemas.CourseResponseWithChaptersCount, error) {
-	ret := _m.Called()
+// ExistsByName provides a mock function with given fields: name, organizationID
+func (_m *CourseRepositoryInterface) ExistsByName(name string, organizationID *uint) (bool, error) {
+	ret := _m.Called(name, organizationID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ExistsByName")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, *uint) (bool, error)); ok {
+		return rf(name, organizationID)
+	}
+	if rf, ok := ret.Get(0).(func(string, *uint) bool); ok {
+		r0 = rf(name, organizationID)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(string, *uint) error); ok {
+		r1 = rf(name, organizationID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetAll provides a mock function with given fields: params
+func (_m *CourseRepositoryInterface) GetAll(params schemas.ListParams) ([]schemas.CourseResponseWithChaptersCount, int64, error) {
+	ret := _m.Called(params)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetAll")
 	}
 
 	var r0 []schemas.CourseResponseWithChaptersCount
-	var r1 error
-	if rf, ok := ret.Get(0).(func() ([]schemas.CourseResponseWithChaptersCount, error)); ok {
-		return rf()
+	var r1 int64
+	var r2 error
+	if rf, ok := ret.Get(0).(func(schemas.ListParams) ([]schemas.CourseResponseWithChaptersCount, int64, error)); ok {
+		return rf(params)
 	}
-	if rf, ok := ret.Get(0).(func() []schemas.CourseResponseWithChaptersCount); ok {
-		r0 = rf()
+	if rf, ok := ret.Get(0).(func(schemas.ListParams) []schemas.CourseResponseWithChaptersCount); ok {
+		r0 = rf(params)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]schemas.CourseResponseWithChaptersCount)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func() error); ok {
-		r1 = rf()
+	if rf, ok := ret.Get(1).(func(schemas.ListParams) int64); ok {
+		r1 = rf(params)
 	} else {
-		r1 = ret.Error(1)
+		r1 = ret.Get(1).(int64)
 	}
 
-	return r0, r1
+	if rf, ok := ret.Get(2).(func(schemas.ListParams) error); ok {
+		r2 = rf(params)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
 }
 
 // GetByID provides a mock function with given fields: id
@@ -147,6 +212,99 @@ func (_m *CourseRepositoryInterface) GetByIDWithChaptersCount(id uint) (schemas.
 	return r0, r1
 }
 
+// GetStatusHistory provides a mock function with given fields: courseID
+func (_m *CourseRepositoryInterface) GetStatusHistory(courseID uint) ([]models.CourseStatusHistory, error) {
+	ret := _m.Called(courseID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetStatusHistory")
+	}
+
+	var r0 []models.CourseStatusHistory
+	var r1 error
+	if rf, ok := ret.Get(0).(func(uint) ([]models.CourseStatusHistory, error)); ok {
+		return rf(courseID)
+	}
+	if rf, ok := ret.Get(0).(func(uint) []models.CourseStatusHistory); ok {
+		r0 = rf(courseID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.CourseStatusHistory)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(uint) error); ok {
+		r1 = rf(courseID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTree provides a mock function with given fields: id
+func (_m *CourseRepositoryInterface) GetTree(id uint) (models.Course, error) {
+	ret := _m.Called(id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTree")
+	}
+
+	var r0 models.Course
+	var r1 error
+	if rf, ok := ret.Get(0).(func(uint) (models.Course, error)); ok {
+		return rf(id)
+	}
+	if rf, ok := ret.Get(0).(func(uint) models.Course); ok {
+		r0 = rf(id)
+	} else {
+		r0 = ret.Get(0).(models.Course)
+	}
+
+	if rf, ok := ret.Get(1).(func(uint) error); ok {
+		r1 = rf(id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Import provides a mock function with given fields: source, options
+func (_m *CourseRepositoryInterface) Import(source models.Course, options repos.CloneOptions) (models.Course, schemas.CourseIDMap, error) {
+	ret := _m.Called(source, options)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Import")
+	}
+
+	var r0 models.Course
+	var r1 schemas.CourseIDMap
+	var r2 error
+	if rf, ok := ret.Get(0).(func(models.Course, repos.CloneOptions) (models.Course, schemas.CourseIDMap, error)); ok {
+		return rf(source, options)
+	}
+	if rf, ok := ret.Get(0).(func(models.Course, repos.CloneOptions) models.Course); ok {
+		r0 = rf(source, options)
+	} else {
+		r0 = ret.Get(0).(models.Course)
+	}
+
+	if rf, ok := ret.Get(1).(func(models.Course, repos.CloneOptions) schemas.CourseIDMap); ok {
+		r1 = rf(source, options)
+	} else {
+		r1 = ret.Get(1).(schemas.CourseIDMap)
+	}
+
+	if rf, ok := ret.Get(2).(func(models.Course, repos.CloneOptions) error); ok {
+		r2 = rf(source, options)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
 // Update provides a mock function with given fields: course, courseRequest
 func (_m *CourseRepositoryInterface) Update(course models.Course, courseRequest schemas.UpdateCourseRequest) (models.Course, error) {
 	ret := _m.Called(course, courseRequest)
@@ -175,6 +333,34 @@ func (_m *CourseRepositoryInterface) Update(course models.Course, courseRequest
 	return r0, r1
 }
 
+// UpdateStatus provides a mock function with given fields: course, history
+func (_m *CourseRepositoryInterface) UpdateStatus(course models.Course, history models.CourseStatusHistory) (models.Course, error) {
+	ret := _m.Called(course, history)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateStatus")
+	}
+
+	var r0 models.Course
+	var r1 error
+	if rf, ok := ret.Get(0).(func(models.Course, models.CourseStatusHistory) (models.Course, error)); ok {
+		return rf(course, history)
+	}
+	if rf, ok := ret.Get(0).(func(models.Course, models.CourseStatusHistory) models.Course); ok {
+		r0 = rf(course, history)
+	} else {
+		r0 = ret.Get(0).(models.Course)
+	}
+
+	if rf, ok := ret.Get(1).(func(models.Course, models.CourseStatusHistory) error); ok {
+		r1 = rf(course, history)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // NewCourseRepositoryInterface creates a new instance of CourseRepositoryInterface. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewCourseRepositoryInterface(t interface {
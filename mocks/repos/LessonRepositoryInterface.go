@@ -61,29 +61,29 @@ func (_m *LessonRepositoryInterface) Delete(id uint) error {
 	return r0
 }
 
-// GetByChapterID provides a mock function with given fields: chapterID, courseID
-func (_m *LessonRepositoryInterface) GetByChapterID(chapterID uint, courseID uint) ([]schemas.LessonResponse, error) {
-	ret := _m.Called(chapterID, courseID)
+// GetBlocks provides a mock function with given fields: lessonID
+func (_m *LessonRepositoryInterface) GetBlocks(lessonID uint) ([]models.LessonBlock, error) {
+	ret := _m.Called(lessonID)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetByChapterID")
+		panic("no return value specified for GetBlocks")
 	}
 
-	var r0 []schemas.LessonResponse
+	var r0 []models.LessonBlock
 	var r1 error
-	if rf, ok := ret.Get(0).(func(uint, uint) ([]schemas.LessonResponse, error)); ok {
-		return rf(chapterID, courseID)
+	if rf, ok := ret.Get(0).(func(uint) ([]models.LessonBlock, error)); ok {
+		return rf(lessonID)
 	}
-	if rf, ok := ret.Get(0).(func(uint, uint) []schemas.LessonResponse); ok {
-		r0 = rf(chapterID, courseID)
+	if rf, ok := ret.Get(0).(func(uint) []models.LessonBlock); ok {
+		r0 = rf(lessonID)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]schemas.LessonResponse)
+			r0 = ret.Get(0).([]models.LessonBlock)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(uint, uint) error); ok {
-		r1 = rf(chapterID, courseID)
+	if rf, ok := ret.Get(1).(func(uint) error); ok {
+		r1 = rf(lessonID)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -91,9 +91,46 @@ func (_m *LessonRepositoryInterface) GetByChapterID(chapterID uint, courseID uin
 	return r0, r1
 }
 
-// GetByID provides a mock function with given fields: courseID, chapterID, id
-func (_m *LessonRepositoryInterface) GetByID(courseID uint, chapterID uint, id uint) (models.Lesson, error) {
-	ret := _m.Called(courseID, chapterID, id)
+// GetByChapterID provides a mock function with given fields: courseID, chapterID, params
+func (_m *LessonRepositoryInterface) GetByChapterID(courseID uint, chapterID uint, params schemas.ListParams) ([]schemas.LessonResponse, int64, error) {
+	ret := _m.Called(courseID, chapterID, params)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByChapterID")
+	}
+
+	var r0 []schemas.LessonResponse
+	var r1 int64
+	var r2 error
+	if rf, ok := ret.Get(0).(func(uint, uint, schemas.ListParams) ([]schemas.LessonResponse, int64, error)); ok {
+		return rf(courseID, chapterID, params)
+	}
+	if rf, ok := ret.Get(0).(func(uint, uint, schemas.ListParams) []schemas.LessonResponse); ok {
+		r0 = rf(courseID, chapterID, params)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]schemas.LessonResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(uint, uint, schemas.ListParams) int64); ok {
+		r1 = rf(courseID, chapterID, params)
+	} else {
+		r1 = ret.Get(1).(int64)
+	}
+
+	if rf, ok := ret.Get(2).(func(uint, uint, schemas.ListParams) error); ok {
+		r2 = rf(courseID, chapterID, params)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetByID provides a mock function with given fields: courseID, chapterID, id, tenant
+func (_m *LessonRepositoryInterface) GetByID(courseID uint, chapterID uint, id uint, tenant *schemas.TenantFilter) (models.Lesson, error) {
+	ret := _m.Called(courseID, chapterID, id, tenant)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetByID")
@@ -101,17 +138,17 @@ func (_m *LessonRepositoryInterface) GetByID(courseID uint, chapterID uint, id u
 
 	var r0 models.Lesson
 	var r1 error
-	if rf, ok := ret.Get(0).(func(uint, uint, uint) (models.Lesson, error)); ok {
-		return rf(courseID, chapterID, id)
+	if rf, ok := ret.Get(0).(func(uint, uint, uint, *schemas.TenantFilter) (models.Lesson, error)); ok {
+		return rf(courseID, chapterID, id, tenant)
 	}
-	if rf, ok := ret.Get(0).(func(uint, uint, uint) models.Lesson); ok {
-		r0 = rf(courseID, chapterID, id)
+	if rf, ok := ret.Get(0).(func(uint, uint, uint, *schemas.TenantFilter) models.Lesson); ok {
+		r0 = rf(courseID, chapterID, id, tenant)
 	} else {
 		r0 = ret.Get(0).(models.Lesson)
 	}
 
-	if rf, ok := ret.Get(1).(func(uint, uint, uint) error); ok {
-		r1 = rf(courseID, chapterID, id)
+	if rf, ok := ret.Get(1).(func(uint, uint, uint, *schemas.TenantFilter) error); ok {
+		r1 = rf(courseID, chapterID, id, tenant)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -119,22 +156,236 @@ func (_m *LessonRepositoryInterface) GetByID(courseID uint, chapterID uint, id u
 	return r0, r1
 }
 
-// Update provides a mock function with given fields: lesson
-func (_m *LessonRepositoryInterface) Update(lesson models.Lesson) error {
-	ret := _m.Called(lesson)
+// GetCourseID provides a mock function with given fields: lessonID
+func (_m *LessonRepositoryInterface) GetCourseID(lessonID uint) (uint, error) {
+	ret := _m.Called(lessonID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetCourseID")
+	}
+
+	var r0 uint
+	var r1 error
+	if rf, ok := ret.Get(0).(func(uint) (uint, error)); ok {
+		return rf(lessonID)
+	}
+	if rf, ok := ret.Get(0).(func(uint) uint); ok {
+		r0 = rf(lessonID)
+	} else {
+		r0 = ret.Get(0).(uint)
+	}
+
+	if rf, ok := ret.Get(1).(func(uint) error); ok {
+		r1 = rf(lessonID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetCurrentRevision provides a mock function with given fields: lessonID
+func (_m *LessonRepositoryInterface) GetCurrentRevision(lessonID uint) (int, error) {
+	ret := _m.Called(lessonID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetCurrentRevision")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(uint) (int, error)); ok {
+		return rf(lessonID)
+	}
+	if rf, ok := ret.Get(0).(func(uint) int); ok {
+		r0 = rf(lessonID)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(uint) error); ok {
+		r1 = rf(lessonID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetOrganizationID provides a mock function with given fields: lessonID
+func (_m *LessonRepositoryInterface) GetOrganizationID(lessonID uint) (*uint, error) {
+	ret := _m.Called(lessonID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetOrganizationID")
+	}
+
+	var r0 *uint
+	var r1 error
+	if rf, ok := ret.Get(0).(func(uint) (*uint, error)); ok {
+		return rf(lessonID)
+	}
+	if rf, ok := ret.Get(0).(func(uint) *uint); ok {
+		r0 = rf(lessonID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*uint)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(uint) error); ok {
+		r1 = rf(lessonID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetRevision provides a mock function with given fields: lessonID, revision
+func (_m *LessonRepositoryInterface) GetRevision(lessonID uint, revision int) (models.LessonRevision, error) {
+	ret := _m.Called(lessonID, revision)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetRevision")
+	}
+
+	var r0 models.LessonRevision
+	var r1 error
+	if rf, ok := ret.Get(0).(func(uint, int) (models.LessonRevision, error)); ok {
+		return rf(lessonID, revision)
+	}
+	if rf, ok := ret.Get(0).(func(uint, int) models.LessonRevision); ok {
+		r0 = rf(lessonID, revision)
+	} else {
+		r0 = ret.Get(0).(models.LessonRevision)
+	}
+
+	if rf, ok := ret.Get(1).(func(uint, int) error); ok {
+		r1 = rf(lessonID, revision)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetRevisions provides a mock function with given fields: lessonID
+func (_m *LessonRepositoryInterface) GetRevisions(lessonID uint) ([]models.LessonRevision, error) {
+	ret := _m.Called(lessonID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetRevisions")
+	}
+
+	var r0 []models.LessonRevision
+	var r1 error
+	if rf, ok := ret.Get(0).(func(uint) ([]models.LessonRevision, error)); ok {
+		return rf(lessonID)
+	}
+	if rf, ok := ret.Get(0).(func(uint) []models.LessonRevision); ok {
+		r0 = rf(lessonID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.LessonRevision)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(uint) error); ok {
+		r1 = rf(lessonID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Move provides a mock function with given fields: lessonID, toCourseID, toChapterID, position
+func (_m *LessonRepositoryInterface) Move(lessonID uint, toCourseID uint, toChapterID uint, position int) ([]models.Lesson, error) {
+	ret := _m.Called(lessonID, toCourseID, toChapterID, position)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Move")
+	}
+
+	var r0 []models.Lesson
+	var r1 error
+	if rf, ok := ret.Get(0).(func(uint, uint, uint, int) ([]models.Lesson, error)); ok {
+		return rf(lessonID, toCourseID, toChapterID, position)
+	}
+	if rf, ok := ret.Get(0).(func(uint, uint, uint, int) []models.Lesson); ok {
+		r0 = rf(lessonID, toCourseID, toChapterID, position)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Lesson)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(uint, uint, uint, int) error); ok {
+		r1 = rf(lessonID, toCourseID, toChapterID, position)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Reorder provides a mock function with given fields: chapterID, lessonIDs
+func (_m *LessonRepositoryInterface) Reorder(chapterID uint, lessonIDs []uint) ([]models.Lesson, error) {
+	ret := _m.Called(chapterID, lessonIDs)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Reorder")
+	}
+
+	var r0 []models.Lesson
+	var r1 error
+	if rf, ok := ret.Get(0).(func(uint, []uint) ([]models.Lesson, error)); ok {
+		return rf(chapterID, lessonIDs)
+	}
+	if rf, ok := ret.Get(0).(func(uint, []uint) []models.Lesson); ok {
+		r0 = rf(chapterID, lessonIDs)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Lesson)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(uint, []uint) error); ok {
+		r1 = rf(chapterID, lessonIDs)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Update provides a mock function with given fields: lesson, revision
+func (_m *LessonRepositoryInterface) Update(lesson models.Lesson, revision models.LessonRevision) (models.LessonRevision, error) {
+	ret := _m.Called(lesson, revision)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Update")
 	}
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func(models.Lesson) error); ok {
-		r0 = rf(lesson)
+	var r0 models.LessonRevision
+	var r1 error
+	if rf, ok := ret.Get(0).(func(models.Lesson, models.LessonRevision) (models.LessonRevision, error)); ok {
+		return rf(lesson, revision)
+	}
+	if rf, ok := ret.Get(0).(func(models.Lesson, models.LessonRevision) models.LessonRevision); ok {
+		r0 = rf(lesson, revision)
 	} else {
-		r0 = ret.Error(0)
+		r0 = ret.Get(0).(models.LessonRevision)
 	}
 
-	return r0
+	if rf, ok := ret.Get(1).(func(models.Lesson, models.LessonRevision) error); ok {
+		r1 = rf(lesson, revision)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
 }
 
 // NewLessonRepositoryInterface creates a new instance of LessonRepositoryInterface. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
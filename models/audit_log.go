@@ -0,0 +1,30 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// AuditLog records a create, update or delete made through the API. Before and After are
+// the JSON state of the resource around the operation, nil when it did not exist.
+// swagger:model
+type AuditLog struct {
+	tableName      struct{}        `gorm:"table:audit_log"`
+	ID             uint            `gorm:"primaryKey" json:"id" example:"1"`
+	ActorID        *uint           `gorm:"column:actor_id" json:"actor_id,omitempty" example:"1"`
+	ActorUsername  string          `gorm:"type:varchar(255)" json:"actor_username,omitempty" example:"johndoe"`
+	OrganizationID *uint           `gorm:"column:organization_id" json:"organization_id,omitempty" example:"1"`
+	Action         string          `gorm:"type:varchar(50);not null" json:"action" example:"update"`
+	ResourceType   string          `gorm:"type:varchar(50);not null" json:"resource_type" example:"course"`
+	ResourceID     *uint           `gorm:"column:resource_id" json:"resource_id,omitempty" example:"1"`
+	Before         json.RawMessage `gorm:"type:jsonb;serializer:json" json:"before,omitempty" swaggertype:"object"`
+	After          json.RawMessage `gorm:"type:jsonb;serializer:json" json:"after,omitempty" swaggertype:"object"`
+	RequestID      string          `gorm:"type:varchar(100)" json:"request_id,omitempty" example:"4f8c2a9e1b7d3c6a"`
+	Method         string          `gorm:"type:varchar(10);not null" json:"method" example:"PUT"`
+	Path           string          `gorm:"type:varchar(500);not null" json:"path" example:"/api/v1/courses/1"`
+	CreatedAt      time.Time       `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
+}
+
+func (AuditLog) TableName() string {
+	return "audit_log"
+}
@@ -0,0 +1,26 @@
+package models
+
+import (
+	"time"
+)
+
+// Certificate represents a course completion certificate issued to a user. CourseName is
+// the name of the course at issue time, CourseID is nil once the course was purged.
+// swagger:model
+type Certificate struct {
+	tableName  struct{}  `gorm:"table:certificate"`
+	ID         uint      `gorm:"primaryKey" json:"id,omitempty" example:"1"`
+	Code       string    `gorm:"type:varchar(32);not null;uniqueIndex" json:"code" example:"9F3A-11C2-7B0D-E4A5"`
+	UserID     uint      `gorm:"not null" json:"user_id,omitempty" example:"1"`
+	User       User      `gorm:"foreignKey:UserID" json:"user,omitempty"`
+	CourseID   *uint     `gorm:"column:course_id" json:"course_id,omitempty" example:"1"`
+	Course     Course    `gorm:"foreignKey:CourseID" json:"course,omitempty"`
+	CourseName string    `gorm:"type:varchar(255);not null" json:"course_name" example:"Introduction to Go Programming"`
+	ObjectName string    `gorm:"type:varchar(255);not null" json:"-"`
+	IssuedBy   *uint     `gorm:"column:issued_by" json:"issued_by,omitempty"`
+	IssuedAt   time.Time `gorm:"default:CURRENT_TIMESTAMP" json:"issued_at,omitempty"`
+}
+
+func (Certificate) TableName() string {
+	return "certificate"
+}
@@ -0,0 +1,31 @@
+package models
+
+import (
+	"time"
+)
+
+const (
+	CourseMemberRoleOwner             = "owner"
+	CourseMemberRoleCoInstructor      = "co_instructor"
+	CourseMemberRoleTeachingAssistant = "teaching_assistant"
+	CourseMemberRoleViewer            = "viewer"
+)
+
+// CourseMember is a user collaborating on a course. The creator of a course is its owner,
+// the other members are invited by the owner.
+// swagger:model
+type CourseMember struct {
+	tableName struct{}  `gorm:"table:course_member"`
+	ID        uint      `gorm:"primaryKey" json:"id,omitempty" example:"1"`
+	CourseID  uint      `gorm:"not null" json:"course_id" example:"1"`
+	UserID    uint      `gorm:"not null" json:"user_id" example:"2"`
+	User      User      `gorm:"foreignKey:UserID" json:"user,omitempty"`
+	Role      string    `gorm:"type:varchar(30);not null" json:"role" example:"co_instructor"`
+	InvitedBy *uint     `gorm:"column:invited_by" json:"invited_by,omitempty" example:"1"`
+	CreatedAt time.Time `gorm:"default:CURRENT_TIMESTAMP" json:"created_at,omitempty"`
+	UpdatedAt time.Time `gorm:"default:CURRENT_TIMESTAMP" json:"updated_at,omitempty"`
+}
+
+func (CourseMember) TableName() string {
+	return "course_member"
+}
@@ -0,0 +1,23 @@
+package models
+
+import (
+	"time"
+)
+
+// CourseStatusHistory records a transition of a course through the publishing workflow
+// swagger:model
+type CourseStatusHistory struct {
+	tableName  struct{}  `gorm:"table:course_status_history"`
+	ID         uint      `gorm:"primaryKey" json:"id,omitempty" example:"1"`
+	CourseID   uint      `gorm:"not null" json:"course_id" example:"1"`
+	FromStatus string    `gorm:"type:varchar(20);not null" json:"from_status" example:"draft"`
+	ToStatus   string    `gorm:"type:varchar(20);not null" json:"to_status" example:"in_review"`
+	ChangedBy  *uint     `gorm:"column:changed_by" json:"changed_by,omitempty" example:"1"`
+	Changer    *User     `gorm:"foreignKey:ChangedBy" json:"changer,omitempty"`
+	Comment    string    `gorm:"type:text" json:"comment,omitempty" example:"Ready for review"`
+	CreatedAt  time.Time `gorm:"default:CURRENT_TIMESTAMP" json:"created_at,omitempty"`
+}
+
+func (CourseStatusHistory) TableName() string {
+	return "course_status_history"
+}
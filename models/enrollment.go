@@ -0,0 +1,24 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Enrollment represents a user's enrollment in a course
+// swagger:model
+type Enrollment struct {
+	tableName struct{}       `gorm:"table:enrollment"`
+	ID        uint           `gorm:"primaryKey" json:"id,omitempty" example:"1"`
+	UserID    uint           `gorm:"not null" json:"user_id,omitempty" example:"1"`
+	User      User           `gorm:"foreignKey:UserID" json:"user,omitempty"`
+	CourseID  uint           `gorm:"not null" json:"course_id,omitempty" example:"1"`
+	Course    Course         `gorm:"foreignKey:CourseID" json:"course,omitempty"`
+	CreatedAt time.Time      `gorm:"default:CURRENT_TIMESTAMP" json:"created_at,omitempty"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+func (Enrollment) TableName() string {
+	return "enrollment"
+}
@@ -0,0 +1,46 @@
+package models
+
+import (
+	"time"
+)
+
+const (
+	ImportJobStatusPending   = "pending"
+	ImportJobStatusRunning   = "running"
+	ImportJobStatusCompleted = "completed"
+	ImportJobStatusFailed    = "failed"
+)
+
+const (
+	ImportJobKindSCORM12         = "scorm_1_2"
+	ImportJobKindCommonCartridge = "common_cartridge"
+)
+
+// ImportJob is a background import of a content package into a new course
+// swagger:model
+type ImportJob struct {
+	tableName      struct{}           `gorm:"table:import_job"`
+	ID             uint               `gorm:"primaryKey" json:"id,omitempty" example:"1"`
+	Kind           string             `gorm:"type:varchar(20)" json:"kind,omitempty" example:"scorm_1_2"`
+	Status         string             `gorm:"type:varchar(20);not null" json:"status" example:"completed"`
+	FileName       string             `gorm:"type:varchar(255);not null" json:"file_name" example:"vendor-course.zip"`
+	CourseID       *uint              `gorm:"column:course_id" json:"course_id,omitempty" example:"1"`
+	Warnings       []ImportJobWarning `gorm:"type:jsonb;serializer:json" json:"warnings"`
+	Error          string             `gorm:"type:text" json:"error,omitempty"`
+	CreatedBy      *uint              `gorm:"column:created_by" json:"created_by,omitempty" example:"1"`
+	OrganizationID *uint              `gorm:"column:organization_id" json:"organization_id,omitempty" example:"1"`
+	CreatedAt      time.Time          `gorm:"default:CURRENT_TIMESTAMP" json:"created_at,omitempty"`
+	StartedAt      *time.Time         `json:"started_at,omitempty"`
+	FinishedAt     *time.Time         `json:"finished_at,omitempty"`
+}
+
+// ImportJobWarning reports a package item that was skipped or only partly imported
+type ImportJobWarning struct {
+	Item    string `json:"item,omitempty" example:"ITEM-3"`
+	Title   string `json:"title,omitempty" example:"Final quiz"`
+	Message string `json:"message" example:"assessments are not supported"`
+}
+
+func (ImportJob) TableName() string {
+	return "import_job"
+}
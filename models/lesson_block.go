@@ -0,0 +1,45 @@
+package models
+
+import (
+	"time"
+)
+
+const (
+	LessonBlockTypeMarkdown   = "markdown"
+	LessonBlockTypeCode       = "code"
+	LessonBlockTypeAttachment = "attachment"
+	LessonBlockTypeVideo      = "video"
+	LessonBlockTypeCallout    = "callout"
+)
+
+const (
+	CalloutVariantInfo    = "info"
+	CalloutVariantTip     = "tip"
+	CalloutVariantWarning = "warning"
+	CalloutVariantDanger  = "danger"
+)
+
+// LessonBlock is one typed piece of lesson content. Which fields are used depends on the type:
+// markdown and callout use Text, code uses Text and Language, video uses URL,
+// attachment uses AttachmentID. Title is an optional caption.
+// swagger:model
+type LessonBlock struct {
+	tableName    struct{}  `gorm:"table:lesson_block"`
+	ID           uint      `gorm:"primaryKey" json:"id,omitempty" example:"1"`
+	LessonID     uint      `gorm:"not null" json:"lesson_id,omitempty" example:"1"`
+	Position     int       `gorm:"not null" json:"position" example:"1"`
+	Type         string    `gorm:"type:varchar(20);not null" json:"type" example:"code"`
+	Text         string    `gorm:"type:text" json:"text,omitempty" example:"fmt.Println(\"Hello\")"`
+	Language     string    `gorm:"type:varchar(50)" json:"language,omitempty" example:"go"`
+	Variant      string    `gorm:"type:varchar(20)" json:"variant,omitempty" example:"info"`
+	Title        string    `gorm:"type:varchar(255)" json:"title,omitempty" example:"Hello world"`
+	URL          string    `gorm:"column:url;type:text" json:"url,omitempty" example:"https://www.youtube.com/watch?v=dQw4w9WgXcQ"`
+	AttachmentID *uint     `gorm:"column:attachment_id" json:"attachment_id,omitempty" example:"1"`
+	CreatedBy    *uint     `gorm:"column:created_by" json:"created_by,omitempty"`
+	CreatedAt    time.Time `gorm:"default:CURRENT_TIMESTAMP" json:"created_at,omitempty"`
+	UpdatedAt    time.Time `gorm:"default:CURRENT_TIMESTAMP" json:"updated_at,omitempty"`
+}
+
+func (LessonBlock) TableName() string {
+	return "lesson_block"
+}
@@ -0,0 +1,32 @@
+package models
+
+import (
+	"time"
+)
+
+const (
+	ProgressStatusNotStarted = "not_started"
+	ProgressStatusInProgress = "in_progress"
+	ProgressStatusCompleted  = "completed"
+)
+
+// LessonProgress represents a user's progress through a lesson
+// swagger:model
+type LessonProgress struct {
+	tableName    struct{}   `gorm:"table:lesson_progress"`
+	ID           uint       `gorm:"primaryKey" json:"id,omitempty" example:"1"`
+	UserID       uint       `gorm:"not null" json:"user_id,omitempty" example:"1"`
+	User         User       `gorm:"foreignKey:UserID" json:"user,omitempty"`
+	LessonID     uint       `gorm:"not null" json:"lesson_id,omitempty" example:"1"`
+	Lesson       Lesson     `gorm:"foreignKey:LessonID" json:"lesson,omitempty"`
+	Status       string     `gorm:"type:varchar(20);not null;default:not_started" json:"status" example:"in_progress"`
+	Percent      int        `gorm:"not null;default:0" json:"percent" example:"50"`
+	LastPosition int        `gorm:"not null;default:0" json:"last_position" example:"120"`
+	CompletedAt  *time.Time `json:"completed_at,omitempty"`
+	CreatedAt    time.Time  `gorm:"default:CURRENT_TIMESTAMP" json:"created_at,omitempty"`
+	UpdatedAt    time.Time  `gorm:"default:CURRENT_TIMESTAMP" json:"updated_at,omitempty"`
+}
+
+func (LessonProgress) TableName() string {
+	return "lesson_progress"
+}
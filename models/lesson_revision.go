@@ -0,0 +1,27 @@
+package models
+
+import (
+	"time"
+)
+
+// LessonRevision is an immutable snapshot of a lesson written on every change
+// swagger:model
+type LessonRevision struct {
+	tableName    struct{}      `gorm:"table:lesson_revision"`
+	ID           uint          `gorm:"primaryKey" json:"id,omitempty" example:"1"`
+	LessonID     uint          `gorm:"not null" json:"lesson_id" example:"1"`
+	Revision     int           `gorm:"not null" json:"revision" example:"3"`
+	Name         string        `gorm:"type:varchar(255);not null" json:"name" example:"Lesson 1: Introduction"`
+	Description  string        `gorm:"type:text" json:"description" example:"Overview of the chapter content"`
+	Content      string        `gorm:"type:text" json:"content" example:"This lesson covers the basic concepts of the chapter."`
+	Order        int           `gorm:"not null" json:"order" example:"1"`
+	Blocks       []LessonBlock `gorm:"type:jsonb;serializer:json" json:"blocks,omitempty"`
+	RestoredFrom *int          `gorm:"column:restored_from" json:"restored_from,omitempty" example:"1"`
+	CreatedBy    *uint         `gorm:"column:created_by" json:"created_by,omitempty"`
+	Creator      *User         `gorm:"foreignKey:CreatedBy" json:"creator,omitempty"`
+	CreatedAt    time.Time     `gorm:"default:CURRENT_TIMESTAMP" json:"created_at,omitempty"`
+}
+
+func (LessonRevision) TableName() string {
+	return "lesson_revision"
+}
@@ -0,0 +1,24 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Organization is a school hosted on the deployment. Its users only see its courses.
+// The slug is the value of the organization claim in the tokens of its users.
+// swagger:model
+type Organization struct {
+	tableName struct{}       `gorm:"table:organization"`
+	ID        uint           `gorm:"primaryKey" json:"id,omitempty" example:"1"`
+	Name      string         `gorm:"type:varchar(255);not null" json:"name" example:"Springfield High School"`
+	Slug      string         `gorm:"type:varchar(100);not null" json:"slug" example:"springfield-high"`
+	CreatedAt time.Time      `gorm:"default:CURRENT_TIMESTAMP" json:"created_at,omitempty"`
+	UpdatedAt time.Time      `gorm:"default:CURRENT_TIMESTAMP" json:"updated_at,omitempty"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+func (Organization) TableName() string {
+	return "organization"
+}
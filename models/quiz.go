@@ -0,0 +1,110 @@
+package models
+
+import (
+	"time"
+
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+)
+
+const (
+	QuestionTypeSingleChoice   = "single_choice"
+	QuestionTypeMultipleChoice = "multiple_choice"
+	QuestionTypeFreeText       = "free_text"
+	QuestionTypeNumeric        = "numeric"
+)
+
+// Quiz represents an assessment attached to a lesson
+// swagger:model
+type Quiz struct {
+	tableName    struct{}       `gorm:"table:quiz"`
+	ID           uint           `gorm:"primaryKey" json:"id,omitempty" example:"1"`
+	Title        string         `gorm:"type:varchar(255);not null" json:"title" example:"Chapter 1 Quiz"`
+	Description  string         `gorm:"type:text" json:"description" example:"Check your understanding of the basics"`
+	PassingScore int            `gorm:"not null;default:0" json:"passing_score" example:"70"`
+	LessonID     uint           `gorm:"not null" json:"lesson_id,omitempty" example:"1"`
+	Lesson       Lesson         `gorm:"foreignKey:LessonID" json:"lesson,omitempty"`
+	CreatedBy    *uint          `gorm:"column:created_by" json:"created_by,omitempty"`
+	Creator      *User          `gorm:"foreignKey:CreatedBy" json:"creator,omitempty"`
+	Questions    []QuizQuestion `gorm:"foreignKey:QuizID;constraint:OnDelete:CASCADE" json:"questions,omitempty"`
+	CreatedAt    time.Time      `gorm:"default:CURRENT_TIMESTAMP" json:"created_at,omitempty"`
+	UpdatedAt    time.Time      `gorm:"default:CURRENT_TIMESTAMP" json:"updated_at,omitempty"`
+	DeletedAt    gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+func (Quiz) TableName() string {
+	return "quiz"
+}
+
+// QuizQuestion represents a single question of a quiz
+// swagger:model
+type QuizQuestion struct {
+	tableName     struct{}       `gorm:"table:quiz_question"`
+	ID            uint           `gorm:"primaryKey" json:"id,omitempty" example:"1"`
+	QuizID        uint           `gorm:"not null" json:"quiz_id,omitempty" example:"1"`
+	Type          string         `gorm:"type:varchar(20);not null" json:"type" example:"single_choice"`
+	Text          string         `gorm:"type:text;not null" json:"text" example:"Which keyword declares a variable?"`
+	Points        int            `gorm:"not null;default:1" json:"points" example:"1"`
+	Order         int            `gorm:"not null" json:"order" example:"1"`
+	CorrectNumber *float64       `json:"correct_number,omitempty" example:"42"`
+	Tolerance     float64        `gorm:"not null;default:0" json:"tolerance" example:"0.5"`
+	Options       []QuizOption   `gorm:"foreignKey:QuestionID;constraint:OnDelete:CASCADE" json:"options,omitempty"`
+	CreatedAt     time.Time      `gorm:"default:CURRENT_TIMESTAMP" json:"created_at,omitempty"`
+	DeletedAt     gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+func (QuizQuestion) TableName() string {
+	return "quiz_question"
+}
+
+// QuizOption represents an answer option of a choice question
+// swagger:model
+type QuizOption struct {
+	tableName  struct{} `gorm:"table:quiz_option"`
+	ID         uint     `gorm:"primaryKey" json:"id,omitempty" example:"1"`
+	QuestionID uint     `gorm:"not null" json:"question_id,omitempty" example:"1"`
+	Text       string   `gorm:"type:text;not null" json:"text" example:"var"`
+	IsCorrect  bool     `gorm:"not null;default:false" json:"is_correct" example:"true"`
+	Order      int      `gorm:"not null" json:"order" example:"1"`
+}
+
+func (QuizOption) TableName() string {
+	return "quiz_option"
+}
+
+// QuizAttempt represents a user's graded submission of a quiz
+// swagger:model
+type QuizAttempt struct {
+	tableName     struct{}     `gorm:"table:quiz_attempt"`
+	ID            uint         `gorm:"primaryKey" json:"id,omitempty" example:"1"`
+	QuizID        uint         `gorm:"not null" json:"quiz_id,omitempty" example:"1"`
+	UserID        uint         `gorm:"not null" json:"user_id,omitempty" example:"1"`
+	Score         int          `gorm:"not null;default:0" json:"score" example:"8"`
+	MaxScore      int          `gorm:"not null;default:0" json:"max_score" example:"10"`
+	Passed        bool         `gorm:"not null;default:false" json:"passed" example:"true"`
+	PendingReview bool         `gorm:"not null;default:false" json:"pending_review" example:"false"`
+	Answers       []QuizAnswer `gorm:"foreignKey:AttemptID;constraint:OnDelete:CASCADE" json:"answers,omitempty"`
+	SubmittedAt   time.Time    `gorm:"default:CURRENT_TIMESTAMP" json:"submitted_at,omitempty"`
+}
+
+func (QuizAttempt) TableName() string {
+	return "quiz_attempt"
+}
+
+// QuizAnswer represents the answer given to one question within an attempt
+// swagger:model
+type QuizAnswer struct {
+	tableName         struct{}      `gorm:"table:quiz_answer"`
+	ID                uint          `gorm:"primaryKey" json:"id,omitempty" example:"1"`
+	AttemptID         uint          `gorm:"not null" json:"attempt_id,omitempty" example:"1"`
+	QuestionID        uint          `gorm:"not null" json:"question_id,omitempty" example:"1"`
+	SelectedOptionIDs pq.Int64Array `gorm:"type:bigint[]" json:"selected_option_ids,omitempty"`
+	TextAnswer        string        `gorm:"type:text" json:"text_answer,omitempty"`
+	NumericAnswer     *float64      `json:"numeric_answer,omitempty"`
+	IsCorrect         *bool         `json:"is_correct,omitempty"`
+	PointsAwarded     int           `gorm:"not null;default:0" json:"points_awarded" example:"1"`
+}
+
+func (QuizAnswer) TableName() string {
+	return "quiz_answer"
+}
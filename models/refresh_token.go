@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+// RefreshToken is a session of the local identity provider. TokenHash is the SHA-256 of the
+// current refresh token of the session, the token itself is never stored.
+type RefreshToken struct {
+	tableName struct{}   `gorm:"table:refresh_token"`
+	ID        uint       `gorm:"primaryKey" json:"id"`
+	UserID    uint       `gorm:"not null" json:"user_id"`
+	TokenHash string     `gorm:"type:varchar(64);not null;uniqueIndex" json:"-"`
+	ExpiresAt time.Time  `gorm:"not null" json:"expires_at"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt time.Time  `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt time.Time  `gorm:"default:CURRENT_TIMESTAMP" json:"updated_at"`
+}
+
+func (RefreshToken) TableName() string {
+	return "refresh_token"
+}
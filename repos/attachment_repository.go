@@ -4,11 +4,12 @@ import (
 	"errors"
 	"gorm.io/gorm"
 	"web/models"
+	"web/schemas"
 )
 
 type AttachmentRepositoryInterface interface {
-	GetByID(id uint) (models.Attachment, error)
-	GetByLessonID(lessonID uint) ([]models.Attachment, error)
+	GetByID(id uint, tenant *schemas.TenantFilter) (models.Attachment, error)
+	GetByLessonID(lessonID uint, tenant *schemas.TenantFilter) ([]models.Attachment, error)
 	Create(attachment models.Attachment) (uint, error)
 	Delete(id uint) error
 }
@@ -25,9 +26,9 @@ func NewAttachmentRepository(db *gorm.DB) *AttachmentRepository {
 	}
 }
 
-func (r *AttachmentRepository) GetByID(id uint) (models.Attachment, error) {
+func (r *AttachmentRepository) GetByID(id uint, tenant *schemas.TenantFilter) (models.Attachment, error) {
 	var attachment models.Attachment
-	result := r.DB.First(&attachment, id)
+	result := applyLessonTenantFilter(r.DB, "attachment.lesson_id", tenant).First(&attachment, id)
 	if result.Error != nil {
 		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
 			return attachment, errors.New("attachment not found")
@@ -37,9 +38,10 @@ func (r *AttachmentRepository) GetByID(id uint) (models.Attachment, error) {
 	return attachment, nil
 }
 
-func (r *AttachmentRepository) GetByLessonID(lessonID uint) ([]models.Attachment, error) {
+func (r *AttachmentRepository) GetByLessonID(lessonID uint, tenant *schemas.TenantFilter) ([]models.Attachment, error) {
 	var attachments []models.Attachment
-	result := r.DB.Where("lesson_id = ?", lessonID).Find(&attachments)
+	query := r.DB.Where("lesson_id = ?", lessonID)
+	result := applyLessonTenantFilter(query, "attachment.lesson_id", tenant).Find(&attachments)
 	if result.Error != nil {
 		return nil, result.Error
 	}
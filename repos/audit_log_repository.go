@@ -0,0 +1,116 @@
+package repos
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"gorm.io/gorm"
+	"web/models"
+	"web/schemas"
+)
+
+// auditSnapshotQueries select the JSON state of an audited resource by its ID. Soft deleted
+// rows are still selected, deleting a resource shows in its deleted_at. Orders are the
+// positions of the chapters of a course and of the lessons of a chapter.
+var auditSnapshotQueries = map[string]string{
+	"course":        `SELECT to_jsonb(t) FROM course t WHERE t.id = ?`,
+	"chapter":       `SELECT to_jsonb(t) FROM chapter t WHERE t.id = ?`,
+	"lesson":        `SELECT to_jsonb(t) FROM lesson t WHERE t.id = ?`,
+	"attachment":    `SELECT to_jsonb(t) FROM attachment t WHERE t.id = ?`,
+	"user":          `SELECT to_jsonb(t) - 'password' FROM users t WHERE t.id = ?`,
+	"chapter_order": `SELECT jsonb_agg(jsonb_build_object('id', t.id, 'order', t."order") ORDER BY t."order", t.id) FROM chapter t WHERE t.course_id = ? AND t.deleted_at IS NULL`,
+	"lesson_order":  `SELECT jsonb_agg(jsonb_build_object('id', t.id, 'order', t."order") ORDER BY t."order", t.id) FROM lesson t WHERE t.chapter_id = ? AND t.deleted_at IS NULL`,
+}
+
+// AuditLogRepositoryInterface only appends entries, the table rejects updates and deletes
+type AuditLogRepositoryInterface interface {
+	Create(entry models.AuditLog) (models.AuditLog, error)
+	GetAll(params schemas.AuditLogParams) ([]models.AuditLog, int64, error)
+	Snapshot(resourceType string, id uint) (json.RawMessage, error)
+}
+
+var _ AuditLogRepositoryInterface = (*AuditLogRepository)(nil)
+
+type AuditLogRepository struct {
+	DB *gorm.DB
+}
+
+func NewAuditLogRepository(db *gorm.DB) *AuditLogRepository {
+	return &AuditLogRepository{
+		DB: db,
+	}
+}
+
+func (r *AuditLogRepository) Create(entry models.AuditLog) (models.AuditLog, error) {
+	if err := r.DB.Create(&entry).Error; err != nil {
+		return models.AuditLog{}, err
+	}
+
+	return entry, nil
+}
+
+func (r *AuditLogRepository) GetAll(params schemas.AuditLogParams) ([]models.AuditLog, int64, error) {
+	query := r.DB.Model(&models.AuditLog{})
+	if params.ActorID != nil {
+		query = query.Where("audit_log.actor_id = ?", *params.ActorID)
+	}
+	if params.Action != "" {
+		query = query.Where("audit_log.action = ?", params.Action)
+	}
+	if params.ResourceType != "" {
+		query = query.Where("audit_log.resource_type = ?", params.ResourceType)
+	}
+	if params.ResourceID != nil {
+		query = query.Where("audit_log.resource_id = ?", *params.ResourceID)
+	}
+	if params.RequestID != "" {
+		query = query.Where("audit_log.request_id = ?", params.RequestID)
+	}
+	if params.From != nil {
+		query = query.Where("audit_log.created_at >= ?", *params.From)
+	}
+	if params.To != nil {
+		query = query.Where("audit_log.created_at <= ?", *params.To)
+	}
+	query = applyTenantFilter(query, "audit_log", params.Tenant).Session(&gorm.Session{})
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var entries []models.AuditLog
+	offset := 0
+	if params.Page > 1 {
+		offset = (params.Page - 1) * params.Limit
+	}
+	err := query.Order("audit_log.created_at DESC, audit_log.id DESC").
+		Offset(offset).
+		Limit(params.Limit).
+		Find(&entries).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return entries, total, nil
+}
+
+// Snapshot returns the JSON state of a resource, nil when it does not exist
+func (r *AuditLogRepository) Snapshot(resourceType string, id uint) (json.RawMessage, error) {
+	query, ok := auditSnapshotQueries[resourceType]
+	if !ok {
+		return nil, fmt.Errorf("unknown audit resource type %q", resourceType)
+	}
+
+	var snapshot sql.NullString
+	err := r.DB.Raw(query, id).Row().Scan(&snapshot)
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && !snapshot.Valid) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return json.RawMessage(snapshot.String), nil
+}
@@ -0,0 +1,104 @@
+package repos
+
+import (
+	"errors"
+	"gorm.io/gorm"
+	"web/models"
+)
+
+type CertificateRepositoryInterface interface {
+	Create(certificate models.Certificate) (models.Certificate, error)
+	GetByCode(code string) (models.Certificate, error)
+	GetByUserAndCourse(userID, courseID uint) (models.Certificate, error)
+	GetByUserID(userID uint) ([]models.Certificate, error)
+	Delete(id uint) error
+}
+
+var _ CertificateRepositoryInterface = (*CertificateRepository)(nil)
+
+type CertificateRepository struct {
+	DB *gorm.DB
+}
+
+func NewCertificateRepository(db *gorm.DB) *CertificateRepository {
+	return &CertificateRepository{
+		DB: db,
+	}
+}
+
+func (r *CertificateRepository) Create(certificate models.Certificate) (models.Certificate, error) {
+	result := r.DB.Create(&certificate)
+	if result.Error != nil {
+		return models.Certificate{}, result.Error
+	}
+
+	return certificate, nil
+}
+
+func (r *CertificateRepository) GetByCode(code string) (models.Certificate, error) {
+	var certificate models.Certificate
+	err := r.DB.
+		Preload("User").
+		Preload("Course", withTrashed).
+		Where("code = ?", code).
+		First(&certificate).Error
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return certificate, errors.New("certificate not found")
+		}
+		return certificate, err
+	}
+
+	return certificate, nil
+}
+
+func (r *CertificateRepository) GetByUserAndCourse(userID, courseID uint) (models.Certificate, error) {
+	var certificate models.Certificate
+	err := r.DB.Where("user_id = ? and course_id = ?", userID, courseID).First(&certificate).Error
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return certificate, errors.New("certificate not found")
+		}
+		return certificate, err
+	}
+
+	return certificate, nil
+}
+
+func (r *CertificateRepository) GetByUserID(userID uint) ([]models.Certificate, error) {
+	var certificates []models.Certificate
+	err := r.DB.
+		Preload("User").
+		Preload("Course", withTrashed).
+		Where("user_id = ?", userID).
+		Order("issued_at desc").
+		Find(&certificates).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return certificates, nil
+}
+
+func (r *CertificateRepository) Delete(id uint) error {
+	result := r.DB.Delete(&models.Certificate{}, id)
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return errors.New("certificate not found")
+	}
+
+	return nil
+}
+
+// withTrashed includes soft-deleted rows in a preload, certificates keep showing their
+// course while it is in the trash
+func withTrashed(db *gorm.DB) *gorm.DB {
+	return db.Unscoped()
+}
@@ -3,21 +3,31 @@ package repos
 import (
 	"errors"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
 	"web/models"
 	"web/schemas"
 )
 
 type ChapterRepositoryInterface interface {
-	GetByID(id, courseId uint) (models.Chapter, error)
-	GetByIDWithLessonsCount(id uint, courseID uint) (schemas.ChapterResponseWithLessonsCount, error)
-	GetByCourseID(courseID uint) ([]schemas.ChapterResponseWithLessonsCount, error)
+	GetByID(id, courseId uint, tenant *schemas.TenantFilter) (models.Chapter, error)
+	GetByIDWithLessonsCount(id uint, courseID uint, tenant *schemas.TenantFilter) (schemas.ChapterResponseWithLessonsCount, error)
+	GetByCourseID(courseID uint, params schemas.ListParams) ([]schemas.ChapterResponseWithLessonsCount, int64, error)
 	Create(chapter models.Chapter) (uint, error)
 	Update(chapter models.Chapter) error
 	Delete(id uint) error
+	Reorder(courseID uint, chapterIDs []uint) ([]models.Chapter, error)
+	Move(chapterID, toCourseID uint, position int) ([]models.Chapter, error)
 }
 
 var _ ChapterRepositoryInterface = (*ChapterRepository)(nil)
 
+var chapterSortColumns = map[string]string{
+	"order":      `chapter."order"`,
+	"name":       "chapter.name",
+	"created_at": "chapter.created_at",
+}
+
 type ChapterRepository struct {
 	DB *gorm.DB
 }
@@ -28,29 +38,38 @@ func NewChapterRepository(db *gorm.DB) *ChapterRepository {
 	}
 }
 
-func (r *ChapterRepository) GetByCourseID(courseID uint) ([]schemas.ChapterResponseWithLessonsCount, error) {
+func (r *ChapterRepository) GetByCourseID(courseID uint, params schemas.ListParams) ([]schemas.ChapterResponseWithLessonsCount, int64, error) {
 	var chapterResponses []schemas.ChapterResponseWithLessonsCount
 
+	query := r.DB.Model(&models.Chapter{}).Where("chapter.course_id = ?", courseID)
+	query = applyCourseTenantFilter(query, "chapter.course_id", params.Tenant)
+	query = applyListFilters(query, "chapter", params).Session(&gorm.Session{})
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
 	subQuery := r.DB.Model(&models.Lesson{}).
 		Select("chapter_id, count(*) as lessons_count").
 		Group("chapter_id")
 
-	err := r.DB.Model(&models.Chapter{}).
-		Select("chapter.id, chapter.name, chapter.description, chapter.created_at, chapter.updated_at, COALESCE(lessons_count, 0) as lessons_count").
+	err := applyListPage(query, "chapter", params, chapterSortColumns, "order").
+		Select("chapter.id, chapter.name, chapter.description, chapter.created_by, chapter.created_at, chapter.updated_at, COALESCE(lessons_count, 0) as lessons_count").
 		Joins("LEFT JOIN (?) AS lesson_counts ON chapter.id = lesson_counts.chapter_id", subQuery).
-		Where("chapter.course_id = ?", courseID).
-		Order(`"order" ASC`).
 		Scan(&chapterResponses).Error
 
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 
-	return chapterResponses, nil
+	return chapterResponses, total, nil
 }
-func (r *ChapterRepository) GetByID(id, courseId uint) (models.Chapter, error) {
+
+func (r *ChapterRepository) GetByID(id, courseId uint, tenant *schemas.TenantFilter) (models.Chapter, error) {
 	var chapter models.Chapter
-	result := r.DB.Where("course_id = ? and id = ?", courseId, id).First(&chapter)
+	query := r.DB.Where("course_id = ? and id = ?", courseId, id)
+	result := applyCourseTenantFilter(query, "chapter.course_id", tenant).First(&chapter)
 
 	if result.Error != nil {
 		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
@@ -62,7 +81,7 @@ func (r *ChapterRepository) GetByID(id, courseId uint) (models.Chapter, error) {
 	return chapter, nil
 }
 
-func (r *ChapterRepository) GetByIDWithLessonsCount(id, courseID uint) (schemas.ChapterResponseWithLessonsCount, error) {
+func (r *ChapterRepository) GetByIDWithLessonsCount(id, courseID uint, tenant *schemas.TenantFilter) (schemas.ChapterResponseWithLessonsCount, error) {
 	var chapterResponse schemas.ChapterResponseWithLessonsCount
 
 	subQuery := r.DB.Model(&models.Lesson{}).
@@ -70,10 +89,11 @@ func (r *ChapterRepository) GetByIDWithLessonsCount(id, courseID uint) (schemas.
 		Where("chapter_id = ?", id).
 		Group("chapter_id")
 
-	err := r.DB.Model(&models.Chapter{}).
+	query := r.DB.Model(&models.Chapter{}).
 		Select("chapter.id, chapter.name, chapter.description, chapter.created_at, chapter.updated_at, COALESCE(lessons_count, 0) as lessons_count").
 		Joins("LEFT JOIN (?) AS lesson_counts ON chapter.id = lesson_counts.chapter_id", subQuery).
-		Where("chapter.id = ? and chapter.course_id = ?", id, courseID).
+		Where("chapter.id = ? and chapter.course_id = ?", id, courseID)
+	err := applyCourseTenantFilter(query, "chapter.course_id", tenant).
 		Scan(&chapterResponse).Error
 
 	if err != nil {
@@ -118,16 +138,130 @@ func (r *ChapterRepository) Update(chapter models.Chapter) error {
 	return nil
 }
 
+// Delete moves the chapter to the trash with its content, see TrashRepository
 func (r *ChapterRepository) Delete(id uint) error {
-	result := r.DB.Delete(&models.Chapter{}, id)
+	return softDeleteTree(r.DB, "chapter", id)
+}
 
-	if result.Error != nil {
-		return result.Error
+// Reorder sets the order of the chapters of a course to their position in chapterIDs,
+// which must list every chapter of the course exactly once
+func (r *ChapterRepository) Reorder(courseID uint, chapterIDs []uint) ([]models.Chapter, error) {
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		if err := lockRow(tx, &models.Course{}, courseID); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("course not found")
+			}
+			return err
+		}
+
+		var existing []uint
+		if err := tx.Model(&models.Chapter{}).Where("course_id = ?", courseID).Pluck("id", &existing).Error; err != nil {
+			return err
+		}
+		if !sameIDs(existing, chapterIDs) {
+			return errors.New("chapter order must list every chapter of the course exactly once")
+		}
+
+		return renumber(tx, &models.Chapter{}, chapterIDs)
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	if result.RowsAffected == 0 {
-		return errors.New("chapter not found")
+	return r.getCourseChapters(courseID)
+}
+
+// Move puts the chapter with its lessons at the 1-based position of another course, or at
+// its end when position is 0, and closes the gap it leaves in its old course. Both courses
+// are renumbered in one transaction. The lessons and their attachments stay unchanged.
+func (r *ChapterRepository) Move(chapterID, toCourseID uint, position int) ([]models.Chapter, error) {
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		var chapter models.Chapter
+		if err := tx.Select("id", "course_id").First(&chapter, chapterID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("chapter not found")
+			}
+			return err
+		}
+
+		// Lock both courses in ID order so concurrent moves cannot deadlock
+		var courses []models.Course
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("id").
+			Where("id in ?", []uint{chapter.CourseID, toCourseID}).
+			Order("id").
+			Find(&courses).Error
+		if err != nil {
+			return err
+		}
+		found := false
+		for _, course := range courses {
+			found = found || course.ID == toCourseID
+		}
+		if !found {
+			return errors.New("target course not found")
+		}
+
+		target, err := courseChapterIDs(tx, toCourseID)
+		if err != nil {
+			return err
+		}
+		for i, id := range target {
+			if id == chapterID {
+				target = append(target[:i], target[i+1:]...)
+				break
+			}
+		}
+		if position == 0 {
+			position = len(target) + 1
+		}
+		if position < 1 || position > len(target)+1 {
+			return errors.New("invalid chapter position")
+		}
+		target = append(target[:position-1], append([]uint{chapterID}, target[position-1:]...)...)
+
+		if chapter.CourseID != toCourseID {
+			err := tx.Model(&models.Chapter{}).
+				Where("id = ?", chapterID).
+				Updates(map[string]interface{}{"course_id": toCourseID, "updated_at": time.Now()}).Error
+			if err != nil {
+				return err
+			}
+
+			source, err := courseChapterIDs(tx, chapter.CourseID)
+			if err != nil {
+				return err
+			}
+			if err := renumber(tx, &models.Chapter{}, source); err != nil {
+				return err
+			}
+		}
+
+		return renumber(tx, &models.Chapter{}, target)
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	return nil
+	return r.getCourseChapters(toCourseID)
+}
+
+// courseChapterIDs returns the IDs of the chapters of a course in their current order
+func courseChapterIDs(tx *gorm.DB, courseID uint) ([]uint, error) {
+	var ids []uint
+	err := tx.Model(&models.Chapter{}).
+		Where("course_id = ?", courseID).
+		Order(`"order", id`).
+		Pluck("id", &ids).Error
+	return ids, err
+}
+
+func (r *ChapterRepository) getCourseChapters(courseID uint) ([]models.Chapter, error) {
+	var chapters []models.Chapter
+	err := r.DB.Where("course_id = ?", courseID).Order(`"order", id`).Find(&chapters).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return chapters, nil
 }
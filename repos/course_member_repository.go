@@ -0,0 +1,110 @@
+package repos
+
+import (
+	"errors"
+	"gorm.io/gorm"
+	"time"
+	"web/models"
+)
+
+type CourseMemberRepositoryInterface interface {
+	GetByCourseID(courseID uint) ([]models.CourseMember, error)
+	Get(courseID, userID uint) (models.CourseMember, error)
+	GetRole(courseID, userID uint) (string, error)
+	Create(member models.CourseMember) (models.CourseMember, error)
+	UpdateRole(courseID, userID uint, role string) error
+	Delete(courseID, userID uint) error
+}
+
+var _ CourseMemberRepositoryInterface = (*CourseMemberRepository)(nil)
+
+type CourseMemberRepository struct {
+	DB *gorm.DB
+}
+
+func NewCourseMemberRepository(db *gorm.DB) *CourseMemberRepository {
+	return &CourseMemberRepository{
+		DB: db,
+	}
+}
+
+// GetByCourseID returns the members of a course with their users, the owner first
+func (r *CourseMemberRepository) GetByCourseID(courseID uint) ([]models.CourseMember, error) {
+	var members []models.CourseMember
+	err := r.DB.Preload("User").
+		Where("course_id = ?", courseID).
+		Order("role = 'owner' DESC, created_at, id").
+		Find(&members).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return members, nil
+}
+
+func (r *CourseMemberRepository) Get(courseID, userID uint) (models.CourseMember, error) {
+	var member models.CourseMember
+	err := r.DB.Preload("User").Where("course_id = ? and user_id = ?", courseID, userID).First(&member).Error
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return member, errors.New("course member not found")
+		}
+		return member, err
+	}
+
+	return member, nil
+}
+
+// GetRole returns the role of the user in the course, or an empty string when the user is
+// not a member
+func (r *CourseMemberRepository) GetRole(courseID, userID uint) (string, error) {
+	var roles []string
+	err := r.DB.Model(&models.CourseMember{}).
+		Where("course_id = ? and user_id = ?", courseID, userID).
+		Limit(1).
+		Pluck("role", &roles).Error
+	if err != nil || len(roles) == 0 {
+		return "", err
+	}
+
+	return roles[0], nil
+}
+
+func (r *CourseMemberRepository) Create(member models.CourseMember) (models.CourseMember, error) {
+	if err := r.DB.Omit("User").Create(&member).Error; err != nil {
+		return models.CourseMember{}, err
+	}
+
+	return member, nil
+}
+
+func (r *CourseMemberRepository) UpdateRole(courseID, userID uint, role string) error {
+	result := r.DB.Model(&models.CourseMember{}).
+		Where("course_id = ? and user_id = ?", courseID, userID).
+		Updates(map[string]interface{}{"role": role, "updated_at": time.Now()})
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return errors.New("course member not found")
+	}
+
+	return nil
+}
+
+func (r *CourseMemberRepository) Delete(courseID, userID uint) error {
+	result := r.DB.Where("course_id = ? and user_id = ?", courseID, userID).Delete(&models.CourseMember{})
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return errors.New("course member not found")
+	}
+
+	return nil
+}
@@ -2,23 +2,61 @@ package repos
 
 import (
 	"errors"
+	"fmt"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"path"
 	"time"
 	"web/models"
 	"web/schemas"
 )
 
 type CourseRepositoryInterface interface {
-	GetAll() ([]schemas.CourseResponseWithChaptersCount, error)
+	GetAll(params schemas.ListParams) ([]schemas.CourseResponseWithChaptersCount, int64, error)
 	GetByID(id uint) (models.Course, error)
 	Create(course models.Course) (models.Course, error)
 	Update(course models.Course, courseRequest schemas.UpdateCourseRequest) (models.Course, error)
 	Delete(id uint) error
 	GetByIDWithChaptersCount(id uint) (schemas.CourseResponseWithChaptersCount, error)
+	UpdateStatus(course models.Course, history models.CourseStatusHistory) (models.Course, error)
+	GetStatusHistory(courseID uint) ([]models.CourseStatusHistory, error)
+	GetTree(id uint) (models.Course, error)
+	Clone(source models.Course, options CloneOptions) (models.Course, error)
+	Import(source models.Course, options CloneOptions) (models.Course, schemas.CourseIDMap, error)
+	ExistsByName(name string, organizationID *uint) (bool, error)
+}
+
+// ObjectCopy is a storage object that has to be copied for a cloned attachment
+type ObjectCopy struct {
+	Source      string
+	Destination string
+}
+
+// CloneOptions controls how a course tree is cloned
+type CloneOptions struct {
+	// Name of the new course
+	Name string
+	// CreatedBy owns the new course and everything in it
+	CreatedBy uint
+	// OrganizationID is the tenant of the new course
+	OrganizationID *uint
+	// RenderContent renders the content of a lesson from its blocks, used for lessons
+	// whose blocks point at copied attachments
+	RenderContent func(blocks []models.LessonBlock) string
+	// CopyObjects copies the storage objects of the cloned attachments
+	CopyObjects func(copies []ObjectCopy) error
+	// KeepAttachmentPaths names the copied objects after the attachment name, which must
+	// be a clean relative path, instead of the base name of the source object
+	KeepAttachmentPaths bool
 }
 
 var _ CourseRepositoryInterface = (*CourseRepository)(nil)
 
+var courseSortColumns = map[string]string{
+	"name":       "course.name",
+	"created_at": "course.created_at",
+}
+
 type CourseRepository struct {
 	DB *gorm.DB
 }
@@ -29,15 +67,33 @@ func NewCourseRepository(db *gorm.DB) *CourseRepository {
 	}
 }
 
+// Create creates the course and makes its creator the owner member
 func (r *CourseRepository) Create(course models.Course) (models.Course, error) {
-	result := r.DB.Create(&course)
-	if result.Error != nil {
-		return models.Course{}, result.Error
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&course).Error; err != nil {
+			return err
+		}
+		return createOwnerMember(tx, course)
+	})
+	if err != nil {
+		return models.Course{}, err
 	}
 
 	return course, nil
 }
 
+// createOwnerMember adds the creator of a new course to its members as the owner
+func createOwnerMember(tx *gorm.DB, course models.Course) error {
+	if course.CreatedBy == nil {
+		return nil
+	}
+	return tx.Omit("User").Create(&models.CourseMember{
+		CourseID: course.ID,
+		UserID:   *course.CreatedBy,
+		Role:     models.CourseMemberRoleOwner,
+	}).Error
+}
+
 func (r *CourseRepository) Update(course models.Course, courseRequest schemas.UpdateCourseRequest) (models.Course, error) {
 	result := r.DB.Model(&course).Updates(models.Course{
 		Name:        courseRequest.Name,
@@ -56,37 +112,44 @@ func (r *CourseRepository) Update(course models.Course, courseRequest schemas.Up
 	return course, nil
 }
 
+// Delete moves the course to the trash with its content, see TrashRepository
 func (r *CourseRepository) Delete(id uint) error {
-	result := r.DB.Delete(&models.Course{}, id)
+	return softDeleteTree(r.DB, "course", id)
+}
 
-	if result.Error != nil {
-		return result.Error
-	}
+func (r *CourseRepository) GetAll(params schemas.ListParams) ([]schemas.CourseResponseWithChaptersCount, int64, error) {
+	var courseResponses []schemas.CourseResponseWithChaptersCount
 
-	if result.RowsAffected == 0 {
-		return errors.New("course not found")
+	query := applyListFilters(r.DB.Model(&models.Course{}), "course", params)
+	if params.Status != "" {
+		query = query.Where("course.status = ?", params.Status)
 	}
+	query = applyTenantFilter(query, "course", params.Tenant)
+	if params.VisibleTo != nil {
+		query = query.Where("(course.status = ? OR course.created_by = ? OR EXISTS (SELECT 1 FROM course_member WHERE course_member.course_id = course.id AND course_member.user_id = ?))",
+			models.CourseStatusPublished, *params.VisibleTo, *params.VisibleTo)
+	}
+	query = query.Session(&gorm.Session{})
 
-	return nil
-}
-
-func (r *CourseRepository) GetAll() ([]schemas.CourseResponseWithChaptersCount, error) {
-	var courseResponses []schemas.CourseResponseWithChaptersCount
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
 
 	subQuery := r.DB.Model(&models.Chapter{}).
 		Select("course_id, count(*) as chapters_count").
 		Group("course_id")
 
-	err := r.DB.Model(&models.Course{}).
-		Select("course.id, course.name, course.description, course.created_by, course.created_at, COALESCE(chapters_count, 0) as chapters_count").
+	err := applyListPage(query, "course", params, courseSortColumns, "created_at").
+		Select("course.id, course.name, course.description, course.status, course.published_at, course.created_by, course.created_at, COALESCE(chapters_count, 0) as chapters_count").
 		Joins("LEFT JOIN (?) AS chapter_counts ON course.id = chapter_counts.course_id", subQuery).
 		Scan(&courseResponses).Error
 
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 
-	return courseResponses, nil
+	return courseResponses, total, nil
 }
 
 func (r *CourseRepository) GetByID(id uint) (models.Course, error) {
@@ -112,7 +175,7 @@ func (r *CourseRepository) GetByIDWithChaptersCount(id uint) (schemas.CourseResp
 		Group("course_id")
 
 	err := r.DB.Model(&models.Course{}).
-		Select("course.id, course.name, course.description, course.created_by, course.created_at, COALESCE(chapters_count, 0) as chapters_count").
+		Select("course.id, course.name, course.description, course.status, course.published_at, course.created_by, course.created_at, COALESCE(chapters_count, 0) as chapters_count").
 		Joins("LEFT JOIN (?) AS chapter_counts ON course.id = chapter_counts.course_id", subQuery).
 		Where("course.id = ?", id).
 		Scan(&courseResponse).Error
@@ -130,3 +193,232 @@ func (r *CourseRepository) GetByIDWithChaptersCount(id uint) (schemas.CourseResp
 
 	return courseResponse, nil
 }
+
+// UpdateStatus moves the course to history.ToStatus and records the transition. The
+// update only applies while the course is still in history.FromStatus so concurrent
+// transitions cannot overwrite each other.
+func (r *CourseRepository) UpdateStatus(course models.Course, history models.CourseStatusHistory) (models.Course, error) {
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		updates := map[string]interface{}{
+			"status":     history.ToStatus,
+			"updated_at": time.Now(),
+		}
+		if history.ToStatus == models.CourseStatusPublished {
+			updates["published_at"] = time.Now()
+		}
+
+		result := tx.Model(&models.Course{}).
+			Where("id = ? and status = ?", course.ID, history.FromStatus).
+			Updates(updates)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.New("course status has changed, please reload and try again")
+		}
+
+		return tx.Create(&history).Error
+	})
+	if err != nil {
+		return models.Course{}, err
+	}
+
+	return r.GetByID(course.ID)
+}
+
+func (r *CourseRepository) GetStatusHistory(courseID uint) ([]models.CourseStatusHistory, error) {
+	var history []models.CourseStatusHistory
+	err := r.DB.
+		Preload("Changer").
+		Where("course_id = ?", courseID).
+		Order("created_at ASC, id ASC").
+		Find(&history).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return history, nil
+}
+
+// ExistsByName reports whether a course with the given name exists in the organization,
+// ignoring case
+func (r *CourseRepository) ExistsByName(name string, organizationID *uint) (bool, error) {
+	var count int64
+	query := r.DB.Model(&models.Course{}).Where("lower(name) = lower(?)", name)
+	err := applyTenantFilter(query, "course", &schemas.TenantFilter{OrganizationID: organizationID}).Count(&count).Error
+	if err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+// GetTree returns the course with its chapters, lessons, lesson blocks and attachments, all in order
+func (r *CourseRepository) GetTree(id uint) (models.Course, error) {
+	var course models.Course
+	err := r.DB.
+		Preload("Chapters", func(db *gorm.DB) *gorm.DB { return db.Order(`"order", id`) }).
+		Preload("Chapters.Lessons", func(db *gorm.DB) *gorm.DB { return db.Order(`"order", id`) }).
+		Preload("Chapters.Lessons.Blocks", func(db *gorm.DB) *gorm.DB { return db.Order("position") }).
+		Preload("Chapters.Lessons.Attachments", func(db *gorm.DB) *gorm.DB { return db.Order("id") }).
+		First(&course, id).Error
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return course, errors.New("course not found")
+		}
+		return course, err
+	}
+
+	return course, nil
+}
+
+// Clone copies a course tree loaded by GetTree into a new draft course. Attachments get
+// new object names under the new lesson; options.CopyObjects is called with the objects
+// to copy before the transaction commits, so a failed copy rolls everything back.
+func (r *CourseRepository) Clone(source models.Course, options CloneOptions) (models.Course, error) {
+	course, _, err := r.Import(source, options)
+	return course, err
+}
+
+// Import creates a new draft course from a course tree that is not stored yet, such as one
+// read from a course archive, the same way Clone does. The IDs of the source tree are only
+// used to build the returned map from source IDs to the IDs of the created records.
+func (r *CourseRepository) Import(source models.Course, options CloneOptions) (models.Course, schemas.CourseIDMap, error) {
+	idMap := schemas.CourseIDMap{
+		Chapters:    make(map[uint]uint),
+		Lessons:     make(map[uint]uint),
+		Attachments: make(map[uint]uint),
+	}
+
+	var courseID uint
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		course := models.Course{
+			Name:           options.Name,
+			Description:    source.Description,
+			Status:         models.CourseStatusDraft,
+			CreatedBy:      &options.CreatedBy,
+			OrganizationID: options.OrganizationID,
+		}
+		if err := tx.Omit(clause.Associations).Create(&course).Error; err != nil {
+			return err
+		}
+		if err := createOwnerMember(tx, course); err != nil {
+			return err
+		}
+		courseID = course.ID
+
+		var copies []ObjectCopy
+		for _, sourceChapter := range source.Chapters {
+			chapter := models.Chapter{
+				Name:        sourceChapter.Name,
+				Description: sourceChapter.Description,
+				Order:       sourceChapter.Order,
+				CourseID:    course.ID,
+				CreatedBy:   &options.CreatedBy,
+			}
+			if err := tx.Omit(clause.Associations).Create(&chapter).Error; err != nil {
+				return err
+			}
+			idMap.Chapters[sourceChapter.ID] = chapter.ID
+
+			for _, sourceLesson := range sourceChapter.Lessons {
+				lessonCopies, err := cloneLesson(tx, sourceLesson, chapter.ID, options, idMap)
+				if err != nil {
+					return err
+				}
+				copies = append(copies, lessonCopies...)
+			}
+		}
+
+		if len(copies) == 0 || options.CopyObjects == nil {
+			return nil
+		}
+		return options.CopyObjects(copies)
+	})
+	if err != nil {
+		return models.Course{}, schemas.CourseIDMap{}, err
+	}
+
+	course, err := r.GetTree(courseID)
+	if err != nil {
+		return models.Course{}, schemas.CourseIDMap{}, err
+	}
+
+	return course, idMap, nil
+}
+
+// cloneLesson copies a lesson with its attachments and blocks into the chapter, records
+// the new IDs in idMap and returns the storage objects of the copied attachments
+func cloneLesson(tx *gorm.DB, source models.Lesson, chapterID uint, options CloneOptions, idMap schemas.CourseIDMap) ([]ObjectCopy, error) {
+	lesson := models.Lesson{
+		Name:        source.Name,
+		Description: source.Description,
+		Content:     source.Content,
+		Order:       source.Order,
+		ChapterID:   chapterID,
+		CreatedBy:   &options.CreatedBy,
+	}
+	if err := tx.Omit(clause.Associations).Create(&lesson).Error; err != nil {
+		return nil, err
+	}
+	idMap.Lessons[source.ID] = lesson.ID
+
+	var copies []ObjectCopy
+	attachmentIDs := make(map[uint]uint, len(source.Attachments))
+	for _, sourceAttachment := range source.Attachments {
+		objectName := path.Base(sourceAttachment.URL)
+		if options.KeepAttachmentPaths {
+			objectName = sourceAttachment.Name
+		}
+		attachment := models.Attachment{
+			Name:     sourceAttachment.Name,
+			URL:      TenantObjectPrefix(options.OrganizationID) + fmt.Sprintf("lesson-%d/%s", lesson.ID, objectName),
+			LessonID: lesson.ID,
+		}
+		if err := tx.Omit(clause.Associations).Create(&attachment).Error; err != nil {
+			return nil, err
+		}
+		attachmentIDs[sourceAttachment.ID] = attachment.ID
+		idMap.Attachments[sourceAttachment.ID] = attachment.ID
+		copies = append(copies, ObjectCopy{Source: sourceAttachment.URL, Destination: attachment.URL})
+	}
+
+	referencesAttachments := false
+	blocks := make([]models.LessonBlock, 0, len(source.Blocks))
+	for _, sourceBlock := range source.Blocks {
+		block := sourceBlock
+		block.ID = 0
+		block.LessonID = lesson.ID
+		block.CreatedBy = &options.CreatedBy
+		if block.AttachmentID != nil {
+			referencesAttachments = true
+			// Point attachment blocks at the copied attachment. Blocks of attachments that
+			// were not copied, such as trashed ones, are skipped like on course import.
+			attachmentID, ok := attachmentIDs[*block.AttachmentID]
+			if !ok {
+				continue
+			}
+			block.AttachmentID = &attachmentID
+		}
+		block.Position = len(blocks) + 1
+		if err := tx.Create(&block).Error; err != nil {
+			return nil, err
+		}
+		blocks = append(blocks, block)
+	}
+
+	if referencesAttachments && options.RenderContent != nil {
+		lesson.Content = options.RenderContent(blocks)
+		if err := tx.Model(&lesson).Omit(clause.Associations).Update("content", lesson.Content).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	if _, err := createLessonRevision(tx, lesson, models.LessonRevision{CreatedBy: &options.CreatedBy}); err != nil {
+		return nil, err
+	}
+
+	return copies, nil
+}
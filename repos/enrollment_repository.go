@@ -0,0 +1,80 @@
+package repos
+
+import (
+	"errors"
+	"gorm.io/gorm"
+	"web/models"
+	"web/schemas"
+)
+
+type EnrollmentRepositoryInterface interface {
+	Create(enrollment models.Enrollment) (models.Enrollment, error)
+	Delete(userID, courseID uint) error
+	Exists(userID, courseID uint) (bool, error)
+	GetByUserID(userID uint) ([]schemas.EnrollmentResponse, error)
+}
+
+var _ EnrollmentRepositoryInterface = (*EnrollmentRepository)(nil)
+
+type EnrollmentRepository struct {
+	DB *gorm.DB
+}
+
+func NewEnrollmentRepository(db *gorm.DB) *EnrollmentRepository {
+	return &EnrollmentRepository{
+		DB: db,
+	}
+}
+
+func (r *EnrollmentRepository) Create(enrollment models.Enrollment) (models.Enrollment, error) {
+	result := r.DB.Create(&enrollment)
+	if result.Error != nil {
+		return models.Enrollment{}, result.Error
+	}
+
+	return enrollment, nil
+}
+
+func (r *EnrollmentRepository) Delete(userID, courseID uint) error {
+	result := r.DB.Where("user_id = ? and course_id = ?", userID, courseID).Delete(&models.Enrollment{})
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return errors.New("enrollment not found")
+	}
+
+	return nil
+}
+
+func (r *EnrollmentRepository) Exists(userID, courseID uint) (bool, error) {
+	var count int64
+	err := r.DB.Model(&models.Enrollment{}).
+		Where("user_id = ? and course_id = ?", userID, courseID).
+		Count(&count).Error
+
+	if err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+func (r *EnrollmentRepository) GetByUserID(userID uint) ([]schemas.EnrollmentResponse, error) {
+	var enrollments []schemas.EnrollmentResponse
+
+	err := r.DB.Model(&models.Enrollment{}).
+		Select("enrollment.id, enrollment.course_id, course.name as course_name, course.description as course_description, enrollment.created_at as enrolled_at").
+		Joins("INNER JOIN course ON course.id = enrollment.course_id AND course.deleted_at IS NULL").
+		Where("enrollment.user_id = ?", userID).
+		Order("enrollment.created_at DESC").
+		Scan(&enrollments).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return enrollments, nil
+}
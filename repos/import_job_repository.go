@@ -0,0 +1,86 @@
+package repos
+
+import (
+	"errors"
+	"gorm.io/gorm"
+	"time"
+	"web/models"
+)
+
+type ImportJobRepositoryInterface interface {
+	Create(job models.ImportJob) (models.ImportJob, error)
+	GetByID(id uint) (models.ImportJob, error)
+	Update(job models.ImportJob) (models.ImportJob, error)
+	FailUnfinished(message string) (int64, error)
+}
+
+var _ ImportJobRepositoryInterface = (*ImportJobRepository)(nil)
+
+type ImportJobRepository struct {
+	DB *gorm.DB
+}
+
+func NewImportJobRepository(db *gorm.DB) *ImportJobRepository {
+	return &ImportJobRepository{
+		DB: db,
+	}
+}
+
+func (r *ImportJobRepository) Create(job models.ImportJob) (models.ImportJob, error) {
+	result := r.DB.Create(&job)
+	if result.Error != nil {
+		return models.ImportJob{}, result.Error
+	}
+
+	return job, nil
+}
+
+func (r *ImportJobRepository) GetByID(id uint) (models.ImportJob, error) {
+	var job models.ImportJob
+	err := r.DB.First(&job, id).Error
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return job, errors.New("import job not found")
+		}
+		return job, err
+	}
+
+	return job, nil
+}
+
+// Update saves the progress of a job: its kind, status, course, warnings, error and timestamps
+func (r *ImportJobRepository) Update(job models.ImportJob) (models.ImportJob, error) {
+	result := r.DB.Model(&models.ImportJob{}).
+		Where("id = ?", job.ID).
+		Select("kind", "status", "course_id", "warnings", "error", "started_at", "finished_at").
+		Updates(&job)
+
+	if result.Error != nil {
+		return models.ImportJob{}, result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return models.ImportJob{}, errors.New("import job not found")
+	}
+
+	return job, nil
+}
+
+// FailUnfinished marks pending and running jobs as failed. Jobs run in the server
+// process, so after a restart unfinished jobs will never complete.
+func (r *ImportJobRepository) FailUnfinished(message string) (int64, error) {
+	result := r.DB.Model(&models.ImportJob{}).
+		Where("status in ?", []string{models.ImportJobStatusPending, models.ImportJobStatusRunning}).
+		Updates(map[string]interface{}{
+			"status":      models.ImportJobStatusFailed,
+			"error":       message,
+			"finished_at": time.Now(),
+		})
+
+	if result.Error != nil {
+		return 0, result.Error
+	}
+
+	return result.RowsAffected, nil
+}
@@ -4,20 +4,36 @@ import (
 	"errors"
 	"fmt"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
 	"web/models"
 	"web/schemas"
 )
 
 type LessonRepositoryInterface interface {
-	GetByID(courseID, chapterID, id uint) (models.Lesson, error)
-	GetByChapterID(chapterID, courseID uint) ([]schemas.LessonResponse, error)
+	GetByID(courseID, chapterID, id uint, tenant *schemas.TenantFilter) (models.Lesson, error)
+	GetByChapterID(courseID, chapterID uint, params schemas.ListParams) ([]schemas.LessonResponse, int64, error)
+	GetCourseID(lessonID uint) (uint, error)
+	GetOrganizationID(lessonID uint) (*uint, error)
 	Create(lesson models.Lesson) (uint, error)
-	Update(lesson models.Lesson) error
+	Update(lesson models.Lesson, revision models.LessonRevision) (models.LessonRevision, error)
 	Delete(id uint) error
+	GetBlocks(lessonID uint) ([]models.LessonBlock, error)
+	GetRevisions(lessonID uint) ([]models.LessonRevision, error)
+	GetCurrentRevision(lessonID uint) (int, error)
+	GetRevision(lessonID uint, revision int) (models.LessonRevision, error)
+	Reorder(chapterID uint, lessonIDs []uint) ([]models.Lesson, error)
+	Move(lessonID, toCourseID, toChapterID uint, position int) ([]models.Lesson, error)
 }
 
 var _ LessonRepositoryInterface = (*LessonRepository)(nil)
 
+var lessonSortColumns = map[string]string{
+	"order":      `lesson."order"`,
+	"name":       "lesson.name",
+	"created_at": "lesson.created_at",
+}
+
 type LessonRepository struct {
 	DB *gorm.DB
 }
@@ -28,31 +44,38 @@ func NewLessonRepository(db *gorm.DB) *LessonRepository {
 	}
 }
 
-func (r *LessonRepository) GetByChapterID(courseID, chapterID uint) ([]schemas.LessonResponse, error) {
+func (r *LessonRepository) GetByChapterID(courseID, chapterID uint, params schemas.ListParams) ([]schemas.LessonResponse, int64, error) {
 	var lessons []schemas.LessonResponse
-	result := r.DB.Model(&models.Lesson{}).
-		Select("lesson.id, lesson.name, lesson.description, lesson.content, lesson.order, lesson.created_at").
+
+	query := r.DB.Model(&models.Lesson{}).
 		Joins("INNER JOIN chapter ON chapter.id = lesson.chapter_id").
-		Where("chapter_id = ? and chapter.course_id = ?", chapterID, courseID).
-		Order(
-			"lesson.order ASC",
-		).
+		Where("chapter_id = ? and chapter.course_id = ?", chapterID, courseID)
+	query = applyCourseTenantFilter(query, "chapter.course_id", params.Tenant)
+	query = applyListFilters(query, "lesson", params).Session(&gorm.Session{})
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	result := applyListPage(query, "lesson", params, lessonSortColumns, "order").
+		Select("lesson.id, lesson.name, lesson.description, lesson.content, lesson.order, lesson.created_by, lesson.created_at").
 		Find(&lessons)
 	if result.Error != nil {
-		return nil, result.Error
+		return nil, 0, result.Error
 	}
 
-	return lessons, nil
+	return lessons, total, nil
 }
 
-func (r *LessonRepository) GetByID(courseID, chapterID, id uint) (models.Lesson, error) {
+func (r *LessonRepository) GetByID(courseID, chapterID, id uint, tenant *schemas.TenantFilter) (models.Lesson, error) {
 	var lesson models.Lesson
 	fmt.Println(courseID, chapterID, id)
-	result := r.DB.Model(&models.Lesson{}).
+	query := r.DB.Model(&models.Lesson{}).
 		Select("lesson.id, lesson.name, lesson.description, lesson.content, lesson.order, lesson.created_at").
 		Joins("INNER JOIN chapter ON chapter.id = lesson.chapter_id").
-		Where("chapter_id = ? and chapter.course_id = ? and lesson.id = ?", chapterID, courseID, id).
-		First(&lesson)
+		Where("chapter_id = ? and chapter.course_id = ? and lesson.id = ?", chapterID, courseID, id)
+	result := applyCourseTenantFilter(query, "chapter.course_id", tenant).First(&lesson)
 
 	if result.Error != nil {
 		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
@@ -64,45 +87,384 @@ func (r *LessonRepository) GetByID(courseID, chapterID, id uint) (models.Lesson,
 	return lesson, nil
 }
 
-func (r *LessonRepository) Create(lesson models.Lesson) (uint, error) {
-	result := r.DB.Create(&lesson)
+// GetCourseID returns the ID of the course the lesson belongs to
+func (r *LessonRepository) GetCourseID(lessonID uint) (uint, error) {
+	var courseIDs []uint
+	result := r.DB.Model(&models.Lesson{}).
+		Joins("INNER JOIN chapter ON chapter.id = lesson.chapter_id AND chapter.deleted_at IS NULL").
+		Where("lesson.id = ?", lessonID).
+		Limit(1).
+		Pluck("chapter.course_id", &courseIDs)
+
 	if result.Error != nil {
 		return 0, result.Error
 	}
 
+	if len(courseIDs) == 0 {
+		return 0, errors.New("lesson not found")
+	}
+
+	return courseIDs[0], nil
+}
+
+// GetOrganizationID returns the organization of the course the lesson belongs to, nil for
+// the default tenant
+func (r *LessonRepository) GetOrganizationID(lessonID uint) (*uint, error) {
+	var rows []struct {
+		OrganizationID *uint
+	}
+	result := r.DB.Model(&models.Lesson{}).
+		Joins("INNER JOIN chapter ON chapter.id = lesson.chapter_id AND chapter.deleted_at IS NULL").
+		Joins("INNER JOIN course ON course.id = chapter.course_id AND course.deleted_at IS NULL").
+		Where("lesson.id = ?", lessonID).
+		Limit(1).
+		Select("course.organization_id").
+		Scan(&rows)
+
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	if len(rows) == 0 {
+		return nil, errors.New("lesson not found")
+	}
+
+	return rows[0].OrganizationID, nil
+}
+
+// Create stores the lesson and its blocks together with its first revision
+func (r *LessonRepository) Create(lesson models.Lesson) (uint, error) {
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&lesson).Error; err != nil {
+			return err
+		}
+
+		_, err := createLessonRevision(tx, lesson, models.LessonRevision{CreatedBy: lesson.CreatedBy})
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+
 	return lesson.ID, nil
 }
 
-func (r *LessonRepository) Update(lesson models.Lesson) error {
-	result := r.DB.Model(&lesson).Updates(models.Lesson{
-		Name:        lesson.Name,
-		Description: lesson.Description,
-		Content:     lesson.Content,
-		Order:       lesson.Order,
-		UpdatedAt:   lesson.UpdatedAt,
+// Update stores the changed lesson and records its new state as the next revision.
+// When lesson.Blocks is not nil the blocks of the lesson are replaced by it and
+// lesson.Content is stored as is, even when empty. revision carries the author and,
+// for rollbacks, the restored revision number.
+func (r *LessonRepository) Update(lesson models.Lesson, revision models.LessonRevision) (models.LessonRevision, error) {
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&lesson).Omit(clause.Associations).Updates(models.Lesson{
+			Name:        lesson.Name,
+			Description: lesson.Description,
+			Content:     lesson.Content,
+			Order:       lesson.Order,
+			UpdatedAt:   lesson.UpdatedAt,
+		})
+
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return errors.New("lesson not found or no changes made")
+		}
+
+		if lesson.Blocks != nil {
+			if err := tx.Model(&lesson).Omit(clause.Associations).Update("content", lesson.Content).Error; err != nil {
+				return err
+			}
+			if err := syncLessonBlocks(tx, lesson.ID, lesson.Blocks); err != nil {
+				return err
+			}
+		}
+
+		var err error
+		revision, err = createLessonRevision(tx, lesson, revision)
+		return err
 	})
+	if err != nil {
+		return models.LessonRevision{}, err
+	}
 
-	if result.Error != nil {
-		return result.Error
+	return revision, nil
+}
+
+// createLessonRevision writes the stored state of the lesson as its next revision. The
+// lesson row is re-read under a lock, so the revision matches the database even when
+// Updates skipped zero values, and concurrent updates get consecutive revision numbers.
+func createLessonRevision(tx *gorm.DB, lesson models.Lesson, revision models.LessonRevision) (models.LessonRevision, error) {
+	var stored models.Lesson
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Select("id", "name", "description", "content", "order").
+		Where("id = ?", lesson.ID).
+		First(&stored).Error
+	if err != nil {
+		return models.LessonRevision{}, err
 	}
 
-	if result.RowsAffected == 0 {
-		return errors.New("lesson not found or no changes made")
+	var latest int
+	err = tx.Model(&models.LessonRevision{}).
+		Where("lesson_id = ?", lesson.ID).
+		Select("coalesce(max(revision), 0)").
+		Scan(&latest).Error
+	if err != nil {
+		return models.LessonRevision{}, err
 	}
 
-	return nil
+	var blocks []models.LessonBlock
+	err = tx.Where("lesson_id = ?", lesson.ID).Order("position").Find(&blocks).Error
+	if err != nil {
+		return models.LessonRevision{}, err
+	}
+
+	revision.LessonID = lesson.ID
+	revision.Revision = latest + 1
+	revision.Blocks = blocks
+	revision.Name = stored.Name
+	revision.Description = stored.Description
+	revision.Content = stored.Content
+	revision.Order = stored.Order
+	if err := tx.Create(&revision).Error; err != nil {
+		return models.LessonRevision{}, err
+	}
+
+	return revision, nil
 }
 
+// Delete moves the lesson to the trash with its content, see TrashRepository
 func (r *LessonRepository) Delete(id uint) error {
-	result := r.DB.Delete(&models.Lesson{}, id)
+	return softDeleteTree(r.DB, "lesson", id)
+}
 
-	if result.Error != nil {
-		return result.Error
+// syncLessonBlocks makes the stored blocks of the lesson match blocks. Blocks with an
+// ID are updated, blocks without one are created and all other blocks are removed.
+func syncLessonBlocks(tx *gorm.DB, lessonID uint, blocks []models.LessonBlock) error {
+	var keep []uint
+	for _, block := range blocks {
+		if block.ID != 0 {
+			keep = append(keep, block.ID)
+		}
 	}
 
-	if result.RowsAffected == 0 {
-		return errors.New("lesson not found")
+	remove := tx.Where("lesson_id = ?", lessonID)
+	if len(keep) > 0 {
+		remove = remove.Where("id NOT IN ?", keep)
+	}
+	if err := remove.Delete(&models.LessonBlock{}).Error; err != nil {
+		return err
+	}
+
+	for i := range blocks {
+		block := blocks[i]
+		block.LessonID = lessonID
+		if block.ID == 0 {
+			if err := tx.Create(&block).Error; err != nil {
+				return err
+			}
+			continue
+		}
+
+		block.UpdatedAt = time.Now()
+		result := tx.Model(&block).
+			Where("lesson_id = ?", lessonID).
+			Select("position", "type", "text", "language", "variant", "title", "url", "attachment_id", "updated_at").
+			Updates(&block)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.New("block not found")
+		}
 	}
 
 	return nil
 }
+
+// GetBlocks returns the blocks of a lesson in order
+func (r *LessonRepository) GetBlocks(lessonID uint) ([]models.LessonBlock, error) {
+	var blocks []models.LessonBlock
+	result := r.DB.Where("lesson_id = ?", lessonID).Order("position").Find(&blocks)
+
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return blocks, nil
+}
+
+// GetRevisions returns the revisions of a lesson, newest first, without their content
+func (r *LessonRepository) GetRevisions(lessonID uint) ([]models.LessonRevision, error) {
+	var revisions []models.LessonRevision
+	result := r.DB.Model(&models.LessonRevision{}).
+		Select("id, lesson_id, revision, name, \"order\", restored_from, created_by, created_at").
+		Preload("Creator").
+		Where("lesson_id = ?", lessonID).
+		Order("revision DESC").
+		Find(&revisions)
+
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return revisions, nil
+}
+
+// GetCurrentRevision returns the number of the latest revision of a lesson, 0 if it has none
+func (r *LessonRepository) GetCurrentRevision(lessonID uint) (int, error) {
+	var revision int
+	result := r.DB.Model(&models.LessonRevision{}).
+		Where("lesson_id = ?", lessonID).
+		Select("coalesce(max(revision), 0)").
+		Scan(&revision)
+
+	if result.Error != nil {
+		return 0, result.Error
+	}
+
+	return revision, nil
+}
+
+func (r *LessonRepository) GetRevision(lessonID uint, revision int) (models.LessonRevision, error) {
+	var lessonRevision models.LessonRevision
+	result := r.DB.Preload("Creator").
+		Where("lesson_id = ? and revision = ?", lessonID, revision).
+		First(&lessonRevision)
+
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return lessonRevision, errors.New("revision not found")
+		}
+		return lessonRevision, result.Error
+	}
+
+	return lessonRevision, nil
+}
+
+// Reorder sets the order of the lessons of a chapter to their position in lessonIDs,
+// which must list every lesson of the chapter exactly once
+func (r *LessonRepository) Reorder(chapterID uint, lessonIDs []uint) ([]models.Lesson, error) {
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		if err := lockRow(tx, &models.Chapter{}, chapterID); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("chapter not found")
+			}
+			return err
+		}
+
+		existing, err := chapterLessonIDs(tx, chapterID)
+		if err != nil {
+			return err
+		}
+		if !sameIDs(existing, lessonIDs) {
+			return errors.New("lesson order must list every lesson of the chapter exactly once")
+		}
+
+		return renumber(tx, &models.Lesson{}, lessonIDs)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return r.getChapterLessons(chapterID)
+}
+
+// Move puts the lesson at the 1-based position of a chapter of toCourseID, or at its end
+// when position is 0, and closes the gap it leaves in its old chapter. Both chapters are
+// renumbered in one transaction. Attachments keep their object names, which only contain
+// the lesson ID.
+func (r *LessonRepository) Move(lessonID, toCourseID, toChapterID uint, position int) ([]models.Lesson, error) {
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		var lesson models.Lesson
+		if err := tx.Select("id", "chapter_id").First(&lesson, lessonID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("lesson not found")
+			}
+			return err
+		}
+
+		// Lock both chapters in ID order so concurrent moves cannot deadlock
+		var chapters []models.Chapter
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("id", "course_id").
+			Where("id in ?", []uint{lesson.ChapterID, toChapterID}).
+			Order("id").
+			Find(&chapters).Error
+		if err != nil {
+			return err
+		}
+		found := false
+		for _, chapter := range chapters {
+			found = found || (chapter.ID == toChapterID && chapter.CourseID == toCourseID)
+		}
+		if !found {
+			return errors.New("target chapter not found in this course")
+		}
+
+		target, err := chapterLessonIDs(tx, toChapterID)
+		if err != nil {
+			return err
+		}
+		for i, id := range target {
+			if id == lessonID {
+				target = append(target[:i], target[i+1:]...)
+				break
+			}
+		}
+		if position == 0 {
+			position = len(target) + 1
+		}
+		if position < 1 || position > len(target)+1 {
+			return errors.New("invalid lesson position")
+		}
+		target = append(target[:position-1], append([]uint{lessonID}, target[position-1:]...)...)
+
+		if lesson.ChapterID != toChapterID {
+			err := tx.Model(&models.Lesson{}).
+				Where("id = ?", lessonID).
+				Updates(map[string]interface{}{"chapter_id": toChapterID, "updated_at": time.Now()}).Error
+			if err != nil {
+				return err
+			}
+
+			source, err := chapterLessonIDs(tx, lesson.ChapterID)
+			if err != nil {
+				return err
+			}
+			if err := renumber(tx, &models.Lesson{}, source); err != nil {
+				return err
+			}
+		}
+
+		return renumber(tx, &models.Lesson{}, target)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return r.getChapterLessons(toChapterID)
+}
+
+// chapterLessonIDs returns the IDs of the lessons of a chapter in their current order
+func chapterLessonIDs(tx *gorm.DB, chapterID uint) ([]uint, error) {
+	var ids []uint
+	err := tx.Model(&models.Lesson{}).
+		Where("chapter_id = ?", chapterID).
+		Order(`"order", id`).
+		Pluck("id", &ids).Error
+	return ids, err
+}
+
+func (r *LessonRepository) getChapterLessons(chapterID uint) ([]models.Lesson, error) {
+	var lessons []models.Lesson
+	err := r.DB.Select("id", "name", "order", "chapter_id").
+		Where("chapter_id = ?", chapterID).
+		Order(`"order", id`).
+		Find(&lessons).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return lessons, nil
+}
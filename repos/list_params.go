@@ -0,0 +1,50 @@
+package repos
+
+import (
+	"strings"
+	"web/schemas"
+
+	"gorm.io/gorm"
+)
+
+// applyListFilters adds the filters of a listing request for the given table
+func applyListFilters(query *gorm.DB, table string, params schemas.ListParams) *gorm.DB {
+	if params.CreatedBy != nil {
+		query = query.Where(table+".created_by = ?", *params.CreatedBy)
+	}
+	if params.NameContains != "" {
+		query = query.Where(table+".name ILIKE ?", "%"+escapeLike(params.NameContains)+"%")
+	}
+	if params.CreatedFrom != nil {
+		query = query.Where(table+".created_at >= ?", *params.CreatedFrom)
+	}
+	if params.CreatedTo != nil {
+		query = query.Where(table+".created_at <= ?", *params.CreatedTo)
+	}
+	return query
+}
+
+// applyListPage adds ordering, limit and offset of a listing request. Sort keys map
+// to columns through sortColumns; the primary key breaks ties so pages are stable.
+func applyListPage(query *gorm.DB, table string, params schemas.ListParams, sortColumns map[string]string, defaultSort string) *gorm.DB {
+	column, ok := sortColumns[params.SortBy]
+	if !ok {
+		column = sortColumns[defaultSort]
+	}
+
+	direction := " ASC"
+	if params.SortDesc {
+		direction = " DESC"
+	}
+
+	query = query.Order(column + direction).Order(table + ".id" + direction)
+	if params.Limit > 0 {
+		query = query.Limit(params.Limit).Offset(params.Offset())
+	}
+	return query
+}
+
+// escapeLike escapes the LIKE wildcards in user input
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(value)
+}
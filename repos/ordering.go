@@ -0,0 +1,49 @@
+package repos
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// lockRow locks a row of the table for the rest of the transaction. Reorders lock the
+// parent row so concurrent reorders and moves of its children are serialized.
+func lockRow(tx *gorm.DB, model interface{}, id uint) error {
+	return tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Select("id").
+		Where("id = ?", id).
+		First(model).Error
+}
+
+// sameIDs reports whether ids lists every ID of existing exactly once
+func sameIDs(existing, ids []uint) bool {
+	if len(existing) != len(ids) {
+		return false
+	}
+	remaining := make(map[uint]bool, len(existing))
+	for _, id := range existing {
+		remaining[id] = true
+	}
+	for _, id := range ids {
+		if !remaining[id] {
+			return false
+		}
+		delete(remaining, id)
+	}
+	return true
+}
+
+// renumber sets the order column of the rows to their 1-based position in ids
+func renumber(tx *gorm.DB, model interface{}, ids []uint) error {
+	now := time.Now()
+	for i, id := range ids {
+		err := tx.Model(model).
+			Where("id = ?", id).
+			Updates(map[string]interface{}{"order": i + 1, "updated_at": now}).Error
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
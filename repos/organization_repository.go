@@ -0,0 +1,131 @@
+package repos
+
+import (
+	"errors"
+	"gorm.io/gorm"
+	"time"
+	"web/models"
+)
+
+type OrganizationRepositoryInterface interface {
+	GetAll() ([]models.Organization, error)
+	GetByID(id uint) (models.Organization, error)
+	GetBySlug(slug string) (models.Organization, error)
+	ExistsBySlug(slug string) (bool, error)
+	Create(organization models.Organization) (models.Organization, error)
+	Update(organization models.Organization) (models.Organization, error)
+	Delete(id uint) error
+	IsEmpty(id uint) (bool, error)
+}
+
+var _ OrganizationRepositoryInterface = (*OrganizationRepository)(nil)
+
+type OrganizationRepository struct {
+	DB *gorm.DB
+}
+
+func NewOrganizationRepository(db *gorm.DB) *OrganizationRepository {
+	return &OrganizationRepository{
+		DB: db,
+	}
+}
+
+func (r *OrganizationRepository) GetAll() ([]models.Organization, error) {
+	var organizations []models.Organization
+	if err := r.DB.Order("name, id").Find(&organizations).Error; err != nil {
+		return nil, err
+	}
+
+	return organizations, nil
+}
+
+func (r *OrganizationRepository) GetByID(id uint) (models.Organization, error) {
+	var organization models.Organization
+	err := r.DB.First(&organization, id).Error
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return organization, errors.New("organization not found")
+		}
+		return organization, err
+	}
+
+	return organization, nil
+}
+
+func (r *OrganizationRepository) GetBySlug(slug string) (models.Organization, error) {
+	var organization models.Organization
+	err := r.DB.Where("slug = ?", slug).First(&organization).Error
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return organization, errors.New("organization not found")
+		}
+		return organization, err
+	}
+
+	return organization, nil
+}
+
+func (r *OrganizationRepository) ExistsBySlug(slug string) (bool, error) {
+	var count int64
+	if err := r.DB.Model(&models.Organization{}).Where("slug = ?", slug).Count(&count).Error; err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+func (r *OrganizationRepository) Create(organization models.Organization) (models.Organization, error) {
+	if err := r.DB.Create(&organization).Error; err != nil {
+		return models.Organization{}, err
+	}
+
+	return organization, nil
+}
+
+func (r *OrganizationRepository) Update(organization models.Organization) (models.Organization, error) {
+	organization.UpdatedAt = time.Now()
+	result := r.DB.Model(&organization).Updates(map[string]interface{}{
+		"name":       organization.Name,
+		"slug":       organization.Slug,
+		"updated_at": organization.UpdatedAt,
+	})
+
+	if result.Error != nil {
+		return models.Organization{}, result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return models.Organization{}, errors.New("organization not found")
+	}
+
+	return organization, nil
+}
+
+func (r *OrganizationRepository) Delete(id uint) error {
+	result := r.DB.Delete(&models.Organization{}, id)
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return errors.New("organization not found")
+	}
+
+	return nil
+}
+
+// IsEmpty reports whether no user and no course belongs to the organization anymore
+func (r *OrganizationRepository) IsEmpty(id uint) (bool, error) {
+	var users, courses int64
+	if err := r.DB.Model(&models.User{}).Where("organization_id = ?", id).Count(&users).Error; err != nil {
+		return false, err
+	}
+	if err := r.DB.Model(&models.Course{}).Where("organization_id = ?", id).Count(&courses).Error; err != nil {
+		return false, err
+	}
+
+	return users == 0 && courses == 0, nil
+}
@@ -0,0 +1,119 @@
+package repos
+
+import (
+	"errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"web/models"
+	"web/schemas"
+)
+
+type ProgressRepositoryInterface interface {
+	GetByUserAndLesson(userID, lessonID uint) (models.LessonProgress, error)
+	Upsert(progress models.LessonProgress) (models.LessonProgress, error)
+	Delete(userID, lessonID uint) error
+	GetCourseSummaries(userID uint, courseIDs []uint) (map[uint]schemas.ProgressSummary, error)
+	GetChapterSummaries(userID uint, chapterIDs []uint) (map[uint]schemas.ProgressSummary, error)
+}
+
+var _ ProgressRepositoryInterface = (*ProgressRepository)(nil)
+
+type ProgressRepository struct {
+	DB *gorm.DB
+}
+
+func NewProgressRepository(db *gorm.DB) *ProgressRepository {
+	return &ProgressRepository{
+		DB: db,
+	}
+}
+
+// progressSummaryRow is the grouped result of a progress aggregate query
+type progressSummaryRow struct {
+	GroupID          uint
+	TotalLessons     int
+	CompletedLessons int
+}
+
+func (r *ProgressRepository) GetByUserAndLesson(userID, lessonID uint) (models.LessonProgress, error) {
+	var progress models.LessonProgress
+	err := r.DB.Where("user_id = ? and lesson_id = ?", userID, lessonID).First(&progress).Error
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return progress, errors.New("progress not found")
+		}
+		return progress, err
+	}
+
+	return progress, nil
+}
+
+func (r *ProgressRepository) Upsert(progress models.LessonProgress) (models.LessonProgress, error) {
+	result := r.DB.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "lesson_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"status", "percent", "last_position", "completed_at", "updated_at"}),
+	}).Create(&progress)
+
+	if result.Error != nil {
+		return models.LessonProgress{}, result.Error
+	}
+
+	return progress, nil
+}
+
+func (r *ProgressRepository) Delete(userID, lessonID uint) error {
+	result := r.DB.Where("user_id = ? and lesson_id = ?", userID, lessonID).Delete(&models.LessonProgress{})
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return errors.New("progress not found")
+	}
+
+	return nil
+}
+
+func (r *ProgressRepository) GetCourseSummaries(userID uint, courseIDs []uint) (map[uint]schemas.ProgressSummary, error) {
+	return r.summarize("chapter.course_id", userID, courseIDs)
+}
+
+func (r *ProgressRepository) GetChapterSummaries(userID uint, chapterIDs []uint) (map[uint]schemas.ProgressSummary, error) {
+	return r.summarize("lesson.chapter_id", userID, chapterIDs)
+}
+
+// summarize counts total and completed lessons per groupColumn for the given user
+func (r *ProgressRepository) summarize(groupColumn string, userID uint, ids []uint) (map[uint]schemas.ProgressSummary, error) {
+	summaries := make(map[uint]schemas.ProgressSummary, len(ids))
+	if len(ids) == 0 {
+		return summaries, nil
+	}
+
+	var rows []progressSummaryRow
+	err := r.DB.Model(&models.Lesson{}).
+		Select(groupColumn+" as group_id, count(lesson.id) as total_lessons, count(lesson_progress.id) as completed_lessons").
+		Joins("INNER JOIN chapter ON chapter.id = lesson.chapter_id AND chapter.deleted_at IS NULL").
+		Joins("LEFT JOIN lesson_progress ON lesson_progress.lesson_id = lesson.id AND lesson_progress.user_id = ? AND lesson_progress.status = ?", userID, models.ProgressStatusCompleted).
+		Where(groupColumn+" IN ?", ids).
+		Group(groupColumn).
+		Scan(&rows).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		summary := schemas.ProgressSummary{
+			TotalLessons:     row.TotalLessons,
+			CompletedLessons: row.CompletedLessons,
+		}
+		if row.TotalLessons > 0 {
+			summary.Percent = row.CompletedLessons * 100 / row.TotalLessons
+		}
+		summaries[row.GroupID] = summary
+	}
+
+	return summaries, nil
+}
@@ -0,0 +1,257 @@
+package repos
+
+import (
+	"errors"
+	"fmt"
+	"gorm.io/gorm"
+	"time"
+	"web/models"
+)
+
+type QuizRepositoryInterface interface {
+	GetByLessonID(lessonID uint) ([]models.Quiz, error)
+	GetByID(lessonID, id uint) (models.Quiz, error)
+	Create(quiz models.Quiz) (uint, error)
+	Update(quiz models.Quiz) error
+	Delete(id uint) error
+	CreateAttempt(attempt models.QuizAttempt) (models.QuizAttempt, error)
+	GetAttempts(quizID, userID uint) ([]models.QuizAttempt, error)
+	GetAttempt(quizID, id uint) (models.QuizAttempt, error)
+	GetPendingAttempts(quizID uint) ([]models.QuizAttempt, error)
+	UpdateAttemptReview(attempt models.QuizAttempt) error
+}
+
+var _ QuizRepositoryInterface = (*QuizRepository)(nil)
+
+type QuizRepository struct {
+	DB *gorm.DB
+}
+
+func NewQuizRepository(db *gorm.DB) *QuizRepository {
+	return &QuizRepository{
+		DB: db,
+	}
+}
+
+func (r *QuizRepository) GetByLessonID(lessonID uint) ([]models.Quiz, error) {
+	var quizzes []models.Quiz
+	result := r.DB.Where("lesson_id = ?", lessonID).
+		Preload("Questions").
+		Order("id ASC").
+		Find(&quizzes)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return quizzes, nil
+}
+
+func (r *QuizRepository) GetByID(lessonID, id uint) (models.Quiz, error) {
+	var quiz models.Quiz
+	result := r.DB.Where("lesson_id = ? and id = ?", lessonID, id).
+		Preload("Questions", func(db *gorm.DB) *gorm.DB {
+			return db.Order(`"order" ASC`)
+		}).
+		Preload("Questions.Options", func(db *gorm.DB) *gorm.DB {
+			return db.Order(`"order" ASC`)
+		}).
+		First(&quiz)
+
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return quiz, errors.New("quiz not found")
+		}
+		return quiz, result.Error
+	}
+
+	return quiz, nil
+}
+
+func (r *QuizRepository) Create(quiz models.Quiz) (uint, error) {
+	result := r.DB.Create(&quiz)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+
+	return quiz.ID, nil
+}
+
+// Update saves the quiz fields and its questions. Questions and options with an ID are
+// changed in place and the others are added, so answers of earlier attempts keep pointing
+// at their questions. Questions left out are soft deleted, options left out are removed.
+func (r *QuizRepository) Update(quiz models.Quiz) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&quiz).Select("title", "description", "passing_score", "updated_at").Updates(models.Quiz{
+			Title:        quiz.Title,
+			Description:  quiz.Description,
+			PassingScore: quiz.PassingScore,
+			UpdatedAt:    time.Now(),
+		})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.New("quiz not found or no changes made")
+		}
+
+		keep := make([]uint, 0, len(quiz.Questions))
+		for _, question := range quiz.Questions {
+			question.QuizID = quiz.ID
+			if question.ID == 0 {
+				if err := tx.Create(&question).Error; err != nil {
+					return err
+				}
+			} else {
+				if err := updateQuizQuestion(tx, question); err != nil {
+					return err
+				}
+			}
+			keep = append(keep, question.ID)
+		}
+
+		return tx.Where("quiz_id = ? and id not in ?", quiz.ID, keep).Delete(&models.QuizQuestion{}).Error
+	})
+}
+
+// updateQuizQuestion writes the fields of an existing question and makes its options
+// match question.Options
+func updateQuizQuestion(tx *gorm.DB, question models.QuizQuestion) error {
+	result := tx.Model(&models.QuizQuestion{}).
+		Where("id = ? and quiz_id = ?", question.ID, question.QuizID).
+		Select("text", "points", "order", "correct_number", "tolerance").
+		Updates(models.QuizQuestion{
+			Text:          question.Text,
+			Points:        question.Points,
+			Order:         question.Order,
+			CorrectNumber: question.CorrectNumber,
+			Tolerance:     question.Tolerance,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("question %d not found", question.ID)
+	}
+
+	// 0 keeps the list non-empty, gorm turns an empty list into NOT IN (NULL)
+	keep := []uint{0}
+	for _, option := range question.Options {
+		option.QuestionID = question.ID
+		if option.ID == 0 {
+			if err := tx.Create(&option).Error; err != nil {
+				return err
+			}
+		} else {
+			err := tx.Model(&models.QuizOption{}).
+				Where("id = ? and question_id = ?", option.ID, question.ID).
+				Select("text", "is_correct", "order").
+				Updates(models.QuizOption{Text: option.Text, IsCorrect: option.IsCorrect, Order: option.Order}).Error
+			if err != nil {
+				return err
+			}
+		}
+		keep = append(keep, option.ID)
+	}
+
+	return tx.Where("question_id = ? and id not in ?", question.ID, keep).Delete(&models.QuizOption{}).Error
+}
+
+func (r *QuizRepository) Delete(id uint) error {
+	result := r.DB.Delete(&models.Quiz{}, id)
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return errors.New("quiz not found")
+	}
+
+	return nil
+}
+
+func (r *QuizRepository) CreateAttempt(attempt models.QuizAttempt) (models.QuizAttempt, error) {
+	result := r.DB.Create(&attempt)
+	if result.Error != nil {
+		return models.QuizAttempt{}, result.Error
+	}
+
+	return attempt, nil
+}
+
+func (r *QuizRepository) GetAttempts(quizID, userID uint) ([]models.QuizAttempt, error) {
+	var attempts []models.QuizAttempt
+	result := r.DB.Where("quiz_id = ? and user_id = ?", quizID, userID).
+		Preload("Answers").
+		Order("submitted_at DESC").
+		Find(&attempts)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return attempts, nil
+}
+
+func (r *QuizRepository) GetAttempt(quizID, id uint) (models.QuizAttempt, error) {
+	var attempt models.QuizAttempt
+	result := r.DB.Where("quiz_id = ? and id = ?", quizID, id).
+		Preload("Answers").
+		First(&attempt)
+
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return attempt, errors.New("quiz attempt not found")
+		}
+		return attempt, result.Error
+	}
+
+	return attempt, nil
+}
+
+// GetPendingAttempts returns the attempts of the quiz that still have answers to review
+func (r *QuizRepository) GetPendingAttempts(quizID uint) ([]models.QuizAttempt, error) {
+	var attempts []models.QuizAttempt
+	result := r.DB.Where("quiz_id = ? and pending_review", quizID).
+		Preload("Answers").
+		Order("id ASC").
+		Find(&attempts)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return attempts, nil
+}
+
+// UpdateAttemptReview stores the grades of the answers of a reviewed attempt with its new
+// score and result
+func (r *QuizRepository) UpdateAttemptReview(attempt models.QuizAttempt) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		for _, answer := range attempt.Answers {
+			err := tx.Model(&models.QuizAnswer{}).
+				Where("id = ? and attempt_id = ?", answer.ID, attempt.ID).
+				Updates(map[string]interface{}{
+					"is_correct":     answer.IsCorrect,
+					"points_awarded": answer.PointsAwarded,
+				}).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		result := tx.Model(&models.QuizAttempt{}).
+			Where("id = ?", attempt.ID).
+			Updates(map[string]interface{}{
+				"score":          attempt.Score,
+				"passed":         attempt.Passed,
+				"pending_review": attempt.PendingReview,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.New("quiz attempt not found")
+		}
+
+		return nil
+	})
+}
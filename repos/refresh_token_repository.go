@@ -0,0 +1,91 @@
+package repos
+
+import (
+	"errors"
+	"gorm.io/gorm"
+	"time"
+	"web/models"
+)
+
+type RefreshTokenRepositoryInterface interface {
+	Create(token models.RefreshToken) (models.RefreshToken, error)
+	GetByID(id uint) (models.RefreshToken, error)
+	GetByHash(tokenHash string) (models.RefreshToken, error)
+	Rotate(id uint, tokenHash, newTokenHash string, expiresAt time.Time) error
+	Revoke(id uint) error
+}
+
+var _ RefreshTokenRepositoryInterface = (*RefreshTokenRepository)(nil)
+
+type RefreshTokenRepository struct {
+	DB *gorm.DB
+}
+
+func NewRefreshTokenRepository(db *gorm.DB) *RefreshTokenRepository {
+	return &RefreshTokenRepository{
+		DB: db,
+	}
+}
+
+func (r *RefreshTokenRepository) Create(token models.RefreshToken) (models.RefreshToken, error) {
+	if err := r.DB.Create(&token).Error; err != nil {
+		return models.RefreshToken{}, err
+	}
+
+	return token, nil
+}
+
+func (r *RefreshTokenRepository) GetByID(id uint) (models.RefreshToken, error) {
+	var token models.RefreshToken
+	err := r.DB.First(&token, id).Error
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return token, errors.New("refresh token not found")
+		}
+		return token, err
+	}
+
+	return token, nil
+}
+
+func (r *RefreshTokenRepository) GetByHash(tokenHash string) (models.RefreshToken, error) {
+	var token models.RefreshToken
+	err := r.DB.Where("token_hash = ?", tokenHash).First(&token).Error
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return token, errors.New("refresh token not found")
+		}
+		return token, err
+	}
+
+	return token, nil
+}
+
+// Rotate replaces the refresh token of an active session. It fails when the session was
+// revoked or its token was already rotated, so a refresh token can only be used once.
+func (r *RefreshTokenRepository) Rotate(id uint, tokenHash, newTokenHash string, expiresAt time.Time) error {
+	result := r.DB.Model(&models.RefreshToken{}).
+		Where("id = ? AND token_hash = ? AND revoked_at IS NULL", id, tokenHash).
+		Updates(map[string]interface{}{
+			"token_hash": newTokenHash,
+			"expires_at": expiresAt,
+			"updated_at": time.Now(),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("refresh token not found")
+	}
+	return nil
+}
+
+// Revoke ends a session, revoking a session twice keeps the first revocation time
+func (r *RefreshTokenRepository) Revoke(id uint) error {
+	result := r.DB.Model(&models.RefreshToken{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Update("revoked_at", time.Now())
+	return result.Error
+}
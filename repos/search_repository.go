@@ -0,0 +1,192 @@
+package repos
+
+import (
+	"fmt"
+	"strings"
+	"web/schemas"
+
+	"gorm.io/gorm"
+)
+
+type SearchRepositoryInterface interface {
+	Search(params schemas.SearchParams) ([]schemas.SearchHit, int64, error)
+}
+
+var _ SearchRepositoryInterface = (*SearchRepository)(nil)
+
+type SearchRepository struct {
+	DB *gorm.DB
+}
+
+func NewSearchRepository(db *gorm.DB) *SearchRepository {
+	return &SearchRepository{
+		DB: db,
+	}
+}
+
+// searchHeadlineOptions controls the snippets returned by ts_headline. The content is not
+// escaped by ts_headline, so matches are marked with control characters rather than HTML.
+var searchHeadlineOptions = fmt.Sprintf(`StartSel="%s", StopSel="%s", MaxWords=35, MinWords=15, MaxFragments=2`,
+	schemas.SearchHighlightStart, schemas.SearchHighlightStop)
+
+// searchMemberCondition matches the courses the user collaborates on
+const searchMemberCondition = `EXISTS (
+	SELECT 1 FROM course_member
+	WHERE course_member.course_id = course.id AND course_member.user_id = @user_id)`
+
+// searchAccessCondition limits hits to courses the user owns or collaborates on, and to the
+// published courses the user is enrolled in
+const searchAccessCondition = `(course.created_by = @user_id OR ` + searchMemberCondition + ` OR EXISTS (
+	SELECT 1 FROM enrollment
+	WHERE enrollment.course_id = course.id AND enrollment.user_id = @user_id AND enrollment.deleted_at IS NULL
+		AND course.status = 'published'))`
+
+// searchVisibilityCondition limits course hits to published courses and the drafts of the
+// user's own courses
+const searchVisibilityCondition = `(course.status = 'published' OR course.created_by = @user_id OR ` + searchMemberCondition + `)`
+
+// searchSelects holds one select of the search union per hit type. Every select
+// returns the columns of searchHitRow.
+var searchSelects = map[string]string{
+	schemas.SearchTypeCourse: `SELECT 'course' AS type, course.id AS id, course.name AS name,
+	ts_rank(course.search_vector, query) AS rank,
+	ts_headline('english', coalesce(course.description, course.name), query, @headline) AS snippet,
+	course.id AS course_id, course.name AS course_name,
+	NULL::bigint AS chapter_id, NULL::varchar AS chapter_name,
+	NULL::bigint AS lesson_id, NULL::varchar AS lesson_name
+FROM course, websearch_to_tsquery('english', @query) AS query
+WHERE course.deleted_at IS NULL AND course.search_vector @@ query`,
+
+	schemas.SearchTypeChapter: `SELECT 'chapter' AS type, chapter.id AS id, chapter.name AS name,
+	ts_rank(chapter.search_vector, query) AS rank,
+	ts_headline('english', coalesce(chapter.description, chapter.name), query, @headline) AS snippet,
+	course.id AS course_id, course.name AS course_name,
+	chapter.id AS chapter_id, chapter.name AS chapter_name,
+	NULL::bigint AS lesson_id, NULL::varchar AS lesson_name
+FROM chapter
+	INNER JOIN course ON course.id = chapter.course_id AND course.deleted_at IS NULL,
+	websearch_to_tsquery('english', @query) AS query
+WHERE chapter.deleted_at IS NULL AND chapter.search_vector @@ query`,
+
+	schemas.SearchTypeLesson: `SELECT 'lesson' AS type, lesson.id AS id, lesson.name AS name,
+	ts_rank(lesson.search_vector, query) AS rank,
+	ts_headline('english', concat_ws(' ', lesson.description, lesson.content), query, @headline) AS snippet,
+	course.id AS course_id, course.name AS course_name,
+	chapter.id AS chapter_id, chapter.name AS chapter_name,
+	lesson.id AS lesson_id, lesson.name AS lesson_name
+FROM lesson
+	INNER JOIN chapter ON chapter.id = lesson.chapter_id AND chapter.deleted_at IS NULL
+	INNER JOIN course ON course.id = chapter.course_id AND course.deleted_at IS NULL,
+	websearch_to_tsquery('english', @query) AS query
+WHERE lesson.deleted_at IS NULL AND lesson.search_vector @@ query`,
+}
+
+// searchHitRow is a single row of the search union
+type searchHitRow struct {
+	Type        string
+	ID          uint
+	Name        string
+	Rank        float64
+	Snippet     string
+	CourseID    uint
+	CourseName  string
+	ChapterID   *uint
+	ChapterName *string
+	LessonID    *uint
+	LessonName  *string
+}
+
+// Search runs a ranked full-text search over courses, chapters and lessons
+func (r *SearchRepository) Search(params schemas.SearchParams) ([]schemas.SearchHit, int64, error) {
+	types := params.Types
+	if len(types) == 0 {
+		types = []string{schemas.SearchTypeCourse, schemas.SearchTypeChapter, schemas.SearchTypeLesson}
+	}
+
+	var selects []string
+	for _, hitType := range types {
+		selectSQL, ok := searchSelects[hitType]
+		if !ok {
+			continue
+		}
+		if params.CourseID != nil {
+			selectSQL += " AND course.id = @course_id"
+		}
+		if params.Tenant != nil {
+			if params.Tenant.OrganizationID == nil {
+				selectSQL += " AND course.organization_id IS NULL"
+			} else {
+				selectSQL += " AND course.organization_id = @organization_id"
+			}
+		}
+		// Published courses stay discoverable, their content is only searchable with access
+		if !params.Unrestricted {
+			if hitType == schemas.SearchTypeCourse {
+				selectSQL += " AND " + searchVisibilityCondition
+			} else {
+				selectSQL += " AND " + searchAccessCondition
+			}
+		}
+		selects = append(selects, selectSQL)
+	}
+	if len(selects) == 0 {
+		return []schemas.SearchHit{}, 0, nil
+	}
+
+	union := strings.Join(selects, "\nUNION ALL\n")
+	args := map[string]interface{}{
+		"query":    params.Query,
+		"headline": searchHeadlineOptions,
+		"user_id":  params.UserID,
+		"limit":    params.Limit,
+		"offset":   0,
+	}
+	if params.CourseID != nil {
+		args["course_id"] = *params.CourseID
+	}
+	if params.Tenant != nil && params.Tenant.OrganizationID != nil {
+		args["organization_id"] = *params.Tenant.OrganizationID
+	}
+	if params.Page > 1 {
+		args["offset"] = (params.Page - 1) * params.Limit
+	}
+
+	var total int64
+	err := r.DB.Raw("SELECT count(*) FROM ("+union+") AS hits", args).Scan(&total).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var rows []searchHitRow
+	err = r.DB.Raw("SELECT * FROM ("+union+") AS hits ORDER BY rank DESC, type, id LIMIT @limit OFFSET @offset", args).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	hits := make([]schemas.SearchHit, 0, len(rows))
+	for _, row := range rows {
+		hit := schemas.SearchHit{
+			Type:    row.Type,
+			ID:      row.ID,
+			Name:    row.Name,
+			Snippet: row.Snippet,
+			Rank:    row.Rank,
+			Path: schemas.SearchPath{
+				CourseID:   row.CourseID,
+				CourseName: row.CourseName,
+				ChapterID:  row.ChapterID,
+				LessonID:   row.LessonID,
+			},
+		}
+		if row.ChapterName != nil {
+			hit.Path.ChapterName = *row.ChapterName
+		}
+		if row.LessonName != nil {
+			hit.Path.LessonName = *row.LessonName
+		}
+		hits = append(hits, hit)
+	}
+
+	return hits, total, nil
+}
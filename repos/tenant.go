@@ -0,0 +1,55 @@
+package repos
+
+import (
+	"fmt"
+	"web/schemas"
+
+	"gorm.io/gorm"
+)
+
+// applyTenantFilter limits a query to the rows of table that belong to the organization of
+// the filter. Chapters, lessons and attachments belong to the organization of their course.
+// A nil filter leaves the query unscoped.
+func applyTenantFilter(query *gorm.DB, table string, tenant *schemas.TenantFilter) *gorm.DB {
+	if tenant == nil {
+		return query
+	}
+	if tenant.OrganizationID == nil {
+		return query.Where(table + ".organization_id IS NULL")
+	}
+	return query.Where(table+".organization_id = ?", *tenant.OrganizationID)
+}
+
+// applyCourseTenantFilter limits a query to the rows whose course, named by courseColumn,
+// belongs to the organization of the filter. A nil filter leaves the query unscoped.
+func applyCourseTenantFilter(query *gorm.DB, courseColumn string, tenant *schemas.TenantFilter) *gorm.DB {
+	if tenant == nil {
+		return query
+	}
+	if tenant.OrganizationID == nil {
+		return query.Where(courseColumn + " IN (SELECT id FROM course WHERE organization_id IS NULL)")
+	}
+	return query.Where(courseColumn+" IN (SELECT id FROM course WHERE organization_id = ?)", *tenant.OrganizationID)
+}
+
+// applyLessonTenantFilter limits a query to the rows whose lesson, named by lessonColumn,
+// belongs to a course of the organization of the filter
+func applyLessonTenantFilter(query *gorm.DB, lessonColumn string, tenant *schemas.TenantFilter) *gorm.DB {
+	if tenant == nil {
+		return query
+	}
+	lessons := query.Session(&gorm.Session{NewDB: true}).
+		Table("lesson").
+		Select("lesson.id").
+		Joins("INNER JOIN chapter ON chapter.id = lesson.chapter_id")
+	return query.Where(lessonColumn+" IN (?)", applyCourseTenantFilter(lessons, "chapter.course_id", tenant))
+}
+
+// TenantObjectPrefix is the prefix of the storage objects of an organization, which keeps
+// the files of the tenants apart in the shared bucket. The default tenant has no prefix.
+func TenantObjectPrefix(organizationID *uint) string {
+	if organizationID == nil {
+		return ""
+	}
+	return fmt.Sprintf("org-%d/", *organizationID)
+}
@@ -0,0 +1,286 @@
+package repos
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"web/schemas"
+
+	"gorm.io/gorm"
+)
+
+// trashResource describes how a soft deletable resource reaches its course. Rows of table
+// are aliased t and joins reach the course as c. ancestors are the aliases of the parents
+// from the direct parent up to the course, descendants select the content below the
+// resource of an ID and orderedWithin is the column grouping the siblings of ordered rows.
+type trashResource struct {
+	table         string
+	joins         string
+	ancestors     []string
+	descendants   []trashScope
+	orderedWithin string
+}
+
+// trashScope selects the rows of table below a resource, the condition takes its ID
+type trashScope struct {
+	table     string
+	condition string
+}
+
+var trashResources = map[string]trashResource{
+	"course": {
+		table: "course",
+		joins: "JOIN course c ON c.id = t.id",
+		descendants: []trashScope{
+			{"chapter", "course_id = ?"},
+			{"lesson", "chapter_id IN (SELECT id FROM chapter WHERE course_id = ?)"},
+			{"attachment", "lesson_id IN (SELECT lesson.id FROM lesson JOIN chapter ON chapter.id = lesson.chapter_id WHERE chapter.course_id = ?)"},
+		},
+	},
+	"chapter": {
+		table:     "chapter",
+		joins:     "JOIN course c ON c.id = t.course_id",
+		ancestors: []string{"c"},
+		descendants: []trashScope{
+			{"lesson", "chapter_id = ?"},
+			{"attachment", "lesson_id IN (SELECT id FROM lesson WHERE chapter_id = ?)"},
+		},
+		orderedWithin: "course_id",
+	},
+	"lesson": {
+		table:     "lesson",
+		joins:     "JOIN chapter p ON p.id = t.chapter_id JOIN course c ON c.id = p.course_id",
+		ancestors: []string{"p", "c"},
+		descendants: []trashScope{
+			{"attachment", "lesson_id = ?"},
+		},
+		orderedWithin: "chapter_id",
+	},
+	"attachment": {
+		table:     "attachment",
+		joins:     "JOIN lesson p ON p.id = t.lesson_id JOIN chapter ch ON ch.id = p.chapter_id JOIN course c ON c.id = ch.course_id",
+		ancestors: []string{"p", "ch", "c"},
+	},
+}
+
+// TrashRepositoryInterface reads, restores and purges soft deleted courses, chapters,
+// lessons and attachments
+type TrashRepositoryInterface interface {
+	GetAll(params schemas.TrashParams) ([]schemas.TrashItem, int64, error)
+	GetByID(resourceType string, id uint) (schemas.TrashItem, error)
+	Restore(resourceType string, id uint) error
+	Purge(deletedBefore time.Time) ([]string, error)
+}
+
+var _ TrashRepositoryInterface = (*TrashRepository)(nil)
+
+type TrashRepository struct {
+	DB *gorm.DB
+}
+
+func NewTrashRepository(db *gorm.DB) *TrashRepository {
+	return &TrashRepository{
+		DB: db,
+	}
+}
+
+// GetAll lists the deleted resources of a type, newest first. Content deleted together with
+// its parent is left out, it is restored with the parent.
+func (r *TrashRepository) GetAll(params schemas.TrashParams) ([]schemas.TrashItem, int64, error) {
+	resource, ok := trashResources[params.ResourceType]
+	if !ok {
+		return nil, 0, fmt.Errorf("unknown trash resource type %q", params.ResourceType)
+	}
+
+	query := r.DB.Table(resource.table + " AS t").Joins(resource.joins).Where("t.deleted_at IS NOT NULL")
+	if len(resource.ancestors) > 0 {
+		parent := resource.ancestors[0]
+		query = query.Where(fmt.Sprintf("(%[1]s.deleted_at IS NULL OR %[1]s.deleted_at <> t.deleted_at)", parent))
+	}
+	query = applyTenantFilter(query, "c", params.Tenant)
+	if params.ManagedBy != nil {
+		query = query.Where("(c.created_by = ? OR EXISTS (SELECT 1 FROM course_member m WHERE m.course_id = c.id AND m.user_id = ? AND m.role IN ?))",
+			*params.ManagedBy, *params.ManagedBy, params.ManagerRoles)
+	}
+	query = query.Session(&gorm.Session{})
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var items []schemas.TrashItem
+	offset := 0
+	if params.Page > 1 {
+		offset = (params.Page - 1) * params.Limit
+	}
+	err := query.Select(trashItemColumns(resource)).
+		Order("t.deleted_at DESC, t.id DESC").
+		Offset(offset).
+		Limit(params.Limit).
+		Scan(&items).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	for i := range items {
+		items[i].ResourceType = params.ResourceType
+	}
+	return items, total, nil
+}
+
+// GetByID returns a deleted resource, reporting resources that are not deleted as not found
+func (r *TrashRepository) GetByID(resourceType string, id uint) (schemas.TrashItem, error) {
+	resource, ok := trashResources[resourceType]
+	if !ok {
+		return schemas.TrashItem{}, fmt.Errorf("unknown trash resource type %q", resourceType)
+	}
+
+	var item schemas.TrashItem
+	result := r.DB.Table(resource.table+" AS t").
+		Joins(resource.joins).
+		Select(trashItemColumns(resource)).
+		Where("t.id = ? AND t.deleted_at IS NOT NULL", id).
+		Scan(&item)
+	if result.Error != nil {
+		return schemas.TrashItem{}, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return schemas.TrashItem{}, errors.New(resourceType + " not found")
+	}
+
+	item.ResourceType = resourceType
+	return item, nil
+}
+
+// Restore undeletes a resource and the content that was deleted together with it. Restored
+// chapters and lessons are placed after their current siblings.
+func (r *TrashRepository) Restore(resourceType string, id uint) error {
+	resource, ok := trashResources[resourceType]
+	if !ok {
+		return fmt.Errorf("unknown trash resource type %q", resourceType)
+	}
+
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		var deletedAt time.Time
+		err := tx.Raw("SELECT deleted_at FROM "+resource.table+" WHERE id = ? AND deleted_at IS NOT NULL FOR UPDATE", id).
+			Row().
+			Scan(&deletedAt)
+		if errors.Is(err, sql.ErrNoRows) {
+			return errors.New(resourceType + " not found")
+		}
+		if err != nil {
+			return err
+		}
+
+		restore := "UPDATE " + resource.table + " AS t SET deleted_at = NULL"
+		if resource.orderedWithin != "" {
+			restore += fmt.Sprintf(`, "order" = (SELECT COALESCE(MAX(s."order"), 0) + 1 FROM %[1]s s WHERE s.%[2]s = t.%[2]s AND s.deleted_at IS NULL)`,
+				resource.table, resource.orderedWithin)
+		}
+		if err := tx.Exec(restore+" WHERE t.id = ?", id).Error; err != nil {
+			return err
+		}
+
+		for _, scope := range resource.descendants {
+			err := tx.Exec("UPDATE "+scope.table+" SET deleted_at = NULL WHERE deleted_at = ? AND "+scope.condition, deletedAt, id).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Purge deletes the resources deleted before deletedBefore for good, together with the
+// content below them. It returns the storage objects of the purged attachments that no
+// remaining attachment refers to.
+func (r *TrashRepository) Purge(deletedBefore time.Time) ([]string, error) {
+	var objectNames []string
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		var purged []string
+		err := tx.Raw(`SELECT DISTINCT t.url FROM attachment t
+	JOIN lesson l ON l.id = t.lesson_id
+	JOIN chapter ch ON ch.id = l.chapter_id
+	JOIN course c ON c.id = ch.course_id
+WHERE t.deleted_at < @before OR l.deleted_at < @before OR ch.deleted_at < @before OR c.deleted_at < @before`,
+			sql.Named("before", deletedBefore)).Scan(&purged).Error
+		if err != nil {
+			return err
+		}
+
+		// The foreign keys delete the content below the purged rows and keep the
+		// certificates of purged courses without their course
+		for _, table := range []string{"course", "chapter", "lesson", "attachment"} {
+			if err := tx.Exec("DELETE FROM "+table+" WHERE deleted_at < ?", deletedBefore).Error; err != nil {
+				return err
+			}
+		}
+
+		if len(purged) == 0 {
+			return nil
+		}
+		var kept []string
+		if err := tx.Raw("SELECT DISTINCT url FROM attachment WHERE url IN ?", purged).Scan(&kept).Error; err != nil {
+			return err
+		}
+		referenced := make(map[string]bool, len(kept))
+		for _, url := range kept {
+			referenced[url] = true
+		}
+		for _, url := range purged {
+			if !referenced[url] {
+				objectNames = append(objectNames, url)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return objectNames, nil
+}
+
+// trashItemColumns selects the fields of a TrashItem for a resource aliased t
+func trashItemColumns(resource trashResource) string {
+	parentDeleted := "false"
+	if len(resource.ancestors) > 0 {
+		conditions := make([]string, len(resource.ancestors))
+		for i, alias := range resource.ancestors {
+			conditions[i] = alias + ".deleted_at IS NOT NULL"
+		}
+		parentDeleted = "(" + strings.Join(conditions, " OR ") + ")"
+	}
+
+	return "t.id, t.name, c.id AS course_id, c.name AS course_name, t.deleted_at, " +
+		"c.created_by AS course_created_by, c.organization_id, " + parentDeleted + " AS parent_deleted"
+}
+
+// softDeleteTree soft deletes a course, chapter or lesson and the content below it that is
+// not deleted yet. The rows share one deleted_at, so restoring the resource restores the
+// rows deleted with it while content deleted earlier stays in the trash.
+func softDeleteTree(db *gorm.DB, resourceType string, id uint) error {
+	resource := trashResources[resourceType]
+	deletedAt := time.Now()
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Exec("UPDATE "+resource.table+" SET deleted_at = ? WHERE id = ? AND deleted_at IS NULL", deletedAt, id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.New(resourceType + " not found")
+		}
+
+		for _, scope := range resource.descendants {
+			err := tx.Exec("UPDATE "+scope.table+" SET deleted_at = ? WHERE deleted_at IS NULL AND "+scope.condition, deletedAt, id).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -3,16 +3,21 @@ package repos
 import (
 	"errors"
 	"gorm.io/gorm"
+	"time"
 	"web/models"
 )
 
 type UserRepositoryInterface interface {
 	Create(user models.User) (models.User, error)
+	GetByID(id uint) (models.User, error)
 	GetByUsername(username string) (models.User, error)
 	GetByEmail(email string) (models.User, error)
 	GetBySub(sub string) (models.User, error)
 	Update(user models.User) (models.User, error)
 	UpdatePassword(userID uint, hashedPassword string) error
+	UpdateOrganization(userID uint, organizationID *uint) error
+	UpdateSub(userID uint, sub string) error
+	UpdateRoles(userID uint, roles string) error
 }
 
 var _ UserRepositoryInterface = (*UserRepository)(nil)
@@ -36,6 +41,20 @@ func (r *UserRepository) Create(user models.User) (models.User, error) {
 	return user, nil
 }
 
+func (r *UserRepository) GetByID(id uint) (models.User, error) {
+	var user models.User
+	err := r.DB.First(&user, id).Error
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return user, errors.New("user not found")
+		}
+		return user, err
+	}
+
+	return user, nil
+}
+
 func (r *UserRepository) GetByUsername(username string) (models.User, error) {
 	var user models.User
 	err := r.DB.Where("username = ?", username).First(&user).Error
@@ -90,10 +109,53 @@ func (r *UserRepository) Update(user models.User) (models.User, error) {
 	return user, nil
 }
 
+// UpdatePassword sets the password hash of the user and revokes the refresh tokens of its
+// sessions, so that only the new password grants access
 func (r *UserRepository) UpdatePassword(userID uint, hashedPassword string) error {
-	result := r.DB.Model(&models.User{}).Where("id = ?", userID).Update("password", hashedPassword)
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.User{}).Where("id = ?", userID).Update("password", hashedPassword)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.New("user not found")
+		}
+
+		return tx.Model(&models.RefreshToken{}).
+			Where("user_id = ? AND revoked_at IS NULL", userID).
+			Update("revoked_at", time.Now()).Error
+	})
+}
+
+func (r *UserRepository) UpdateOrganization(userID uint, organizationID *uint) error {
+	result := r.DB.Model(&models.User{}).Where("id = ?", userID).Update("organization_id", organizationID)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("user not found")
+	}
+	return nil
+}
+
+func (r *UserRepository) UpdateSub(userID uint, sub string) error {
+	result := r.DB.Model(&models.User{}).Where("id = ?", userID).Update("sub", sub)
 	if result.Error != nil {
 		return result.Error
 	}
+	if result.RowsAffected == 0 {
+		return errors.New("user not found")
+	}
+	return nil
+}
+
+func (r *UserRepository) UpdateRoles(userID uint, roles string) error {
+	result := r.DB.Model(&models.User{}).Where("id = ?", userID).Update("roles", roles)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("user not found")
+	}
 	return nil
 }
@@ -0,0 +1,18 @@
+package schemas
+
+import "time"
+
+// AuditLogParams holds the filters of an audit log query. Entries are listed newest first.
+type AuditLogParams struct {
+	ActorID      *uint
+	Action       string
+	ResourceType string
+	ResourceID   *uint
+	RequestID    string
+	From         *time.Time
+	To           *time.Time
+	// Tenant limits entries to the actors of one organization, nil lists every organization
+	Tenant *TenantFilter
+	Page   int
+	Limit  int
+}
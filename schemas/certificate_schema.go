@@ -0,0 +1,28 @@
+package schemas
+
+import "time"
+
+type IssueCertificateRequest struct {
+	UserID uint `json:"user_id" example:"1"`
+}
+
+type CertificateResponse struct {
+	ID          uint      `json:"id" example:"1"`
+	Code        string    `json:"code" example:"9F3A-11C2-7B0D-E4A5"`
+	UserID      uint      `json:"user_id" example:"1"`
+	Username    string    `json:"username" example:"johndoe"`
+	CourseID    *uint     `json:"course_id,omitempty" example:"1"`
+	CourseName  string    `json:"course_name" example:"Introduction to Go Programming"`
+	IssuedBy    *uint     `json:"issued_by,omitempty" example:"2"`
+	IssuedAt    time.Time `json:"issued_at" example:"2020-01-01T12:00:00Z"`
+	DownloadURL string    `json:"download_url" example:"/api/v1/certificates/9F3A-11C2-7B0D-E4A5/download"`
+	VerifyURL   string    `json:"verify_url" example:"/api/v1/certificates/9F3A-11C2-7B0D-E4A5/verify"`
+}
+
+type CertificateVerificationResponse struct {
+	Valid      bool      `json:"valid" example:"true"`
+	Code       string    `json:"code" example:"9F3A-11C2-7B0D-E4A5"`
+	Username   string    `json:"username" example:"johndoe"`
+	CourseName string    `json:"course_name" example:"Introduction to Go Programming"`
+	IssuedAt   time.Time `json:"issued_at" example:"2020-01-01T12:00:00Z"`
+}
@@ -0,0 +1,89 @@
+package schemas
+
+import "time"
+
+const (
+	// CourseArchiveFormatVersion is the manifest format written by course exports
+	CourseArchiveFormatVersion = 1
+	// CourseArchiveManifestName is the name of the manifest inside a course archive
+	CourseArchiveManifestName = "manifest.json"
+)
+
+const (
+	ImportOnConflictFail    = "fail"
+	ImportOnConflictResolve = "resolve"
+)
+
+const (
+	ImportConflictCourseName        = "course_name"
+	ImportConflictMissingFile       = "missing_attachment_file"
+	ImportConflictUnknownAttachment = "unknown_attachment"
+)
+
+// CourseArchiveManifest describes the course stored in a course archive. Attachment
+// binaries are stored next to it under the names given in CourseArchiveAttachment.File.
+type CourseArchiveManifest struct {
+	FormatVersion int                 `json:"format_version" example:"1"`
+	ExportedAt    time.Time           `json:"exported_at" example:"2020-01-01T12:00:00Z"`
+	Course        CourseArchiveCourse `json:"course"`
+}
+
+type CourseArchiveCourse struct {
+	ID          uint                   `json:"id" example:"1"`
+	Name        string                 `json:"name" example:"Introduction to Go Programming"`
+	Description string                 `json:"description" example:"Learn the basics of Go programming language"`
+	Chapters    []CourseArchiveChapter `json:"chapters"`
+}
+
+type CourseArchiveChapter struct {
+	ID          uint                  `json:"id" example:"1"`
+	Name        string                `json:"name" example:"Chapter 1: Getting Started"`
+	Description string                `json:"description" example:"Introduction to the course material"`
+	Order       int                   `json:"order" example:"1"`
+	Lessons     []CourseArchiveLesson `json:"lessons"`
+}
+
+type CourseArchiveLesson struct {
+	ID          uint                      `json:"id" example:"1"`
+	Name        string                    `json:"name" example:"Lesson 1: Introduction"`
+	Description string                    `json:"description" example:"Overview of the chapter content"`
+	Content     string                    `json:"content" example:"# Introduction"`
+	Order       int                       `json:"order" example:"1"`
+	Blocks      []LessonBlockRequest      `json:"blocks"`
+	Attachments []CourseArchiveAttachment `json:"attachments"`
+}
+
+type CourseArchiveAttachment struct {
+	ID   uint   `json:"id" example:"1"`
+	Name string `json:"name" example:"lecture_slides.pdf"`
+	File string `json:"file" example:"attachments/1/lecture_slides.pdf"`
+	Size int64  `json:"size" example:"1024"`
+}
+
+type ImportCourseRequest struct {
+	// Name overrides the course name of the archive
+	Name string `form:"name" example:"Introduction to Go Programming"`
+	// OnConflict is fail (default) to reject an archive with conflicts or resolve to apply the reported resolutions
+	OnConflict string `form:"on_conflict" example:"fail"`
+}
+
+// ImportConflict is a problem found in a course archive and how it is resolved
+type ImportConflict struct {
+	Type       string `json:"type" example:"course_name"`
+	Path       string `json:"path" example:"course"`
+	Message    string `json:"message" example:"a course named Introduction to Go Programming already exists"`
+	Resolution string `json:"resolution" example:"imported as Introduction to Go Programming (imported)"`
+}
+
+// CourseIDMap maps the IDs of a source course tree to the IDs of the created records
+type CourseIDMap struct {
+	Chapters    map[uint]uint `json:"chapters"`
+	Lessons     map[uint]uint `json:"lessons"`
+	Attachments map[uint]uint `json:"attachments"`
+}
+
+type CourseImportResponse struct {
+	Course    *CourseTreeResponse `json:"course,omitempty"`
+	IDMap     *CourseIDMap        `json:"id_map,omitempty"`
+	Conflicts []ImportConflict    `json:"conflicts"`
+}
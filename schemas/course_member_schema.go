@@ -0,0 +1,23 @@
+package schemas
+
+import "time"
+
+// InviteCourseMemberRequest adds a user to a course by ID or by email
+type InviteCourseMemberRequest struct {
+	UserID uint   `json:"user_id" example:"2"`
+	Email  string `json:"email" binding:"omitempty,email" example:"jane.doe@example.com"`
+	Role   string `json:"role" binding:"required" example:"co_instructor"`
+}
+
+type UpdateCourseMemberRequest struct {
+	Role string `json:"role" binding:"required" example:"teaching_assistant"`
+}
+
+type CourseMemberResponse struct {
+	UserID    uint      `json:"user_id" example:"2"`
+	Username  string    `json:"username" example:"janedoe"`
+	Email     string    `json:"email" example:"jane.doe@example.com"`
+	Role      string    `json:"role" example:"co_instructor"`
+	InvitedBy *uint     `json:"invited_by,omitempty" example:"1"`
+	CreatedAt time.Time `json:"created_at" example:"2020-01-01T12:00:00Z"`
+}
@@ -2,10 +2,12 @@ package schemas
 
 import "time"
 
+// CreateCourseRequest creates a course. The course belongs to the organization of its creator.
 type CreateCourseRequest struct {
-	Name        string `json:"name" example:"Introduction to Go Programming"`
-	Description string `json:"description" example:"Learn the basics of Go programming language"`
-	CreatedBy   *uint  `json:"created_by,omitempty"`
+	Name           string `json:"name" example:"Introduction to Go Programming"`
+	Description    string `json:"description" example:"Learn the basics of Go programming language"`
+	CreatedBy      *uint  `json:"created_by,omitempty"`
+	OrganizationID *uint  `json:"-"`
 }
 type UpdateCourseRequest struct {
 	Name        string `json:"name" example:"Introduction to Go Programming"`
@@ -13,18 +15,28 @@ type UpdateCourseRequest struct {
 }
 
 type CourseResponseWithChaptersCount struct {
-	ID            uint      `json:"id,omitempty" example:"1"`
-	Name          string    `json:"name" example:"Introduction to Go Programming"`
-	Description   string    `json:"description" example:"Learn the basics of Go programming language"`
-	CreatedBy     *uint     `json:"created_by,omitempty"`
-	CreatedAt     time.Time `json:"created_at,omitempty" example:"2020-01-01T12:00:00Z"`
-	ChaptersCount int       `json:"chapters_count" example:"1"`
+	ID            uint             `json:"id,omitempty" example:"1"`
+	Name          string           `json:"name" example:"Introduction to Go Programming"`
+	Description   string           `json:"description" example:"Learn the basics of Go programming language"`
+	Status        string           `json:"status" example:"published"`
+	PublishedAt   *time.Time       `json:"published_at,omitempty" example:"2020-01-01T12:00:00Z"`
+	CreatedBy     *uint            `json:"created_by,omitempty"`
+	CreatedAt     time.Time        `json:"created_at,omitempty" example:"2020-01-01T12:00:00Z"`
+	ChaptersCount int              `json:"chapters_count" example:"1"`
+	Progress      *ProgressSummary `json:"progress,omitempty" gorm:"-"`
 }
 
 type CourseResponse struct {
-	ID          uint      `json:"id,omitempty" example:"1"`
-	Name        string    `json:"name" example:"Introduction to Go Programming"`
-	Description string    `json:"description" example:"Learn the basics of Go programming language"`
-	CreatedBy   *uint     `json:"created_by,omitempty"`
-	CreatedAt   time.Time `json:"created_at,omitempty" example:"2020-01-01T12:00:00Z"`
+	ID          uint       `json:"id,omitempty" example:"1"`
+	Name        string     `json:"name" example:"Introduction to Go Programming"`
+	Description string     `json:"description" example:"Learn the basics of Go programming language"`
+	Status      string     `json:"status" example:"draft"`
+	PublishedAt *time.Time `json:"published_at,omitempty" example:"2020-01-01T12:00:00Z"`
+	CreatedBy   *uint      `json:"created_by,omitempty"`
+	CreatedAt   time.Time  `json:"created_at,omitempty" example:"2020-01-01T12:00:00Z"`
+}
+
+type ChangeCourseStatusRequest struct {
+	Status  string `json:"status" example:"in_review"`
+	Comment string `json:"comment" example:"Ready for review"`
 }
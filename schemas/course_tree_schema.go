@@ -0,0 +1,40 @@
+package schemas
+
+import "time"
+
+type CloneCourseRequest struct {
+	Name string `json:"name,omitempty" example:"Introduction to Go Programming (Spring)"`
+}
+
+// CourseTreeResponse is a course with all of its chapters, lessons and attachments
+type CourseTreeResponse struct {
+	ID          uint                  `json:"id" example:"1"`
+	Name        string                `json:"name" example:"Introduction to Go Programming"`
+	Description string                `json:"description" example:"Learn the basics of Go programming language"`
+	Status      string                `json:"status" example:"draft"`
+	CreatedBy   *uint                 `json:"created_by,omitempty"`
+	CreatedAt   time.Time             `json:"created_at" example:"2020-01-01T12:00:00Z"`
+	Chapters    []ChapterTreeResponse `json:"chapters"`
+}
+
+type ChapterTreeResponse struct {
+	ID          uint                 `json:"id" example:"1"`
+	Name        string               `json:"name" example:"Chapter 1: Getting Started"`
+	Description string               `json:"description" example:"Introduction to the course material"`
+	Order       int                  `json:"order" example:"1"`
+	Lessons     []LessonTreeResponse `json:"lessons"`
+}
+
+type LessonTreeResponse struct {
+	ID          uint                     `json:"id" example:"1"`
+	Name        string                   `json:"name" example:"Lesson 1: Introduction"`
+	Description string                   `json:"description" example:"Overview of the chapter content"`
+	Order       int                      `json:"order" example:"1"`
+	BlocksCount int                      `json:"blocks_count" example:"3"`
+	Attachments []AttachmentTreeResponse `json:"attachments"`
+}
+
+type AttachmentTreeResponse struct {
+	ID   uint   `json:"id" example:"1"`
+	Name string `json:"name" example:"lecture_slides.pdf"`
+}
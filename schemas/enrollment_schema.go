@@ -0,0 +1,11 @@
+package schemas
+
+import "time"
+
+type EnrollmentResponse struct {
+	ID                uint      `json:"id,omitempty" example:"1"`
+	CourseID          uint      `json:"course_id" example:"1"`
+	CourseName        string    `json:"course_name" example:"Introduction to Go Programming"`
+	CourseDescription string    `json:"course_description" example:"Learn the basics of Go programming language"`
+	EnrolledAt        time.Time `json:"enrolled_at" example:"2020-01-01T12:00:00Z"`
+}
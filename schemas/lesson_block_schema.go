@@ -0,0 +1,31 @@
+package schemas
+
+// LessonBlockRequest creates or replaces a lesson block. Which fields are used depends
+// on the type: markdown and callout use text, code uses text and language, video uses
+// url and attachment uses attachment_id. title is an optional caption.
+type LessonBlockRequest struct {
+	Type         string `json:"type" example:"code"`
+	Position     int    `json:"position,omitempty" example:"2"`
+	Text         string `json:"text,omitempty" example:"fmt.Println(\"Hello\")"`
+	Language     string `json:"language,omitempty" example:"go"`
+	Variant      string `json:"variant,omitempty" example:"info"`
+	Title        string `json:"title,omitempty" example:"Hello world"`
+	URL          string `json:"url,omitempty" example:"https://www.youtube.com/watch?v=dQw4w9WgXcQ"`
+	AttachmentID *uint  `json:"attachment_id,omitempty" example:"1"`
+}
+
+type ReorderLessonBlocksRequest struct {
+	BlockIDs []uint `json:"block_ids" example:"3,1,2"`
+}
+
+type LessonBlockResponse struct {
+	ID           uint   `json:"id" example:"1"`
+	Position     int    `json:"position" example:"1"`
+	Type         string `json:"type" example:"code"`
+	Text         string `json:"text,omitempty" example:"fmt.Println(\"Hello\")"`
+	Language     string `json:"language,omitempty" example:"go"`
+	Variant      string `json:"variant,omitempty" example:"info"`
+	Title        string `json:"title,omitempty" example:"Hello world"`
+	URL          string `json:"url,omitempty" example:"https://www.youtube.com/watch?v=dQw4w9WgXcQ"`
+	AttachmentID *uint  `json:"attachment_id,omitempty" example:"1"`
+}
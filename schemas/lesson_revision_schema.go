@@ -0,0 +1,54 @@
+package schemas
+
+// Diff operations of a LessonDiffLine
+const (
+	DiffOpEqual  = "equal"
+	DiffOpInsert = "insert"
+	DiffOpDelete = "delete"
+)
+
+type LessonRevisionSummary struct {
+	Revision     int    `json:"revision" example:"3"`
+	Name         string `json:"name" example:"Introduction to Go"`
+	RestoredFrom *int   `json:"restored_from,omitempty" example:"1"`
+	CreatedBy    *uint  `json:"created_by,omitempty" example:"1"`
+	Author       string `json:"author,omitempty" example:"johndoe"`
+	CreatedAt    string `json:"created_at" example:"2020-01-01T12:00:00Z"`
+}
+
+type LessonRevisionResponse struct {
+	LessonID     uint   `json:"lesson_id" example:"1"`
+	Revision     int    `json:"revision" example:"3"`
+	Name         string `json:"name" example:"Introduction to Go"`
+	Description  string `json:"description" example:"Learn the basics of Go programming language"`
+	Content      string `json:"content" example:"This lesson covers the basic concepts of the chapter."`
+	Order        int    `json:"order" example:"1"`
+	RestoredFrom *int   `json:"restored_from,omitempty" example:"1"`
+	CreatedBy    *uint  `json:"created_by,omitempty" example:"1"`
+	Author       string `json:"author,omitempty" example:"johndoe"`
+	CreatedAt    string `json:"created_at" example:"2020-01-01T12:00:00Z"`
+}
+
+// LessonDiffLine is a single line of a line-level diff. OldLine and NewLine are
+// 1-based line numbers in the old and new text, zero when the line is missing there.
+type LessonDiffLine struct {
+	Op      string `json:"op" example:"insert"`
+	OldLine int    `json:"old_line,omitempty" example:"4"`
+	NewLine int    `json:"new_line,omitempty" example:"5"`
+	Text    string `json:"text" example:"A new paragraph"`
+}
+
+// LessonFieldDiff is the diff of one changed lesson field
+type LessonFieldDiff struct {
+	Field   string           `json:"field" example:"content"`
+	Added   int              `json:"added" example:"2"`
+	Removed int              `json:"removed" example:"1"`
+	Lines   []LessonDiffLine `json:"lines"`
+}
+
+type LessonRevisionDiffResponse struct {
+	LessonID     uint              `json:"lesson_id" example:"1"`
+	FromRevision int               `json:"from_revision" example:"1"`
+	ToRevision   int               `json:"to_revision" example:"3"`
+	Changes      []LessonFieldDiff `json:"changes"`
+}
@@ -8,12 +8,29 @@ type LessonRequest struct {
 	CreatedBy   *uint  `json:"created_by,omitempty"`
 }
 
+// Output formats of a lesson
+const (
+	LessonFormatMarkdown = "markdown"
+	LessonFormatHTML     = "html"
+)
+
+// LessonTOCEntry is a heading of the lesson content. Anchor is the id of the heading in the rendered HTML.
+type LessonTOCEntry struct {
+	Level  int    `json:"level" example:"2"`
+	Title  string `json:"title" example:"Variables"`
+	Anchor string `json:"anchor" example:"variables"`
+}
+
 type LessonResponse struct {
-	ID          uint   `json:"id,omitempty" example:"1"`
-	Name        string `json:"name" example:"Introduction to Go"`
-	Description string `json:"description" example:"Learn the basics of Go programming language"`
-	Content     string `json:"content" example:"This lesson covers the basic concepts of the chapter."`
-	Order       int    `json:"order" example:"1"`
-	CreatedBy   *uint  `json:"created_by,omitempty"`
-	CreatedAt   string `json:"created_at,omitempty" example:"2020-01-01T12:00:00Z"`
+	ID          uint                  `json:"id,omitempty" example:"1"`
+	Name        string                `json:"name" example:"Introduction to Go"`
+	Description string                `json:"description" example:"Learn the basics of Go programming language"`
+	Content     string                `json:"content" example:"This lesson covers the basic concepts of the chapter."`
+	Order       int                   `json:"order" example:"1"`
+	Blocks      []LessonBlockResponse `json:"blocks,omitempty" gorm:"-"`
+	HTML        string                `json:"html,omitempty" gorm:"-" example:"<p>This lesson covers the basic concepts of the chapter.</p>"`
+	Excerpt     string                `json:"excerpt,omitempty" gorm:"-" example:"This lesson covers the basic concepts of the chapter."`
+	TOC         []LessonTOCEntry      `json:"toc,omitempty" gorm:"-"`
+	CreatedBy   *uint                 `json:"created_by,omitempty"`
+	CreatedAt   string                `json:"created_at,omitempty" example:"2020-01-01T12:00:00Z"`
 }
@@ -0,0 +1,32 @@
+package schemas
+
+type ReorderChaptersRequest struct {
+	ChapterIDs []uint `json:"chapter_ids" example:"3,1,2"`
+}
+
+type ReorderLessonsRequest struct {
+	LessonIDs []uint `json:"lesson_ids" example:"3,1,2"`
+}
+
+// MoveLessonRequest moves a lesson to another chapter. CourseID is the course of the target
+// chapter and defaults to the lesson's course. Position is the 1-based position in the
+// target chapter, the lesson is appended when it is omitted.
+type MoveLessonRequest struct {
+	CourseID  uint `json:"course_id,omitempty" example:"1"`
+	ChapterID uint `json:"chapter_id" example:"2"`
+	Position  int  `json:"position,omitempty" example:"1"`
+}
+
+// MoveChapterRequest moves a chapter with its lessons to another course. Position is the
+// 1-based position in the target course, the chapter is appended when it is omitted.
+type MoveChapterRequest struct {
+	CourseID uint `json:"course_id" example:"2"`
+	Position int  `json:"position,omitempty" example:"1"`
+}
+
+// OrderedItemResponse is a chapter or lesson with its position after a reorder
+type OrderedItemResponse struct {
+	ID    uint   `json:"id" example:"1"`
+	Name  string `json:"name" example:"Chapter 1: Getting Started"`
+	Order int    `json:"order" example:"1"`
+}
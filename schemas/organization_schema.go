@@ -0,0 +1,13 @@
+package schemas
+
+// CreateOrganizationRequest creates an organization. The slug is the value of the
+// organization claim in the tokens of its users.
+type CreateOrganizationRequest struct {
+	Name string `json:"name" binding:"required" example:"Springfield High School"`
+	Slug string `json:"slug" binding:"required" example:"springfield-high"`
+}
+
+type UpdateOrganizationRequest struct {
+	Name string `json:"name" binding:"required" example:"Springfield High School"`
+	Slug string `json:"slug" binding:"required" example:"springfield-high"`
+}
@@ -0,0 +1,56 @@
+package schemas
+
+import "time"
+
+const (
+	DefaultPageLimit = 20
+	MaxPageLimit     = 100
+)
+
+// Sort keys accepted by the listing endpoints. A leading "-" sorts descending.
+var (
+	CourseSortKeys  = []string{"name", "created_at"}
+	ChapterSortKeys = []string{"order", "name", "created_at"}
+	LessonSortKeys  = []string{"order", "name", "created_at"}
+)
+
+// ListParams holds the pagination, sorting and filtering options of a listing request
+type ListParams struct {
+	Page         int
+	Limit        int
+	SortBy       string
+	SortDesc     bool
+	CreatedBy    *uint
+	NameContains string
+	CreatedFrom  *time.Time
+	CreatedTo    *time.Time
+	// Status filters courses by publishing status
+	Status string
+	// VisibleTo hides unpublished courses unless this user owns or collaborates on them
+	VisibleTo *uint
+	// Tenant limits the listing to the courses of one organization, nil lists every organization
+	Tenant *TenantFilter
+}
+
+// TenantFilter limits a listing to one organization. A nil OrganizationID is the default
+// tenant of the users and courses without an organization.
+type TenantFilter struct {
+	OrganizationID *uint
+}
+
+// Offset returns the number of rows to skip for the requested page
+func (p ListParams) Offset() int {
+	if p.Page < 1 {
+		return 0
+	}
+	return (p.Page - 1) * p.Limit
+}
+
+type PaginationMeta struct {
+	Page       int    `json:"page" example:"1"`
+	Limit      int    `json:"limit" example:"20"`
+	Total      int64  `json:"total" example:"42"`
+	TotalPages int    `json:"total_pages" example:"3"`
+	HasNext    bool   `json:"has_next" example:"true"`
+	Sort       string `json:"sort,omitempty" example:"-created_at"`
+}
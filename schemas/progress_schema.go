@@ -0,0 +1,24 @@
+package schemas
+
+import "time"
+
+type UpdateLessonProgressRequest struct {
+	Status       string `json:"status" example:"in_progress"`
+	Percent      int    `json:"percent" example:"50"`
+	LastPosition int    `json:"last_position" example:"120"`
+}
+
+type LessonProgressResponse struct {
+	LessonID     uint       `json:"lesson_id" example:"1"`
+	Status       string     `json:"status" example:"in_progress"`
+	Percent      int        `json:"percent" example:"50"`
+	LastPosition int        `json:"last_position" example:"120"`
+	CompletedAt  *time.Time `json:"completed_at,omitempty" example:"2020-01-01T12:00:00Z"`
+	UpdatedAt    *time.Time `json:"updated_at,omitempty" example:"2020-01-01T12:00:00Z"`
+}
+
+type ProgressSummary struct {
+	CompletedLessons int `json:"completed_lessons" example:"3"`
+	TotalLessons     int `json:"total_lessons" example:"10"`
+	Percent          int `json:"percent" example:"30"`
+}
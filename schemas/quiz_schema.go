@@ -0,0 +1,99 @@
+package schemas
+
+import "time"
+
+type QuizRequest struct {
+	Title        string                `json:"title" example:"Chapter 1 Quiz"`
+	Description  string                `json:"description" example:"Check your understanding of the basics"`
+	PassingScore int                   `json:"passing_score" example:"70"`
+	Questions    []QuizQuestionRequest `json:"questions"`
+	CreatedBy    *uint                 `json:"created_by,omitempty"`
+}
+
+// QuizQuestionRequest describes a question of a quiz. On update, questions and options
+// carrying the ID of an existing one are changed in place, the others are added.
+type QuizQuestionRequest struct {
+	ID            uint                `json:"id,omitempty" example:"1"`
+	Type          string              `json:"type" example:"single_choice"`
+	Text          string              `json:"text" example:"Which keyword declares a variable?"`
+	Points        int                 `json:"points" example:"1"`
+	Order         int                 `json:"order" example:"1"`
+	Options       []QuizOptionRequest `json:"options,omitempty"`
+	CorrectNumber *float64            `json:"correct_number,omitempty" example:"42"`
+	Tolerance     float64             `json:"tolerance,omitempty" example:"0.5"`
+}
+
+type QuizOptionRequest struct {
+	ID        uint   `json:"id,omitempty" example:"1"`
+	Text      string `json:"text" example:"var"`
+	IsCorrect bool   `json:"is_correct" example:"true"`
+}
+
+type QuizResponse struct {
+	ID             uint                   `json:"id,omitempty" example:"1"`
+	LessonID       uint                   `json:"lesson_id" example:"1"`
+	Title          string                 `json:"title" example:"Chapter 1 Quiz"`
+	Description    string                 `json:"description" example:"Check your understanding of the basics"`
+	PassingScore   int                    `json:"passing_score" example:"70"`
+	QuestionsCount int                    `json:"questions_count" example:"5"`
+	Questions      []QuizQuestionResponse `json:"questions,omitempty"`
+	CreatedBy      *uint                  `json:"created_by,omitempty"`
+	CreatedAt      time.Time              `json:"created_at,omitempty" example:"2020-01-01T12:00:00Z"`
+}
+
+type QuizQuestionResponse struct {
+	ID            uint                 `json:"id" example:"1"`
+	Type          string               `json:"type" example:"single_choice"`
+	Text          string               `json:"text" example:"Which keyword declares a variable?"`
+	Points        int                  `json:"points" example:"1"`
+	Order         int                  `json:"order" example:"1"`
+	Options       []QuizOptionResponse `json:"options,omitempty"`
+	CorrectNumber *float64             `json:"correct_number,omitempty" example:"42"`
+	Tolerance     *float64             `json:"tolerance,omitempty" example:"0.5"`
+}
+
+type QuizOptionResponse struct {
+	ID        uint   `json:"id" example:"1"`
+	Text      string `json:"text" example:"var"`
+	IsCorrect *bool  `json:"is_correct,omitempty" example:"true"`
+}
+
+type SubmitQuizAttemptRequest struct {
+	Answers []QuizAnswerRequest `json:"answers"`
+}
+
+type QuizAnswerRequest struct {
+	QuestionID uint     `json:"question_id" example:"1"`
+	OptionIDs  []uint   `json:"option_ids,omitempty"`
+	Text       string   `json:"text,omitempty" example:"Goroutines are lightweight threads"`
+	Number     *float64 `json:"number,omitempty" example:"42"`
+}
+
+// ReviewQuizAttemptRequest grades the free text answers of an attempt
+type ReviewQuizAttemptRequest struct {
+	Answers []QuizAnswerReview `json:"answers"`
+}
+
+// QuizAnswerReview awards points to a free text answer, full points mark it correct
+type QuizAnswerReview struct {
+	QuestionID    uint `json:"question_id" example:"4"`
+	PointsAwarded int  `json:"points_awarded" example:"2"`
+}
+
+type QuizAttemptResponse struct {
+	ID            uint               `json:"id" example:"1"`
+	QuizID        uint               `json:"quiz_id" example:"1"`
+	Score         int                `json:"score" example:"8"`
+	MaxScore      int                `json:"max_score" example:"10"`
+	Percent       int                `json:"percent" example:"80"`
+	Passed        bool               `json:"passed" example:"true"`
+	PendingReview bool               `json:"pending_review" example:"false"`
+	Answers       []QuizAnswerResult `json:"answers,omitempty"`
+	SubmittedAt   time.Time          `json:"submitted_at" example:"2020-01-01T12:00:00Z"`
+}
+
+type QuizAnswerResult struct {
+	QuestionID    uint  `json:"question_id" example:"1"`
+	IsCorrect     *bool `json:"is_correct,omitempty" example:"true"`
+	PointsAwarded int   `json:"points_awarded" example:"1"`
+}
@@ -0,0 +1,49 @@
+package schemas
+
+const (
+	SearchTypeCourse  = "course"
+	SearchTypeChapter = "chapter"
+	SearchTypeLesson  = "lesson"
+)
+
+// The search repository marks the matched words of a snippet with these control characters.
+// The snippet is plain text until the search service escapes it and turns the markers into
+// <mark> tags.
+const (
+	SearchHighlightStart = "\x02"
+	SearchHighlightStop  = "\x03"
+)
+
+// SearchParams holds the options of a full-text search request
+type SearchParams struct {
+	Query    string
+	Types    []string
+	CourseID *uint
+	// UserID limits chapter and lesson hits to courses the user owns or is enrolled in,
+	// unless Unrestricted is set for admins and teachers
+	UserID       uint
+	Unrestricted bool
+	// Tenant limits hits to the courses of one organization, nil searches every organization
+	Tenant *TenantFilter
+	Page   int
+	Limit  int
+}
+
+type SearchPath struct {
+	CourseID    uint   `json:"course_id" example:"1"`
+	CourseName  string `json:"course_name" example:"Introduction to Go Programming"`
+	ChapterID   *uint  `json:"chapter_id,omitempty" example:"2"`
+	ChapterName string `json:"chapter_name,omitempty" example:"Chapter 1: Getting Started"`
+	LessonID    *uint  `json:"lesson_id,omitempty" example:"3"`
+	LessonName  string `json:"lesson_name,omitempty" example:"Introduction to Go"`
+}
+
+type SearchHit struct {
+	Type    string     `json:"type" example:"lesson"`
+	ID      uint       `json:"id" example:"3"`
+	Name    string     `json:"name" example:"Introduction to Go"`
+	Snippet string     `json:"snippet" example:"Learn the basics of <mark>Go</mark> programming language"`
+	Rank    float64    `json:"rank" example:"0.6079"`
+	Path    SearchPath `json:"path"`
+	URL     string     `json:"url" example:"/api/v1/courses/1/chapters/2/lessons/3"`
+}
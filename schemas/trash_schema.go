@@ -0,0 +1,33 @@
+package schemas
+
+import "time"
+
+// TrashItem is a soft deleted course, chapter, lesson or attachment. Content deleted together
+// with its course, chapter or lesson is not listed on its own, it is restored with it.
+// PurgeAt is when the item is deleted for good.
+type TrashItem struct {
+	ResourceType string    `json:"resource_type" example:"chapter"`
+	ID           uint      `json:"id" example:"3"`
+	Name         string    `json:"name" example:"Chapter 1: Getting Started"`
+	CourseID     uint      `json:"course_id" example:"1"`
+	CourseName   string    `json:"course_name" example:"Introduction to Go Programming"`
+	DeletedAt    time.Time `json:"deleted_at"`
+	PurgeAt      time.Time `json:"purge_at"`
+	// CourseCreatedBy and OrganizationID authorize the restore of the item
+	CourseCreatedBy *uint `json:"-"`
+	OrganizationID  *uint `json:"-"`
+	// ParentDeleted is set when the course, chapter or lesson holding the item is deleted
+	ParentDeleted bool `json:"-"`
+}
+
+// TrashParams holds the filters of a trash listing. Items are listed newest first.
+type TrashParams struct {
+	ResourceType string
+	// Tenant limits items to the courses of one organization, nil lists every organization
+	Tenant *TenantFilter
+	// ManagedBy limits items to the courses this user created or holds one of ManagerRoles in
+	ManagedBy    *uint
+	ManagerRoles []string
+	Page         int
+	Limit        int
+}
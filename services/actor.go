@@ -0,0 +1,62 @@
+package services
+
+import "web/schemas"
+
+// RolePlatformAdmin is the identity provider role of the admins of the whole deployment.
+// It is granted explicitly, an admin without an organization only administers the
+// default tenant.
+const RolePlatformAdmin = "platform_admin"
+
+// Actor is the authenticated user performing an action, with the roles that matter
+// for authorization decisions in the services
+type Actor struct {
+	UserID    uint
+	IsAdmin   bool
+	IsTeacher bool
+	// PlatformAdmin is set by the platform_admin role, platform admins are admins of
+	// every organization
+	PlatformAdmin bool
+	// OrganizationID is the tenant of the actor, nil for the default tenant
+	OrganizationID *uint
+}
+
+// IsStaff reports whether the actor is an admin or a teacher
+func (a Actor) IsStaff() bool {
+	return a.IsAdmin || a.IsTeacher
+}
+
+// Owns reports whether the actor created the resource with the given owner
+func (a Actor) Owns(createdBy *uint) bool {
+	return createdBy != nil && a.UserID != 0 && *createdBy == a.UserID
+}
+
+// IsPlatformAdmin reports whether the actor administers the whole deployment
+func (a Actor) IsPlatformAdmin() bool {
+	return a.PlatformAdmin
+}
+
+// InOrganization reports whether the actor may reach resources of the organization.
+// Platform admins reach every organization, everyone else only their own.
+func (a Actor) InOrganization(organizationID *uint) bool {
+	if a.IsPlatformAdmin() {
+		return true
+	}
+	return sameOrganization(a.OrganizationID, organizationID)
+}
+
+// TenantFilter restricts lookups and listings to the organization of the actor. Platform
+// admins reach every organization.
+func (a Actor) TenantFilter() *schemas.TenantFilter {
+	if a.IsPlatformAdmin() {
+		return nil
+	}
+	return &schemas.TenantFilter{OrganizationID: a.OrganizationID}
+}
+
+// sameOrganization reports whether two resources belong to the same organization
+func sameOrganization(a, b *uint) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
@@ -1,8 +1,11 @@
 package services
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"mime/multipart"
 	"path/filepath"
 	"time"
@@ -16,22 +19,28 @@ import (
 )
 
 type AttachmentServiceInterface interface {
-	UploadFile(file *multipart.FileHeader, courseID, chapterID, lessonID uint) (schemas.UploadResponse, error)
-	DownloadFile(id uint) (models.Attachment, *minio.Object, error)
-	GetAttachmentsByLessonID(courseID, chapterID, lessonID uint) ([]models.Attachment, error)
-	DeleteAttachment(id uint) error
-	HasAccessToLesson(userID, lessonID uint) (bool, error)
+	UploadFile(file *multipart.FileHeader, courseID, chapterID, lessonID uint, tenant *schemas.TenantFilter) (schemas.UploadResponse, error)
+	GetAttachmentByID(id uint, tenant *schemas.TenantFilter) (models.Attachment, error)
+	DownloadFile(id uint, tenant *schemas.TenantFilter) (models.Attachment, *minio.Object, error)
+	GetAttachmentsByLessonID(courseID, chapterID, lessonID uint, tenant *schemas.TenantFilter) ([]models.Attachment, error)
+	DeleteAttachment(id uint, tenant *schemas.TenantFilter) error
+	HasAccessToLesson(actor Actor, lessonID uint) (bool, error)
+	StoreObject(objectName string, data []byte, contentType string) error
+	GetObject(objectName string) (io.ReadCloser, error)
+	CopyObject(sourceName, destinationName string) error
+	RemoveObject(objectName string) error
 }
 
 type AttachmentService struct {
-	config      *config.AppConfig
-	repo        *repos.AttachmentRepository
-	lessonRepo  *repos.LessonRepository
-	uploadDir   string
-	minioClient *minio.Client
+	config            *config.AppConfig
+	repo              *repos.AttachmentRepository
+	lessonRepo        repos.LessonRepositoryInterface
+	enrollmentService *EnrollmentService
+	uploadDir         string
+	minioClient       *minio.Client
 }
 
-func NewAttachmentService(config *config.AppConfig, repo *repos.AttachmentRepository, lessonRepo *repos.LessonRepository) (*AttachmentService, error) {
+func NewAttachmentService(config *config.AppConfig, repo *repos.AttachmentRepository, lessonRepo repos.LessonRepositoryInterface, enrollmentService *EnrollmentService) (*AttachmentService, error) {
 	minioClient, err := minio.New(config.MinioEndpoint, &minio.Options{
 		Creds:  credentials.NewStaticV4(config.MinioAccessKey, config.MinioSecretKey, ""),
 		Secure: config.MinioUseSSL,
@@ -54,29 +63,35 @@ func NewAttachmentService(config *config.AppConfig, repo *repos.AttachmentReposi
 	uploadDir := "."
 
 	return &AttachmentService{
-		config:      config,
-		repo:        repo,
-		lessonRepo:  lessonRepo,
-		uploadDir:   uploadDir,
-		minioClient: minioClient,
+		config:            config,
+		repo:              repo,
+		lessonRepo:        lessonRepo,
+		enrollmentService: enrollmentService,
+		uploadDir:         uploadDir,
+		minioClient:       minioClient,
 	}, nil
 }
 
-func (s *AttachmentService) UploadFile(file *multipart.FileHeader, courseID, chapterID, lessonID uint) (schemas.UploadResponse, error) {
+func (s *AttachmentService) UploadFile(file *multipart.FileHeader, courseID, chapterID, lessonID uint, tenant *schemas.TenantFilter) (schemas.UploadResponse, error) {
 	if courseID > 0 && chapterID > 0 {
-		_, err := s.lessonRepo.GetByID(courseID, chapterID, lessonID)
+		_, err := s.lessonRepo.GetByID(courseID, chapterID, lessonID, tenant)
 		if err != nil {
 			return schemas.UploadResponse{}, fmt.Errorf("lesson not found or does not belong to the specified chapter and course: %w", err)
 		}
 	} else {
-		_, err := s.lessonRepo.GetByID(0, 0, lessonID)
+		_, err := s.lessonRepo.GetByID(0, 0, lessonID, tenant)
 		if err != nil {
 			return schemas.UploadResponse{}, fmt.Errorf("lesson not found: %w", err)
 		}
 	}
 
+	organizationID, err := s.lessonRepo.GetOrganizationID(lessonID)
+	if err != nil {
+		return schemas.UploadResponse{}, err
+	}
+
 	filename := filepath.Base(file.Filename)
-	objectName := fmt.Sprintf("lesson-%d/%s", lessonID, filename)
+	objectName := repos.TenantObjectPrefix(organizationID) + fmt.Sprintf("lesson-%d/%s", lessonID, filename)
 
 	src, err := file.Open()
 	if err != nil {
@@ -131,9 +146,13 @@ func (s *AttachmentService) UploadFile(file *multipart.FileHeader, courseID, cha
 	}, nil
 }
 
-func (s *AttachmentService) DownloadFile(id uint) (models.Attachment, *minio.Object, error) {
+func (s *AttachmentService) GetAttachmentByID(id uint, tenant *schemas.TenantFilter) (models.Attachment, error) {
+	return s.repo.GetByID(id, tenant)
+}
+
+func (s *AttachmentService) DownloadFile(id uint, tenant *schemas.TenantFilter) (models.Attachment, *minio.Object, error) {
 
-	attachment, err := s.repo.GetByID(id)
+	attachment, err := s.repo.GetByID(id, tenant)
 	if err != nil {
 		return models.Attachment{}, nil, fmt.Errorf("attachment not found: %w", err)
 	}
@@ -153,15 +172,15 @@ func (s *AttachmentService) DownloadFile(id uint) (models.Attachment, *minio.Obj
 	return attachment, object, nil
 }
 
-func (s *AttachmentService) GetAttachmentsByLessonID(courseID, chapterID, lessonID uint) ([]models.Attachment, error) {
+func (s *AttachmentService) GetAttachmentsByLessonID(courseID, chapterID, lessonID uint, tenant *schemas.TenantFilter) ([]models.Attachment, error) {
 	if courseID > 0 && chapterID > 0 {
-		_, err := s.lessonRepo.GetByID(courseID, chapterID, lessonID)
+		_, err := s.lessonRepo.GetByID(courseID, chapterID, lessonID, tenant)
 		if err != nil {
 			return nil, err
 		}
 	}
 
-	attachments, err := s.repo.GetByLessonID(lessonID)
+	attachments, err := s.repo.GetByLessonID(lessonID, tenant)
 	if err != nil {
 		return nil, err
 	}
@@ -192,30 +211,92 @@ func (s *AttachmentService) generatePresignedURL(objectName string) (string, err
 	return presignedURL.String(), nil
 }
 
-func (s *AttachmentService) DeleteAttachment(id uint) error {
-	attachment, err := s.repo.GetByID(id)
+// DeleteAttachment moves the attachment to the trash. Its file stays in MinIO until the
+// trash is purged, so the attachment can be restored.
+func (s *AttachmentService) DeleteAttachment(id uint, tenant *schemas.TenantFilter) error {
+	_, err := s.repo.GetByID(id, tenant)
 	if err != nil {
 		return fmt.Errorf("attachment not found: %w", err)
 	}
 
-	objectName := attachment.URL
+	return s.repo.Delete(id)
+}
 
-	err = s.minioClient.RemoveObject(context.Background(), s.config.MinioBucket, objectName, minio.RemoveObjectOptions{})
+// HasAccessToLesson reports whether the user may read the lesson and its attachments.
+// Access follows the course the lesson belongs to: admins and teachers of its organization,
+// the course members and learners enrolled in the course are allowed. Lessons of courses in
+// other organizations are reported as not found.
+func (s *AttachmentService) HasAccessToLesson(actor Actor, lessonID uint) (bool, error) {
+	courseID, err := s.lessonRepo.GetCourseID(lessonID)
 	if err != nil {
-		return fmt.Errorf("failed to delete file from MinIO: %w", err)
+		return false, err
 	}
 
-	return s.repo.Delete(id)
-}
-
-func (s *AttachmentService) HasAccessToLesson(userID, lessonID uint) (bool, error) {
-	_, err := s.lessonRepo.GetByID(0, 0, lessonID)
+	hasAccess, err := s.enrollmentService.HasAccessToCourse(actor, courseID)
 	if err != nil {
-		if err.Error() == "lesson not found" {
-			return false, nil
+		if err.Error() == "course not found" {
+			return false, errors.New("lesson not found")
 		}
 		return false, err
 	}
 
-	return true, nil
+	return hasAccess, nil
+}
+
+// StoreObject uploads generated content, such as certificate PDFs, to the MinIO bucket
+func (s *AttachmentService) StoreObject(objectName string, data []byte, contentType string) error {
+	_, err := s.minioClient.PutObject(
+		context.Background(),
+		s.config.MinioBucket,
+		objectName,
+		bytes.NewReader(data),
+		int64(len(data)),
+		minio.PutObjectOptions{
+			ContentType: contentType,
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to upload file to MinIO: %w", err)
+	}
+
+	return nil
+}
+
+// GetObject opens an object stored in the MinIO bucket for reading
+func (s *AttachmentService) GetObject(objectName string) (io.ReadCloser, error) {
+	_, err := s.minioClient.StatObject(context.Background(), s.config.MinioBucket, objectName, minio.StatObjectOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("file not found in MinIO: %w", err)
+	}
+
+	object, err := s.minioClient.GetObject(context.Background(), s.config.MinioBucket, objectName, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get object from MinIO: %w", err)
+	}
+
+	return object, nil
+}
+
+// CopyObject copies an object inside the MinIO bucket without downloading it
+func (s *AttachmentService) CopyObject(sourceName, destinationName string) error {
+	_, err := s.minioClient.CopyObject(
+		context.Background(),
+		minio.CopyDestOptions{Bucket: s.config.MinioBucket, Object: destinationName},
+		minio.CopySrcOptions{Bucket: s.config.MinioBucket, Object: sourceName},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to copy file in MinIO: %w", err)
+	}
+
+	return nil
+}
+
+// RemoveObject deletes an object from the MinIO bucket
+func (s *AttachmentService) RemoveObject(objectName string) error {
+	err := s.minioClient.RemoveObject(context.Background(), s.config.MinioBucket, objectName, minio.RemoveObjectOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to delete file from MinIO: %w", err)
+	}
+
+	return nil
 }
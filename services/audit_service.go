@@ -0,0 +1,65 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"web/models"
+	"web/repos"
+	"web/schemas"
+)
+
+// Audit resource types of the order of the chapters of a course and of the lessons of a
+// chapter. The other audited resources use the policy resource types.
+const (
+	AuditResourceChapterOrder = "chapter_order"
+	AuditResourceLessonOrder  = "lesson_order"
+)
+
+type AuditServiceInterface interface {
+	Record(entry models.AuditLog) error
+	Snapshot(resourceType string, id uint) (json.RawMessage, error)
+	GetAll(params schemas.AuditLogParams) ([]models.AuditLog, schemas.PaginationMeta, error)
+}
+
+var _ AuditServiceInterface = (*AuditService)(nil)
+
+// AuditService keeps the append-only log of the creates, updates and deletes made through
+// the API
+type AuditService struct {
+	repo repos.AuditLogRepositoryInterface
+}
+
+func NewAuditService(repo repos.AuditLogRepositoryInterface) *AuditService {
+	return &AuditService{
+		repo: repo,
+	}
+}
+
+// Record appends an entry to the audit log
+func (s *AuditService) Record(entry models.AuditLog) error {
+	if entry.Action == "" || entry.ResourceType == "" {
+		return errors.New("audit action and resource type are required")
+	}
+
+	_, err := s.repo.Create(entry)
+	return err
+}
+
+// Snapshot returns the JSON state of a resource to record before and after an operation,
+// nil when the resource does not exist
+func (s *AuditService) Snapshot(resourceType string, id uint) (json.RawMessage, error) {
+	return s.repo.Snapshot(resourceType, id)
+}
+
+// GetAll lists the entries matching the filters, newest first
+func (s *AuditService) GetAll(params schemas.AuditLogParams) ([]models.AuditLog, schemas.PaginationMeta, error) {
+	if params.From != nil && params.To != nil && params.From.After(*params.To) {
+		return nil, schemas.PaginationMeta{}, errors.New("invalid time range")
+	}
+
+	entries, total, err := s.repo.GetAll(params)
+	if err != nil {
+		return nil, schemas.PaginationMeta{}, err
+	}
+	return entries, NewPaginationMeta(schemas.ListParams{Page: params.Page, Limit: params.Limit}, total), nil
+}
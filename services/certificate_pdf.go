@@ -0,0 +1,102 @@
+package services
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Page size of a landscape A4 page in PDF points
+const (
+	certificatePageWidth  = 842.0
+	certificatePageHeight = 595.0
+)
+
+// certificateLine is a single centered line of text on the certificate
+type certificateLine struct {
+	text string
+	font string
+	size float64
+	y    float64
+}
+
+// RenderCertificatePDF renders a single page landscape certificate. It writes the PDF
+// by hand with the standard Helvetica fonts so no font files have to be embedded.
+func RenderCertificatePDF(username, courseName, code string, issuedAt time.Time, verifyURL string) []byte {
+	lines := []certificateLine{
+		{text: "Certificate of Completion", font: "F2", size: 36, y: 440},
+		{text: "This certifies that", font: "F1", size: 16, y: 380},
+		{text: username, font: "F2", size: 28, y: 335},
+		{text: "has successfully completed the course", font: "F1", size: 16, y: 290},
+		{text: courseName, font: "F2", size: 22, y: 250},
+		{text: "Issued on " + issuedAt.UTC().Format("January 2, 2006"), font: "F1", size: 14, y: 190},
+		{text: "Verification code: " + code, font: "F1", size: 12, y: 110},
+		{text: verifyURL, font: "F1", size: 10, y: 90},
+	}
+
+	var content bytes.Buffer
+	// Double border around the page
+	content.WriteString("0.2 0.3 0.5 RG\n3 w\n30 30 782 535 re S\n1 w\n40 40 762 515 re S\n0 0 0 rg\n")
+	for _, line := range lines {
+		if line.text == "" {
+			continue
+		}
+		x := (certificatePageWidth - textWidth(line.text, line.size)) / 2
+		fmt.Fprintf(&content, "BT /%s %.0f Tf %.2f %.2f Td (%s) Tj ET\n", line.font, line.size, x, line.y, escapePDFText(line.text))
+	}
+
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.0f %.0f] /Resources << /Font << /F1 4 0 R /F2 5 0 R >> >> /Contents 6 0 R >>", certificatePageWidth, certificatePageHeight),
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>",
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>",
+		fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", content.Len(), content.String()),
+	}
+
+	var pdf bytes.Buffer
+	pdf.WriteString("%PDF-1.4\n")
+	offsets := make([]int, len(objects))
+	for i, object := range objects {
+		offsets[i] = pdf.Len()
+		fmt.Fprintf(&pdf, "%d 0 obj\n%s\nendobj\n", i+1, object)
+	}
+
+	xrefOffset := pdf.Len()
+	fmt.Fprintf(&pdf, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&pdf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&pdf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xrefOffset)
+
+	return pdf.Bytes()
+}
+
+// textWidth estimates the rendered width of Helvetica text. The average glyph is a
+// little over half the font size wide, which is close enough for centering.
+func textWidth(text string, size float64) float64 {
+	return float64(len([]rune(text))) * size * 0.52
+}
+
+// escapePDFText escapes a string for use in a PDF literal string. Characters outside
+// of Latin-1 cannot be shown with the standard fonts and are replaced.
+func escapePDFText(text string) string {
+	var b strings.Builder
+	for _, r := range text {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r < 32:
+			b.WriteByte(' ')
+		case r < 128:
+			b.WriteRune(r)
+		case r < 256:
+			fmt.Fprintf(&b, "\\%03o", r)
+		default:
+			b.WriteByte('?')
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,282 @@
+package services
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"web/config"
+	"web/models"
+	"web/repos"
+	"web/schemas"
+)
+
+// ObjectStorage stores generated files. It is implemented by AttachmentService so
+// certificates end up in the same MinIO bucket as lesson attachments.
+type ObjectStorage interface {
+	StoreObject(objectName string, data []byte, contentType string) error
+	GetObject(objectName string) (io.ReadCloser, error)
+}
+
+type CertificateServiceInterface interface {
+	IssueCertificate(courseID, userID uint, issuedBy *uint) (schemas.CertificateResponse, error)
+	ClaimCertificate(courseID, userID uint) (schemas.CertificateResponse, error)
+	GetUserCertificates(userID uint) ([]schemas.CertificateResponse, error)
+	DownloadCertificate(actor Actor, code string) (models.Certificate, io.ReadCloser, error)
+	VerifyCertificate(code string) (schemas.CertificateVerificationResponse, error)
+}
+
+var _ CertificateServiceInterface = (*CertificateService)(nil)
+
+type CertificateService struct {
+	config         *config.AppConfig
+	repo           repos.CertificateRepositoryInterface
+	courseRepo     repos.CourseRepositoryInterface
+	enrollmentRepo repos.EnrollmentRepositoryInterface
+	progressRepo   repos.ProgressRepositoryInterface
+	userRepo       repos.UserRepositoryInterface
+	policy         PolicyServiceInterface
+	storage        ObjectStorage
+}
+
+func NewCertificateService(config *config.AppConfig, repo repos.CertificateRepositoryInterface, courseRepo repos.CourseRepositoryInterface, enrollmentRepo repos.EnrollmentRepositoryInterface, progressRepo repos.ProgressRepositoryInterface, userRepo repos.UserRepositoryInterface, policy PolicyServiceInterface, storage ObjectStorage) *CertificateService {
+	return &CertificateService{
+		config:         config,
+		repo:           repo,
+		courseRepo:     courseRepo,
+		enrollmentRepo: enrollmentRepo,
+		progressRepo:   progressRepo,
+		userRepo:       userRepo,
+		policy:         policy,
+		storage:        storage,
+	}
+}
+
+// IssueCertificate issues a certificate to an enrolled learner. It is used by
+// instructors marking a learner as having completed the course.
+func (s *CertificateService) IssueCertificate(courseID, userID uint, issuedBy *uint) (schemas.CertificateResponse, error) {
+	if userID == 0 {
+		return schemas.CertificateResponse{}, errors.New("user ID is required")
+	}
+
+	course, err := s.courseRepo.GetByID(courseID)
+	if err != nil {
+		return schemas.CertificateResponse{}, err
+	}
+
+	enrolled, err := s.enrollmentRepo.Exists(userID, course.ID)
+	if err != nil {
+		return schemas.CertificateResponse{}, err
+	}
+	if !enrolled {
+		return schemas.CertificateResponse{}, errors.New("user is not enrolled in this course")
+	}
+
+	return s.issue(course, userID, issuedBy)
+}
+
+// ClaimCertificate issues a certificate to the learner when the completion rule is
+// met: at least CertificateCompletionPercent of the course lessons are completed.
+func (s *CertificateService) ClaimCertificate(courseID, userID uint) (schemas.CertificateResponse, error) {
+	course, err := s.courseRepo.GetByID(courseID)
+	if err != nil {
+		return schemas.CertificateResponse{}, err
+	}
+
+	summaries, err := s.progressRepo.GetCourseSummaries(userID, []uint{course.ID})
+	if err != nil {
+		return schemas.CertificateResponse{}, err
+	}
+
+	summary := summaries[course.ID]
+	if summary.TotalLessons == 0 || summary.Percent < s.completionPercent() {
+		return schemas.CertificateResponse{}, errors.New("course completion requirements not met")
+	}
+
+	return s.issue(course, userID, nil)
+}
+
+func (s *CertificateService) GetUserCertificates(userID uint) ([]schemas.CertificateResponse, error) {
+	certificates, err := s.repo.GetByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	certificateResponses := make([]schemas.CertificateResponse, 0, len(certificates))
+	for _, certificate := range certificates {
+		certificateResponses = append(certificateResponses, toCertificateResponse(certificate))
+	}
+
+	return certificateResponses, nil
+}
+
+// DownloadCertificate returns the PDF of a certificate to the learner who earned it and to
+// the staff of its course. Certificates of other organizations are reported as not found.
+func (s *CertificateService) DownloadCertificate(actor Actor, code string) (models.Certificate, io.ReadCloser, error) {
+	certificate, err := s.repo.GetByCode(normalizeCertificateCode(code))
+	if err != nil {
+		return models.Certificate{}, nil, err
+	}
+
+	if err := s.authorizeDownload(actor, certificate); err != nil {
+		return models.Certificate{}, nil, err
+	}
+
+	object, err := s.storage.GetObject(certificate.ObjectName)
+	if err != nil {
+		return models.Certificate{}, nil, err
+	}
+
+	return certificate, object, nil
+}
+
+// authorizeDownload lets the holder download a certificate, and the staff of its course
+// through the policy. Once the course is purged only the admins of the organization of the
+// issuer may, a claimed certificate was issued by its holder.
+func (s *CertificateService) authorizeDownload(actor Actor, certificate models.Certificate) error {
+	if actor.UserID != 0 && actor.UserID == certificate.UserID {
+		return nil
+	}
+
+	if certificate.CourseID != nil {
+		err := s.policy.AuthorizeLoadedCourse(actor, ActionRead, ResourceCertificate, certificate.Course)
+		if err == nil {
+			return nil
+		}
+		if err.Error() == "course not found" {
+			return errors.New("certificate not found")
+		}
+		return errors.New("insufficient permissions to download this certificate")
+	}
+
+	issuerID := certificate.UserID
+	if certificate.IssuedBy != nil {
+		issuerID = *certificate.IssuedBy
+	}
+	issuer, err := s.userRepo.GetByID(issuerID)
+	if err != nil {
+		if err.Error() == "user not found" {
+			return errors.New("certificate not found")
+		}
+		return err
+	}
+
+	if !actor.InOrganization(issuer.OrganizationID) {
+		return errors.New("certificate not found")
+	}
+	if !s.policy.Can(actor, ActionRead, ResourceCertificate, nil) {
+		return errors.New("insufficient permissions to download this certificate")
+	}
+	return nil
+}
+
+// VerifyCertificate looks up a certificate by its public verification code. Unknown
+// codes are reported as invalid rather than as an error.
+func (s *CertificateService) VerifyCertificate(code string) (schemas.CertificateVerificationResponse, error) {
+	code = normalizeCertificateCode(code)
+
+	certificate, err := s.repo.GetByCode(code)
+	if err != nil {
+		if err.Error() == "certificate not found" {
+			return schemas.CertificateVerificationResponse{Valid: false, Code: code}, nil
+		}
+		return schemas.CertificateVerificationResponse{}, err
+	}
+
+	return schemas.CertificateVerificationResponse{
+		Valid:      true,
+		Code:       certificate.Code,
+		Username:   certificate.User.Username,
+		CourseName: certificate.CourseName,
+		IssuedAt:   certificate.IssuedAt,
+	}, nil
+}
+
+func (s *CertificateService) issue(course models.Course, userID uint, issuedBy *uint) (schemas.CertificateResponse, error) {
+	_, err := s.repo.GetByUserAndCourse(userID, course.ID)
+	if err == nil {
+		return schemas.CertificateResponse{}, errors.New("certificate already issued")
+	}
+	if err.Error() != "certificate not found" {
+		return schemas.CertificateResponse{}, err
+	}
+
+	code, err := generateCertificateCode()
+	if err != nil {
+		return schemas.CertificateResponse{}, err
+	}
+
+	created, err := s.repo.Create(models.Certificate{
+		Code:       code,
+		UserID:     userID,
+		CourseID:   &course.ID,
+		CourseName: course.Name,
+		ObjectName: repos.TenantObjectPrefix(course.OrganizationID) + fmt.Sprintf("certificates/%s.pdf", code),
+		IssuedBy:   issuedBy,
+		IssuedAt:   time.Now(),
+	})
+	if err != nil {
+		return schemas.CertificateResponse{}, err
+	}
+
+	// Reload to get the learner name for the PDF
+	certificate, err := s.repo.GetByCode(created.Code)
+	if err != nil {
+		return schemas.CertificateResponse{}, err
+	}
+
+	pdf := RenderCertificatePDF(certificate.User.Username, certificate.CourseName, certificate.Code, certificate.IssuedAt, certificateVerifyURL(certificate.Code))
+	if err := s.storage.StoreObject(certificate.ObjectName, pdf, "application/pdf"); err != nil {
+		// Do not leave a certificate behind that cannot be downloaded
+		if deleteErr := s.repo.Delete(certificate.ID); deleteErr != nil {
+			return schemas.CertificateResponse{}, fmt.Errorf("%w (cleanup failed: %v)", err, deleteErr)
+		}
+		return schemas.CertificateResponse{}, err
+	}
+
+	return toCertificateResponse(certificate), nil
+}
+
+func (s *CertificateService) completionPercent() int {
+	if s.config == nil || s.config.CertificateCompletionPercent <= 0 || s.config.CertificateCompletionPercent > 100 {
+		return 100
+	}
+	return s.config.CertificateCompletionPercent
+}
+
+// generateCertificateCode returns a random code formatted as four groups of four hex digits
+func generateCertificateCode() (string, error) {
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate certificate code: %w", err)
+	}
+
+	encoded := strings.ToUpper(hex.EncodeToString(buf))
+	return fmt.Sprintf("%s-%s-%s-%s", encoded[0:4], encoded[4:8], encoded[8:12], encoded[12:16]), nil
+}
+
+func normalizeCertificateCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+func certificateVerifyURL(code string) string {
+	return fmt.Sprintf("/api/v1/certificates/%s/verify", code)
+}
+
+func toCertificateResponse(certificate models.Certificate) schemas.CertificateResponse {
+	return schemas.CertificateResponse{
+		ID:          certificate.ID,
+		Code:        certificate.Code,
+		UserID:      certificate.UserID,
+		Username:    certificate.User.Username,
+		CourseID:    certificate.CourseID,
+		CourseName:  certificate.CourseName,
+		IssuedBy:    certificate.IssuedBy,
+		IssuedAt:    certificate.IssuedAt,
+		DownloadURL: fmt.Sprintf("/api/v1/certificates/%s/download", certificate.Code),
+		VerifyURL:   certificateVerifyURL(certificate.Code),
+	}
+}
@@ -9,8 +9,8 @@ import (
 )
 
 type ChapterServiceInterface interface {
-	GetChapterByID(id, courseId uint) (models.Chapter, error)
-	GetChaptersByCourseID(courseID uint) ([]schemas.ChapterResponseWithLessonsCount, error)
+	GetChapterByID(id, courseId uint, tenant *schemas.TenantFilter) (models.Chapter, error)
+	GetChaptersByCourseID(courseID uint, params schemas.ListParams) ([]schemas.ChapterResponseWithLessonsCount, schemas.PaginationMeta, error)
 	CreateChapter(chapterRequest schemas.ChapterRequest, courseID uint) (uint, error)
 	UpdateChapter(chapter models.Chapter) error
 	DeleteChapter(id uint) error
@@ -30,19 +30,23 @@ func NewChapterService(repo *repos.ChapterRepository, courseRepo *repos.CourseRe
 	}
 }
 
-func (s *ChapterService) GetChaptersByCourseID(courseID uint) ([]schemas.ChapterResponseWithLessonsCount, error) {
+func (s *ChapterService) GetChaptersByCourseID(courseID uint, params schemas.ListParams) ([]schemas.ChapterResponseWithLessonsCount, schemas.PaginationMeta, error) {
 	if courseID == 0 {
-		return nil, errors.New("course ID is required")
+		return nil, schemas.PaginationMeta{}, errors.New("course ID is required")
 	}
-	return s.repo.GetByCourseID(courseID)
+	chapters, total, err := s.repo.GetByCourseID(courseID, params)
+	if err != nil {
+		return nil, schemas.PaginationMeta{}, err
+	}
+	return chapters, NewPaginationMeta(params, total), nil
 }
 
-func (s *ChapterService) GetChapterByID(id, courseId uint) (models.Chapter, error) {
-	return s.repo.GetByID(id, courseId)
+func (s *ChapterService) GetChapterByID(id, courseId uint, tenant *schemas.TenantFilter) (models.Chapter, error) {
+	return s.repo.GetByID(id, courseId, tenant)
 }
 
-func (s *ChapterService) GetChapterByIDWithLessonsCount(id, courseID uint) (schemas.ChapterResponseWithLessonsCount, error) {
-	return s.repo.GetByIDWithLessonsCount(id, courseID)
+func (s *ChapterService) GetChapterByIDWithLessonsCount(id, courseID uint, tenant *schemas.TenantFilter) (schemas.ChapterResponseWithLessonsCount, error) {
+	return s.repo.GetByIDWithLessonsCount(id, courseID, tenant)
 }
 
 func (s *ChapterService) CreateChapter(chapterRequest schemas.ChapterRequest, courseID uint) (uint, error) {
@@ -0,0 +1,472 @@
+package services
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"path"
+	"sort"
+	"strings"
+	"time"
+	"web/models"
+	"web/repos"
+	"web/schemas"
+)
+
+const (
+	// maxCourseArchiveManifestSize limits the uncompressed size of an imported manifest
+	maxCourseArchiveManifestSize = 16 << 20
+	// maxCourseArchiveFileSize limits the uncompressed size of an imported attachment
+	maxCourseArchiveFileSize = 512 << 20
+)
+
+// CourseArchiveStorage reads and writes stored objects, implemented by AttachmentService
+type CourseArchiveStorage interface {
+	GetObject(objectName string) (io.ReadCloser, error)
+	StoreObject(objectName string, data []byte, contentType string) error
+	RemoveObject(objectName string) error
+}
+
+type CourseArchiveServiceInterface interface {
+	GetExportableCourse(courseID uint, actor Actor) (models.Course, error)
+	WriteCourseArchive(w io.Writer, course models.Course) error
+	ImportCourse(archive io.ReaderAt, size int64, actor Actor, importRequest schemas.ImportCourseRequest) (schemas.CourseImportResponse, error)
+}
+
+var _ CourseArchiveServiceInterface = (*CourseArchiveService)(nil)
+
+type CourseArchiveService struct {
+	courseRepo repos.CourseRepositoryInterface
+	storage    CourseArchiveStorage
+}
+
+func NewCourseArchiveService(courseRepo repos.CourseRepositoryInterface, storage CourseArchiveStorage) *CourseArchiveService {
+	return &CourseArchiveService{
+		courseRepo: courseRepo,
+		storage:    storage,
+	}
+}
+
+// GetExportableCourse loads the course tree to export. The same users that may clone a
+// course may export it.
+func (s *CourseArchiveService) GetExportableCourse(courseID uint, actor Actor) (models.Course, error) {
+	if courseID == 0 {
+		return models.Course{}, errors.New("course ID is required")
+	}
+
+	course, err := s.courseRepo.GetTree(courseID)
+	if err != nil {
+		return models.Course{}, err
+	}
+
+	if !canCopyCourse(actor, course) {
+		return models.Course{}, errors.New("insufficient permissions to export this course")
+	}
+
+	return course, nil
+}
+
+// WriteCourseArchive writes a zip archive with the attachment binaries of the course tree
+// and a manifest describing the tree. Attachments whose object is missing from storage are
+// listed without a file, the import reports them as conflicts.
+func (s *CourseArchiveService) WriteCourseArchive(w io.Writer, course models.Course) error {
+	archive := zip.NewWriter(w)
+
+	manifest := schemas.CourseArchiveManifest{
+		FormatVersion: schemas.CourseArchiveFormatVersion,
+		ExportedAt:    time.Now().UTC(),
+		Course: schemas.CourseArchiveCourse{
+			ID:          course.ID,
+			Name:        course.Name,
+			Description: course.Description,
+			Chapters:    make([]schemas.CourseArchiveChapter, 0, len(course.Chapters)),
+		},
+	}
+
+	for _, chapter := range course.Chapters {
+		archiveChapter := schemas.CourseArchiveChapter{
+			ID:          chapter.ID,
+			Name:        chapter.Name,
+			Description: chapter.Description,
+			Order:       chapter.Order,
+			Lessons:     make([]schemas.CourseArchiveLesson, 0, len(chapter.Lessons)),
+		}
+
+		for _, lesson := range chapter.Lessons {
+			archiveLesson := schemas.CourseArchiveLesson{
+				ID:          lesson.ID,
+				Name:        lesson.Name,
+				Description: lesson.Description,
+				Content:     lesson.Content,
+				Order:       lesson.Order,
+				Blocks:      make([]schemas.LessonBlockRequest, 0, len(lesson.Blocks)),
+				Attachments: make([]schemas.CourseArchiveAttachment, 0, len(lesson.Attachments)),
+			}
+			for _, block := range lesson.Blocks {
+				archiveLesson.Blocks = append(archiveLesson.Blocks, schemas.LessonBlockRequest{
+					Type:         block.Type,
+					Position:     block.Position,
+					Text:         block.Text,
+					Language:     block.Language,
+					Variant:      block.Variant,
+					Title:        block.Title,
+					URL:          block.URL,
+					AttachmentID: block.AttachmentID,
+				})
+			}
+			for _, attachment := range lesson.Attachments {
+				archiveAttachment, err := s.writeArchiveAttachment(archive, attachment)
+				if err != nil {
+					return err
+				}
+				archiveLesson.Attachments = append(archiveLesson.Attachments, archiveAttachment)
+			}
+			archiveChapter.Lessons = append(archiveChapter.Lessons, archiveLesson)
+		}
+
+		manifest.Course.Chapters = append(manifest.Course.Chapters, archiveChapter)
+	}
+
+	manifestWriter, err := archive.Create(schemas.CourseArchiveManifestName)
+	if err != nil {
+		return fmt.Errorf("failed to write course archive: %w", err)
+	}
+	encoder := json.NewEncoder(manifestWriter)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(manifest); err != nil {
+		return fmt.Errorf("failed to write course archive manifest: %w", err)
+	}
+
+	if err := archive.Close(); err != nil {
+		return fmt.Errorf("failed to write course archive: %w", err)
+	}
+
+	return nil
+}
+
+// writeArchiveAttachment copies the object of an attachment into the archive
+func (s *CourseArchiveService) writeArchiveAttachment(archive *zip.Writer, attachment models.Attachment) (schemas.CourseArchiveAttachment, error) {
+	archiveAttachment := schemas.CourseArchiveAttachment{
+		ID:   attachment.ID,
+		Name: attachment.Name,
+	}
+
+	object, err := s.storage.GetObject(attachment.URL)
+	if err != nil {
+		if strings.HasPrefix(err.Error(), "file not found") {
+			return archiveAttachment, nil
+		}
+		return schemas.CourseArchiveAttachment{}, err
+	}
+	defer object.Close()
+
+	file := fmt.Sprintf("attachments/%d/%s", attachment.ID, path.Base(attachment.URL))
+	fileWriter, err := archive.Create(file)
+	if err != nil {
+		return schemas.CourseArchiveAttachment{}, fmt.Errorf("failed to write course archive: %w", err)
+	}
+	size, err := io.Copy(fileWriter, object)
+	if err != nil {
+		return schemas.CourseArchiveAttachment{}, fmt.Errorf("failed to write attachment %d to course archive: %w", attachment.ID, err)
+	}
+
+	archiveAttachment.File = file
+	archiveAttachment.Size = size
+	return archiveAttachment, nil
+}
+
+// ImportCourse recreates the course of an archive written by WriteCourseArchive as a new
+// draft course owned by the actor. Problems that can be worked around are reported as
+// conflicts: with the fail mode an archive with conflicts is rejected, with the resolve
+// mode the reported resolutions are applied. The whole tree is created in one transaction.
+func (s *CourseArchiveService) ImportCourse(archive io.ReaderAt, size int64, actor Actor, importRequest schemas.ImportCourseRequest) (schemas.CourseImportResponse, error) {
+	if !actor.IsStaff() {
+		return schemas.CourseImportResponse{}, errors.New("insufficient permissions to import courses")
+	}
+
+	onConflict := importRequest.OnConflict
+	if onConflict == "" {
+		onConflict = schemas.ImportOnConflictFail
+	}
+	if onConflict != schemas.ImportOnConflictFail && onConflict != schemas.ImportOnConflictResolve {
+		return schemas.CourseImportResponse{}, errors.New("invalid conflict mode, allowed: fail, resolve")
+	}
+
+	reader, err := zip.NewReader(archive, size)
+	if err != nil {
+		return schemas.CourseImportResponse{}, errors.New("invalid course archive")
+	}
+	files := make(map[string]*zip.File, len(reader.File))
+	for _, file := range reader.File {
+		files[file.Name] = file
+	}
+
+	manifest, err := readCourseArchiveManifest(files)
+	if err != nil {
+		return schemas.CourseImportResponse{}, err
+	}
+
+	source, conflicts, err := buildImportedCourse(manifest, files)
+	if err != nil {
+		return schemas.CourseImportResponse{}, err
+	}
+
+	name := strings.TrimSpace(importRequest.Name)
+	if name == "" {
+		name = strings.TrimSpace(manifest.Course.Name)
+	}
+	if name == "" {
+		return schemas.CourseImportResponse{}, errors.New("course name is required")
+	}
+	exists, err := s.courseRepo.ExistsByName(name, actor.OrganizationID)
+	if err != nil {
+		return schemas.CourseImportResponse{}, err
+	}
+	if exists {
+		freeName, err := s.freeCourseName(name, actor.OrganizationID)
+		if err != nil {
+			return schemas.CourseImportResponse{}, err
+		}
+		conflicts = append(conflicts, schemas.ImportConflict{
+			Type:       schemas.ImportConflictCourseName,
+			Path:       "course",
+			Message:    fmt.Sprintf("a course named %s already exists", name),
+			Resolution: fmt.Sprintf("imported as %s", freeName),
+		})
+		name = freeName
+	}
+	if len(name) > 255 {
+		return schemas.CourseImportResponse{}, errors.New("course name must not exceed 255 characters")
+	}
+
+	response := schemas.CourseImportResponse{Conflicts: conflicts}
+	if len(conflicts) > 0 && onConflict == schemas.ImportOnConflictFail {
+		return response, errors.New("course archive has conflicts")
+	}
+
+	course, idMap, err := s.courseRepo.Import(source, repos.CloneOptions{
+		Name:           name,
+		CreatedBy:      actor.UserID,
+		OrganizationID: actor.OrganizationID,
+		RenderContent:  renderLessonBlocksMarkdown,
+		CopyObjects: func(copies []repos.ObjectCopy) error {
+			return storeArchiveFiles(s.storage, files, copies)
+		},
+	})
+	if err != nil {
+		return schemas.CourseImportResponse{}, err
+	}
+
+	tree := toCourseTreeResponse(course)
+	response.Course = &tree
+	response.IDMap = &idMap
+	return response, nil
+}
+
+// freeCourseName returns the first of "<name> (imported)", "<name> (imported 2)", ...
+// that no course of the organization uses yet
+func (s *CourseArchiveService) freeCourseName(name string, organizationID *uint) (string, error) {
+	for i := 1; i <= 100; i++ {
+		candidate := name + " (imported)"
+		if i > 1 {
+			candidate = fmt.Sprintf("%s (imported %d)", name, i)
+		}
+		exists, err := s.courseRepo.ExistsByName(candidate, organizationID)
+		if err != nil {
+			return "", err
+		}
+		if !exists {
+			return candidate, nil
+		}
+	}
+	return "", errors.New("could not find a free course name, pass a name for the imported course")
+}
+
+// storeArchiveFiles uploads the archive files of imported attachments. When an upload
+// fails the objects uploaded so far are removed.
+func storeArchiveFiles(storage CourseArchiveStorage, files map[string]*zip.File, copies []repos.ObjectCopy) error {
+	for i, objectCopy := range copies {
+		err := storeArchiveFile(storage, files[objectCopy.Source], objectCopy.Destination)
+		if err != nil {
+			for _, stored := range copies[:i] {
+				_ = storage.RemoveObject(stored.Destination)
+			}
+			return fmt.Errorf("failed to import attachment %s: %w", objectCopy.Source, err)
+		}
+	}
+	return nil
+}
+
+func storeArchiveFile(storage CourseArchiveStorage, file *zip.File, objectName string) error {
+	data, err := readArchiveFile(file, maxCourseArchiveFileSize)
+	if err != nil {
+		return err
+	}
+
+	contentType := mime.TypeByExtension(path.Ext(objectName))
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	return storage.StoreObject(objectName, data, contentType)
+}
+
+// readArchiveFile reads a file of the archive, failing when it is larger than limit
+func readArchiveFile(file *zip.File, limit int64) ([]byte, error) {
+	if file.UncompressedSize64 > uint64(limit) {
+		return nil, fmt.Errorf("%s is too large", file.Name)
+	}
+
+	reader, err := file.Open()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", file.Name, err)
+	}
+	defer reader.Close()
+
+	// The header size is not trusted, the data is limited as well
+	data, err := io.ReadAll(io.LimitReader(reader, limit+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", file.Name, err)
+	}
+	if int64(len(data)) > limit {
+		return nil, fmt.Errorf("%s is too large", file.Name)
+	}
+
+	return data, nil
+}
+
+func readCourseArchiveManifest(files map[string]*zip.File) (schemas.CourseArchiveManifest, error) {
+	file, ok := files[schemas.CourseArchiveManifestName]
+	if !ok {
+		return schemas.CourseArchiveManifest{}, errors.New("course archive has no manifest")
+	}
+
+	data, err := readArchiveFile(file, maxCourseArchiveManifestSize)
+	if err != nil {
+		return schemas.CourseArchiveManifest{}, err
+	}
+
+	var manifest schemas.CourseArchiveManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return schemas.CourseArchiveManifest{}, errors.New("invalid course archive manifest")
+	}
+	if manifest.FormatVersion != schemas.CourseArchiveFormatVersion {
+		return schemas.CourseArchiveManifest{}, fmt.Errorf("unsupported course archive format version %d", manifest.FormatVersion)
+	}
+
+	return manifest, nil
+}
+
+// buildImportedCourse validates the manifest and turns it into a course tree for
+// CourseRepository.Import. The tree keeps the manifest IDs, attachment URLs are the names
+// of their archive files. Missing attachment files and blocks pointing at unknown
+// attachments are left out of the tree and reported as conflicts.
+func buildImportedCourse(manifest schemas.CourseArchiveManifest, files map[string]*zip.File) (models.Course, []schemas.ImportConflict, error) {
+	conflicts := []schemas.ImportConflict{}
+	course := models.Course{
+		ID:          manifest.Course.ID,
+		Name:        manifest.Course.Name,
+		Description: manifest.Course.Description,
+	}
+
+	chapterIDs := make(map[uint]bool)
+	lessonIDs := make(map[uint]bool)
+	attachmentIDs := make(map[uint]bool)
+
+	for _, archiveChapter := range manifest.Course.Chapters {
+		if chapterIDs[archiveChapter.ID] {
+			return models.Course{}, nil, fmt.Errorf("duplicate chapter ID %d in manifest", archiveChapter.ID)
+		}
+		chapterIDs[archiveChapter.ID] = true
+		if strings.TrimSpace(archiveChapter.Name) == "" {
+			return models.Course{}, nil, fmt.Errorf("chapter %d: chapter name is required", archiveChapter.ID)
+		}
+
+		chapter := models.Chapter{
+			ID:          archiveChapter.ID,
+			Name:        archiveChapter.Name,
+			Description: archiveChapter.Description,
+			Order:       archiveChapter.Order,
+		}
+
+		for _, archiveLesson := range archiveChapter.Lessons {
+			if lessonIDs[archiveLesson.ID] {
+				return models.Course{}, nil, fmt.Errorf("duplicate lesson ID %d in manifest", archiveLesson.ID)
+			}
+			lessonIDs[archiveLesson.ID] = true
+			if strings.TrimSpace(archiveLesson.Name) == "" {
+				return models.Course{}, nil, fmt.Errorf("lesson %d: lesson name is required", archiveLesson.ID)
+			}
+			lessonPath := fmt.Sprintf("chapter:%d/lesson:%d", archiveChapter.ID, archiveLesson.ID)
+
+			lesson := models.Lesson{
+				ID:          archiveLesson.ID,
+				Name:        archiveLesson.Name,
+				Description: archiveLesson.Description,
+				Content:     archiveLesson.Content,
+				Order:       archiveLesson.Order,
+			}
+
+			lessonAttachments := make(map[uint]bool, len(archiveLesson.Attachments))
+			for _, archiveAttachment := range archiveLesson.Attachments {
+				if attachmentIDs[archiveAttachment.ID] {
+					return models.Course{}, nil, fmt.Errorf("duplicate attachment ID %d in manifest", archiveAttachment.ID)
+				}
+				attachmentIDs[archiveAttachment.ID] = true
+
+				base := path.Base(archiveAttachment.File)
+				if _, ok := files[archiveAttachment.File]; !ok || base == "." || base == ".." || base == "/" {
+					conflicts = append(conflicts, schemas.ImportConflict{
+						Type:       schemas.ImportConflictMissingFile,
+						Path:       fmt.Sprintf("%s/attachment:%d", lessonPath, archiveAttachment.ID),
+						Message:    fmt.Sprintf("the file of attachment %s is missing from the archive", archiveAttachment.Name),
+						Resolution: "attachment skipped",
+					})
+					continue
+				}
+
+				lessonAttachments[archiveAttachment.ID] = true
+				lesson.Attachments = append(lesson.Attachments, models.Attachment{
+					ID:   archiveAttachment.ID,
+					Name: archiveAttachment.Name,
+					URL:  archiveAttachment.File,
+				})
+			}
+
+			archiveBlocks := append([]schemas.LessonBlockRequest(nil), archiveLesson.Blocks...)
+			sort.SliceStable(archiveBlocks, func(i, j int) bool { return archiveBlocks[i].Position < archiveBlocks[j].Position })
+			for i, archiveBlock := range archiveBlocks {
+				block, err := newLessonBlock(archiveBlock)
+				if err != nil {
+					return models.Course{}, nil, fmt.Errorf("lesson %d block %d: %w", archiveLesson.ID, i+1, err)
+				}
+				if block.AttachmentID != nil && !lessonAttachments[*block.AttachmentID] {
+					conflicts = append(conflicts, schemas.ImportConflict{
+						Type:       schemas.ImportConflictUnknownAttachment,
+						Path:       fmt.Sprintf("%s/block:%d", lessonPath, i+1),
+						Message:    fmt.Sprintf("the block refers to attachment %d which is not imported with the lesson", *block.AttachmentID),
+						Resolution: "block skipped",
+					})
+					continue
+				}
+				block.Position = len(lesson.Blocks) + 1
+				lesson.Blocks = append(lesson.Blocks, block)
+			}
+
+			if len(lesson.Blocks) > 0 {
+				lesson.Content = renderLessonBlocksMarkdown(lesson.Blocks)
+			} else if strings.TrimSpace(lesson.Content) != "" {
+				lesson.Blocks = []models.LessonBlock{markdownBlock(lesson.Content, nil)}
+			}
+
+			chapter.Lessons = append(chapter.Lessons, lesson)
+		}
+
+		course.Chapters = append(course.Chapters, chapter)
+	}
+
+	return course, conflicts, nil
+}
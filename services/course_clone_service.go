@@ -0,0 +1,138 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"web/models"
+	"web/repos"
+	"web/schemas"
+)
+
+// ObjectCopier copies and removes stored objects, implemented by AttachmentService
+type ObjectCopier interface {
+	CopyObject(sourceName, destinationName string) error
+	RemoveObject(objectName string) error
+}
+
+type CourseCloneServiceInterface interface {
+	CloneCourse(courseID uint, actor Actor, cloneRequest schemas.CloneCourseRequest) (schemas.CourseTreeResponse, error)
+}
+
+var _ CourseCloneServiceInterface = (*CourseCloneService)(nil)
+
+type CourseCloneService struct {
+	courseRepo repos.CourseRepositoryInterface
+	storage    ObjectCopier
+}
+
+func NewCourseCloneService(courseRepo repos.CourseRepositoryInterface, storage ObjectCopier) *CourseCloneService {
+	return &CourseCloneService{
+		courseRepo: courseRepo,
+		storage:    storage,
+	}
+}
+
+// CloneCourse copies a course with its chapters, lessons, lesson blocks and attachments
+// into a new draft course owned by the actor. Admins may clone any course, teachers
+// their own courses and published ones.
+func (s *CourseCloneService) CloneCourse(courseID uint, actor Actor, cloneRequest schemas.CloneCourseRequest) (schemas.CourseTreeResponse, error) {
+	if courseID == 0 {
+		return schemas.CourseTreeResponse{}, errors.New("course ID is required")
+	}
+
+	source, err := s.courseRepo.GetTree(courseID)
+	if err != nil {
+		return schemas.CourseTreeResponse{}, err
+	}
+
+	if !canCopyCourse(actor, source) {
+		return schemas.CourseTreeResponse{}, errors.New("insufficient permissions to clone this course")
+	}
+
+	name := strings.TrimSpace(cloneRequest.Name)
+	if name == "" {
+		name = source.Name + " (copy)"
+	}
+	if len(name) > 255 {
+		return schemas.CourseTreeResponse{}, errors.New("course name must not exceed 255 characters")
+	}
+
+	clone, err := s.courseRepo.Clone(source, repos.CloneOptions{
+		Name:           name,
+		CreatedBy:      actor.UserID,
+		OrganizationID: source.OrganizationID,
+		RenderContent:  renderLessonBlocksMarkdown,
+		CopyObjects:    s.copyObjects,
+	})
+	if err != nil {
+		return schemas.CourseTreeResponse{}, err
+	}
+
+	return toCourseTreeResponse(clone), nil
+}
+
+// canCopyCourse reports whether the actor may copy the course out by cloning or exporting it:
+// admins any course, teachers their own courses and published ones, all within their
+// organization
+func canCopyCourse(actor Actor, course models.Course) bool {
+	if !actor.InOrganization(course.OrganizationID) {
+		return false
+	}
+	return actor.IsAdmin || (actor.IsTeacher && (actor.Owns(course.CreatedBy) || course.Status == models.CourseStatusPublished))
+}
+
+// copyObjects copies all objects or none: when a copy fails the objects copied so far are removed
+func (s *CourseCloneService) copyObjects(copies []repos.ObjectCopy) error {
+	for i, objectCopy := range copies {
+		if err := s.storage.CopyObject(objectCopy.Source, objectCopy.Destination); err != nil {
+			for _, copied := range copies[:i] {
+				_ = s.storage.RemoveObject(copied.Destination)
+			}
+			return fmt.Errorf("failed to copy attachment %s: %w", objectCopy.Source, err)
+		}
+	}
+	return nil
+}
+
+func toCourseTreeResponse(course models.Course) schemas.CourseTreeResponse {
+	tree := schemas.CourseTreeResponse{
+		ID:          course.ID,
+		Name:        course.Name,
+		Description: course.Description,
+		Status:      course.Status,
+		CreatedBy:   course.CreatedBy,
+		CreatedAt:   course.CreatedAt,
+		Chapters:    make([]schemas.ChapterTreeResponse, 0, len(course.Chapters)),
+	}
+
+	for _, chapter := range course.Chapters {
+		chapterTree := schemas.ChapterTreeResponse{
+			ID:          chapter.ID,
+			Name:        chapter.Name,
+			Description: chapter.Description,
+			Order:       chapter.Order,
+			Lessons:     make([]schemas.LessonTreeResponse, 0, len(chapter.Lessons)),
+		}
+		for _, lesson := range chapter.Lessons {
+			lessonTree := schemas.LessonTreeResponse{
+				ID:          lesson.ID,
+				Name:        lesson.Name,
+				Description: lesson.Description,
+				Order:       lesson.Order,
+				BlocksCount: len(lesson.Blocks),
+				Attachments: make([]schemas.AttachmentTreeResponse, 0, len(lesson.Attachments)),
+			}
+			for _, attachment := range lesson.Attachments {
+				lessonTree.Attachments = append(lessonTree.Attachments, schemas.AttachmentTreeResponse{
+					ID:   attachment.ID,
+					Name: attachment.Name,
+				})
+			}
+			chapterTree.Lessons = append(chapterTree.Lessons, lessonTree)
+		}
+		tree.Chapters = append(tree.Chapters, chapterTree)
+	}
+
+	return tree
+}
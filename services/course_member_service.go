@@ -0,0 +1,160 @@
+package services
+
+import (
+	"errors"
+	"web/models"
+	"web/repos"
+	"web/schemas"
+)
+
+type CourseMemberServiceInterface interface {
+	ListMembers(courseID uint) ([]schemas.CourseMemberResponse, error)
+	InviteMember(courseID uint, actor Actor, inviteRequest schemas.InviteCourseMemberRequest) (schemas.CourseMemberResponse, error)
+	UpdateMemberRole(courseID, userID uint, updateRequest schemas.UpdateCourseMemberRequest) (schemas.CourseMemberResponse, error)
+	RemoveMember(courseID, userID uint) error
+}
+
+var _ CourseMemberServiceInterface = (*CourseMemberService)(nil)
+
+// CourseMemberService manages the collaborators of a course. Every course has exactly one
+// owner, its creator, who invites co-instructors, teaching assistants and viewers.
+type CourseMemberService struct {
+	repo       repos.CourseMemberRepositoryInterface
+	courseRepo repos.CourseRepositoryInterface
+	userRepo   repos.UserRepositoryInterface
+}
+
+func NewCourseMemberService(repo repos.CourseMemberRepositoryInterface, courseRepo repos.CourseRepositoryInterface, userRepo repos.UserRepositoryInterface) *CourseMemberService {
+	return &CourseMemberService{
+		repo:       repo,
+		courseRepo: courseRepo,
+		userRepo:   userRepo,
+	}
+}
+
+func (s *CourseMemberService) ListMembers(courseID uint) ([]schemas.CourseMemberResponse, error) {
+	if _, err := s.courseRepo.GetByID(courseID); err != nil {
+		return nil, err
+	}
+
+	members, err := s.repo.GetByCourseID(courseID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]schemas.CourseMemberResponse, len(members))
+	for i, member := range members {
+		responses[i] = toCourseMemberResponse(member)
+	}
+	return responses, nil
+}
+
+// InviteMember adds a user, found by ID or email, to a course with a collaborator role.
+// Only users of the organization of the course can be invited.
+func (s *CourseMemberService) InviteMember(courseID uint, actor Actor, inviteRequest schemas.InviteCourseMemberRequest) (schemas.CourseMemberResponse, error) {
+	if !isInvitableMemberRole(inviteRequest.Role) {
+		return schemas.CourseMemberResponse{}, errors.New("invalid member role")
+	}
+	if inviteRequest.UserID == 0 && inviteRequest.Email == "" {
+		return schemas.CourseMemberResponse{}, errors.New("user ID or email is required")
+	}
+
+	course, err := s.courseRepo.GetByID(courseID)
+	if err != nil {
+		return schemas.CourseMemberResponse{}, err
+	}
+
+	var user models.User
+	if inviteRequest.UserID != 0 {
+		user, err = s.userRepo.GetByID(inviteRequest.UserID)
+	} else {
+		user, err = s.userRepo.GetByEmail(inviteRequest.Email)
+	}
+	if err != nil {
+		return schemas.CourseMemberResponse{}, err
+	}
+	if !sameOrganization(user.OrganizationID, course.OrganizationID) {
+		return schemas.CourseMemberResponse{}, errors.New("user does not belong to the organization of this course")
+	}
+
+	role, err := s.repo.GetRole(courseID, user.ID)
+	if err != nil {
+		return schemas.CourseMemberResponse{}, err
+	}
+	if role != "" {
+		return schemas.CourseMemberResponse{}, errors.New("user is already a member of this course")
+	}
+
+	member := models.CourseMember{
+		CourseID: courseID,
+		UserID:   user.ID,
+		Role:     inviteRequest.Role,
+	}
+	if actor.UserID != 0 {
+		invitedBy := actor.UserID
+		member.InvitedBy = &invitedBy
+	}
+
+	member, err = s.repo.Create(member)
+	if err != nil {
+		return schemas.CourseMemberResponse{}, err
+	}
+	member.User = user
+
+	return toCourseMemberResponse(member), nil
+}
+
+// UpdateMemberRole changes the role of a member. The owner keeps the owner role.
+func (s *CourseMemberService) UpdateMemberRole(courseID, userID uint, updateRequest schemas.UpdateCourseMemberRequest) (schemas.CourseMemberResponse, error) {
+	if !isInvitableMemberRole(updateRequest.Role) {
+		return schemas.CourseMemberResponse{}, errors.New("invalid member role")
+	}
+
+	member, err := s.repo.Get(courseID, userID)
+	if err != nil {
+		return schemas.CourseMemberResponse{}, err
+	}
+	if member.Role == models.CourseMemberRoleOwner {
+		return schemas.CourseMemberResponse{}, errors.New("the course owner cannot be changed")
+	}
+
+	if err := s.repo.UpdateRole(courseID, userID, updateRequest.Role); err != nil {
+		return schemas.CourseMemberResponse{}, err
+	}
+	member.Role = updateRequest.Role
+
+	return toCourseMemberResponse(member), nil
+}
+
+// RemoveMember removes a collaborator from a course. The owner cannot be removed.
+func (s *CourseMemberService) RemoveMember(courseID, userID uint) error {
+	member, err := s.repo.Get(courseID, userID)
+	if err != nil {
+		return err
+	}
+	if member.Role == models.CourseMemberRoleOwner {
+		return errors.New("the course owner cannot be removed")
+	}
+
+	return s.repo.Delete(courseID, userID)
+}
+
+// isInvitableMemberRole reports whether a role can be given to an invited member
+func isInvitableMemberRole(role string) bool {
+	switch role {
+	case models.CourseMemberRoleCoInstructor, models.CourseMemberRoleTeachingAssistant, models.CourseMemberRoleViewer:
+		return true
+	}
+	return false
+}
+
+func toCourseMemberResponse(member models.CourseMember) schemas.CourseMemberResponse {
+	return schemas.CourseMemberResponse{
+		UserID:    member.UserID,
+		Username:  member.User.Username,
+		Email:     member.User.Email,
+		Role:      member.Role,
+		InvitedBy: member.InvitedBy,
+		CreatedAt: member.CreatedAt,
+	}
+}
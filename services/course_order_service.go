@@ -0,0 +1,212 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"web/models"
+	"web/repos"
+	"web/schemas"
+)
+
+type CourseOrderServiceInterface interface {
+	ReorderChapters(courseID uint, reorderRequest schemas.ReorderChaptersRequest) ([]schemas.OrderedItemResponse, error)
+	ReorderLessons(courseID, chapterID uint, reorderRequest schemas.ReorderLessonsRequest, tenant *schemas.TenantFilter) ([]schemas.OrderedItemResponse, error)
+	MoveLesson(courseID, chapterID, lessonID uint, actor Actor, moveRequest schemas.MoveLessonRequest) ([]schemas.OrderedItemResponse, error)
+	MoveChapter(courseID, chapterID uint, actor Actor, moveRequest schemas.MoveChapterRequest) ([]schemas.OrderedItemResponse, error)
+}
+
+var _ CourseOrderServiceInterface = (*CourseOrderService)(nil)
+
+// CourseOrderService keeps the order of the chapters of a course and the lessons of a
+// chapter dense: every reorder or move assigns the positions 1..n in one transaction
+type CourseOrderService struct {
+	courseRepo  repos.CourseRepositoryInterface
+	chapterRepo repos.ChapterRepositoryInterface
+	lessonRepo  repos.LessonRepositoryInterface
+	memberRepo  repos.CourseMemberRepositoryInterface
+}
+
+func NewCourseOrderService(courseRepo repos.CourseRepositoryInterface, chapterRepo repos.ChapterRepositoryInterface, lessonRepo repos.LessonRepositoryInterface, memberRepo repos.CourseMemberRepositoryInterface) *CourseOrderService {
+	return &CourseOrderService{
+		courseRepo:  courseRepo,
+		chapterRepo: chapterRepo,
+		lessonRepo:  lessonRepo,
+		memberRepo:  memberRepo,
+	}
+}
+
+// ReorderChapters puts the chapters of a course in the given order. The request must list
+// every chapter of the course exactly once.
+func (s *CourseOrderService) ReorderChapters(courseID uint, reorderRequest schemas.ReorderChaptersRequest) ([]schemas.OrderedItemResponse, error) {
+	if courseID == 0 {
+		return nil, errors.New("course ID is required")
+	}
+	if err := checkOrderIDs("chapter", reorderRequest.ChapterIDs); err != nil {
+		return nil, err
+	}
+
+	chapters, err := s.chapterRepo.Reorder(courseID, reorderRequest.ChapterIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	return toOrderedChapters(chapters), nil
+}
+
+// ReorderLessons puts the lessons of a chapter in the given order. The request must list
+// every lesson of the chapter exactly once.
+func (s *CourseOrderService) ReorderLessons(courseID, chapterID uint, reorderRequest schemas.ReorderLessonsRequest, tenant *schemas.TenantFilter) ([]schemas.OrderedItemResponse, error) {
+	if err := checkOrderIDs("lesson", reorderRequest.LessonIDs); err != nil {
+		return nil, err
+	}
+
+	chapter, err := s.chapterRepo.GetByID(chapterID, courseID, tenant)
+	if err != nil {
+		return nil, err
+	}
+
+	lessons, err := s.lessonRepo.Reorder(chapter.ID, reorderRequest.LessonIDs)
+	if err != nil {
+		return nil, err
+	}
+	return toOrderedLessons(lessons), nil
+}
+
+// MoveLesson moves a lesson to a position in another chapter, or to another position in its
+// own chapter. Moving it to a chapter of another course requires editing both courses. It
+// returns the lessons of the target chapter.
+func (s *CourseOrderService) MoveLesson(courseID, chapterID, lessonID uint, actor Actor, moveRequest schemas.MoveLessonRequest) ([]schemas.OrderedItemResponse, error) {
+	if moveRequest.ChapterID == 0 {
+		return nil, errors.New("target chapter ID is required")
+	}
+	if moveRequest.Position < 0 {
+		return nil, errors.New("invalid lesson position")
+	}
+
+	lesson, err := s.lessonRepo.GetByID(courseID, chapterID, lessonID, actor.TenantFilter())
+	if err != nil {
+		return nil, err
+	}
+
+	toCourseID := courseID
+	if moveRequest.CourseID != 0 && moveRequest.CourseID != courseID {
+		if err := s.checkMoveOwnership(actor, courseID, moveRequest.CourseID); err != nil {
+			return nil, err
+		}
+		toCourseID = moveRequest.CourseID
+	}
+
+	lessons, err := s.lessonRepo.Move(lesson.ID, toCourseID, moveRequest.ChapterID, moveRequest.Position)
+	if err != nil {
+		return nil, err
+	}
+	return toOrderedLessons(lessons), nil
+}
+
+// MoveChapter moves a chapter with its lessons to a position in another course, which
+// requires editing both courses, or to another position in its own course. It returns the
+// chapters of the target course.
+func (s *CourseOrderService) MoveChapter(courseID, chapterID uint, actor Actor, moveRequest schemas.MoveChapterRequest) ([]schemas.OrderedItemResponse, error) {
+	if moveRequest.CourseID == 0 {
+		return nil, errors.New("target course ID is required")
+	}
+	if moveRequest.Position < 0 {
+		return nil, errors.New("invalid chapter position")
+	}
+
+	chapter, err := s.chapterRepo.GetByID(chapterID, courseID, actor.TenantFilter())
+	if err != nil {
+		return nil, err
+	}
+
+	if moveRequest.CourseID != courseID {
+		if err := s.checkMoveOwnership(actor, courseID, moveRequest.CourseID); err != nil {
+			return nil, err
+		}
+	}
+
+	chapters, err := s.chapterRepo.Move(chapter.ID, moveRequest.CourseID, moveRequest.Position)
+	if err != nil {
+		return nil, err
+	}
+	return toOrderedChapters(chapters), nil
+}
+
+// checkMoveOwnership allows admins, and users who own or co-instruct both courses, to move
+// content from one course to the other
+func (s *CourseOrderService) checkMoveOwnership(actor Actor, sourceCourseID, targetCourseID uint) error {
+	source, err := s.courseRepo.GetByID(sourceCourseID)
+	if err != nil {
+		return err
+	}
+	target, err := s.courseRepo.GetByID(targetCourseID)
+	if err != nil {
+		if err.Error() == "course not found" {
+			return errors.New("target course not found")
+		}
+		return err
+	}
+	// Content never leaves its organization
+	if !actor.InOrganization(target.OrganizationID) || !sameOrganization(source.OrganizationID, target.OrganizationID) {
+		return errors.New("target course not found")
+	}
+
+	if actor.IsAdmin {
+		return nil
+	}
+	for _, course := range []models.Course{source, target} {
+		canEdit, err := s.canEditCourse(actor, course)
+		if err != nil {
+			return err
+		}
+		if !canEdit {
+			return errors.New("insufficient permissions to move content between these courses")
+		}
+	}
+	return nil
+}
+
+// canEditCourse reports whether the actor created the course or is its owner or
+// co-instructor
+func (s *CourseOrderService) canEditCourse(actor Actor, course models.Course) (bool, error) {
+	if actor.Owns(course.CreatedBy) {
+		return true, nil
+	}
+
+	role, err := s.memberRepo.GetRole(course.ID, actor.UserID)
+	if err != nil {
+		return false, err
+	}
+	return role == models.CourseMemberRoleOwner || role == models.CourseMemberRoleCoInstructor, nil
+}
+
+// checkOrderIDs rejects empty lists and lists that name an item twice
+func checkOrderIDs(item string, ids []uint) error {
+	if len(ids) == 0 {
+		return fmt.Errorf("%s order must not be empty", item)
+	}
+	seen := make(map[uint]bool, len(ids))
+	for _, id := range ids {
+		if seen[id] {
+			return fmt.Errorf("%s order lists %s %d more than once", item, item, id)
+		}
+		seen[id] = true
+	}
+	return nil
+}
+
+func toOrderedChapters(chapters []models.Chapter) []schemas.OrderedItemResponse {
+	items := make([]schemas.OrderedItemResponse, 0, len(chapters))
+	for _, chapter := range chapters {
+		items = append(items, schemas.OrderedItemResponse{ID: chapter.ID, Name: chapter.Name, Order: chapter.Order})
+	}
+	return items
+}
+
+func toOrderedLessons(lessons []models.Lesson) []schemas.OrderedItemResponse {
+	items := make([]schemas.OrderedItemResponse, 0, len(lessons))
+	for _, lesson := range lessons {
+		items = append(items, schemas.OrderedItemResponse{ID: lesson.ID, Name: lesson.Name, Order: lesson.Order})
+	}
+	return items
+}
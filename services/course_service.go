@@ -2,34 +2,39 @@ package services
 
 import (
 	"errors"
+	"fmt"
 	"web/models"
 	"web/repos"
 	"web/schemas"
 )
 
 type CourseServiceInterface interface {
-	GetAllCourses() ([]schemas.CourseResponseWithChaptersCount, error)
+	GetAllCourses(params schemas.ListParams) ([]schemas.CourseResponseWithChaptersCount, schemas.PaginationMeta, error)
 	GetCourseByID(id uint) (models.Course, error)
 	GetCourseByIDWithChapterCount(id uint) (schemas.CourseResponseWithChaptersCount, error)
 	CreateCourse(courseDTO schemas.CreateCourseRequest) (schemas.CourseResponse, error)
 	UpdateCourse(course models.Course, courseRequest schemas.UpdateCourseRequest) (schemas.CourseResponse, error)
 	DeleteCourse(id uint) error
+	ChangeCourseStatus(courseID uint, actor Actor, statusRequest schemas.ChangeCourseStatusRequest) (schemas.CourseResponse, error)
+	GetCourseStatusHistory(courseID uint) ([]models.CourseStatusHistory, error)
 }
 
 // Ensure CourseService implements CourseServiceInterface
 var _ CourseServiceInterface = (*CourseService)(nil)
 
 type CourseService struct {
-	repo repos.CourseRepositoryInterface
+	repo       repos.CourseRepositoryInterface
+	memberRepo repos.CourseMemberRepositoryInterface
 }
 
 func (s *CourseService) GetCourseByIDWithChapterCount(id uint) (schemas.CourseResponseWithChaptersCount, error) {
 	return s.repo.GetByIDWithChaptersCount(id)
 }
 
-func NewCourseService(repo repos.CourseRepositoryInterface) *CourseService {
+func NewCourseService(repo repos.CourseRepositoryInterface, memberRepo repos.CourseMemberRepositoryInterface) *CourseService {
 	return &CourseService{
-		repo: repo,
+		repo:       repo,
+		memberRepo: memberRepo,
 	}
 }
 
@@ -51,22 +56,17 @@ func (s *CourseService) CreateCourse(courseRequest schemas.CreateCourseRequest)
 
 	// Convert DTO to model
 	course := models.Course{
-		Name:        courseRequest.Name,
-		Description: courseRequest.Description,
-		CreatedBy:   courseRequest.CreatedBy,
+		Name:           courseRequest.Name,
+		Description:    courseRequest.Description,
+		Status:         models.CourseStatusDraft,
+		CreatedBy:      courseRequest.CreatedBy,
+		OrganizationID: courseRequest.OrganizationID,
 	}
 	course, err := s.repo.Create(course)
 	if err != nil {
 		return schemas.CourseResponse{}, err
 	}
-	courseResponse := schemas.CourseResponse{
-		ID:          course.ID,
-		Name:        course.Name,
-		Description: course.Description,
-		CreatedBy:   course.CreatedBy,
-		CreatedAt:   course.CreatedAt,
-	}
-	return courseResponse, nil
+	return toCourseResponse(course), nil
 }
 
 func (s *CourseService) UpdateCourse(course models.Course, courseRequest schemas.UpdateCourseRequest) (schemas.CourseResponse, error) {
@@ -81,14 +81,7 @@ func (s *CourseService) UpdateCourse(course models.Course, courseRequest schemas
 	if err != nil {
 		return schemas.CourseResponse{}, err
 	}
-	courseResponse := schemas.CourseResponse{
-		ID:          course.ID,
-		Name:        course.Name,
-		Description: course.Description,
-		CreatedBy:   course.CreatedBy,
-		CreatedAt:   course.CreatedAt,
-	}
-	return courseResponse, nil
+	return toCourseResponse(course), nil
 }
 
 func (s *CourseService) DeleteCourse(id uint) error {
@@ -99,6 +92,108 @@ func (s *CourseService) DeleteCourse(id uint) error {
 	return s.repo.Delete(id)
 }
 
-func (s *CourseService) GetAllCourses() ([]schemas.CourseResponseWithChaptersCount, error) {
-	return s.repo.GetAll()
+func (s *CourseService) GetAllCourses(params schemas.ListParams) ([]schemas.CourseResponseWithChaptersCount, schemas.PaginationMeta, error) {
+	courses, total, err := s.repo.GetAll(params)
+	if err != nil {
+		return nil, schemas.PaginationMeta{}, err
+	}
+	return courses, NewPaginationMeta(params, total), nil
+}
+
+// courseTransition is a move between two publishing states
+type courseTransition struct {
+	from string
+	to   string
+}
+
+// courseTransitionRules lists the allowed publishing transitions and who may perform them.
+// Teachers submit their own courses for review and may withdraw them again, as may the
+// co-instructors of the course. Admins approve, reject, archive and restore.
+var courseTransitionRules = map[courseTransition]func(actor Actor, course models.Course, memberRole string) bool{
+	{models.CourseStatusDraft, models.CourseStatusInReview}:     authorOrAdmin,
+	{models.CourseStatusInReview, models.CourseStatusDraft}:     authorOrAdmin,
+	{models.CourseStatusInReview, models.CourseStatusPublished}: adminOnly,
+	{models.CourseStatusPublished, models.CourseStatusArchived}: adminOnly,
+	{models.CourseStatusArchived, models.CourseStatusDraft}:     adminOnly,
+}
+
+func authorOrAdmin(actor Actor, course models.Course, memberRole string) bool {
+	return actor.IsAdmin || (actor.IsTeacher && actor.Owns(course.CreatedBy)) ||
+		memberRole == models.CourseMemberRoleOwner || memberRole == models.CourseMemberRoleCoInstructor
+}
+
+func adminOnly(actor Actor, course models.Course, memberRole string) bool {
+	return actor.IsAdmin
+}
+
+// ChangeCourseStatus moves a course through the publishing workflow and records the
+// transition in the status history
+func (s *CourseService) ChangeCourseStatus(courseID uint, actor Actor, statusRequest schemas.ChangeCourseStatusRequest) (schemas.CourseResponse, error) {
+	switch statusRequest.Status {
+	case models.CourseStatusDraft, models.CourseStatusInReview, models.CourseStatusPublished, models.CourseStatusArchived:
+	default:
+		return schemas.CourseResponse{}, errors.New("invalid course status")
+	}
+
+	course, err := s.GetCourseByID(courseID)
+	if err != nil {
+		return schemas.CourseResponse{}, err
+	}
+
+	if course.Status == statusRequest.Status {
+		return schemas.CourseResponse{}, fmt.Errorf("course is already %s", course.Status)
+	}
+
+	allowed, ok := courseTransitionRules[courseTransition{from: course.Status, to: statusRequest.Status}]
+	if !ok {
+		return schemas.CourseResponse{}, fmt.Errorf("cannot change course status from %s to %s", course.Status, statusRequest.Status)
+	}
+	var memberRole string
+	if !actor.IsAdmin {
+		memberRole, err = s.memberRepo.GetRole(course.ID, actor.UserID)
+		if err != nil {
+			return schemas.CourseResponse{}, err
+		}
+	}
+	if !allowed(actor, course, memberRole) {
+		return schemas.CourseResponse{}, errors.New("insufficient permissions to change course status")
+	}
+
+	history := models.CourseStatusHistory{
+		CourseID:   course.ID,
+		FromStatus: course.Status,
+		ToStatus:   statusRequest.Status,
+		Comment:    statusRequest.Comment,
+	}
+	if actor.UserID != 0 {
+		changedBy := actor.UserID
+		history.ChangedBy = &changedBy
+	}
+
+	course, err = s.repo.UpdateStatus(course, history)
+	if err != nil {
+		return schemas.CourseResponse{}, err
+	}
+
+	return toCourseResponse(course), nil
+}
+
+func (s *CourseService) GetCourseStatusHistory(courseID uint) ([]models.CourseStatusHistory, error) {
+	if courseID == 0 {
+		return nil, errors.New("course ID is required")
+	}
+
+	return s.repo.GetStatusHistory(courseID)
+}
+
+func toCourseResponse(course models.Course) schemas.CourseResponse {
+	return schemas.CourseResponse{
+		ID:          course.ID,
+		Name:        course.Name,
+		Description: course.Description,
+		Status:      course.Status,
+		PublishedAt: course.PublishedAt,
+		CreatedBy:   course.CreatedBy,
+		CreatedAt:   course.CreatedAt,
+	}
 }
@@ -0,0 +1,131 @@
+package services
+
+import (
+	"errors"
+	"web/models"
+	"web/repos"
+	"web/schemas"
+)
+
+type EnrollmentServiceInterface interface {
+	Enroll(userID, courseID uint) (schemas.EnrollmentResponse, error)
+	Unenroll(userID, courseID uint) error
+	GetUserEnrollments(userID uint) ([]schemas.EnrollmentResponse, error)
+	IsEnrolled(userID, courseID uint) (bool, error)
+	HasAccessToCourse(actor Actor, courseID uint) (bool, error)
+}
+
+var _ EnrollmentServiceInterface = (*EnrollmentService)(nil)
+
+type EnrollmentService struct {
+	repo       repos.EnrollmentRepositoryInterface
+	courseRepo repos.CourseRepositoryInterface
+	memberRepo repos.CourseMemberRepositoryInterface
+}
+
+func NewEnrollmentService(repo repos.EnrollmentRepositoryInterface, courseRepo repos.CourseRepositoryInterface, memberRepo repos.CourseMemberRepositoryInterface) *EnrollmentService {
+	return &EnrollmentService{
+		repo:       repo,
+		courseRepo: courseRepo,
+		memberRepo: memberRepo,
+	}
+}
+
+func (s *EnrollmentService) Enroll(userID, courseID uint) (schemas.EnrollmentResponse, error) {
+	if userID == 0 {
+		return schemas.EnrollmentResponse{}, errors.New("user ID is required")
+	}
+	if courseID == 0 {
+		return schemas.EnrollmentResponse{}, errors.New("course ID is required")
+	}
+
+	course, err := s.courseRepo.GetByID(courseID)
+	if err != nil {
+		return schemas.EnrollmentResponse{}, err
+	}
+	if course.Status != models.CourseStatusPublished {
+		return schemas.EnrollmentResponse{}, errors.New("course is not published")
+	}
+
+	enrolled, err := s.repo.Exists(userID, courseID)
+	if err != nil {
+		return schemas.EnrollmentResponse{}, err
+	}
+	if enrolled {
+		return schemas.EnrollmentResponse{}, errors.New("already enrolled in this course")
+	}
+
+	enrollment, err := s.repo.Create(models.Enrollment{
+		UserID:   userID,
+		CourseID: course.ID,
+	})
+	if err != nil {
+		return schemas.EnrollmentResponse{}, err
+	}
+
+	enrollmentResponse := schemas.EnrollmentResponse{
+		ID:                enrollment.ID,
+		CourseID:          course.ID,
+		CourseName:        course.Name,
+		CourseDescription: course.Description,
+		EnrolledAt:        enrollment.CreatedAt,
+	}
+	return enrollmentResponse, nil
+}
+
+func (s *EnrollmentService) Unenroll(userID, courseID uint) error {
+	if userID == 0 {
+		return errors.New("user ID is required")
+	}
+	if courseID == 0 {
+		return errors.New("course ID is required")
+	}
+
+	return s.repo.Delete(userID, courseID)
+}
+
+func (s *EnrollmentService) GetUserEnrollments(userID uint) ([]schemas.EnrollmentResponse, error) {
+	if userID == 0 {
+		return nil, errors.New("user ID is required")
+	}
+
+	return s.repo.GetByUserID(userID)
+}
+
+func (s *EnrollmentService) IsEnrolled(userID, courseID uint) (bool, error) {
+	return s.repo.Exists(userID, courseID)
+}
+
+// HasAccessToCourse reports whether the actor may read the content of a course.
+// Courses of other organizations are reported as not found. Admins and teachers can
+// read every course of their organization, owners and members can read their courses
+// and everyone else has to be enrolled in a published course.
+func (s *EnrollmentService) HasAccessToCourse(actor Actor, courseID uint) (bool, error) {
+	course, err := s.courseRepo.GetByID(courseID)
+	if err != nil {
+		return false, err
+	}
+
+	if !actor.InOrganization(course.OrganizationID) {
+		return false, errors.New("course not found")
+	}
+
+	if actor.IsStaff() || actor.Owns(course.CreatedBy) {
+		return true, nil
+	}
+
+	role, err := s.memberRepo.GetRole(courseID, actor.UserID)
+	if err != nil {
+		return false, err
+	}
+	if role != "" {
+		return true, nil
+	}
+
+	// Learners lose access while the course is back in draft, in review or archived
+	if course.Status != models.CourseStatusPublished {
+		return false, nil
+	}
+
+	return s.repo.Exists(actor.UserID, courseID)
+}
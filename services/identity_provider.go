@@ -0,0 +1,217 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+	"web/config"
+	"web/repos"
+	"web/schemas"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// IdentityClaims is the identity of the user of a validated access token, independent of the
+// identity provider that issued it. Organization is the slug of the organization of the user
+// when the provider names one, Groups may name it as a group below /organizations instead.
+type IdentityClaims struct {
+	Subject      string
+	Username     string
+	Email        string
+	Name         string
+	Roles        []string
+	Organization string
+	Groups       []string
+	ExpiresAt    time.Time
+}
+
+// IdentityProvider authenticates the users of the API and manages their accounts
+type IdentityProvider interface {
+	// ValidateToken checks the signature, expiry and issuer of an access token
+	ValidateToken(tokenString string) (*IdentityClaims, error)
+	// IntrospectToken asks the provider whether a token is still active
+	IntrospectToken(tokenString string) (bool, error)
+	Login(username, password string) (*schemas.LoginResponse, error)
+	RefreshToken(refreshToken string) (*schemas.LoginResponse, error)
+	// RevokeToken ends the session of a refresh token
+	RevokeToken(refreshToken string) error
+	// CreateUser provisions an account and returns its subject
+	CreateUser(username, email, password string) (string, error)
+	// AssignRoles grants roles to the account of a subject, unknown roles are ignored
+	AssignRoles(subject string, roles []string) error
+	// RunKeyRefresh keeps the signing keys of the provider fresh until ctx is done
+	RunKeyRefresh(ctx context.Context)
+}
+
+var (
+	_ IdentityProvider = (*KeycloakProvider)(nil)
+	_ IdentityProvider = (*OIDCProvider)(nil)
+	_ IdentityProvider = (*LocalProvider)(nil)
+)
+
+// NewIdentityProvider returns the identity provider selected by the configuration. The
+// generic OIDC provider reads the discovery document of its issuer and fails when the
+// issuer cannot be reached, or when it has no introspection endpoint while the token
+// validation introspects tokens. The repositories are only used by the local provider.
+func NewIdentityProvider(appConfig *config.AppConfig, userRepo repos.UserRepositoryInterface, refreshTokenRepo repos.RefreshTokenRepositoryInterface) (IdentityProvider, error) {
+	switch appConfig.IdentityProvider {
+	case config.IdentityProviderOIDC:
+		provider, err := NewOIDCProvider(appConfig.OIDCIssuerURL, appConfig.OIDCClientID, appConfig.OIDCClientSecret, appConfig.OIDCRolesClaim)
+		if err != nil {
+			return nil, err
+		}
+		if appConfig.TokenValidation != config.TokenValidationLocal && !provider.SupportsIntrospection() {
+			return nil, fmt.Errorf("OIDC discovery document has no introspection_endpoint, set TOKEN_VALIDATION=%s to validate tokens without it", config.TokenValidationLocal)
+		}
+		return provider, nil
+	case config.IdentityProviderLocal:
+		return NewLocalProvider(appConfig, userRepo, refreshTokenRepo)
+	case config.IdentityProviderKeycloak, "":
+		return NewKeycloakProvider(appConfig), nil
+	}
+	return nil, fmt.Errorf("unknown identity provider %q", appConfig.IdentityProvider)
+}
+
+// identityHTTPClient is the client of the requests to identity providers
+var identityHTTPClient = &http.Client{Timeout: 10 * time.Second}
+
+// parseSignedToken verifies the signature of a token with the key named by its kid and
+// decodes its claims. Tokens must be signed with RSA or ECDSA and expire.
+func parseSignedToken(jwks *JWKSCache, tokenString string, claims jwt.Claims) error {
+	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+		switch token.Method.(type) {
+		case *jwt.SigningMethodRSA, *jwt.SigningMethodRSAPSS, *jwt.SigningMethodECDSA:
+		default:
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+
+		kid, ok := token.Header["kid"].(string)
+		if !ok {
+			return nil, errors.New("kid header not found in token")
+		}
+
+		return jwks.Key(context.Background(), kid)
+	}, jwt.WithExpirationRequired())
+	if err != nil {
+		return err
+	}
+
+	if !token.Valid {
+		return errors.New("invalid token")
+	}
+	return nil
+}
+
+// requestTokens posts an OAuth 2.0 grant to a token endpoint. failure prefixes the error of
+// a rejected grant.
+func requestTokens(tokenURL string, formData url.Values, failure string) (*schemas.LoginResponse, error) {
+	req, err := http.NewRequest("POST", tokenURL, strings.NewReader(formData.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := identityHTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("%s: %s (status code: %d)", failure, string(body), resp.StatusCode)
+	}
+
+	var tokenResponse struct {
+		AccessToken  string `json:"access_token"`
+		RefreshToken string `json:"refresh_token"`
+		TokenType    string `json:"token_type"`
+		ExpiresIn    int    `json:"expires_in"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&tokenResponse); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return &schemas.LoginResponse{
+		AccessToken:  tokenResponse.AccessToken,
+		RefreshToken: tokenResponse.RefreshToken,
+		TokenType:    tokenResponse.TokenType,
+		ExpiresIn:    tokenResponse.ExpiresIn,
+	}, nil
+}
+
+// introspectToken asks an RFC 7662 introspection endpoint whether a token is active
+func introspectToken(introspectionURL, clientID, clientSecret, tokenString string) (bool, error) {
+	if tokenString == "" {
+		return false, errors.New("token is required")
+	}
+
+	formData := url.Values{}
+	formData.Set("token", tokenString)
+	formData.Set("client_id", clientID)
+	formData.Set("client_secret", clientSecret)
+
+	req, err := http.NewRequest("POST", introspectionURL, strings.NewReader(formData.Encode()))
+	if err != nil {
+		return false, fmt.Errorf("failed to create introspection request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := identityHTTPClient.Do(req)
+	if err != nil {
+		return false, fmt.Errorf("failed to send introspection request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return false, fmt.Errorf("token introspection failed: %s (status code: %d)", string(body), resp.StatusCode)
+	}
+
+	var introspectionResponse struct {
+		Active bool `json:"active"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&introspectionResponse); err != nil {
+		return false, fmt.Errorf("failed to parse introspection response: %w", err)
+	}
+
+	return introspectionResponse.Active, nil
+}
+
+// revokeToken asks an RFC 7009 revocation endpoint to revoke a refresh token
+func revokeToken(revocationURL, clientID, clientSecret, refreshToken string) error {
+	formData := url.Values{}
+	formData.Set("token", refreshToken)
+	formData.Set("token_type_hint", "refresh_token")
+	formData.Set("client_id", clientID)
+	formData.Set("client_secret", clientSecret)
+
+	req, err := http.NewRequest("POST", revocationURL, strings.NewReader(formData.Encode()))
+	if err != nil {
+		return fmt.Errorf("failed to create revocation request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := identityHTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send revocation request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("token revocation failed: %s (status code: %d)", string(body), resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,438 @@
+package services
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+	"web/models"
+)
+
+// imsManifestName is the manifest of SCORM and IMS Common Cartridge packages
+const imsManifestName = "imsmanifest.xml"
+
+// maxPackageAttachmentNameLength keeps attachment names and object names within their columns
+const maxPackageAttachmentNameLength = 200
+
+// imsManifest is the part of imsmanifest.xml the importer uses. Elements are matched by
+// their local names, so the namespaces of the different package versions do not matter.
+type imsManifest struct {
+	XMLName  xml.Name `xml:"manifest"`
+	Metadata struct {
+		Schema        string `xml:"schema"`
+		SchemaVersion string `xml:"schemaversion"`
+	} `xml:"metadata"`
+	Organizations struct {
+		Default       string            `xml:"default,attr"`
+		Organizations []imsOrganization `xml:"organization"`
+	} `xml:"organizations"`
+	Resources struct {
+		Base      string        `xml:"base,attr"`
+		Resources []imsResource `xml:"resource"`
+	} `xml:"resources"`
+}
+
+type imsOrganization struct {
+	Identifier string    `xml:"identifier,attr"`
+	Title      string    `xml:"title"`
+	Items      []imsItem `xml:"item"`
+}
+
+type imsItem struct {
+	Identifier    string    `xml:"identifier,attr"`
+	IdentifierRef string    `xml:"identifierref,attr"`
+	Title         string    `xml:"title"`
+	Items         []imsItem `xml:"item"`
+}
+
+type imsResource struct {
+	Identifier string `xml:"identifier,attr"`
+	Type       string `xml:"type,attr"`
+	ScormType  string `xml:"scormtype,attr"`
+	Href       string `xml:"href,attr"`
+	Base       string `xml:"base,attr"`
+	Files      []struct {
+		Href string `xml:"href,attr"`
+	} `xml:"file"`
+	Dependencies []struct {
+		IdentifierRef string `xml:"identifierref,attr"`
+	} `xml:"dependency"`
+}
+
+// imsWebLink is a Common Cartridge web link resource
+type imsWebLink struct {
+	Title string `xml:"title"`
+	URL   struct {
+		Href string `xml:"href,attr"`
+	} `xml:"url"`
+}
+
+// parseIMSManifest reads imsmanifest.xml and detects the package kind
+func parseIMSManifest(files map[string]*zip.File) (imsManifest, string, error) {
+	file, ok := files[imsManifestName]
+	if !ok {
+		return imsManifest{}, "", errors.New("package has no imsmanifest.xml")
+	}
+
+	data, err := readArchiveFile(file, maxCourseArchiveManifestSize)
+	if err != nil {
+		return imsManifest{}, "", err
+	}
+
+	var manifest imsManifest
+	if err := xml.Unmarshal(data, &manifest); err != nil {
+		return imsManifest{}, "", fmt.Errorf("invalid imsmanifest.xml: %w", err)
+	}
+
+	schema := strings.ToLower(strings.TrimSpace(manifest.Metadata.Schema))
+	version := strings.TrimSpace(manifest.Metadata.SchemaVersion)
+	switch {
+	case strings.Contains(schema, "common cartridge"):
+		return manifest, models.ImportJobKindCommonCartridge, nil
+	case strings.Contains(schema, "scorm") && strings.HasPrefix(version, "1.2"):
+		return manifest, models.ImportJobKindSCORM12, nil
+	case strings.Contains(schema, "scorm"):
+		return imsManifest{}, "", fmt.Errorf("unsupported SCORM version %s, only SCORM 1.2 is supported", version)
+	}
+	return imsManifest{}, "", errors.New("unsupported package, expected a SCORM 1.2 or IMS Common Cartridge package")
+}
+
+// packageCourseBuilder maps the organization of a package to a course tree for
+// CourseRepository.Import. The tree uses sequential IDs and attachment URLs are the names
+// of the package files.
+type packageCourseBuilder struct {
+	manifest  imsManifest
+	kind      string
+	files     map[string]*zip.File
+	resources map[string]imsResource
+	warnings  []models.ImportJobWarning
+	nextID    uint
+}
+
+func newPackageCourseBuilder(manifest imsManifest, kind string, files map[string]*zip.File) *packageCourseBuilder {
+	resources := make(map[string]imsResource, len(manifest.Resources.Resources))
+	for _, resource := range manifest.Resources.Resources {
+		resources[resource.Identifier] = resource
+	}
+
+	return &packageCourseBuilder{
+		manifest:  manifest,
+		kind:      kind,
+		files:     files,
+		resources: resources,
+		warnings:  []models.ImportJobWarning{},
+	}
+}
+
+func (b *packageCourseBuilder) warn(item imsItem, message string) {
+	b.warnings = append(b.warnings, models.ImportJobWarning{
+		Item:    item.Identifier,
+		Title:   strings.TrimSpace(item.Title),
+		Message: message,
+	})
+}
+
+func (b *packageCourseBuilder) id() uint {
+	b.nextID++
+	return b.nextID
+}
+
+// Build maps the items of the default organization: top-level items with children become
+// chapters and the items below them lessons. Top-level items that are lessons themselves
+// are collected in a chapter named after the organization.
+func (b *packageCourseBuilder) Build(fallbackName string) (models.Course, error) {
+	organization, ok := b.defaultOrganization()
+	if !ok {
+		return models.Course{}, errors.New("package has no organization")
+	}
+
+	items := organization.Items
+	// Common Cartridge wraps the modules in a single root item
+	if b.kind == models.ImportJobKindCommonCartridge && len(items) == 1 && items[0].IdentifierRef == "" {
+		items = items[0].Items
+	}
+
+	course := models.Course{Name: limitName(strings.TrimSpace(organization.Title))}
+	if course.Name == "" {
+		course.Name = fallbackName
+	}
+
+	var looseChapter *models.Chapter
+	for _, item := range items {
+		if len(item.Items) == 0 {
+			lesson, ok := b.lesson(item, strings.TrimSpace(item.Title))
+			if !ok {
+				continue
+			}
+			if looseChapter == nil {
+				course.Chapters = append(course.Chapters, models.Chapter{ID: b.id(), Name: course.Name})
+				looseChapter = &course.Chapters[len(course.Chapters)-1]
+			}
+			lesson.Order = len(looseChapter.Lessons) + 1
+			looseChapter.Lessons = append(looseChapter.Lessons, lesson)
+			continue
+		}
+
+		// A new chapter starts after it, following loose lessons get a chapter of their own
+		looseChapter = nil
+		chapter := models.Chapter{ID: b.id(), Name: limitName(itemTitle(item))}
+		if item.IdentifierRef != "" {
+			b.warn(item, "the content of an item with sub-items is not imported")
+		}
+		b.collectLessons(&chapter, item.Items, "")
+		if len(chapter.Lessons) == 0 {
+			b.warn(item, "the item has no importable content and is skipped")
+			continue
+		}
+		course.Chapters = append(course.Chapters, chapter)
+	}
+
+	if len(course.Chapters) == 0 {
+		return models.Course{}, errors.New("package has no importable content")
+	}
+	for i := range course.Chapters {
+		course.Chapters[i].Order = i + 1
+	}
+
+	return course, nil
+}
+
+func (b *packageCourseBuilder) defaultOrganization() (imsOrganization, bool) {
+	organizations := b.manifest.Organizations.Organizations
+	if len(organizations) == 0 {
+		return imsOrganization{}, false
+	}
+	for _, organization := range organizations {
+		if organization.Identifier == b.manifest.Organizations.Default {
+			return organization, true
+		}
+	}
+	return organizations[0], true
+}
+
+// collectLessons adds the items to the chapter. Deeper levels are flattened into the
+// chapter, their lesson names are prefixed with the names of their parents.
+func (b *packageCourseBuilder) collectLessons(chapter *models.Chapter, items []imsItem, prefix string) {
+	for _, item := range items {
+		name := prefix + itemTitle(item)
+		if len(item.Items) > 0 {
+			if item.IdentifierRef != "" {
+				b.warn(item, "the content of an item with sub-items is not imported")
+			}
+			b.warn(item, "nested items are flattened into the chapter")
+			b.collectLessons(chapter, item.Items, name+" / ")
+			continue
+		}
+
+		lesson, ok := b.lesson(item, name)
+		if !ok {
+			continue
+		}
+		lesson.Order = len(chapter.Lessons) + 1
+		chapter.Lessons = append(chapter.Lessons, lesson)
+	}
+}
+
+// lesson maps an item and its resource to a lesson. Unsupported resources are reported
+// and skipped.
+func (b *packageCourseBuilder) lesson(item imsItem, name string) (models.Lesson, bool) {
+	if item.IdentifierRef == "" {
+		b.warn(item, "the item has no content and is skipped")
+		return models.Lesson{}, false
+	}
+	resource, ok := b.resources[item.IdentifierRef]
+	if !ok {
+		b.warn(item, fmt.Sprintf("the item refers to the unknown resource %s and is skipped", item.IdentifierRef))
+		return models.Lesson{}, false
+	}
+	if name == "" {
+		name = item.Identifier
+	}
+	name = limitName(name)
+
+	lesson := models.Lesson{ID: b.id(), Name: name}
+	resourceType := strings.ToLower(resource.Type)
+	switch {
+	case resourceType == "webcontent":
+		if strings.EqualFold(resource.ScormType, "sco") {
+			b.warn(item, "SCORM runtime tracking is not supported, the SCO is imported as static content")
+		}
+		if !b.addResourceFiles(&lesson, item, resource) {
+			return models.Lesson{}, false
+		}
+
+	case strings.HasPrefix(resourceType, "imswl_"):
+		link, ok := b.webLink(item, resource)
+		if !ok {
+			return models.Lesson{}, false
+		}
+		title := strings.TrimSpace(link.Title)
+		if title == "" {
+			title = name
+		}
+		lesson.Blocks = []models.LessonBlock{{Type: models.LessonBlockTypeMarkdown, Text: fmt.Sprintf("[%s](%s)", title, link.URL.Href)}}
+
+	case strings.HasPrefix(resourceType, "imsdt_"):
+		b.warn(item, "discussion topics are not supported")
+		return models.Lesson{}, false
+	case strings.HasPrefix(resourceType, "imsqti_"), strings.Contains(resourceType, "assessment"):
+		b.warn(item, "assessments are not supported")
+		return models.Lesson{}, false
+	case strings.HasPrefix(resourceType, "assignment_"):
+		b.warn(item, "assignments are not supported")
+		return models.Lesson{}, false
+	case strings.HasPrefix(resourceType, "imsbasiclti_"):
+		b.warn(item, "LTI links are not supported")
+		return models.Lesson{}, false
+	default:
+		b.warn(item, fmt.Sprintf("resources of type %s are not supported", resource.Type))
+		return models.Lesson{}, false
+	}
+
+	for i := range lesson.Blocks {
+		lesson.Blocks[i].Position = i + 1
+	}
+	lesson.Content = renderLessonBlocksMarkdown(lesson.Blocks)
+	return lesson, true
+}
+
+// addResourceFiles attaches the files of the resource and its dependencies to the lesson
+// and adds an attachment block for the launch file
+func (b *packageCourseBuilder) addResourceFiles(lesson *models.Lesson, item imsItem, resource imsResource) bool {
+	launch := ""
+	if resource.Href != "" {
+		launch, _ = b.resolve(resource, resource.Href)
+	}
+
+	seen := make(map[string]bool)
+	visited := make(map[string]bool)
+	var launchID uint
+	var addFiles func(resource imsResource)
+	addFiles = func(resource imsResource) {
+		if visited[resource.Identifier] {
+			return
+		}
+		visited[resource.Identifier] = true
+
+		hrefs := make([]string, 0, len(resource.Files)+1)
+		if resource.Href != "" {
+			hrefs = append(hrefs, resource.Href)
+		}
+		for _, file := range resource.Files {
+			hrefs = append(hrefs, file.Href)
+		}
+		for _, href := range hrefs {
+			name, ok := b.resolve(resource, href)
+			if !ok {
+				b.warn(item, fmt.Sprintf("the file %s is outside of the package and is skipped", href))
+				continue
+			}
+			if seen[name] {
+				continue
+			}
+			seen[name] = true
+			if _, ok := b.files[name]; !ok {
+				b.warn(item, fmt.Sprintf("the file %s is missing from the package", name))
+				continue
+			}
+			if len(name) > maxPackageAttachmentNameLength {
+				b.warn(item, fmt.Sprintf("the file name %s is too long and the file is skipped", name))
+				continue
+			}
+
+			attachment := models.Attachment{ID: b.id(), Name: name, URL: name}
+			lesson.Attachments = append(lesson.Attachments, attachment)
+			if name == launch {
+				launchID = attachment.ID
+			}
+		}
+
+		for _, dependency := range resource.Dependencies {
+			if dependencyResource, ok := b.resources[dependency.IdentifierRef]; ok {
+				addFiles(dependencyResource)
+			}
+		}
+	}
+	addFiles(resource)
+
+	if len(lesson.Attachments) == 0 {
+		b.warn(item, "the resource has no files and is skipped")
+		return false
+	}
+	if launchID == 0 {
+		// Without a launch file the first file of the resource is opened
+		launchID = lesson.Attachments[0].ID
+	}
+	lesson.Blocks = []models.LessonBlock{{Type: models.LessonBlockTypeAttachment, Title: lesson.Name, AttachmentID: &launchID}}
+	return true
+}
+
+// webLink reads the XML file of a Common Cartridge web link resource
+func (b *packageCourseBuilder) webLink(item imsItem, resource imsResource) (imsWebLink, bool) {
+	for _, file := range resource.Files {
+		name, ok := b.resolve(resource, file.Href)
+		if !ok {
+			continue
+		}
+		zipFile, ok := b.files[name]
+		if !ok {
+			continue
+		}
+		data, err := readArchiveFile(zipFile, maxCourseArchiveManifestSize)
+		if err != nil {
+			continue
+		}
+		var link imsWebLink
+		if xml.Unmarshal(data, &link) != nil || link.URL.Href == "" {
+			continue
+		}
+		if !strings.HasPrefix(link.URL.Href, "http://") && !strings.HasPrefix(link.URL.Href, "https://") {
+			b.warn(item, "the web link is not an http or https URL and is skipped")
+			return imsWebLink{}, false
+		}
+		return link, true
+	}
+
+	b.warn(item, "the web link has no URL and is skipped")
+	return imsWebLink{}, false
+}
+
+// resolve returns the package file name of a resource href, applying the xml:base of
+// the resources. It fails for hrefs that point outside of the package.
+func (b *packageCourseBuilder) resolve(resource imsResource, href string) (string, bool) {
+	if i := strings.IndexAny(href, "?#"); i >= 0 {
+		href = href[:i]
+	}
+	if unescaped, err := url.PathUnescape(href); err == nil {
+		href = unescaped
+	}
+	if href == "" || strings.Contains(href, "://") || strings.HasPrefix(href, "/") {
+		return "", false
+	}
+
+	name := path.Clean(path.Join(b.manifest.Resources.Base, resource.Base, href))
+	if name == "." || name == ".." || strings.HasPrefix(name, "../") || strings.HasPrefix(name, "/") {
+		return "", false
+	}
+	return name, true
+}
+
+func itemTitle(item imsItem) string {
+	title := strings.TrimSpace(item.Title)
+	if title == "" {
+		return item.Identifier
+	}
+	return title
+}
+
+// limitName shortens a name to the 255 characters of the name columns
+func limitName(name string) string {
+	if len(name) <= 255 {
+		return name
+	}
+	// Do not cut a multi-byte character in half
+	return strings.ToValidUTF8(name[:255], "")
+}
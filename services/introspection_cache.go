@@ -0,0 +1,75 @@
+package services
+
+import (
+	"crypto/sha256"
+	"sync"
+	"time"
+)
+
+// introspectionCacheSweepSize is the number of cached results above which the expired ones
+// are dropped when a result is added
+const introspectionCacheSweepSize = 1024
+
+// introspectionCache remembers the introspection results of tokens for a short time. Results
+// are keyed by the SHA-256 of the token, so the cache never holds a usable token. It is safe
+// for concurrent use.
+type introspectionCache struct {
+	ttl     time.Duration
+	mu      sync.Mutex
+	results map[[sha256.Size]byte]introspectionResult
+}
+
+type introspectionResult struct {
+	active    bool
+	expiresAt time.Time
+}
+
+func newIntrospectionCache(ttl time.Duration) *introspectionCache {
+	return &introspectionCache{
+		ttl:     ttl,
+		results: make(map[[sha256.Size]byte]introspectionResult),
+	}
+}
+
+// get returns the cached result for the token, ok is false when there is none
+func (c *introspectionCache) get(token string) (active, ok bool) {
+	if c.ttl <= 0 {
+		return false, false
+	}
+
+	key := sha256.Sum256([]byte(token))
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	result, ok := c.results[key]
+	if !ok || !time.Now().Before(result.expiresAt) {
+		return false, false
+	}
+	return result.active, true
+}
+
+// put caches the result for the token until the TTL passes or the token expires
+func (c *introspectionCache) put(token string, active bool, tokenExpiresAt time.Time) {
+	if c.ttl <= 0 {
+		return
+	}
+
+	now := time.Now()
+	expiresAt := now.Add(c.ttl)
+	if !tokenExpiresAt.IsZero() && tokenExpiresAt.Before(expiresAt) {
+		expiresAt = tokenExpiresAt
+	}
+
+	key := sha256.Sum256([]byte(token))
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(c.results) >= introspectionCacheSweepSize {
+		for cachedKey, result := range c.results {
+			if !now.Before(result.expiresAt) {
+				delete(c.results, cachedKey)
+			}
+		}
+	}
+	c.results[key] = introspectionResult{active: active, expiresAt: expiresAt}
+}
@@ -0,0 +1,324 @@
+package services
+
+import (
+	"context"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// JWKSCacheOptions tune how long the keys of a JWKS are cached and how often it is fetched
+type JWKSCacheOptions struct {
+	// MinRefetchInterval is the least time between two fetches. It rate limits the fetches
+	// triggered by tokens signed with an unknown kid.
+	MinRefetchInterval time.Duration
+	// DefaultTTL is how long the keys are cached when the response has no cache headers.
+	// The lifetime taken from the headers is bounded by MinTTL and MaxTTL.
+	DefaultTTL time.Duration
+	MinTTL     time.Duration
+	MaxTTL     time.Duration
+	HTTPClient *http.Client
+}
+
+// DefaultJWKSCacheOptions are the options of the JWKS cache of the identity provider
+func DefaultJWKSCacheOptions() JWKSCacheOptions {
+	return JWKSCacheOptions{
+		MinRefetchInterval: 10 * time.Second,
+		DefaultTTL:         time.Hour,
+		MinTTL:             time.Minute,
+		MaxTTL:             24 * time.Hour,
+		HTTPClient:         &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// JWKSCache holds the public keys of a JSON Web Key Set by kid. The set is fetched again when
+// its lifetime from the HTTP cache headers ends, by Run ahead of time, and when a token names
+// a kid that is not cached, which happens after a key rotation. It is safe for concurrent use.
+type JWKSCache struct {
+	url     string
+	options JWKSCacheOptions
+
+	// fetchMu serializes the fetches, concurrent lookups of a missing kid share one fetch
+	fetchMu sync.Mutex
+
+	mu           sync.RWMutex
+	keys         map[string]interface{}
+	etag         string
+	lastModified string
+	fetchedAt    time.Time
+	expiresAt    time.Time
+	lastAttempt  time.Time
+}
+
+func NewJWKSCache(url string, options JWKSCacheOptions) *JWKSCache {
+	if options.HTTPClient == nil {
+		options.HTTPClient = http.DefaultClient
+	}
+
+	return &JWKSCache{
+		url:     url,
+		options: options,
+		keys:    make(map[string]interface{}),
+	}
+}
+
+// Key returns the public key with the given kid, an *rsa.PublicKey or an *ecdsa.PublicKey.
+// Keys that outlived their cache lifetime are still returned while the JWKS cannot be
+// fetched, so an unreachable identity provider does not reject tokens it already signed.
+func (c *JWKSCache) Key(ctx context.Context, kid string) (interface{}, error) {
+	c.mu.RLock()
+	key, ok := c.keys[kid]
+	fresh := time.Now().Before(c.expiresAt)
+	c.mu.RUnlock()
+	if ok && fresh {
+		return key, nil
+	}
+
+	fetchErr := c.refresh(ctx, false)
+
+	c.mu.RLock()
+	key, ok = c.keys[kid]
+	c.mu.RUnlock()
+	if ok {
+		return key, nil
+	}
+	if fetchErr != nil {
+		return nil, fmt.Errorf("failed to get JWKS: %w", fetchErr)
+	}
+	return nil, fmt.Errorf("key with ID %s not found", kid)
+}
+
+// Refresh fetches the JWKS now, regardless of the lifetime of the cached keys
+func (c *JWKSCache) Refresh(ctx context.Context) error {
+	return c.refresh(ctx, true)
+}
+
+// Run keeps the keys fresh until ctx is done. The JWKS is fetched when nine tenths of its
+// lifetime have passed, failed fetches are retried after MinRefetchInterval.
+func (c *JWKSCache) Run(ctx context.Context) {
+	for {
+		timer := time.NewTimer(c.untilRefresh())
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		_ = c.Refresh(ctx)
+	}
+}
+
+// untilRefresh returns the time until Run fetches the JWKS again
+func (c *JWKSCache) untilRefresh() time.Duration {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.fetchedAt.IsZero() || c.lastAttempt.After(c.fetchedAt) {
+		// Nothing fetched yet or the last fetch failed
+		return max(c.options.MinRefetchInterval, time.Second) - time.Since(c.lastAttempt)
+	}
+	lifetime := c.expiresAt.Sub(c.fetchedAt)
+	return time.Until(c.fetchedAt.Add(lifetime * 9 / 10))
+}
+
+// refresh fetches the JWKS unless it was fetched less than MinRefetchInterval ago. Callers
+// that waited for a concurrent fetch use its result.
+func (c *JWKSCache) refresh(ctx context.Context, force bool) error {
+	c.fetchMu.Lock()
+	defer c.fetchMu.Unlock()
+
+	c.mu.RLock()
+	lastAttempt, etag, lastModified := c.lastAttempt, c.etag, c.lastModified
+	c.mu.RUnlock()
+	if !force && time.Since(lastAttempt) < c.options.MinRefetchInterval {
+		return nil
+	}
+
+	c.mu.Lock()
+	c.lastAttempt = time.Now()
+	c.mu.Unlock()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.url, nil)
+	if err != nil {
+		return err
+	}
+	if etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
+	if lastModified != "" {
+		req.Header.Set("If-Modified-Since", lastModified)
+	}
+
+	resp, err := c.options.HTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	var keys map[string]interface{}
+	switch resp.StatusCode {
+	case http.StatusOK:
+		keys, err = parseJWKS(resp)
+		if err != nil {
+			return err
+		}
+	case http.StatusNotModified:
+		// The cached keys are still current, only their lifetime is renewed
+	default:
+		return fmt.Errorf("unexpected JWKS response: %s", resp.Status)
+	}
+
+	now := time.Now()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if keys != nil {
+		c.keys = keys
+		c.etag = resp.Header.Get("ETag")
+		c.lastModified = resp.Header.Get("Last-Modified")
+	}
+	c.fetchedAt = now
+	c.expiresAt = now.Add(c.lifetime(resp.Header, now))
+	return nil
+}
+
+// lifetime returns how long a JWKS response may be cached from its Cache-Control, Age and
+// Expires headers, bounded by MinTTL and MaxTTL
+func (c *JWKSCache) lifetime(header http.Header, now time.Time) time.Duration {
+	lifetime := c.options.DefaultTTL
+	maxAge, hasMaxAge := -1, false
+
+	for _, directive := range strings.Split(header.Get("Cache-Control"), ",") {
+		name, value, _ := strings.Cut(strings.ToLower(strings.TrimSpace(directive)), "=")
+		switch name {
+		case "no-store", "no-cache":
+			return c.options.MinTTL
+		case "max-age":
+			if seconds, err := strconv.Atoi(strings.Trim(value, `"`)); err == nil {
+				maxAge, hasMaxAge = seconds, true
+			}
+		}
+	}
+
+	if hasMaxAge {
+		lifetime = time.Duration(maxAge) * time.Second
+		if age, err := strconv.Atoi(header.Get("Age")); err == nil {
+			lifetime -= time.Duration(age) * time.Second
+		}
+	} else if expires, err := http.ParseTime(header.Get("Expires")); err == nil {
+		date, err := http.ParseTime(header.Get("Date"))
+		if err != nil {
+			date = now
+		}
+		lifetime = expires.Sub(date)
+	}
+
+	return min(max(lifetime, c.options.MinTTL), c.options.MaxTTL)
+}
+
+// jsonWebKey is a key of a JWKS, RFC 7517. RSA keys have a modulus and exponent, EC keys a
+// curve and point.
+type jsonWebKey struct {
+	Kid string `json:"kid"`
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// parseJWKS reads the signature keys of a JWKS response by kid. Encryption keys and keys of
+// unsupported types are left out, they never verify a token.
+func parseJWKS(resp *http.Response) (map[string]interface{}, error) {
+	var jwks struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&jwks); err != nil {
+		return nil, fmt.Errorf("failed to parse JWKS: %w", err)
+	}
+
+	keys := make(map[string]interface{}, len(jwks.Keys))
+	for _, jwk := range jwks.Keys {
+		if jwk.Kid == "" || jwk.Use == "enc" {
+			continue
+		}
+		key, err := jwk.publicKey()
+		if err != nil {
+			continue
+		}
+		keys[jwk.Kid] = key
+	}
+	return keys, nil
+}
+
+func (k jsonWebKey) publicKey() (interface{}, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			return nil, err
+		}
+		exponent := new(big.Int).SetBytes(e)
+		if !exponent.IsInt64() || exponent.Int64() > 1<<31-1 || exponent.Int64() < 2 {
+			return nil, errors.New("invalid RSA exponent")
+		}
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(exponent.Int64())}, nil
+
+	case "EC":
+		var curve elliptic.Curve
+		var ecdhCurve ecdh.Curve
+		switch k.Crv {
+		case "P-256":
+			curve, ecdhCurve = elliptic.P256(), ecdh.P256()
+		case "P-384":
+			curve, ecdhCurve = elliptic.P384(), ecdh.P384()
+		case "P-521":
+			curve, ecdhCurve = elliptic.P521(), ecdh.P521()
+		default:
+			return nil, fmt.Errorf("curve %s not supported", k.Crv)
+		}
+
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := base64.RawURLEncoding.DecodeString(k.Y)
+		if err != nil {
+			return nil, err
+		}
+
+		// The point is checked to lie on the curve through its uncompressed encoding
+		size := (curve.Params().BitSize + 7) / 8
+		if len(x) > size || len(y) > size {
+			return nil, errors.New("invalid EC point")
+		}
+		point := make([]byte, 1+2*size)
+		point[0] = 4
+		copy(point[1+size-len(x):1+size], x)
+		copy(point[1+2*size-len(y):], y)
+		if _, err := ecdhCurve.NewPublicKey(point); err != nil {
+			return nil, fmt.Errorf("invalid EC point: %w", err)
+		}
+
+		return &ecdsa.PublicKey{Curve: curve, X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}, nil
+	}
+
+	return nil, fmt.Errorf("key type %s not supported", k.Kty)
+}
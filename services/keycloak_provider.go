@@ -0,0 +1,270 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"web/config"
+	"web/schemas"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+type KeycloakClaims struct {
+	jwt.RegisteredClaims
+	RealmAccess struct {
+		Roles []string `json:"roles"`
+	} `json:"realm_access"`
+	ResourceAccess map[string]struct {
+		Roles []string `json:"roles"`
+	} `json:"resource_access"`
+	Email             string `json:"email"`
+	EmailVerified     bool   `json:"email_verified"`
+	Name              string `json:"name"`
+	PreferredUsername string `json:"preferred_username"`
+	Sub               string `json:"sub"`
+	// Organization is the slug of the organization of the user, set by a user attribute
+	// mapper. Without it the organization is taken from a group below /organizations.
+	Organization string   `json:"organization"`
+	Groups       []string `json:"groups"`
+}
+
+// KeycloakProvider authenticates users against a Keycloak realm and provisions their
+// accounts through the admin API
+type KeycloakProvider struct {
+	config *config.AppConfig
+	jwks   *JWKSCache
+}
+
+func NewKeycloakProvider(config *config.AppConfig) *KeycloakProvider {
+	jwksURL := fmt.Sprintf("%s/realms/%s/protocol/openid-connect/certs",
+		config.KeycloakURL, config.KeycloakRealm)
+
+	return &KeycloakProvider{
+		config: config,
+		jwks:   NewJWKSCache(jwksURL, DefaultJWKSCacheOptions()),
+	}
+}
+
+func (p *KeycloakProvider) realmURL() string {
+	return fmt.Sprintf("%s/realms/%s", p.config.KeycloakURL, p.config.KeycloakRealm)
+}
+
+func (p *KeycloakProvider) RunKeyRefresh(ctx context.Context) {
+	p.jwks.Run(ctx)
+}
+
+func (p *KeycloakProvider) ValidateToken(tokenString string) (*IdentityClaims, error) {
+	claims := &KeycloakClaims{}
+	if err := parseSignedToken(p.jwks, tokenString, claims); err != nil {
+		return nil, err
+	}
+
+	expectedIssuerPrefix := p.realmURL()
+	if claims.Issuer == "" || !strings.HasPrefix(claims.Issuer, expectedIssuerPrefix) {
+		return nil, fmt.Errorf("invalid token issuer: expected issuer to start with %s", expectedIssuerPrefix)
+	}
+
+	return p.identityClaims(claims), nil
+}
+
+// identityClaims maps Keycloak claims, the realm roles and the roles of the API client are
+// both roles of the user
+func (p *KeycloakProvider) identityClaims(claims *KeycloakClaims) *IdentityClaims {
+	subject := claims.Sub
+	if subject == "" {
+		subject = claims.Subject
+	}
+
+	roles := append([]string{}, claims.RealmAccess.Roles...)
+	if clientRoles, ok := claims.ResourceAccess[p.config.KeycloakClientID]; ok {
+		roles = append(roles, clientRoles.Roles...)
+	}
+
+	identity := &IdentityClaims{
+		Subject:      subject,
+		Username:     claims.PreferredUsername,
+		Email:        claims.Email,
+		Name:         claims.Name,
+		Roles:        roles,
+		Organization: claims.Organization,
+		Groups:       claims.Groups,
+	}
+	if claims.ExpiresAt != nil {
+		identity.ExpiresAt = claims.ExpiresAt.Time
+	}
+	return identity
+}
+
+func (p *KeycloakProvider) IntrospectToken(tokenString string) (bool, error) {
+	return introspectToken(p.realmURL()+"/protocol/openid-connect/token/introspect",
+		p.config.KeycloakClientID, p.config.KeycloakClientSecret, tokenString)
+}
+
+func (p *KeycloakProvider) Login(username, password string) (*schemas.LoginResponse, error) {
+	formData := url.Values{}
+	formData.Set("grant_type", "password")
+	formData.Set("client_id", p.config.KeycloakClientID)
+	formData.Set("client_secret", p.config.KeycloakClientSecret)
+	formData.Set("username", username)
+	formData.Set("password", password)
+
+	return requestTokens(p.realmURL()+"/protocol/openid-connect/token", formData, "authentication failed")
+}
+
+func (p *KeycloakProvider) RefreshToken(refreshToken string) (*schemas.LoginResponse, error) {
+	formData := url.Values{}
+	formData.Set("grant_type", "refresh_token")
+	formData.Set("client_id", p.config.KeycloakClientID)
+	formData.Set("client_secret", p.config.KeycloakClientSecret)
+	formData.Set("refresh_token", refreshToken)
+
+	return requestTokens(p.realmURL()+"/protocol/openid-connect/token", formData, "token refresh failed")
+}
+
+func (p *KeycloakProvider) RevokeToken(refreshToken string) error {
+	return revokeToken(p.realmURL()+"/protocol/openid-connect/revoke",
+		p.config.KeycloakClientID, p.config.KeycloakClientSecret, refreshToken)
+}
+
+// adminToken authenticates as the admin of the master realm
+func (p *KeycloakProvider) adminToken() (string, error) {
+	formData := url.Values{}
+	formData.Set("grant_type", "password")
+	formData.Set("client_id", "admin-cli")
+	formData.Set("username", p.config.KeycloakAdminUsername)
+	formData.Set("password", p.config.KeycloakAdminPassword)
+
+	tokens, err := requestTokens(fmt.Sprintf("%s/realms/master/protocol/openid-connect/token", p.config.KeycloakURL),
+		formData, "admin authentication failed")
+	if err != nil {
+		return "", err
+	}
+	return tokens.AccessToken, nil
+}
+
+// adminRequest sends a request to the admin API of the realm and decodes the response into
+// result when it is not nil. name describes the request in errors.
+func (p *KeycloakProvider) adminRequest(method, adminPath, accessToken string, body interface{}, result interface{}, name string) (*http.Response, error) {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal %s data: %w", name, err)
+		}
+		reader = bytes.NewBuffer(data)
+	}
+
+	adminURL := fmt.Sprintf("%s/admin/realms/%s%s", p.config.KeycloakURL, p.config.KeycloakRealm, adminPath)
+	req, err := http.NewRequest(method, adminURL, reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s request: %w", name, err)
+	}
+
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+
+	resp, err := identityHTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send %s request: %w", name, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		respBody, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("%s failed: %s (status code: %d)", name, string(respBody), resp.StatusCode)
+	}
+
+	if result != nil {
+		if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+			return nil, fmt.Errorf("failed to parse %s response: %w", name, err)
+		}
+	}
+	return resp, nil
+}
+
+// CreateUser creates an enabled user with a permanent password in the realm and returns the
+// Keycloak ID of the user, which is the subject of its tokens
+func (p *KeycloakProvider) CreateUser(username, email, password string) (string, error) {
+	accessToken, err := p.adminToken()
+	if err != nil {
+		return "", err
+	}
+
+	userData := map[string]interface{}{
+		"username": username,
+		"email":    email,
+		"enabled":  true,
+		"credentials": []map[string]interface{}{
+			{
+				"type":      "password",
+				"value":     password,
+				"temporary": false,
+			},
+		},
+	}
+
+	resp, err := p.adminRequest("POST", "/users", accessToken, userData, nil, "user creation")
+	if err != nil {
+		return "", err
+	}
+
+	if location := resp.Header.Get("Location"); location != "" {
+		return path.Base(location), nil
+	}
+
+	var users []struct {
+		ID string `json:"id"`
+	}
+	if _, err := p.adminRequest("GET", "/users?exact=true&username="+url.QueryEscape(username), accessToken, nil, &users, "get user"); err != nil {
+		return "", err
+	}
+	if len(users) == 0 {
+		return "", errors.New("user not found after creation")
+	}
+	return users[0].ID, nil
+}
+
+// AssignRoles maps the realm roles with the given names to the user
+func (p *KeycloakProvider) AssignRoles(subject string, roles []string) error {
+	if len(roles) == 0 {
+		return nil
+	}
+
+	accessToken, err := p.adminToken()
+	if err != nil {
+		return err
+	}
+
+	var availableRoles []map[string]interface{}
+	if _, err := p.adminRequest("GET", "/roles", accessToken, nil, &availableRoles, "get roles"); err != nil {
+		return err
+	}
+
+	var rolesToAssign []map[string]interface{}
+	for _, role := range availableRoles {
+		roleName, _ := role["name"].(string)
+		for _, requestedRole := range roles {
+			if roleName == requestedRole {
+				rolesToAssign = append(rolesToAssign, role)
+				break
+			}
+		}
+	}
+
+	if len(rolesToAssign) == 0 {
+		return nil
+	}
+
+	_, err = p.adminRequest("POST", "/users/"+url.PathEscape(subject)+"/role-mappings/realm", accessToken, rolesToAssign, nil, "assign roles")
+	return err
+}
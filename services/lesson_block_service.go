@@ -0,0 +1,220 @@
+package services
+
+import (
+	"errors"
+	"time"
+	"web/models"
+	"web/repos"
+	"web/schemas"
+)
+
+type LessonBlockServiceInterface interface {
+	GetBlocks(courseID, chapterID, lessonID uint, tenant *schemas.TenantFilter) ([]schemas.LessonBlockResponse, error)
+	CreateBlock(courseID, chapterID, lessonID uint, blockRequest schemas.LessonBlockRequest, authorID uint, tenant *schemas.TenantFilter) (schemas.LessonBlockResponse, error)
+	UpdateBlock(courseID, chapterID, lessonID, blockID uint, blockRequest schemas.LessonBlockRequest, authorID uint, tenant *schemas.TenantFilter) (schemas.LessonBlockResponse, error)
+	DeleteBlock(courseID, chapterID, lessonID, blockID uint, authorID uint, tenant *schemas.TenantFilter) error
+	ReorderBlocks(courseID, chapterID, lessonID uint, reorderRequest schemas.ReorderLessonBlocksRequest, authorID uint, tenant *schemas.TenantFilter) ([]schemas.LessonBlockResponse, error)
+}
+
+var _ LessonBlockServiceInterface = (*LessonBlockService)(nil)
+
+type LessonBlockService struct {
+	lessonRepo     repos.LessonRepositoryInterface
+	attachmentRepo repos.AttachmentRepositoryInterface
+}
+
+func NewLessonBlockService(lessonRepo repos.LessonRepositoryInterface, attachmentRepo repos.AttachmentRepositoryInterface) *LessonBlockService {
+	return &LessonBlockService{
+		lessonRepo:     lessonRepo,
+		attachmentRepo: attachmentRepo,
+	}
+}
+
+func (s *LessonBlockService) GetBlocks(courseID, chapterID, lessonID uint, tenant *schemas.TenantFilter) ([]schemas.LessonBlockResponse, error) {
+	lesson, err := s.lessonRepo.GetByID(courseID, chapterID, lessonID, tenant)
+	if err != nil {
+		return nil, err
+	}
+
+	blocks, err := s.lessonRepo.GetBlocks(lesson.ID)
+	if err != nil {
+		return nil, err
+	}
+	return toLessonBlockResponses(blocks), nil
+}
+
+// CreateBlock inserts a block at blockRequest.Position, or appends it when no position is given
+func (s *LessonBlockService) CreateBlock(courseID, chapterID, lessonID uint, blockRequest schemas.LessonBlockRequest, authorID uint, tenant *schemas.TenantFilter) (schemas.LessonBlockResponse, error) {
+	lesson, blocks, err := s.getLessonWithBlocks(courseID, chapterID, lessonID, tenant)
+	if err != nil {
+		return schemas.LessonBlockResponse{}, err
+	}
+
+	block, err := s.newBlock(lesson, blockRequest, tenant)
+	if err != nil {
+		return schemas.LessonBlockResponse{}, err
+	}
+	if authorID != 0 {
+		block.CreatedBy = &authorID
+	}
+
+	index := len(blocks)
+	if blockRequest.Position < 0 {
+		return schemas.LessonBlockResponse{}, errors.New("invalid block position")
+	}
+	if blockRequest.Position > 0 && blockRequest.Position <= len(blocks) {
+		index = blockRequest.Position - 1
+	}
+
+	updated := make([]models.LessonBlock, 0, len(blocks)+1)
+	updated = append(updated, blocks[:index]...)
+	updated = append(updated, block)
+	updated = append(updated, blocks[index:]...)
+
+	saved, err := s.saveBlocks(lesson, updated, authorID)
+	if err != nil {
+		return schemas.LessonBlockResponse{}, err
+	}
+	return toLessonBlockResponse(saved[index]), nil
+}
+
+// UpdateBlock replaces the content of a block, keeping its position
+func (s *LessonBlockService) UpdateBlock(courseID, chapterID, lessonID, blockID uint, blockRequest schemas.LessonBlockRequest, authorID uint, tenant *schemas.TenantFilter) (schemas.LessonBlockResponse, error) {
+	lesson, blocks, err := s.getLessonWithBlocks(courseID, chapterID, lessonID, tenant)
+	if err != nil {
+		return schemas.LessonBlockResponse{}, err
+	}
+
+	index := findLessonBlock(blocks, blockID)
+	if index < 0 {
+		return schemas.LessonBlockResponse{}, errors.New("block not found")
+	}
+
+	block, err := s.newBlock(lesson, blockRequest, tenant)
+	if err != nil {
+		return schemas.LessonBlockResponse{}, err
+	}
+	block.ID = blocks[index].ID
+	block.CreatedBy = blocks[index].CreatedBy
+	blocks[index] = block
+
+	saved, err := s.saveBlocks(lesson, blocks, authorID)
+	if err != nil {
+		return schemas.LessonBlockResponse{}, err
+	}
+	return toLessonBlockResponse(saved[index]), nil
+}
+
+func (s *LessonBlockService) DeleteBlock(courseID, chapterID, lessonID, blockID uint, authorID uint, tenant *schemas.TenantFilter) error {
+	lesson, blocks, err := s.getLessonWithBlocks(courseID, chapterID, lessonID, tenant)
+	if err != nil {
+		return err
+	}
+
+	index := findLessonBlock(blocks, blockID)
+	if index < 0 {
+		return errors.New("block not found")
+	}
+
+	remaining := make([]models.LessonBlock, 0, len(blocks)-1)
+	remaining = append(remaining, blocks[:index]...)
+	remaining = append(remaining, blocks[index+1:]...)
+
+	_, err = s.saveBlocks(lesson, remaining, authorID)
+	return err
+}
+
+// ReorderBlocks puts the blocks of a lesson in the given order. The request must list
+// every block of the lesson exactly once.
+func (s *LessonBlockService) ReorderBlocks(courseID, chapterID, lessonID uint, reorderRequest schemas.ReorderLessonBlocksRequest, authorID uint, tenant *schemas.TenantFilter) ([]schemas.LessonBlockResponse, error) {
+	lesson, blocks, err := s.getLessonWithBlocks(courseID, chapterID, lessonID, tenant)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(reorderRequest.BlockIDs) != len(blocks) {
+		return nil, errors.New("block order must list every block of the lesson exactly once")
+	}
+	reordered := make([]models.LessonBlock, 0, len(blocks))
+	seen := make(map[uint]bool, len(blocks))
+	for _, blockID := range reorderRequest.BlockIDs {
+		index := findLessonBlock(blocks, blockID)
+		if index < 0 || seen[blockID] {
+			return nil, errors.New("block order must list every block of the lesson exactly once")
+		}
+		seen[blockID] = true
+		reordered = append(reordered, blocks[index])
+	}
+
+	saved, err := s.saveBlocks(lesson, reordered, authorID)
+	if err != nil {
+		return nil, err
+	}
+	return toLessonBlockResponses(saved), nil
+}
+
+func (s *LessonBlockService) getLessonWithBlocks(courseID, chapterID, lessonID uint, tenant *schemas.TenantFilter) (models.Lesson, []models.LessonBlock, error) {
+	lesson, err := s.lessonRepo.GetByID(courseID, chapterID, lessonID, tenant)
+	if err != nil {
+		return models.Lesson{}, nil, err
+	}
+
+	blocks, err := s.lessonRepo.GetBlocks(lesson.ID)
+	if err != nil {
+		return models.Lesson{}, nil, err
+	}
+	return lesson, blocks, nil
+}
+
+// newBlock validates a block request, including that a referenced attachment belongs to the lesson
+func (s *LessonBlockService) newBlock(lesson models.Lesson, blockRequest schemas.LessonBlockRequest, tenant *schemas.TenantFilter) (models.LessonBlock, error) {
+	block, err := newLessonBlock(blockRequest)
+	if err != nil {
+		return models.LessonBlock{}, err
+	}
+
+	if block.AttachmentID != nil {
+		attachment, err := s.attachmentRepo.GetByID(*block.AttachmentID, tenant)
+		if err != nil {
+			return models.LessonBlock{}, err
+		}
+		if attachment.LessonID != lesson.ID {
+			return models.LessonBlock{}, errors.New("attachment does not belong to this lesson")
+		}
+		if block.Title == "" {
+			block.Title = attachment.Name
+		}
+	}
+
+	return block, nil
+}
+
+// saveBlocks stores the blocks in the given order, refreshes the markdown content of the
+// lesson and records a revision. It returns the stored blocks.
+func (s *LessonBlockService) saveBlocks(lesson models.Lesson, blocks []models.LessonBlock, authorID uint) ([]models.LessonBlock, error) {
+	for i := range blocks {
+		blocks[i].Position = i + 1
+	}
+	lesson.Blocks = blocks
+	lesson.Content = renderLessonBlocksMarkdown(blocks)
+	lesson.UpdatedAt = time.Now()
+
+	revision := models.LessonRevision{}
+	if authorID != 0 {
+		revision.CreatedBy = &authorID
+	}
+	revision, err := s.lessonRepo.Update(lesson, revision)
+	if err != nil {
+		return nil, err
+	}
+	return revision.Blocks, nil
+}
+
+func findLessonBlock(blocks []models.LessonBlock, blockID uint) int {
+	for i, block := range blocks {
+		if block.ID == blockID {
+			return i
+		}
+	}
+	return -1
+}
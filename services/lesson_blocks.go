@@ -0,0 +1,165 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"web/models"
+	"web/schemas"
+)
+
+// codeLanguagePattern matches language names such as go, c++, c# or objective-c
+var codeLanguagePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9+#._-]{0,49}$`)
+
+// newLessonBlock validates a block request and returns the block with only the
+// fields its type uses
+func newLessonBlock(blockRequest schemas.LessonBlockRequest) (models.LessonBlock, error) {
+	block := models.LessonBlock{
+		Type:  blockRequest.Type,
+		Title: strings.TrimSpace(blockRequest.Title),
+	}
+	if len(block.Title) > 255 {
+		return models.LessonBlock{}, errors.New("block title must not exceed 255 characters")
+	}
+
+	switch blockRequest.Type {
+	case models.LessonBlockTypeMarkdown:
+		if strings.TrimSpace(blockRequest.Text) == "" {
+			return models.LessonBlock{}, errors.New("markdown block text is required")
+		}
+		block.Text = blockRequest.Text
+
+	case models.LessonBlockTypeCode:
+		if strings.TrimSpace(blockRequest.Text) == "" {
+			return models.LessonBlock{}, errors.New("code block text is required")
+		}
+		language := strings.ToLower(strings.TrimSpace(blockRequest.Language))
+		if language == "" {
+			return models.LessonBlock{}, errors.New("code block language is required")
+		}
+		if !codeLanguagePattern.MatchString(language) {
+			return models.LessonBlock{}, errors.New("invalid code block language")
+		}
+		block.Text = blockRequest.Text
+		block.Language = language
+
+	case models.LessonBlockTypeCallout:
+		if strings.TrimSpace(blockRequest.Text) == "" {
+			return models.LessonBlock{}, errors.New("callout block text is required")
+		}
+		block.Variant = blockRequest.Variant
+		switch block.Variant {
+		case "":
+			block.Variant = models.CalloutVariantInfo
+		case models.CalloutVariantInfo, models.CalloutVariantTip, models.CalloutVariantWarning, models.CalloutVariantDanger:
+		default:
+			return models.LessonBlock{}, errors.New("invalid callout variant")
+		}
+		block.Text = blockRequest.Text
+
+	case models.LessonBlockTypeVideo:
+		videoURL := strings.TrimSpace(blockRequest.URL)
+		if videoURL == "" {
+			return models.LessonBlock{}, errors.New("video block URL is required")
+		}
+		parsed, err := url.Parse(videoURL)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return models.LessonBlock{}, errors.New("video block URL must be an http or https URL")
+		}
+		block.URL = videoURL
+
+	case models.LessonBlockTypeAttachment:
+		if blockRequest.AttachmentID == nil || *blockRequest.AttachmentID == 0 {
+			return models.LessonBlock{}, errors.New("attachment block requires an attachment ID")
+		}
+		attachmentID := *blockRequest.AttachmentID
+		block.AttachmentID = &attachmentID
+
+	default:
+		return models.LessonBlock{}, errors.New("invalid block type")
+	}
+
+	return block, nil
+}
+
+// markdownBlock returns the single markdown block that represents plain lesson content
+func markdownBlock(content string, createdBy *uint) models.LessonBlock {
+	return models.LessonBlock{
+		Position:  1,
+		Type:      models.LessonBlockTypeMarkdown,
+		Text:      content,
+		CreatedBy: createdBy,
+	}
+}
+
+// renderLessonBlocksMarkdown renders the blocks as one markdown document. It is stored
+// in lesson.content so search and clients that only know the content field keep working.
+func renderLessonBlocksMarkdown(blocks []models.LessonBlock) string {
+	parts := make([]string, 0, len(blocks))
+	for _, block := range blocks {
+		switch block.Type {
+		case models.LessonBlockTypeMarkdown:
+			parts = append(parts, block.Text)
+
+		case models.LessonBlockTypeCode:
+			code := "```" + block.Language + "\n" + strings.TrimSuffix(block.Text, "\n") + "\n```"
+			if block.Title != "" {
+				code = "**" + block.Title + "**\n\n" + code
+			}
+			parts = append(parts, code)
+
+		case models.LessonBlockTypeCallout:
+			title := block.Title
+			if title == "" && block.Variant != "" {
+				title = strings.ToUpper(block.Variant[:1]) + block.Variant[1:]
+			}
+			lines := []string{"> **" + title + "**", ">"}
+			for _, line := range strings.Split(strings.TrimSuffix(block.Text, "\n"), "\n") {
+				lines = append(lines, strings.TrimRight("> "+line, " "))
+			}
+			parts = append(parts, strings.Join(lines, "\n"))
+
+		case models.LessonBlockTypeVideo:
+			title := block.Title
+			if title == "" {
+				title = block.URL
+			}
+			parts = append(parts, "[Video: "+title+"]("+block.URL+")")
+
+		case models.LessonBlockTypeAttachment:
+			if block.AttachmentID == nil {
+				continue
+			}
+			title := block.Title
+			if title == "" {
+				title = "Attachment"
+			}
+			parts = append(parts, fmt.Sprintf("[%s](/api/v1/attachments/download/%d)", title, *block.AttachmentID))
+		}
+	}
+	return strings.Join(parts, "\n\n")
+}
+
+func toLessonBlockResponses(blocks []models.LessonBlock) []schemas.LessonBlockResponse {
+	responses := make([]schemas.LessonBlockResponse, 0, len(blocks))
+	for _, block := range blocks {
+		responses = append(responses, toLessonBlockResponse(block))
+	}
+	return responses
+}
+
+func toLessonBlockResponse(block models.LessonBlock) schemas.LessonBlockResponse {
+	return schemas.LessonBlockResponse{
+		ID:           block.ID,
+		Position:     block.Position,
+		Type:         block.Type,
+		Text:         block.Text,
+		Language:     block.Language,
+		Variant:      block.Variant,
+		Title:        block.Title,
+		URL:          block.URL,
+		AttachmentID: block.AttachmentID,
+	}
+}
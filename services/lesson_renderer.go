@@ -0,0 +1,202 @@
+package services
+
+import (
+	"bytes"
+	"container/list"
+	"regexp"
+	"strings"
+	"sync"
+	"unicode/utf8"
+	"web/schemas"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer/html"
+	"github.com/yuin/goldmark/text"
+)
+
+// lessonExcerptLength is the maximum number of characters of a lesson excerpt
+const lessonExcerptLength = 200
+
+// RenderedLesson is lesson content rendered from markdown
+type RenderedLesson struct {
+	HTML    string
+	Excerpt string
+	TOC     []schemas.LessonTOCEntry
+}
+
+// LessonRenderer renders lesson markdown to sanitized HTML, a plain-text excerpt and a
+// table of contents. Results are cached per lesson revision, since a revision never changes.
+type LessonRenderer struct {
+	markdown  goldmark.Markdown
+	policy    *bluemonday.Policy
+	cacheSize int
+
+	mu    sync.Mutex
+	cache map[lessonRenderKey]*list.Element
+	order *list.List
+}
+
+type lessonRenderKey struct {
+	lessonID uint
+	revision int
+}
+
+type lessonRenderEntry struct {
+	key      lessonRenderKey
+	rendered RenderedLesson
+}
+
+// NewLessonRenderer creates a renderer that keeps the cacheSize most recently used results
+func NewLessonRenderer(cacheSize int) *LessonRenderer {
+	// Raw HTML is passed through by goldmark and cleaned up by the sanitizer afterwards
+	markdown := goldmark.New(
+		goldmark.WithExtensions(extension.GFM),
+		goldmark.WithParserOptions(parser.WithAutoHeadingID()),
+		goldmark.WithRendererOptions(html.WithUnsafe()),
+	)
+
+	policy := bluemonday.UGCPolicy()
+	policy.AllowAttrs("class").Matching(regexp.MustCompile(`^language-[\w+#.-]+$`)).OnElements("code")
+
+	return &LessonRenderer{
+		markdown:  markdown,
+		policy:    policy,
+		cacheSize: cacheSize,
+		cache:     make(map[lessonRenderKey]*list.Element),
+		order:     list.New(),
+	}
+}
+
+// Render returns the rendered content of a lesson revision, rendering it on a cache miss.
+// Content without a revision (revision 0) is rendered but not cached.
+func (r *LessonRenderer) Render(lessonID uint, revision int, content string) RenderedLesson {
+	if revision == 0 || r.cacheSize <= 0 {
+		return r.RenderMarkdown(content)
+	}
+	key := lessonRenderKey{lessonID: lessonID, revision: revision}
+
+	r.mu.Lock()
+	if element, ok := r.cache[key]; ok {
+		r.order.MoveToFront(element)
+		r.mu.Unlock()
+		return element.Value.(*lessonRenderEntry).rendered
+	}
+	r.mu.Unlock()
+
+	rendered := r.RenderMarkdown(content)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.cache[key]; !ok {
+		r.cache[key] = r.order.PushFront(&lessonRenderEntry{key: key, rendered: rendered})
+		for r.order.Len() > r.cacheSize {
+			oldest := r.order.Back()
+			r.order.Remove(oldest)
+			delete(r.cache, oldest.Value.(*lessonRenderEntry).key)
+		}
+	}
+	return rendered
+}
+
+// RenderMarkdown renders markdown without caching
+func (r *LessonRenderer) RenderMarkdown(content string) RenderedLesson {
+	source := []byte(content)
+	document := r.markdown.Parser().Parse(text.NewReader(source))
+
+	// Rendering only fails when the writer fails, which a bytes.Buffer never does
+	var buf bytes.Buffer
+	_ = r.markdown.Renderer().Render(&buf, source, document)
+
+	rendered := RenderedLesson{
+		HTML: r.policy.Sanitize(buf.String()),
+		TOC:  []schemas.LessonTOCEntry{},
+	}
+
+	var paragraphs []string
+	_ = ast.Walk(document, func(node ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		switch n := node.(type) {
+		case *ast.Heading:
+			entry := schemas.LessonTOCEntry{
+				Level: n.Level,
+				Title: collapseWhitespace(nodeText(n, source)),
+			}
+			if id, ok := n.AttributeString("id"); ok {
+				if anchor, ok := id.([]byte); ok {
+					entry.Anchor = string(anchor)
+				}
+			}
+			rendered.TOC = append(rendered.TOC, entry)
+			return ast.WalkSkipChildren, nil
+		case *ast.Paragraph, *ast.TextBlock:
+			paragraphs = append(paragraphs, nodeText(n, source))
+			return ast.WalkSkipChildren, nil
+		}
+		return ast.WalkContinue, nil
+	})
+	rendered.Excerpt = truncateText(collapseWhitespace(strings.Join(paragraphs, " ")), lessonExcerptLength)
+
+	return rendered
+}
+
+// nodeText returns the plain text of the inline children of a node. Inline HTML tags
+// are dropped, together with the content of script and style elements.
+func nodeText(node ast.Node, source []byte) string {
+	var builder strings.Builder
+	skipping := false
+	for child := node.FirstChild(); child != nil; child = child.NextSibling() {
+		if raw, ok := child.(*ast.RawHTML); ok {
+			var tag strings.Builder
+			for i := 0; i < raw.Segments.Len(); i++ {
+				segment := raw.Segments.At(i)
+				tag.Write(segment.Value(source))
+			}
+			switch lower := strings.ToLower(tag.String()); {
+			case strings.HasPrefix(lower, "<script"), strings.HasPrefix(lower, "<style"):
+				skipping = true
+			case strings.HasPrefix(lower, "</script"), strings.HasPrefix(lower, "</style"):
+				skipping = false
+			}
+			continue
+		}
+		if skipping {
+			continue
+		}
+
+		switch n := child.(type) {
+		case *ast.Text:
+			builder.Write(n.Segment.Value(source))
+			if n.SoftLineBreak() || n.HardLineBreak() {
+				builder.WriteByte(' ')
+			}
+		case *ast.String:
+			builder.Write(n.Value)
+		default:
+			builder.WriteString(nodeText(child, source))
+		}
+	}
+	return builder.String()
+}
+
+func collapseWhitespace(value string) string {
+	return strings.Join(strings.Fields(value), " ")
+}
+
+// truncateText shortens text to at most limit characters, cutting at a word boundary when possible
+func truncateText(value string, limit int) string {
+	if utf8.RuneCountInString(value) <= limit {
+		return value
+	}
+
+	truncated := string([]rune(value)[:limit-1])
+	if cut := strings.LastIndex(truncated, " "); cut > limit/2 {
+		truncated = truncated[:cut]
+	}
+	return strings.TrimRight(truncated, " ,.;:") + "…"
+}
@@ -0,0 +1,202 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+	"web/models"
+	"web/repos"
+	"web/schemas"
+)
+
+type LessonRevisionServiceInterface interface {
+	GetRevisions(courseID, chapterID, lessonID uint, tenant *schemas.TenantFilter) ([]schemas.LessonRevisionSummary, error)
+	GetRevision(courseID, chapterID, lessonID uint, revision int, tenant *schemas.TenantFilter) (schemas.LessonRevisionResponse, error)
+	DiffRevisions(courseID, chapterID, lessonID uint, fromRevision, toRevision int, tenant *schemas.TenantFilter) (schemas.LessonRevisionDiffResponse, error)
+	RestoreRevision(courseID, chapterID, lessonID uint, revision int, authorID uint, tenant *schemas.TenantFilter) (schemas.LessonRevisionResponse, error)
+}
+
+var _ LessonRevisionServiceInterface = (*LessonRevisionService)(nil)
+
+type LessonRevisionService struct {
+	lessonRepo repos.LessonRepositoryInterface
+}
+
+func NewLessonRevisionService(lessonRepo repos.LessonRepositoryInterface) *LessonRevisionService {
+	return &LessonRevisionService{
+		lessonRepo: lessonRepo,
+	}
+}
+
+func (s *LessonRevisionService) GetRevisions(courseID, chapterID, lessonID uint, tenant *schemas.TenantFilter) ([]schemas.LessonRevisionSummary, error) {
+	lesson, err := s.lessonRepo.GetByID(courseID, chapterID, lessonID, tenant)
+	if err != nil {
+		return nil, err
+	}
+
+	revisions, err := s.lessonRepo.GetRevisions(lesson.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	summaries := make([]schemas.LessonRevisionSummary, 0, len(revisions))
+	for _, revision := range revisions {
+		summaries = append(summaries, schemas.LessonRevisionSummary{
+			Revision:     revision.Revision,
+			Name:         revision.Name,
+			RestoredFrom: revision.RestoredFrom,
+			CreatedBy:    revision.CreatedBy,
+			Author:       revisionAuthor(revision),
+			CreatedAt:    revision.CreatedAt.Format(time.RFC3339),
+		})
+	}
+	return summaries, nil
+}
+
+func (s *LessonRevisionService) GetRevision(courseID, chapterID, lessonID uint, revision int, tenant *schemas.TenantFilter) (schemas.LessonRevisionResponse, error) {
+	lessonRevision, err := s.getRevision(courseID, chapterID, lessonID, revision, tenant)
+	if err != nil {
+		return schemas.LessonRevisionResponse{}, err
+	}
+	return toLessonRevisionResponse(lessonRevision), nil
+}
+
+// DiffRevisions returns the line-level changes of every lesson field that differs
+// between the two revisions
+func (s *LessonRevisionService) DiffRevisions(courseID, chapterID, lessonID uint, fromRevision, toRevision int, tenant *schemas.TenantFilter) (schemas.LessonRevisionDiffResponse, error) {
+	from, err := s.getRevision(courseID, chapterID, lessonID, fromRevision, tenant)
+	if err != nil {
+		return schemas.LessonRevisionDiffResponse{}, err
+	}
+	to, err := s.lessonRepo.GetRevision(from.LessonID, toRevision)
+	if err != nil {
+		return schemas.LessonRevisionDiffResponse{}, err
+	}
+
+	diff := schemas.LessonRevisionDiffResponse{
+		LessonID:     from.LessonID,
+		FromRevision: from.Revision,
+		ToRevision:   to.Revision,
+		Changes:      []schemas.LessonFieldDiff{},
+	}
+	fields := []struct {
+		name     string
+		from, to string
+	}{
+		{"name", from.Name, to.Name},
+		{"description", from.Description, to.Description},
+		{"content", from.Content, to.Content},
+		{"order", strconv.Itoa(from.Order), strconv.Itoa(to.Order)},
+	}
+	for _, field := range fields {
+		if field.from == field.to {
+			continue
+		}
+
+		lines, err := diffLines(field.from, field.to)
+		if err != nil {
+			return schemas.LessonRevisionDiffResponse{}, err
+		}
+		fieldDiff := schemas.LessonFieldDiff{
+			Field: field.name,
+			Lines: lines,
+		}
+		for _, line := range fieldDiff.Lines {
+			switch line.Op {
+			case schemas.DiffOpInsert:
+				fieldDiff.Added++
+			case schemas.DiffOpDelete:
+				fieldDiff.Removed++
+			}
+		}
+		diff.Changes = append(diff.Changes, fieldDiff)
+	}
+
+	return diff, nil
+}
+
+// RestoreRevision rolls the lesson back to an older revision. The rollback is
+// recorded as a new revision, so no history is lost.
+func (s *LessonRevisionService) RestoreRevision(courseID, chapterID, lessonID uint, revision int, authorID uint, tenant *schemas.TenantFilter) (schemas.LessonRevisionResponse, error) {
+	lesson, err := s.lessonRepo.GetByID(courseID, chapterID, lessonID, tenant)
+	if err != nil {
+		return schemas.LessonRevisionResponse{}, err
+	}
+	if revision < 1 {
+		return schemas.LessonRevisionResponse{}, errors.New("invalid revision")
+	}
+
+	restored, err := s.lessonRepo.GetRevision(lesson.ID, revision)
+	if err != nil {
+		return schemas.LessonRevisionResponse{}, err
+	}
+
+	if lesson.Name == restored.Name && lesson.Description == restored.Description &&
+		lesson.Content == restored.Content && lesson.Order == restored.Order {
+		return schemas.LessonRevisionResponse{}, fmt.Errorf("lesson already matches revision %d", revision)
+	}
+
+	lesson.Name = restored.Name
+	lesson.Description = restored.Description
+	lesson.Content = restored.Content
+	lesson.Order = restored.Order
+	lesson.UpdatedAt = time.Now()
+
+	// Bring back the block structure of the revision. Revisions written before lessons
+	// had blocks only know the content, which becomes a single markdown block.
+	lesson.Blocks = make([]models.LessonBlock, 0, len(restored.Blocks))
+	for _, block := range restored.Blocks {
+		block.ID = 0
+		lesson.Blocks = append(lesson.Blocks, block)
+	}
+	if restored.Blocks == nil && restored.Content != "" {
+		lesson.Blocks = append(lesson.Blocks, markdownBlock(restored.Content, restored.CreatedBy))
+	}
+
+	newRevision := models.LessonRevision{RestoredFrom: &revision}
+	if authorID != 0 {
+		newRevision.CreatedBy = &authorID
+	}
+	newRevision, err = s.lessonRepo.Update(lesson, newRevision)
+	if err != nil {
+		return schemas.LessonRevisionResponse{}, err
+	}
+
+	return toLessonRevisionResponse(newRevision), nil
+}
+
+// getRevision loads a revision after checking the lesson belongs to the course and chapter
+func (s *LessonRevisionService) getRevision(courseID, chapterID, lessonID uint, revision int, tenant *schemas.TenantFilter) (models.LessonRevision, error) {
+	lesson, err := s.lessonRepo.GetByID(courseID, chapterID, lessonID, tenant)
+	if err != nil {
+		return models.LessonRevision{}, err
+	}
+	if revision < 1 {
+		return models.LessonRevision{}, errors.New("invalid revision")
+	}
+
+	return s.lessonRepo.GetRevision(lesson.ID, revision)
+}
+
+func toLessonRevisionResponse(revision models.LessonRevision) schemas.LessonRevisionResponse {
+	return schemas.LessonRevisionResponse{
+		LessonID:     revision.LessonID,
+		Revision:     revision.Revision,
+		Name:         revision.Name,
+		Description:  revision.Description,
+		Content:      revision.Content,
+		Order:        revision.Order,
+		RestoredFrom: revision.RestoredFrom,
+		CreatedBy:    revision.CreatedBy,
+		Author:       revisionAuthor(revision),
+		CreatedAt:    revision.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+func revisionAuthor(revision models.LessonRevision) string {
+	if revision.Creator == nil {
+		return ""
+	}
+	return revision.Creator.Username
+}
@@ -9,39 +9,60 @@ import (
 )
 
 type LessonServiceInterface interface {
-	GetLessonByID(courseID, chapterID, id uint) (schemas.LessonResponse, error)
-	GetLessonsByChapterID(courseID, chapterID uint) ([]schemas.LessonResponse, error)
-	CreateLesson(lessonRequest schemas.LessonRequest, courseId, chapterId uint) (uint, error)
-	UpdateLesson(courseID, chapterID, id uint, lessonRequest schemas.LessonRequest) error
-	DeleteLesson(courseID, chapterID, id uint) error
+	GetLessonByID(courseID, chapterID, id uint, format string, tenant *schemas.TenantFilter) (schemas.LessonResponse, error)
+	GetLessonsByChapterID(courseID, chapterID uint, params schemas.ListParams) ([]schemas.LessonResponse, schemas.PaginationMeta, error)
+	CreateLesson(lessonRequest schemas.LessonRequest, courseId, chapterId uint, tenant *schemas.TenantFilter) (uint, error)
+	UpdateLesson(courseID, chapterID, id uint, lessonRequest schemas.LessonRequest, authorID uint, tenant *schemas.TenantFilter) error
+	DeleteLesson(courseID, chapterID, id uint, tenant *schemas.TenantFilter) error
 }
 
+// lessonRenderCacheSize is the number of rendered lesson revisions kept in memory
+const lessonRenderCacheSize = 500
+
 type LessonService struct {
-	repo        *repos.LessonRepository
-	chapterRepo *repos.ChapterRepository
-	courseRepo  *repos.CourseRepository
+	repo        repos.LessonRepositoryInterface
+	chapterRepo repos.ChapterRepositoryInterface
+	courseRepo  repos.CourseRepositoryInterface
+	renderer    *LessonRenderer
 }
 
-func NewLessonService(repo *repos.LessonRepository, chapterRepo *repos.ChapterRepository, courseRepo *repos.CourseRepository) *LessonService {
+func NewLessonService(repo repos.LessonRepositoryInterface, chapterRepo repos.ChapterRepositoryInterface, courseRepo repos.CourseRepositoryInterface) *LessonService {
 	return &LessonService{
 		repo:        repo,
 		chapterRepo: chapterRepo,
 		courseRepo:  courseRepo,
+		renderer:    NewLessonRenderer(lessonRenderCacheSize),
 	}
 }
 
-func (s *LessonService) GetLessonsByChapterID(courseID, chapterID uint) ([]schemas.LessonResponse, error) {
+func (s *LessonService) GetLessonsByChapterID(courseID, chapterID uint, params schemas.ListParams) ([]schemas.LessonResponse, schemas.PaginationMeta, error) {
 	if chapterID == 0 {
-		return nil, errors.New("chapter ID is required")
+		return nil, schemas.PaginationMeta{}, errors.New("chapter ID is required")
 	}
 	if courseID == 0 {
-		return nil, errors.New("course ID is required")
+		return nil, schemas.PaginationMeta{}, errors.New("course ID is required")
+	}
+	lessons, total, err := s.repo.GetByChapterID(courseID, chapterID, params)
+	if err != nil {
+		return nil, schemas.PaginationMeta{}, err
 	}
-	return s.repo.GetByChapterID(courseID, chapterID)
+	return lessons, NewPaginationMeta(params, total), nil
 }
 
-func (s *LessonService) GetLessonByID(courseID, chapterID, id uint) (schemas.LessonResponse, error) {
-	lesson, err := s.repo.GetByID(courseID, chapterID, id)
+// GetLessonByID returns a lesson. With the html format the response also carries the
+// content rendered to sanitized HTML, a plain-text excerpt and the table of contents.
+func (s *LessonService) GetLessonByID(courseID, chapterID, id uint, format string, tenant *schemas.TenantFilter) (schemas.LessonResponse, error) {
+	switch format {
+	case "", schemas.LessonFormatMarkdown, schemas.LessonFormatHTML:
+	default:
+		return schemas.LessonResponse{}, errors.New("invalid format")
+	}
+
+	lesson, err := s.repo.GetByID(courseID, chapterID, id, tenant)
+	if err != nil {
+		return schemas.LessonResponse{}, err
+	}
+	blocks, err := s.repo.GetBlocks(lesson.ID)
 	if err != nil {
 		return schemas.LessonResponse{}, err
 	}
@@ -51,17 +72,29 @@ func (s *LessonService) GetLessonByID(courseID, chapterID, id uint) (schemas.Les
 		Description: lesson.Description,
 		Content:     lesson.Content,
 		Order:       lesson.Order,
+		Blocks:      toLessonBlockResponses(blocks),
 		CreatedAt:   lesson.CreatedAt.Format(time.RFC3339),
 	}
+
+	if format == schemas.LessonFormatHTML {
+		revision, err := s.repo.GetCurrentRevision(lesson.ID)
+		if err != nil {
+			return schemas.LessonResponse{}, err
+		}
+		rendered := s.renderer.Render(lesson.ID, revision, lesson.Content)
+		lessonResponse.HTML = rendered.HTML
+		lessonResponse.Excerpt = rendered.Excerpt
+		lessonResponse.TOC = rendered.TOC
+	}
 	return lessonResponse, nil
 }
 
-func (s *LessonService) CreateLesson(lessonRequest schemas.LessonRequest, courseId, chapterId uint) (uint, error) {
+func (s *LessonService) CreateLesson(lessonRequest schemas.LessonRequest, courseId, chapterId uint, tenant *schemas.TenantFilter) (uint, error) {
 	course, err := s.courseRepo.GetByID(courseId)
 	if err != nil {
 		return 0, err
 	}
-	chapter, err := s.chapterRepo.GetByID(course.ID, chapterId)
+	chapter, err := s.chapterRepo.GetByID(chapterId, course.ID, tenant)
 	if err != nil {
 		return 0, err
 	}
@@ -91,25 +124,63 @@ func (s *LessonService) CreateLesson(lessonRequest schemas.LessonRequest, course
 		return 0, errors.New("chapter ID is required")
 	}
 
+	// Plain content becomes the first block of the lesson
+	lesson.Blocks = []models.LessonBlock{markdownBlock(lesson.Content, lesson.CreatedBy)}
+
 	return s.repo.Create(lesson)
 }
 
-func (s *LessonService) UpdateLesson(courseID, chapterID, id uint, lessonRequest schemas.LessonRequest) error {
-	lesson, err := s.repo.GetByID(courseID, chapterID, id)
+// UpdateLesson changes the lesson and records the new state as a revision by authorID.
+// Empty request fields leave the stored values untouched.
+// Changed content replaces the text of the lesson's markdown block, which only works
+// while the lesson consists of at most one markdown block.
+func (s *LessonService) UpdateLesson(courseID, chapterID, id uint, lessonRequest schemas.LessonRequest, authorID uint, tenant *schemas.TenantFilter) error {
+	lesson, err := s.repo.GetByID(courseID, chapterID, id, tenant)
 	if err != nil {
 		return err
 	}
-	lesson.Name = lessonRequest.Name
-	lesson.Description = lessonRequest.Description
-	lesson.Content = lessonRequest.Content
-	lesson.Order = lessonRequest.Order
+
+	if lessonRequest.Content != "" && lessonRequest.Content != lesson.Content {
+		blocks, err := s.repo.GetBlocks(lesson.ID)
+		if err != nil {
+			return err
+		}
+		switch {
+		case len(blocks) == 0:
+			blocks = []models.LessonBlock{markdownBlock(lessonRequest.Content, lesson.CreatedBy)}
+		case len(blocks) == 1 && blocks[0].Type == models.LessonBlockTypeMarkdown:
+			blocks[0].Text = lessonRequest.Content
+		default:
+			return errors.New("lesson content consists of several blocks, update them through the blocks endpoints")
+		}
+		lesson.Blocks = blocks
+	}
+
+	// Fields left empty keep their current value, like the columns Updates skips
+	if lessonRequest.Name != "" {
+		lesson.Name = lessonRequest.Name
+	}
+	if lessonRequest.Description != "" {
+		lesson.Description = lessonRequest.Description
+	}
+	if lessonRequest.Content != "" {
+		lesson.Content = lessonRequest.Content
+	}
+	if lessonRequest.Order != 0 {
+		lesson.Order = lessonRequest.Order
+	}
 	lesson.UpdatedAt = time.Now()
 
-	return s.repo.Update(lesson)
+	revision := models.LessonRevision{}
+	if authorID != 0 {
+		revision.CreatedBy = &authorID
+	}
+	_, err = s.repo.Update(lesson, revision)
+	return err
 }
 
-func (s *LessonService) DeleteLesson(courseID, chapterID, id uint) error {
-	lesson, err := s.repo.GetByID(courseID, chapterID, id)
+func (s *LessonService) DeleteLesson(courseID, chapterID, id uint, tenant *schemas.TenantFilter) error {
+	lesson, err := s.repo.GetByID(courseID, chapterID, id, tenant)
 	if err != nil {
 		return err
 	}
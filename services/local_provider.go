@@ -0,0 +1,327 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"web/config"
+	"web/models"
+	"web/repos"
+	"web/schemas"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// localClaims are the claims of the access tokens issued by the local provider. SessionID
+// names the refresh token the access token was issued with, revoking it ends the session.
+type localClaims struct {
+	jwt.RegisteredClaims
+	PreferredUsername string   `json:"preferred_username"`
+	Email             string   `json:"email"`
+	Roles             []string `json:"roles"`
+	SessionID         uint     `json:"sid"`
+}
+
+// LocalProvider authenticates users with the bcrypt passwords of the users table. It signs
+// its access tokens with HMAC-SHA256 and keeps a server-side session per refresh token.
+// Refresh tokens are rotated on every refresh and the session expiry slides with them.
+type LocalProvider struct {
+	config           *config.AppConfig
+	userRepo         repos.UserRepositoryInterface
+	refreshTokenRepo repos.RefreshTokenRepositoryInterface
+}
+
+func NewLocalProvider(config *config.AppConfig, userRepo repos.UserRepositoryInterface, refreshTokenRepo repos.RefreshTokenRepositoryInterface) (*LocalProvider, error) {
+	if config.LocalAuthSecret == "" {
+		return nil, errors.New("local auth secret is required")
+	}
+
+	return &LocalProvider{
+		config:           config,
+		userRepo:         userRepo,
+		refreshTokenRepo: refreshTokenRepo,
+	}, nil
+}
+
+// RunKeyRefresh returns immediately, the signing key of the local provider never changes
+func (p *LocalProvider) RunKeyRefresh(ctx context.Context) {}
+
+// ValidateToken also requires the session of the token to be active, so a logout ends the
+// access tokens of the session whatever the token validation strategy
+func (p *LocalProvider) ValidateToken(tokenString string) (*IdentityClaims, error) {
+	claims, err := p.parseToken(tokenString)
+	if err != nil {
+		return nil, err
+	}
+
+	active, err := p.sessionActive(claims.SessionID)
+	if err != nil {
+		return nil, err
+	}
+	if !active {
+		return nil, errors.New("session is no longer active")
+	}
+
+	identity := &IdentityClaims{
+		Subject:  claims.Subject,
+		Username: claims.PreferredUsername,
+		Email:    claims.Email,
+		Roles:    claims.Roles,
+	}
+	if claims.ExpiresAt != nil {
+		identity.ExpiresAt = claims.ExpiresAt.Time
+	}
+	return identity, nil
+}
+
+// parseToken verifies the signature, issuer and expiry of an access token of the provider
+func (p *LocalProvider) parseToken(tokenString string) (*localClaims, error) {
+	claims := &localClaims{}
+	_, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+		return []byte(p.config.LocalAuthSecret), nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithIssuer(p.config.LocalAuthIssuer), jwt.WithExpirationRequired())
+	if err != nil {
+		return nil, err
+	}
+
+	if claims.Subject == "" {
+		return nil, errors.New("token has no subject")
+	}
+	return claims, nil
+}
+
+// IntrospectToken reports a valid token active while its session is neither revoked nor
+// expired
+func (p *LocalProvider) IntrospectToken(tokenString string) (bool, error) {
+	if tokenString == "" {
+		return false, errors.New("token is required")
+	}
+
+	claims, err := p.parseToken(tokenString)
+	if err != nil {
+		return false, nil
+	}
+
+	return p.sessionActive(claims.SessionID)
+}
+
+// sessionActive reports whether the session of a refresh token is neither revoked nor expired
+func (p *LocalProvider) sessionActive(sessionID uint) (bool, error) {
+	session, err := p.refreshTokenRepo.GetByID(sessionID)
+	if err != nil {
+		if err.Error() == "refresh token not found" {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return session.RevokedAt == nil && time.Now().Before(session.ExpiresAt), nil
+}
+
+func (p *LocalProvider) Login(username, password string) (*schemas.LoginResponse, error) {
+	user, err := p.userRepo.GetByUsername(username)
+	if err != nil {
+		if err.Error() == "user not found" {
+			return nil, errors.New("invalid username or password")
+		}
+		return nil, err
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)); err != nil {
+		return nil, errors.New("invalid username or password")
+	}
+
+	// Users registered before the local provider was enabled may have no subject yet
+	if user.Sub == "" {
+		user.Sub = uuid.NewString()
+		if err := p.userRepo.UpdateSub(user.ID, user.Sub); err != nil {
+			return nil, err
+		}
+	}
+
+	refreshToken, tokenHash, err := newRefreshToken()
+	if err != nil {
+		return nil, err
+	}
+
+	session, err := p.refreshTokenRepo.Create(models.RefreshToken{
+		UserID:    user.ID,
+		TokenHash: tokenHash,
+		ExpiresAt: time.Now().Add(p.config.LocalAuthRefreshTokenTTL),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return p.issueTokens(user, session.ID, refreshToken)
+}
+
+// RefreshToken rotates the refresh token of the session and issues an access token with the
+// current roles of the user
+func (p *LocalProvider) RefreshToken(refreshToken string) (*schemas.LoginResponse, error) {
+	tokenHash := hashRefreshToken(refreshToken)
+	session, err := p.refreshTokenRepo.GetByHash(tokenHash)
+	if err != nil {
+		if err.Error() == "refresh token not found" {
+			return nil, errors.New("invalid refresh token")
+		}
+		return nil, err
+	}
+	if session.RevokedAt != nil || !time.Now().Before(session.ExpiresAt) {
+		return nil, errors.New("invalid refresh token")
+	}
+
+	user, err := p.userRepo.GetByID(session.UserID)
+	if err != nil {
+		if err.Error() == "user not found" {
+			return nil, errors.New("invalid refresh token")
+		}
+		return nil, err
+	}
+
+	newToken, newTokenHash, err := newRefreshToken()
+	if err != nil {
+		return nil, err
+	}
+
+	err = p.refreshTokenRepo.Rotate(session.ID, tokenHash, newTokenHash, time.Now().Add(p.config.LocalAuthRefreshTokenTTL))
+	if err != nil {
+		if err.Error() == "refresh token not found" {
+			return nil, errors.New("invalid refresh token")
+		}
+		return nil, err
+	}
+
+	return p.issueTokens(user, session.ID, newToken)
+}
+
+// RevokeToken ends the session of a refresh token. Unknown tokens are ignored, as there is
+// no session left to end.
+func (p *LocalProvider) RevokeToken(refreshToken string) error {
+	session, err := p.refreshTokenRepo.GetByHash(hashRefreshToken(refreshToken))
+	if err != nil {
+		if err.Error() == "refresh token not found" {
+			return nil
+		}
+		return err
+	}
+
+	return p.refreshTokenRepo.Revoke(session.ID)
+}
+
+// CreateUser stores a user with a bcrypt hash of the password and returns its new subject
+func (p *LocalProvider) CreateUser(username, email, password string) (string, error) {
+	if _, err := p.userRepo.GetByUsername(username); err == nil {
+		return "", errors.New("username already exists")
+	} else if err.Error() != "user not found" {
+		return "", err
+	}
+
+	if _, err := p.userRepo.GetByEmail(email); err == nil {
+		return "", errors.New("email already exists")
+	} else if err.Error() != "user not found" {
+		return "", err
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", errors.New("failed to hash password")
+	}
+
+	user, err := p.userRepo.Create(models.User{
+		Username: username,
+		Email:    email,
+		Password: string(hashedPassword),
+		Sub:      uuid.NewString(),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return user.Sub, nil
+}
+
+// AssignRoles adds the roles to the roles of the user, they are in its next access token
+func (p *LocalProvider) AssignRoles(subject string, roles []string) error {
+	if len(roles) == 0 {
+		return nil
+	}
+
+	user, err := p.userRepo.GetBySub(subject)
+	if err != nil {
+		return err
+	}
+
+	userRoles := splitRoles(user.Roles)
+	for _, role := range roles {
+		if !containsString(userRoles, role) {
+			userRoles = append(userRoles, role)
+		}
+	}
+
+	return p.userRepo.UpdateRoles(user.ID, strings.Join(userRoles, ","))
+}
+
+func (p *LocalProvider) issueTokens(user models.User, sessionID uint, refreshToken string) (*schemas.LoginResponse, error) {
+	now := time.Now()
+	claims := localClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    p.config.LocalAuthIssuer,
+			Subject:   user.Sub,
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(p.config.LocalAuthAccessTokenTTL)),
+			ID:        uuid.NewString(),
+		},
+		PreferredUsername: user.Username,
+		Email:             user.Email,
+		Roles:             splitRoles(user.Roles),
+		SessionID:         sessionID,
+	}
+
+	accessToken, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(p.config.LocalAuthSecret))
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign access token: %w", err)
+	}
+
+	return &schemas.LoginResponse{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    int(p.config.LocalAuthAccessTokenTTL.Seconds()),
+	}, nil
+}
+
+// newRefreshToken returns a random refresh token and the hash it is stored under
+func newRefreshToken() (string, string, error) {
+	data := make([]byte, 32)
+	if _, err := rand.Read(data); err != nil {
+		return "", "", errors.New("failed to generate refresh token")
+	}
+
+	token := base64.RawURLEncoding.EncodeToString(data)
+	return token, hashRefreshToken(token), nil
+}
+
+func hashRefreshToken(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(hash[:])
+}
+
+// splitRoles returns the roles of the comma-separated roles column of a user
+func splitRoles(roles string) []string {
+	var result []string
+	for _, role := range strings.Split(roles, ",") {
+		if role = strings.TrimSpace(role); role != "" {
+			result = append(result, role)
+		}
+	}
+	return result
+}
@@ -0,0 +1,241 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"web/schemas"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// OIDCProvider authenticates users against any OpenID Connect provider. The endpoints are
+// read from the discovery document of the issuer. Users are managed in the provider itself,
+// so it does not support provisioning or role assignment.
+type OIDCProvider struct {
+	issuer       string
+	clientID     string
+	clientSecret string
+	rolesClaim   []string
+	discovery    oidcDiscovery
+	jwks         *JWKSCache
+}
+
+// oidcDiscovery is the part of the OpenID provider metadata used by the API
+type oidcDiscovery struct {
+	Issuer                string `json:"issuer"`
+	JWKSURI               string `json:"jwks_uri"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	IntrospectionEndpoint string `json:"introspection_endpoint"`
+	RevocationEndpoint    string `json:"revocation_endpoint"`
+}
+
+// NewOIDCProvider reads the discovery document of the issuer. rolesClaim is the dotted path
+// of the roles of the user in the access token.
+func NewOIDCProvider(issuer, clientID, clientSecret, rolesClaim string) (*OIDCProvider, error) {
+	if issuer == "" {
+		return nil, errors.New("issuer is required")
+	}
+	if clientID == "" {
+		return nil, errors.New("client ID is required")
+	}
+
+	discovery, err := discoverOIDC(issuer)
+	if err != nil {
+		return nil, err
+	}
+
+	if rolesClaim == "" {
+		rolesClaim = "roles"
+	}
+
+	return &OIDCProvider{
+		issuer:       issuer,
+		clientID:     clientID,
+		clientSecret: clientSecret,
+		rolesClaim:   strings.Split(rolesClaim, "."),
+		discovery:    discovery,
+		jwks:         NewJWKSCache(discovery.JWKSURI, DefaultJWKSCacheOptions()),
+	}, nil
+}
+
+// discoverOIDC fetches the discovery document of the issuer, which must name the issuer
+// itself as required by OpenID Connect Discovery
+func discoverOIDC(issuer string) (oidcDiscovery, error) {
+	discoveryURL := strings.TrimSuffix(issuer, "/") + "/.well-known/openid-configuration"
+
+	resp, err := identityHTTPClient.Get(discoveryURL)
+	if err != nil {
+		return oidcDiscovery{}, fmt.Errorf("failed to get OIDC discovery document: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return oidcDiscovery{}, fmt.Errorf("OIDC discovery failed: %s (status code: %d)", string(body), resp.StatusCode)
+	}
+
+	var discovery oidcDiscovery
+	if err := json.NewDecoder(resp.Body).Decode(&discovery); err != nil {
+		return oidcDiscovery{}, fmt.Errorf("failed to parse OIDC discovery document: %w", err)
+	}
+
+	if discovery.Issuer != issuer {
+		return oidcDiscovery{}, fmt.Errorf("OIDC discovery document names issuer %s, expected %s", discovery.Issuer, issuer)
+	}
+	if discovery.JWKSURI == "" {
+		return oidcDiscovery{}, errors.New("OIDC discovery document has no jwks_uri")
+	}
+
+	return discovery, nil
+}
+
+func (p *OIDCProvider) RunKeyRefresh(ctx context.Context) {
+	p.jwks.Run(ctx)
+}
+
+// ValidateToken accepts tokens of the issuer that are meant for the client, either as an
+// audience or as the authorized party
+func (p *OIDCProvider) ValidateToken(tokenString string) (*IdentityClaims, error) {
+	claims := jwt.MapClaims{}
+	if err := parseSignedToken(p.jwks, tokenString, claims); err != nil {
+		return nil, err
+	}
+
+	issuer, _ := claims.GetIssuer()
+	if issuer != p.issuer {
+		return nil, fmt.Errorf("invalid token issuer: expected %s", p.issuer)
+	}
+
+	audience, _ := claims.GetAudience()
+	authorizedParty, _ := claims["azp"].(string)
+	if !containsString(audience, p.clientID) && authorizedParty != p.clientID {
+		return nil, fmt.Errorf("invalid token audience: expected %s", p.clientID)
+	}
+
+	subject, _ := claims.GetSubject()
+	if subject == "" {
+		return nil, errors.New("token has no subject")
+	}
+
+	identity := &IdentityClaims{
+		Subject:      subject,
+		Username:     stringClaim(claims, "preferred_username"),
+		Email:        stringClaim(claims, "email"),
+		Name:         stringClaim(claims, "name"),
+		Roles:        stringsClaim(claims, p.rolesClaim),
+		Organization: stringClaim(claims, "organization"),
+		Groups:       stringsClaim(claims, []string{"groups"}),
+	}
+	if identity.Username == "" {
+		identity.Username = identity.Email
+	}
+	if expiresAt, _ := claims.GetExpirationTime(); expiresAt != nil {
+		identity.ExpiresAt = expiresAt.Time
+	}
+	return identity, nil
+}
+
+// SupportsIntrospection reports whether the discovery document names an introspection
+// endpoint
+func (p *OIDCProvider) SupportsIntrospection() bool {
+	return p.discovery.IntrospectionEndpoint != ""
+}
+
+func (p *OIDCProvider) IntrospectToken(tokenString string) (bool, error) {
+	if !p.SupportsIntrospection() {
+		return false, errors.New("token introspection is not supported by the identity provider")
+	}
+	return introspectToken(p.discovery.IntrospectionEndpoint, p.clientID, p.clientSecret, tokenString)
+}
+
+func (p *OIDCProvider) Login(username, password string) (*schemas.LoginResponse, error) {
+	if p.discovery.TokenEndpoint == "" {
+		return nil, errors.New("login is not supported by the identity provider")
+	}
+
+	formData := url.Values{}
+	formData.Set("grant_type", "password")
+	formData.Set("client_id", p.clientID)
+	formData.Set("client_secret", p.clientSecret)
+	formData.Set("username", username)
+	formData.Set("password", password)
+	formData.Set("scope", "openid")
+
+	return requestTokens(p.discovery.TokenEndpoint, formData, "authentication failed")
+}
+
+func (p *OIDCProvider) RefreshToken(refreshToken string) (*schemas.LoginResponse, error) {
+	if p.discovery.TokenEndpoint == "" {
+		return nil, errors.New("token refresh is not supported by the identity provider")
+	}
+
+	formData := url.Values{}
+	formData.Set("grant_type", "refresh_token")
+	formData.Set("client_id", p.clientID)
+	formData.Set("client_secret", p.clientSecret)
+	formData.Set("refresh_token", refreshToken)
+
+	return requestTokens(p.discovery.TokenEndpoint, formData, "token refresh failed")
+}
+
+func (p *OIDCProvider) RevokeToken(refreshToken string) error {
+	if p.discovery.RevocationEndpoint == "" {
+		return errors.New("token revocation is not supported by the identity provider")
+	}
+	return revokeToken(p.discovery.RevocationEndpoint, p.clientID, p.clientSecret, refreshToken)
+}
+
+func (p *OIDCProvider) CreateUser(username, email, password string) (string, error) {
+	return "", errors.New("user provisioning is not supported by the identity provider")
+}
+
+func (p *OIDCProvider) AssignRoles(subject string, roles []string) error {
+	return errors.New("role assignment is not supported by the identity provider")
+}
+
+func stringClaim(claims jwt.MapClaims, name string) string {
+	value, _ := claims[name].(string)
+	return value
+}
+
+// stringsClaim returns the strings at the dotted path of a claim, a single string is
+// returned as a list of one
+func stringsClaim(claims jwt.MapClaims, claimPath []string) []string {
+	var value interface{} = map[string]interface{}(claims)
+	for _, name := range claimPath {
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		value = object[name]
+	}
+
+	switch value := value.(type) {
+	case string:
+		return []string{value}
+	case []interface{}:
+		values := make([]string, 0, len(value))
+		for _, item := range value {
+			if s, ok := item.(string); ok {
+				values = append(values, s)
+			}
+		}
+		return values
+	}
+	return nil
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
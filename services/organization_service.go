@@ -0,0 +1,147 @@
+package services
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"web/models"
+	"web/repos"
+	"web/schemas"
+)
+
+// organizationSlugPattern matches the slugs of organizations, they appear in tokens and
+// group paths of the identity provider
+var organizationSlugPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+type OrganizationServiceInterface interface {
+	GetAll() ([]models.Organization, error)
+	GetByID(id uint) (models.Organization, error)
+	Create(createRequest schemas.CreateOrganizationRequest) (models.Organization, error)
+	Update(id uint, updateRequest schemas.UpdateOrganizationRequest) (models.Organization, error)
+	Delete(id uint) error
+	AssignUser(organizationID, userID uint) error
+	RemoveUser(organizationID, userID uint) error
+}
+
+var _ OrganizationServiceInterface = (*OrganizationService)(nil)
+
+// OrganizationService manages the schools hosted on the deployment and the users that
+// belong to them
+type OrganizationService struct {
+	repo     repos.OrganizationRepositoryInterface
+	userRepo repos.UserRepositoryInterface
+}
+
+func NewOrganizationService(repo repos.OrganizationRepositoryInterface, userRepo repos.UserRepositoryInterface) *OrganizationService {
+	return &OrganizationService{
+		repo:     repo,
+		userRepo: userRepo,
+	}
+}
+
+func (s *OrganizationService) GetAll() ([]models.Organization, error) {
+	return s.repo.GetAll()
+}
+
+func (s *OrganizationService) GetByID(id uint) (models.Organization, error) {
+	return s.repo.GetByID(id)
+}
+
+func (s *OrganizationService) Create(createRequest schemas.CreateOrganizationRequest) (models.Organization, error) {
+	name, slug, err := validateOrganization(createRequest.Name, createRequest.Slug)
+	if err != nil {
+		return models.Organization{}, err
+	}
+
+	exists, err := s.repo.ExistsBySlug(slug)
+	if err != nil {
+		return models.Organization{}, err
+	}
+	if exists {
+		return models.Organization{}, errors.New("organization slug already exists")
+	}
+
+	return s.repo.Create(models.Organization{
+		Name: name,
+		Slug: slug,
+	})
+}
+
+func (s *OrganizationService) Update(id uint, updateRequest schemas.UpdateOrganizationRequest) (models.Organization, error) {
+	name, slug, err := validateOrganization(updateRequest.Name, updateRequest.Slug)
+	if err != nil {
+		return models.Organization{}, err
+	}
+
+	organization, err := s.repo.GetByID(id)
+	if err != nil {
+		return models.Organization{}, err
+	}
+
+	if slug != organization.Slug {
+		exists, err := s.repo.ExistsBySlug(slug)
+		if err != nil {
+			return models.Organization{}, err
+		}
+		if exists {
+			return models.Organization{}, errors.New("organization slug already exists")
+		}
+	}
+
+	organization.Name = name
+	organization.Slug = slug
+	return s.repo.Update(organization)
+}
+
+// Delete deletes an organization that no user and no course belongs to anymore
+func (s *OrganizationService) Delete(id uint) error {
+	if _, err := s.repo.GetByID(id); err != nil {
+		return err
+	}
+
+	empty, err := s.repo.IsEmpty(id)
+	if err != nil {
+		return err
+	}
+	if !empty {
+		return errors.New("organization still has users or courses")
+	}
+
+	return s.repo.Delete(id)
+}
+
+// AssignUser moves a user to an organization. Users whose token carries an organization
+// claim are moved back to the organization of the claim when they log in.
+func (s *OrganizationService) AssignUser(organizationID, userID uint) error {
+	if _, err := s.repo.GetByID(organizationID); err != nil {
+		return err
+	}
+
+	return s.userRepo.UpdateOrganization(userID, &organizationID)
+}
+
+// RemoveUser moves a user of an organization back to the default tenant
+func (s *OrganizationService) RemoveUser(organizationID, userID uint) error {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return err
+	}
+	if user.OrganizationID == nil || *user.OrganizationID != organizationID {
+		return errors.New("user does not belong to this organization")
+	}
+
+	return s.userRepo.UpdateOrganization(userID, nil)
+}
+
+// validateOrganization trims the name and slug of an organization and checks them
+func validateOrganization(name, slug string) (string, string, error) {
+	name = strings.TrimSpace(name)
+	slug = strings.TrimSpace(slug)
+	if name == "" {
+		return "", "", errors.New("organization name is required")
+	}
+	if len(slug) > 100 || !organizationSlugPattern.MatchString(slug) {
+		return "", "", errors.New("invalid organization slug")
+	}
+	return name, slug, nil
+}
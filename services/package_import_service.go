@@ -0,0 +1,201 @@
+package services
+
+import (
+	"archive/zip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+	"web/models"
+	"web/repos"
+)
+
+// packageImportWorkers limits how many package imports run at the same time, further
+// jobs wait as pending
+const packageImportWorkers = 2
+
+type PackageImportServiceInterface interface {
+	StartImport(upload io.Reader, fileName string, actor Actor, auditEntry models.AuditLog) (models.ImportJob, error)
+	GetJob(jobID uint, actor Actor) (models.ImportJob, error)
+	FailInterruptedJobs() (int64, error)
+}
+
+var _ PackageImportServiceInterface = (*PackageImportService)(nil)
+
+// PackageImportService imports SCORM 1.2 and IMS Common Cartridge packages as new courses
+// in background jobs
+type PackageImportService struct {
+	jobRepo    repos.ImportJobRepositoryInterface
+	courseRepo repos.CourseRepositoryInterface
+	audit      AuditServiceInterface
+	storage    CourseArchiveStorage
+	workers    chan struct{}
+}
+
+func NewPackageImportService(jobRepo repos.ImportJobRepositoryInterface, courseRepo repos.CourseRepositoryInterface, audit AuditServiceInterface, storage CourseArchiveStorage) *PackageImportService {
+	return &PackageImportService{
+		jobRepo:    jobRepo,
+		courseRepo: courseRepo,
+		audit:      audit,
+		storage:    storage,
+		workers:    make(chan struct{}, packageImportWorkers),
+	}
+}
+
+// StartImport stores the uploaded package in a temporary file and starts a job importing
+// it. The returned job is pending, its progress is read with GetJob. auditEntry describes
+// the request, it is recorded with the created course once the job completes.
+func (s *PackageImportService) StartImport(upload io.Reader, fileName string, actor Actor, auditEntry models.AuditLog) (models.ImportJob, error) {
+	if !actor.IsStaff() {
+		return models.ImportJob{}, errors.New("insufficient permissions to import packages")
+	}
+
+	fileName = filepath.Base(fileName)
+	if !strings.EqualFold(filepath.Ext(fileName), ".zip") {
+		return models.ImportJob{}, errors.New("package must be a zip file")
+	}
+
+	packageFile, err := os.CreateTemp("", "package-import-*.zip")
+	if err != nil {
+		return models.ImportJob{}, fmt.Errorf("failed to store package: %w", err)
+	}
+	_, err = io.Copy(packageFile, upload)
+	if closeErr := packageFile.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(packageFile.Name())
+		return models.ImportJob{}, fmt.Errorf("failed to store package: %w", err)
+	}
+
+	job, err := s.jobRepo.Create(models.ImportJob{
+		Status:         models.ImportJobStatusPending,
+		FileName:       fileName,
+		Warnings:       []models.ImportJobWarning{},
+		CreatedBy:      &actor.UserID,
+		OrganizationID: actor.OrganizationID,
+	})
+	if err != nil {
+		os.Remove(packageFile.Name())
+		return models.ImportJob{}, err
+	}
+
+	go s.run(job, packageFile.Name(), auditEntry)
+
+	return job, nil
+}
+
+// GetJob returns an import job to its creator and the admins of its organization
+func (s *PackageImportService) GetJob(jobID uint, actor Actor) (models.ImportJob, error) {
+	job, err := s.jobRepo.GetByID(jobID)
+	if err != nil {
+		return models.ImportJob{}, err
+	}
+
+	if !actor.Owns(job.CreatedBy) && !(actor.IsAdmin && actor.InOrganization(job.OrganizationID)) {
+		return models.ImportJob{}, errors.New("insufficient permissions to view this import job")
+	}
+
+	return job, nil
+}
+
+// FailInterruptedJobs marks the jobs that were pending or running when the server stopped
+// as failed. It is called on startup.
+func (s *PackageImportService) FailInterruptedJobs() (int64, error) {
+	return s.jobRepo.FailUnfinished("the import was interrupted by a server restart, please upload the package again")
+}
+
+// run imports the package of a job and records the outcome on the job
+func (s *PackageImportService) run(job models.ImportJob, packagePath string, auditEntry models.AuditLog) {
+	defer os.Remove(packagePath)
+
+	s.workers <- struct{}{}
+	defer func() { <-s.workers }()
+
+	startedAt := time.Now()
+	job.Status = models.ImportJobStatusRunning
+	job.StartedAt = &startedAt
+	_, _ = s.jobRepo.Update(job)
+
+	err := s.importPackage(&job, packagePath)
+
+	finishedAt := time.Now()
+	job.FinishedAt = &finishedAt
+	if err != nil {
+		job.Status = models.ImportJobStatusFailed
+		job.Error = err.Error()
+	} else {
+		job.Status = models.ImportJobStatusCompleted
+		s.recordImport(job, auditEntry)
+	}
+	_, _ = s.jobRepo.Update(job)
+}
+
+// recordImport records the course created by a completed job in the audit log, like the
+// archive import route does for the courses it creates. The course already exists, a
+// failure to record it must not fail the job.
+func (s *PackageImportService) recordImport(job models.ImportJob, entry models.AuditLog) {
+	if job.CourseID == nil {
+		return
+	}
+
+	entry.ResourceID = job.CourseID
+	entry.After, _ = s.audit.Snapshot(entry.ResourceType, *job.CourseID)
+	_ = s.audit.Record(entry)
+}
+
+// importPackage maps the package to a course tree and creates it in one transaction. The
+// package files referenced by the tree are uploaded as lesson attachments.
+func (s *PackageImportService) importPackage(job *models.ImportJob, packagePath string) (err error) {
+	// A malformed package must fail the job, not the server
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("failed to import package: %v", r)
+		}
+	}()
+
+	reader, err := zip.OpenReader(packagePath)
+	if err != nil {
+		return errors.New("invalid package, expected a zip archive")
+	}
+	defer reader.Close()
+
+	files := make(map[string]*zip.File, len(reader.File))
+	for _, file := range reader.File {
+		files[file.Name] = file
+	}
+
+	manifest, kind, err := parseIMSManifest(files)
+	if err != nil {
+		return err
+	}
+	job.Kind = kind
+
+	builder := newPackageCourseBuilder(manifest, kind, files)
+	source, err := builder.Build(strings.TrimSuffix(job.FileName, path.Ext(job.FileName)))
+	job.Warnings = builder.warnings
+	if err != nil {
+		return err
+	}
+
+	course, _, err := s.courseRepo.Import(source, repos.CloneOptions{
+		Name:           source.Name,
+		CreatedBy:      *job.CreatedBy,
+		OrganizationID: job.OrganizationID,
+		RenderContent:  renderLessonBlocksMarkdown,
+		CopyObjects: func(copies []repos.ObjectCopy) error {
+			return storeArchiveFiles(s.storage, files, copies)
+		},
+		KeepAttachmentPaths: true,
+	})
+	if err != nil {
+		return err
+	}
+
+	job.CourseID = &course.ID
+	return nil
+}
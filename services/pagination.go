@@ -0,0 +1,31 @@
+package services
+
+import "web/schemas"
+
+// NewPaginationMeta builds the pagination metadata of a listing response
+func NewPaginationMeta(params schemas.ListParams, total int64) schemas.PaginationMeta {
+	meta := schemas.PaginationMeta{
+		Page:  params.Page,
+		Limit: params.Limit,
+		Total: total,
+	}
+	if meta.Page < 1 {
+		meta.Page = 1
+	}
+
+	if params.SortBy != "" {
+		meta.Sort = params.SortBy
+		if params.SortDesc {
+			meta.Sort = "-" + meta.Sort
+		}
+	}
+
+	if params.Limit > 0 {
+		meta.TotalPages = int((total + int64(params.Limit) - 1) / int64(params.Limit))
+		meta.HasNext = int64(params.Offset()+params.Limit) < total
+	} else if total > 0 {
+		meta.TotalPages = 1
+	}
+
+	return meta
+}
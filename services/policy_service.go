@@ -0,0 +1,283 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"web/models"
+	"web/repos"
+)
+
+// Policy actions
+const (
+	ActionRead            = "read"
+	ActionCreate          = "create"
+	ActionUpdate          = "update"
+	ActionDelete          = "delete"
+	ActionChangeStatus    = "change_status"
+	ActionReadHistory     = "read_history"
+	ActionReadUnpublished = "read_unpublished"
+	ActionReadAnswers     = "read_answers"
+	ActionReview          = "review"
+	ActionCopy            = "copy"
+	ActionImport          = "import"
+	ActionEnroll          = "enroll"
+	ActionRestore         = "restore"
+)
+
+// Policy resources
+const (
+	ResourceCourse      = "course"
+	ResourceChapter     = "chapter"
+	ResourceLesson      = "lesson"
+	ResourceAttachment  = "attachment"
+	ResourceQuiz        = "quiz"
+	ResourceCertificate = "certificate"
+	ResourceUser        = "user"
+	// ResourceCourseMember is the membership of a user in a course
+	ResourceCourseMember = "course_member"
+	ResourceOrganization = "organization"
+	ResourceAuditLog     = "audit_log"
+)
+
+// PolicySubject is a group of actors a policy rule grants an action to
+type PolicySubject string
+
+const (
+	// SubjectAnyone is every authenticated user
+	SubjectAnyone PolicySubject = "anyone"
+	// SubjectAdmin is every user with the admin role. Admins of an organization only reach
+	// the courses of their organization.
+	SubjectAdmin PolicySubject = "admin"
+	// SubjectPlatformAdmin is every user with the platform_admin role
+	SubjectPlatformAdmin PolicySubject = "platform_admin"
+	// SubjectTeacher is every user with the teacher role
+	SubjectTeacher PolicySubject = "teacher"
+	// SubjectOwner is the user who created the resource. Chapters, lessons and the rest of
+	// the course content are owned by the owner member of their course.
+	SubjectOwner PolicySubject = "owner"
+	// SubjectCoInstructor is a member of the course who edits it alongside the owner
+	SubjectCoInstructor PolicySubject = models.CourseMemberRoleCoInstructor
+	// SubjectTeachingAssistant is a member of the course who supports its learners
+	SubjectTeachingAssistant PolicySubject = models.CourseMemberRoleTeachingAssistant
+	// SubjectViewer is a member of the course with read access to its drafts and history
+	SubjectViewer PolicySubject = models.CourseMemberRoleViewer
+)
+
+// PolicyRule grants an action on a resource to the listed subjects
+type PolicyRule struct {
+	Action   string
+	Resource string
+	Allow    []PolicySubject
+}
+
+// PolicyRules is the authorization table of the API. An action that is not listed is denied
+// to everyone. Reading course content is not listed: it depends on the enrollment and is
+// checked by RequireCourseAccess. Lesson blocks, revisions and the order of chapters and
+// lessons are part of the lesson and chapter they belong to.
+var PolicyRules = []PolicyRule{
+	{ActionCreate, ResourceCourse, []PolicySubject{SubjectAdmin, SubjectTeacher}},
+	{ActionUpdate, ResourceCourse, []PolicySubject{SubjectAdmin, SubjectOwner, SubjectCoInstructor}},
+	{ActionDelete, ResourceCourse, []PolicySubject{SubjectAdmin, SubjectOwner}},
+	{ActionChangeStatus, ResourceCourse, []PolicySubject{SubjectAdmin, SubjectOwner, SubjectCoInstructor}},
+	{ActionReadHistory, ResourceCourse, []PolicySubject{SubjectAdmin, SubjectTeacher, SubjectOwner, SubjectCoInstructor, SubjectTeachingAssistant, SubjectViewer}},
+	{ActionReadUnpublished, ResourceCourse, []PolicySubject{SubjectAdmin, SubjectTeacher}},
+	{ActionCopy, ResourceCourse, []PolicySubject{SubjectAdmin, SubjectTeacher}},
+	{ActionImport, ResourceCourse, []PolicySubject{SubjectAdmin, SubjectTeacher}},
+	{ActionEnroll, ResourceCourse, []PolicySubject{SubjectAnyone}},
+	{ActionRestore, ResourceCourse, []PolicySubject{SubjectAdmin, SubjectOwner}},
+
+	// Members are listed to every member, only the owner invites and removes them
+	{ActionRead, ResourceCourseMember, []PolicySubject{SubjectAdmin, SubjectOwner, SubjectCoInstructor, SubjectTeachingAssistant, SubjectViewer}},
+	{ActionCreate, ResourceCourseMember, []PolicySubject{SubjectAdmin, SubjectOwner}},
+	{ActionUpdate, ResourceCourseMember, []PolicySubject{SubjectAdmin, SubjectOwner}},
+	{ActionDelete, ResourceCourseMember, []PolicySubject{SubjectAdmin, SubjectOwner}},
+
+	{ActionCreate, ResourceChapter, []PolicySubject{SubjectAdmin, SubjectOwner, SubjectCoInstructor}},
+	{ActionUpdate, ResourceChapter, []PolicySubject{SubjectAdmin, SubjectOwner, SubjectCoInstructor}},
+	{ActionDelete, ResourceChapter, []PolicySubject{SubjectAdmin, SubjectOwner, SubjectCoInstructor}},
+	{ActionRestore, ResourceChapter, []PolicySubject{SubjectAdmin, SubjectOwner, SubjectCoInstructor}},
+
+	{ActionCreate, ResourceLesson, []PolicySubject{SubjectAdmin, SubjectOwner, SubjectCoInstructor}},
+	{ActionUpdate, ResourceLesson, []PolicySubject{SubjectAdmin, SubjectOwner, SubjectCoInstructor}},
+	{ActionDelete, ResourceLesson, []PolicySubject{SubjectAdmin, SubjectOwner, SubjectCoInstructor}},
+	{ActionRestore, ResourceLesson, []PolicySubject{SubjectAdmin, SubjectOwner, SubjectCoInstructor}},
+	{ActionReadHistory, ResourceLesson, []PolicySubject{SubjectAdmin, SubjectTeacher, SubjectOwner, SubjectCoInstructor, SubjectTeachingAssistant, SubjectViewer}},
+
+	{ActionCreate, ResourceAttachment, []PolicySubject{SubjectAdmin, SubjectOwner, SubjectCoInstructor}},
+	{ActionDelete, ResourceAttachment, []PolicySubject{SubjectAdmin, SubjectOwner, SubjectCoInstructor}},
+	{ActionRestore, ResourceAttachment, []PolicySubject{SubjectAdmin, SubjectOwner, SubjectCoInstructor}},
+
+	{ActionCreate, ResourceQuiz, []PolicySubject{SubjectAdmin, SubjectOwner, SubjectCoInstructor}},
+	{ActionUpdate, ResourceQuiz, []PolicySubject{SubjectAdmin, SubjectOwner, SubjectCoInstructor}},
+	{ActionDelete, ResourceQuiz, []PolicySubject{SubjectAdmin, SubjectOwner, SubjectCoInstructor}},
+	{ActionReadAnswers, ResourceQuiz, []PolicySubject{SubjectAdmin, SubjectTeacher, SubjectOwner, SubjectCoInstructor, SubjectTeachingAssistant}},
+	{ActionReview, ResourceQuiz, []PolicySubject{SubjectAdmin, SubjectOwner, SubjectCoInstructor, SubjectTeachingAssistant}},
+
+	// Certificates are issued by the course staff and belong to the learner who earned them,
+	// the learner always reads its own certificates
+	{ActionCreate, ResourceCertificate, []PolicySubject{SubjectAdmin, SubjectOwner, SubjectCoInstructor, SubjectTeachingAssistant}},
+	{ActionRead, ResourceCertificate, []PolicySubject{SubjectAdmin, SubjectOwner, SubjectCoInstructor, SubjectTeachingAssistant}},
+
+	// Users are created without an organization, so only for the whole deployment
+	{ActionCreate, ResourceUser, []PolicySubject{SubjectPlatformAdmin}},
+
+	// Admins of an organization read the entries of its users
+	{ActionRead, ResourceAuditLog, []PolicySubject{SubjectAdmin}},
+
+	// Organizations are managed for the whole deployment
+	{ActionRead, ResourceOrganization, []PolicySubject{SubjectPlatformAdmin}},
+	{ActionCreate, ResourceOrganization, []PolicySubject{SubjectPlatformAdmin}},
+	{ActionUpdate, ResourceOrganization, []PolicySubject{SubjectPlatformAdmin}},
+	{ActionDelete, ResourceOrganization, []PolicySubject{SubjectPlatformAdmin}},
+}
+
+type PolicyServiceInterface interface {
+	Can(actor Actor, action, resource string, owner *uint) bool
+	Authorize(actor Actor, action, resource string, owner *uint) error
+	AuthorizeCourse(actor Actor, action, resource string, courseID uint) error
+	AuthorizeLesson(actor Actor, action, resource string, lessonID uint) error
+	AuthorizeLoadedCourse(actor Actor, action, resource string, course models.Course) error
+	MemberRoles(action, resource string) []string
+}
+
+var _ PolicyServiceInterface = (*PolicyService)(nil)
+
+// PolicyService decides whether an actor may perform an action on a resource from a
+// rule table, the roles of the actor, the owner of the resource and the role of the actor
+// in the course of the resource
+type PolicyService struct {
+	rules      map[policyKey][]PolicySubject
+	courseRepo repos.CourseRepositoryInterface
+	lessonRepo repos.LessonRepositoryInterface
+	memberRepo repos.CourseMemberRepositoryInterface
+}
+
+type policyKey struct {
+	action   string
+	resource string
+}
+
+func NewPolicyService(rules []PolicyRule, courseRepo repos.CourseRepositoryInterface, lessonRepo repos.LessonRepositoryInterface, memberRepo repos.CourseMemberRepositoryInterface) *PolicyService {
+	ruleMap := make(map[policyKey][]PolicySubject, len(rules))
+	for _, rule := range rules {
+		key := policyKey{rule.Action, rule.Resource}
+		ruleMap[key] = append(ruleMap[key], rule.Allow...)
+	}
+
+	return &PolicyService{
+		rules:      ruleMap,
+		courseRepo: courseRepo,
+		lessonRepo: lessonRepo,
+		memberRepo: memberRepo,
+	}
+}
+
+// Can reports whether the actor may perform the action on a resource created by owner.
+// owner is nil for resources without an owner and for resources that are being created.
+func (s *PolicyService) Can(actor Actor, action, resource string, owner *uint) bool {
+	return s.allows(actor, action, resource, owner, "")
+}
+
+// allows is Can for an actor holding memberRole in the course of the resource
+func (s *PolicyService) allows(actor Actor, action, resource string, owner *uint, memberRole string) bool {
+	for _, subject := range s.rules[policyKey{action, resource}] {
+		switch subject {
+		case SubjectAnyone:
+			return true
+		case SubjectAdmin:
+			if actor.IsAdmin {
+				return true
+			}
+		case SubjectPlatformAdmin:
+			if actor.IsPlatformAdmin() {
+				return true
+			}
+		case SubjectTeacher:
+			if actor.IsTeacher {
+				return true
+			}
+		case SubjectOwner:
+			if actor.Owns(owner) || memberRole == models.CourseMemberRoleOwner {
+				return true
+			}
+		case SubjectCoInstructor, SubjectTeachingAssistant, SubjectViewer:
+			if memberRole != "" && memberRole == string(subject) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Authorize is Can returning an error when the action is denied
+func (s *PolicyService) Authorize(actor Actor, action, resource string, owner *uint) error {
+	if !s.Can(actor, action, resource, owner) {
+		return fmt.Errorf("insufficient permissions for %s on %s", action, resource)
+	}
+	return nil
+}
+
+// AuthorizeCourse authorizes an action on a course or on content of the course from the
+// creator of the course and the role of the actor among the course members. Courses of
+// other organizations are reported as not found.
+func (s *PolicyService) AuthorizeCourse(actor Actor, action, resource string, courseID uint) error {
+	// Platform admins reach every organization, their grants need no lookup
+	if actor.IsPlatformAdmin() && s.Can(actor, action, resource, nil) {
+		return nil
+	}
+
+	course, err := s.courseRepo.GetByID(courseID)
+	if err != nil {
+		return err
+	}
+	return s.AuthorizeLoadedCourse(actor, action, resource, course)
+}
+
+// AuthorizeLoadedCourse is AuthorizeCourse for a course the caller already read, such as a
+// course in the trash. Only the ID, creator and organization of the course are used.
+func (s *PolicyService) AuthorizeLoadedCourse(actor Actor, action, resource string, course models.Course) error {
+	if !actor.InOrganization(course.OrganizationID) {
+		return errors.New("course not found")
+	}
+	if s.Can(actor, action, resource, course.CreatedBy) {
+		return nil
+	}
+
+	role, err := s.memberRepo.GetRole(course.ID, actor.UserID)
+	if err != nil {
+		return err
+	}
+	if !s.allows(actor, action, resource, course.CreatedBy, role) {
+		return fmt.Errorf("insufficient permissions for %s on %s", action, resource)
+	}
+	return nil
+}
+
+// AuthorizeLesson authorizes an action on content of a lesson for routes that do not name
+// the course of the lesson
+func (s *PolicyService) AuthorizeLesson(actor Actor, action, resource string, lessonID uint) error {
+	if actor.IsPlatformAdmin() && s.Can(actor, action, resource, nil) {
+		return nil
+	}
+
+	courseID, err := s.lessonRepo.GetCourseID(lessonID)
+	if err != nil {
+		return err
+	}
+	return s.AuthorizeCourse(actor, action, resource, courseID)
+}
+
+// MemberRoles returns the course member roles granted the action on the resource, used to
+// list the resources an actor may act on without checking them one by one
+func (s *PolicyService) MemberRoles(action, resource string) []string {
+	var roles []string
+	for _, subject := range s.rules[policyKey{action, resource}] {
+		switch subject {
+		case SubjectOwner:
+			roles = append(roles, models.CourseMemberRoleOwner)
+		case SubjectCoInstructor, SubjectTeachingAssistant, SubjectViewer:
+			roles = append(roles, string(subject))
+		}
+	}
+	return roles
+}
@@ -0,0 +1,192 @@
+package services
+
+import (
+	"errors"
+	"time"
+	"web/models"
+	"web/repos"
+	"web/schemas"
+)
+
+type ProgressServiceInterface interface {
+	GetLessonProgress(userID, courseID, chapterID, lessonID uint, tenant *schemas.TenantFilter) (schemas.LessonProgressResponse, error)
+	UpdateLessonProgress(userID, courseID, chapterID, lessonID uint, progressRequest schemas.UpdateLessonProgressRequest, tenant *schemas.TenantFilter) (schemas.LessonProgressResponse, error)
+	CompleteLesson(userID, courseID, chapterID, lessonID uint, tenant *schemas.TenantFilter) (schemas.LessonProgressResponse, error)
+	ResetLessonProgress(userID, courseID, chapterID, lessonID uint, tenant *schemas.TenantFilter) error
+	AttachCourseProgress(userID uint, courses []schemas.CourseResponseWithChaptersCount) error
+	AttachChapterProgress(userID uint, chapters []schemas.ChapterResponseWithLessonsCount) error
+}
+
+var _ ProgressServiceInterface = (*ProgressService)(nil)
+
+type ProgressService struct {
+	repo       repos.ProgressRepositoryInterface
+	lessonRepo repos.LessonRepositoryInterface
+}
+
+func NewProgressService(repo repos.ProgressRepositoryInterface, lessonRepo repos.LessonRepositoryInterface) *ProgressService {
+	return &ProgressService{
+		repo:       repo,
+		lessonRepo: lessonRepo,
+	}
+}
+
+func (s *ProgressService) GetLessonProgress(userID, courseID, chapterID, lessonID uint, tenant *schemas.TenantFilter) (schemas.LessonProgressResponse, error) {
+	lesson, err := s.lessonRepo.GetByID(courseID, chapterID, lessonID, tenant)
+	if err != nil {
+		return schemas.LessonProgressResponse{}, err
+	}
+
+	progress, err := s.repo.GetByUserAndLesson(userID, lesson.ID)
+	if err != nil {
+		if err.Error() == "progress not found" {
+			return schemas.LessonProgressResponse{
+				LessonID: lesson.ID,
+				Status:   models.ProgressStatusNotStarted,
+			}, nil
+		}
+		return schemas.LessonProgressResponse{}, err
+	}
+
+	return toLessonProgressResponse(progress), nil
+}
+
+func (s *ProgressService) UpdateLessonProgress(userID, courseID, chapterID, lessonID uint, progressRequest schemas.UpdateLessonProgressRequest, tenant *schemas.TenantFilter) (schemas.LessonProgressResponse, error) {
+	if progressRequest.Percent < 0 || progressRequest.Percent > 100 {
+		return schemas.LessonProgressResponse{}, errors.New("percent must be between 0 and 100")
+	}
+	if progressRequest.LastPosition < 0 {
+		return schemas.LessonProgressResponse{}, errors.New("last position must not be negative")
+	}
+
+	switch progressRequest.Status {
+	case "", models.ProgressStatusNotStarted, models.ProgressStatusInProgress, models.ProgressStatusCompleted:
+	default:
+		return schemas.LessonProgressResponse{}, errors.New("invalid progress status")
+	}
+
+	lesson, err := s.lessonRepo.GetByID(courseID, chapterID, lessonID, tenant)
+	if err != nil {
+		return schemas.LessonProgressResponse{}, err
+	}
+
+	existing, err := s.repo.GetByUserAndLesson(userID, lesson.ID)
+	if err != nil && err.Error() != "progress not found" {
+		return schemas.LessonProgressResponse{}, err
+	}
+
+	status := progressRequest.Status
+	if status == "" {
+		status = models.ProgressStatusInProgress
+		if progressRequest.Percent == 100 {
+			status = models.ProgressStatusCompleted
+		}
+	}
+
+	now := time.Now()
+	progress := models.LessonProgress{
+		UserID:       userID,
+		LessonID:     lesson.ID,
+		Status:       status,
+		Percent:      progressRequest.Percent,
+		LastPosition: progressRequest.LastPosition,
+		UpdatedAt:    now,
+	}
+
+	switch {
+	case progressRequest.Status == "" && existing.Status == models.ProgressStatusCompleted:
+		// Reports without a status, such as the position of a replay, leave a completed
+		// lesson completed
+		progress.Status = models.ProgressStatusCompleted
+		progress.CompletedAt = existing.CompletedAt
+	case status == models.ProgressStatusCompleted:
+		progress.Percent = 100
+		// Keep the moment the lesson was first completed
+		if existing.CompletedAt != nil {
+			progress.CompletedAt = existing.CompletedAt
+		} else {
+			progress.CompletedAt = &now
+		}
+	}
+
+	progress, err = s.repo.Upsert(progress)
+	if err != nil {
+		return schemas.LessonProgressResponse{}, err
+	}
+
+	return toLessonProgressResponse(progress), nil
+}
+
+func (s *ProgressService) CompleteLesson(userID, courseID, chapterID, lessonID uint, tenant *schemas.TenantFilter) (schemas.LessonProgressResponse, error) {
+	current, err := s.GetLessonProgress(userID, courseID, chapterID, lessonID, tenant)
+	if err != nil {
+		return schemas.LessonProgressResponse{}, err
+	}
+
+	return s.UpdateLessonProgress(userID, courseID, chapterID, lessonID, schemas.UpdateLessonProgressRequest{
+		Status:       models.ProgressStatusCompleted,
+		Percent:      100,
+		LastPosition: current.LastPosition,
+	}, tenant)
+}
+
+func (s *ProgressService) ResetLessonProgress(userID, courseID, chapterID, lessonID uint, tenant *schemas.TenantFilter) error {
+	lesson, err := s.lessonRepo.GetByID(courseID, chapterID, lessonID, tenant)
+	if err != nil {
+		return err
+	}
+
+	return s.repo.Delete(userID, lesson.ID)
+}
+
+// AttachCourseProgress fills in the progress summary of the user for each course
+func (s *ProgressService) AttachCourseProgress(userID uint, courses []schemas.CourseResponseWithChaptersCount) error {
+	courseIDs := make([]uint, 0, len(courses))
+	for _, course := range courses {
+		courseIDs = append(courseIDs, course.ID)
+	}
+
+	summaries, err := s.repo.GetCourseSummaries(userID, courseIDs)
+	if err != nil {
+		return err
+	}
+
+	for i := range courses {
+		summary := summaries[courses[i].ID]
+		courses[i].Progress = &summary
+	}
+
+	return nil
+}
+
+// AttachChapterProgress fills in the progress summary of the user for each chapter
+func (s *ProgressService) AttachChapterProgress(userID uint, chapters []schemas.ChapterResponseWithLessonsCount) error {
+	chapterIDs := make([]uint, 0, len(chapters))
+	for _, chapter := range chapters {
+		chapterIDs = append(chapterIDs, chapter.ID)
+	}
+
+	summaries, err := s.repo.GetChapterSummaries(userID, chapterIDs)
+	if err != nil {
+		return err
+	}
+
+	for i := range chapters {
+		summary := summaries[chapters[i].ID]
+		chapters[i].Progress = &summary
+	}
+
+	return nil
+}
+
+func toLessonProgressResponse(progress models.LessonProgress) schemas.LessonProgressResponse {
+	updatedAt := progress.UpdatedAt
+	return schemas.LessonProgressResponse{
+		LessonID:     progress.LessonID,
+		Status:       progress.Status,
+		Percent:      progress.Percent,
+		LastPosition: progress.LastPosition,
+		CompletedAt:  progress.CompletedAt,
+		UpdatedAt:    &updatedAt,
+	}
+}
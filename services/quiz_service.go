@@ -0,0 +1,613 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+	"web/models"
+	"web/repos"
+	"web/schemas"
+)
+
+type QuizServiceInterface interface {
+	GetQuizzesByLessonID(courseID, chapterID, lessonID uint, tenant *schemas.TenantFilter) ([]schemas.QuizResponse, error)
+	GetQuizByID(courseID, chapterID, lessonID, id uint, includeAnswers bool, tenant *schemas.TenantFilter) (schemas.QuizResponse, error)
+	CreateQuiz(courseID, chapterID, lessonID uint, quizRequest schemas.QuizRequest, tenant *schemas.TenantFilter) (uint, error)
+	UpdateQuiz(courseID, chapterID, lessonID, id uint, quizRequest schemas.QuizRequest, tenant *schemas.TenantFilter) error
+	DeleteQuiz(courseID, chapterID, lessonID, id uint, tenant *schemas.TenantFilter) error
+	SubmitAttempt(userID, courseID, chapterID, lessonID, quizID uint, attemptRequest schemas.SubmitQuizAttemptRequest, tenant *schemas.TenantFilter) (schemas.QuizAttemptResponse, error)
+	GetAttempts(userID, courseID, chapterID, lessonID, quizID uint, tenant *schemas.TenantFilter) ([]schemas.QuizAttemptResponse, error)
+	ReviewAttempt(courseID, chapterID, lessonID, quizID, attemptID uint, reviewRequest schemas.ReviewQuizAttemptRequest, tenant *schemas.TenantFilter) (schemas.QuizAttemptResponse, error)
+}
+
+var _ QuizServiceInterface = (*QuizService)(nil)
+
+type QuizService struct {
+	repo       repos.QuizRepositoryInterface
+	lessonRepo repos.LessonRepositoryInterface
+}
+
+func NewQuizService(repo repos.QuizRepositoryInterface, lessonRepo repos.LessonRepositoryInterface) *QuizService {
+	return &QuizService{
+		repo:       repo,
+		lessonRepo: lessonRepo,
+	}
+}
+
+func (s *QuizService) GetQuizzesByLessonID(courseID, chapterID, lessonID uint, tenant *schemas.TenantFilter) ([]schemas.QuizResponse, error) {
+	lesson, err := s.lessonRepo.GetByID(courseID, chapterID, lessonID, tenant)
+	if err != nil {
+		return nil, err
+	}
+
+	quizzes, err := s.repo.GetByLessonID(lesson.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	quizResponses := make([]schemas.QuizResponse, 0, len(quizzes))
+	for _, quiz := range quizzes {
+		quizResponse := toQuizResponse(quiz, false)
+		quizResponse.Questions = nil
+		quizResponses = append(quizResponses, quizResponse)
+	}
+
+	return quizResponses, nil
+}
+
+func (s *QuizService) GetQuizByID(courseID, chapterID, lessonID, id uint, includeAnswers bool, tenant *schemas.TenantFilter) (schemas.QuizResponse, error) {
+	lesson, err := s.lessonRepo.GetByID(courseID, chapterID, lessonID, tenant)
+	if err != nil {
+		return schemas.QuizResponse{}, err
+	}
+
+	quiz, err := s.repo.GetByID(lesson.ID, id)
+	if err != nil {
+		return schemas.QuizResponse{}, err
+	}
+
+	return toQuizResponse(quiz, includeAnswers), nil
+}
+
+func (s *QuizService) CreateQuiz(courseID, chapterID, lessonID uint, quizRequest schemas.QuizRequest, tenant *schemas.TenantFilter) (uint, error) {
+	questions, err := buildQuizQuestions(quizRequest, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	lesson, err := s.lessonRepo.GetByID(courseID, chapterID, lessonID, tenant)
+	if err != nil {
+		return 0, err
+	}
+
+	quiz := models.Quiz{
+		Title:        quizRequest.Title,
+		Description:  quizRequest.Description,
+		PassingScore: quizRequest.PassingScore,
+		LessonID:     lesson.ID,
+		CreatedBy:    quizRequest.CreatedBy,
+		Questions:    questions,
+	}
+
+	return s.repo.Create(quiz)
+}
+
+// UpdateQuiz changes the quiz and its questions. Questions and options keep their IDs
+// when the request refers to them, so answers of earlier attempts can still be reviewed.
+// Answers waiting for review whose question is removed or no longer free text are
+// settled, see settleStaleAnswers.
+func (s *QuizService) UpdateQuiz(courseID, chapterID, lessonID, id uint, quizRequest schemas.QuizRequest, tenant *schemas.TenantFilter) error {
+	lesson, err := s.lessonRepo.GetByID(courseID, chapterID, lessonID, tenant)
+	if err != nil {
+		return err
+	}
+
+	quiz, err := s.repo.GetByID(lesson.ID, id)
+	if err != nil {
+		return err
+	}
+
+	questions, err := buildQuizQuestions(quizRequest, quiz.Questions)
+	if err != nil {
+		return err
+	}
+
+	quiz.Title = quizRequest.Title
+	quiz.Description = quizRequest.Description
+	quiz.PassingScore = quizRequest.PassingScore
+	quiz.Questions = questions
+
+	if err := s.repo.Update(quiz); err != nil {
+		return err
+	}
+
+	attempts, err := s.repo.GetPendingAttempts(quiz.ID)
+	if err != nil {
+		return err
+	}
+	for _, attempt := range attempts {
+		if !settleStaleAnswers(quiz, &attempt) {
+			continue
+		}
+		updateAttemptResult(quiz, &attempt)
+		if err := s.repo.UpdateAttemptReview(attempt); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *QuizService) DeleteQuiz(courseID, chapterID, lessonID, id uint, tenant *schemas.TenantFilter) error {
+	lesson, err := s.lessonRepo.GetByID(courseID, chapterID, lessonID, tenant)
+	if err != nil {
+		return err
+	}
+
+	quiz, err := s.repo.GetByID(lesson.ID, id)
+	if err != nil {
+		return err
+	}
+
+	return s.repo.Delete(quiz.ID)
+}
+
+func (s *QuizService) SubmitAttempt(userID, courseID, chapterID, lessonID, quizID uint, attemptRequest schemas.SubmitQuizAttemptRequest, tenant *schemas.TenantFilter) (schemas.QuizAttemptResponse, error) {
+	lesson, err := s.lessonRepo.GetByID(courseID, chapterID, lessonID, tenant)
+	if err != nil {
+		return schemas.QuizAttemptResponse{}, err
+	}
+
+	quiz, err := s.repo.GetByID(lesson.ID, quizID)
+	if err != nil {
+		return schemas.QuizAttemptResponse{}, err
+	}
+
+	attempt, err := GradeQuizAttempt(quiz, attemptRequest)
+	if err != nil {
+		return schemas.QuizAttemptResponse{}, err
+	}
+	attempt.UserID = userID
+	attempt.SubmittedAt = time.Now()
+
+	attempt, err = s.repo.CreateAttempt(attempt)
+	if err != nil {
+		return schemas.QuizAttemptResponse{}, err
+	}
+
+	return toQuizAttemptResponse(attempt), nil
+}
+
+func (s *QuizService) GetAttempts(userID, courseID, chapterID, lessonID, quizID uint, tenant *schemas.TenantFilter) ([]schemas.QuizAttemptResponse, error) {
+	lesson, err := s.lessonRepo.GetByID(courseID, chapterID, lessonID, tenant)
+	if err != nil {
+		return nil, err
+	}
+
+	quiz, err := s.repo.GetByID(lesson.ID, quizID)
+	if err != nil {
+		return nil, err
+	}
+
+	attempts, err := s.repo.GetAttempts(quiz.ID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	attemptResponses := make([]schemas.QuizAttemptResponse, 0, len(attempts))
+	for _, attempt := range attempts {
+		attemptResponses = append(attemptResponses, toQuizAttemptResponse(attempt))
+	}
+
+	return attemptResponses, nil
+}
+
+// ReviewAttempt grades the free text answers of an attempt on behalf of the course staff
+// and decides whether the attempt is passed once no answer is left to review
+func (s *QuizService) ReviewAttempt(courseID, chapterID, lessonID, quizID, attemptID uint, reviewRequest schemas.ReviewQuizAttemptRequest, tenant *schemas.TenantFilter) (schemas.QuizAttemptResponse, error) {
+	lesson, err := s.lessonRepo.GetByID(courseID, chapterID, lessonID, tenant)
+	if err != nil {
+		return schemas.QuizAttemptResponse{}, err
+	}
+
+	quiz, err := s.repo.GetByID(lesson.ID, quizID)
+	if err != nil {
+		return schemas.QuizAttemptResponse{}, err
+	}
+
+	attempt, err := s.repo.GetAttempt(quiz.ID, attemptID)
+	if err != nil {
+		return schemas.QuizAttemptResponse{}, err
+	}
+
+	attempt, err = ReviewQuizAttempt(quiz, attempt, reviewRequest)
+	if err != nil {
+		return schemas.QuizAttemptResponse{}, err
+	}
+
+	if err := s.repo.UpdateAttemptReview(attempt); err != nil {
+		return schemas.QuizAttemptResponse{}, err
+	}
+
+	return toQuizAttemptResponse(attempt), nil
+}
+
+// GradeQuizAttempt validates the submitted answers against the quiz and grades the
+// objective questions. Free text answers are stored ungraded and mark the attempt
+// as pending review, the attempt is not passed until they are reviewed.
+func GradeQuizAttempt(quiz models.Quiz, attemptRequest schemas.SubmitQuizAttemptRequest) (models.QuizAttempt, error) {
+	questions := make(map[uint]models.QuizQuestion, len(quiz.Questions))
+	for _, question := range quiz.Questions {
+		questions[question.ID] = question
+	}
+
+	attempt := models.QuizAttempt{QuizID: quiz.ID}
+	answered := make(map[uint]bool, len(attemptRequest.Answers))
+
+	for _, answerRequest := range attemptRequest.Answers {
+		question, ok := questions[answerRequest.QuestionID]
+		if !ok {
+			return models.QuizAttempt{}, fmt.Errorf("question %d does not belong to this quiz", answerRequest.QuestionID)
+		}
+		if answered[question.ID] {
+			return models.QuizAttempt{}, fmt.Errorf("question %d is answered more than once", question.ID)
+		}
+		answered[question.ID] = true
+
+		answer, err := gradeAnswer(question, answerRequest)
+		if err != nil {
+			return models.QuizAttempt{}, err
+		}
+		if answer.IsCorrect == nil {
+			attempt.PendingReview = true
+		}
+		attempt.Score += answer.PointsAwarded
+		attempt.Answers = append(attempt.Answers, answer)
+	}
+
+	for _, question := range quiz.Questions {
+		attempt.MaxScore += question.Points
+	}
+
+	attempt.Passed = quizPassed(quiz, attempt)
+
+	return attempt, nil
+}
+
+// ReviewQuizAttempt awards the reviewed points to free text answers of the attempt and
+// updates its score. An answer awarded the full points of its question is correct. Answers
+// may be reviewed again, the attempt stays pending until every free text answer is.
+// Answers left without a free text question to review them by are settled first.
+func ReviewQuizAttempt(quiz models.Quiz, attempt models.QuizAttempt, reviewRequest schemas.ReviewQuizAttemptRequest) (models.QuizAttempt, error) {
+	if len(reviewRequest.Answers) == 0 {
+		return models.QuizAttempt{}, errors.New("at least one answer review is required")
+	}
+
+	settleStaleAnswers(quiz, &attempt)
+
+	questions := make(map[uint]models.QuizQuestion, len(quiz.Questions))
+	for _, question := range quiz.Questions {
+		questions[question.ID] = question
+	}
+	answers := make(map[uint]int, len(attempt.Answers))
+	for i, answer := range attempt.Answers {
+		answers[answer.QuestionID] = i
+	}
+
+	reviewed := make(map[uint]bool, len(reviewRequest.Answers))
+	for _, review := range reviewRequest.Answers {
+		question, ok := questions[review.QuestionID]
+		if !ok {
+			return models.QuizAttempt{}, fmt.Errorf("question %d does not belong to this quiz", review.QuestionID)
+		}
+		i, ok := answers[question.ID]
+		if !ok || question.Type != models.QuestionTypeFreeText {
+			return models.QuizAttempt{}, fmt.Errorf("question %d has no free text answer to review", question.ID)
+		}
+		if reviewed[question.ID] {
+			return models.QuizAttempt{}, fmt.Errorf("question %d is reviewed more than once", question.ID)
+		}
+		reviewed[question.ID] = true
+		if review.PointsAwarded < 0 || review.PointsAwarded > question.Points {
+			return models.QuizAttempt{}, fmt.Errorf("points of question %d must be between 0 and %d", question.ID, question.Points)
+		}
+
+		correct := review.PointsAwarded == question.Points
+		attempt.Score += review.PointsAwarded - attempt.Answers[i].PointsAwarded
+		attempt.Answers[i].PointsAwarded = review.PointsAwarded
+		attempt.Answers[i].IsCorrect = &correct
+	}
+
+	updateAttemptResult(quiz, &attempt)
+
+	return attempt, nil
+}
+
+// settleStaleAnswers grades the ungraded answers of the attempt whose question was removed
+// from the quiz or is no longer a free text question as wrong without points, as they
+// cannot be reviewed anymore. It reports whether any answer was settled.
+func settleStaleAnswers(quiz models.Quiz, attempt *models.QuizAttempt) bool {
+	freeText := make(map[uint]bool, len(quiz.Questions))
+	for _, question := range quiz.Questions {
+		if question.Type == models.QuestionTypeFreeText {
+			freeText[question.ID] = true
+		}
+	}
+
+	settled := false
+	for i, answer := range attempt.Answers {
+		if answer.IsCorrect != nil || freeText[answer.QuestionID] {
+			continue
+		}
+		correct := false
+		attempt.Score -= answer.PointsAwarded
+		attempt.Answers[i].PointsAwarded = 0
+		attempt.Answers[i].IsCorrect = &correct
+		settled = true
+	}
+	return settled
+}
+
+// updateAttemptResult keeps the attempt pending while an answer is ungraded and decides
+// whether it is passed
+func updateAttemptResult(quiz models.Quiz, attempt *models.QuizAttempt) {
+	attempt.PendingReview = false
+	for _, answer := range attempt.Answers {
+		if answer.IsCorrect == nil {
+			attempt.PendingReview = true
+		}
+	}
+	attempt.Passed = quizPassed(quiz, *attempt)
+}
+
+// quizPassed reports whether a graded attempt reaches the passing score. Attempts pending
+// review are not passed yet, and neither are attempts with nothing to grade.
+func quizPassed(quiz models.Quiz, attempt models.QuizAttempt) bool {
+	return !attempt.PendingReview && attempt.MaxScore > 0 &&
+		quizPercent(attempt.Score, attempt.MaxScore) >= quiz.PassingScore
+}
+
+func gradeAnswer(question models.QuizQuestion, answerRequest schemas.QuizAnswerRequest) (models.QuizAnswer, error) {
+	answer := models.QuizAnswer{QuestionID: question.ID}
+
+	switch question.Type {
+	case models.QuestionTypeSingleChoice, models.QuestionTypeMultipleChoice:
+		if question.Type == models.QuestionTypeSingleChoice && len(answerRequest.OptionIDs) > 1 {
+			return models.QuizAnswer{}, fmt.Errorf("question %d accepts a single option", question.ID)
+		}
+
+		options := make(map[uint]bool, len(question.Options))
+		correctCount := 0
+		for _, option := range question.Options {
+			options[option.ID] = option.IsCorrect
+			if option.IsCorrect {
+				correctCount++
+			}
+		}
+
+		selected := make(map[uint]bool, len(answerRequest.OptionIDs))
+		correct := len(answerRequest.OptionIDs) > 0
+		for _, optionID := range answerRequest.OptionIDs {
+			isCorrect, ok := options[optionID]
+			if !ok {
+				return models.QuizAnswer{}, fmt.Errorf("option %d does not belong to question %d", optionID, question.ID)
+			}
+			if selected[optionID] {
+				continue
+			}
+			selected[optionID] = true
+			answer.SelectedOptionIDs = append(answer.SelectedOptionIDs, int64(optionID))
+			if !isCorrect {
+				correct = false
+			}
+		}
+		// All correct options have to be selected and nothing else
+		correct = correct && len(selected) == correctCount
+		answer.IsCorrect = &correct
+	case models.QuestionTypeNumeric:
+		correct := false
+		if answerRequest.Number != nil && question.CorrectNumber != nil {
+			correct = math.Abs(*answerRequest.Number-*question.CorrectNumber) <= question.Tolerance
+		}
+		answer.NumericAnswer = answerRequest.Number
+		answer.IsCorrect = &correct
+	case models.QuestionTypeFreeText:
+		answer.TextAnswer = answerRequest.Text
+	default:
+		return models.QuizAnswer{}, fmt.Errorf("unsupported question type %s", question.Type)
+	}
+
+	if answer.IsCorrect != nil && *answer.IsCorrect {
+		answer.PointsAwarded = question.Points
+	}
+
+	return answer, nil
+}
+
+// buildQuizQuestions validates the questions of the request. Question and option IDs
+// have to refer to the existing questions of the quiz, which is nil for a new quiz.
+func buildQuizQuestions(quizRequest schemas.QuizRequest, existing []models.QuizQuestion) ([]models.QuizQuestion, error) {
+	if quizRequest.Title == "" {
+		return nil, errors.New("quiz title is required")
+	}
+	if quizRequest.PassingScore < 0 || quizRequest.PassingScore > 100 {
+		return nil, errors.New("passing score must be between 0 and 100")
+	}
+	if len(quizRequest.Questions) == 0 {
+		return nil, errors.New("quiz must have at least one question")
+	}
+
+	existingQuestions := make(map[uint]models.QuizQuestion, len(existing))
+	for _, question := range existing {
+		existingQuestions[question.ID] = question
+	}
+	usedQuestions := make(map[uint]bool, len(quizRequest.Questions))
+
+	questions := make([]models.QuizQuestion, 0, len(quizRequest.Questions))
+	for i, questionRequest := range quizRequest.Questions {
+		number := i + 1
+		if questionRequest.Text == "" {
+			return nil, fmt.Errorf("question %d: text is required", number)
+		}
+		if questionRequest.Points < 0 {
+			return nil, fmt.Errorf("question %d: points must not be negative", number)
+		}
+
+		existingOptions := make(map[uint]bool)
+		if questionRequest.ID != 0 {
+			current, ok := existingQuestions[questionRequest.ID]
+			if !ok {
+				return nil, fmt.Errorf("question %d: question %d does not belong to this quiz", number, questionRequest.ID)
+			}
+			if usedQuestions[current.ID] {
+				return nil, fmt.Errorf("question %d: question %d is listed more than once", number, current.ID)
+			}
+			usedQuestions[current.ID] = true
+			for _, option := range current.Options {
+				existingOptions[option.ID] = true
+			}
+		}
+
+		question := models.QuizQuestion{
+			ID:     questionRequest.ID,
+			Type:   questionRequest.Type,
+			Text:   questionRequest.Text,
+			Points: questionRequest.Points,
+			Order:  questionRequest.Order,
+		}
+		if question.Points == 0 {
+			question.Points = 1
+		}
+		if question.Order == 0 {
+			question.Order = number
+		}
+
+		switch questionRequest.Type {
+		case models.QuestionTypeSingleChoice, models.QuestionTypeMultipleChoice:
+			if len(questionRequest.Options) < 2 {
+				return nil, fmt.Errorf("question %d: at least two options are required", number)
+			}
+			correctCount := 0
+			for j, optionRequest := range questionRequest.Options {
+				if optionRequest.Text == "" {
+					return nil, fmt.Errorf("question %d: option %d text is required", number, j+1)
+				}
+				if optionRequest.ID != 0 {
+					if !existingOptions[optionRequest.ID] {
+						return nil, fmt.Errorf("question %d: option %d does not belong to this question", number, optionRequest.ID)
+					}
+					delete(existingOptions, optionRequest.ID)
+				}
+				if optionRequest.IsCorrect {
+					correctCount++
+				}
+				question.Options = append(question.Options, models.QuizOption{
+					ID:        optionRequest.ID,
+					Text:      optionRequest.Text,
+					IsCorrect: optionRequest.IsCorrect,
+					Order:     j + 1,
+				})
+			}
+			if questionRequest.Type == models.QuestionTypeSingleChoice && correctCount != 1 {
+				return nil, fmt.Errorf("question %d: exactly one correct option is required", number)
+			}
+			if correctCount == 0 {
+				return nil, fmt.Errorf("question %d: at least one correct option is required", number)
+			}
+		case models.QuestionTypeNumeric:
+			if questionRequest.CorrectNumber == nil {
+				return nil, fmt.Errorf("question %d: correct number is required", number)
+			}
+			if questionRequest.Tolerance < 0 {
+				return nil, fmt.Errorf("question %d: tolerance must not be negative", number)
+			}
+			question.CorrectNumber = questionRequest.CorrectNumber
+			question.Tolerance = questionRequest.Tolerance
+		case models.QuestionTypeFreeText:
+			if len(questionRequest.Options) > 0 {
+				return nil, fmt.Errorf("question %d: free text questions have no options", number)
+			}
+		default:
+			return nil, fmt.Errorf("question %d: invalid question type", number)
+		}
+
+		questions = append(questions, question)
+	}
+
+	return questions, nil
+}
+
+func toQuizResponse(quiz models.Quiz, includeAnswers bool) schemas.QuizResponse {
+	quizResponse := schemas.QuizResponse{
+		ID:             quiz.ID,
+		LessonID:       quiz.LessonID,
+		Title:          quiz.Title,
+		Description:    quiz.Description,
+		PassingScore:   quiz.PassingScore,
+		QuestionsCount: len(quiz.Questions),
+		CreatedBy:      quiz.CreatedBy,
+		CreatedAt:      quiz.CreatedAt,
+	}
+
+	for _, question := range quiz.Questions {
+		questionResponse := schemas.QuizQuestionResponse{
+			ID:     question.ID,
+			Type:   question.Type,
+			Text:   question.Text,
+			Points: question.Points,
+			Order:  question.Order,
+		}
+		for _, option := range question.Options {
+			optionResponse := schemas.QuizOptionResponse{
+				ID:   option.ID,
+				Text: option.Text,
+			}
+			if includeAnswers {
+				isCorrect := option.IsCorrect
+				optionResponse.IsCorrect = &isCorrect
+			}
+			questionResponse.Options = append(questionResponse.Options, optionResponse)
+		}
+		if includeAnswers && question.Type == models.QuestionTypeNumeric {
+			tolerance := question.Tolerance
+			questionResponse.CorrectNumber = question.CorrectNumber
+			questionResponse.Tolerance = &tolerance
+		}
+		quizResponse.Questions = append(quizResponse.Questions, questionResponse)
+	}
+
+	return quizResponse
+}
+
+func toQuizAttemptResponse(attempt models.QuizAttempt) schemas.QuizAttemptResponse {
+	attemptResponse := schemas.QuizAttemptResponse{
+		ID:            attempt.ID,
+		QuizID:        attempt.QuizID,
+		Score:         attempt.Score,
+		MaxScore:      attempt.MaxScore,
+		Percent:       quizPercent(attempt.Score, attempt.MaxScore),
+		Passed:        attempt.Passed,
+		PendingReview: attempt.PendingReview,
+		SubmittedAt:   attempt.SubmittedAt,
+	}
+
+	for _, answer := range attempt.Answers {
+		attemptResponse.Answers = append(attemptResponse.Answers, schemas.QuizAnswerResult{
+			QuestionID:    answer.QuestionID,
+			IsCorrect:     answer.IsCorrect,
+			PointsAwarded: answer.PointsAwarded,
+		})
+	}
+
+	return attemptResponse
+}
+
+// quizPercent returns the score as a percentage of the max score, 0 when nothing
+// was graded
+func quizPercent(score, maxScore int) int {
+	if maxScore == 0 {
+		return 0
+	}
+	return score * 100 / maxScore
+}
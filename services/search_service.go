@@ -0,0 +1,89 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"strings"
+	"unicode/utf8"
+	"web/repos"
+	"web/schemas"
+)
+
+const maxSearchQueryLength = 200
+
+type SearchServiceInterface interface {
+	Search(params schemas.SearchParams) ([]schemas.SearchHit, schemas.PaginationMeta, error)
+}
+
+var _ SearchServiceInterface = (*SearchService)(nil)
+
+type SearchService struct {
+	repo repos.SearchRepositoryInterface
+}
+
+func NewSearchService(repo repos.SearchRepositoryInterface) *SearchService {
+	return &SearchService{
+		repo: repo,
+	}
+}
+
+// Search runs a full-text search and links every hit to its API path
+func (s *SearchService) Search(params schemas.SearchParams) ([]schemas.SearchHit, schemas.PaginationMeta, error) {
+	params.Query = strings.TrimSpace(params.Query)
+	if params.Query == "" {
+		return nil, schemas.PaginationMeta{}, errors.New("search query is required")
+	}
+	if utf8.RuneCountInString(params.Query) > maxSearchQueryLength {
+		return nil, schemas.PaginationMeta{}, fmt.Errorf("search query must not exceed %d characters", maxSearchQueryLength)
+	}
+	for _, hitType := range params.Types {
+		switch hitType {
+		case schemas.SearchTypeCourse, schemas.SearchTypeChapter, schemas.SearchTypeLesson:
+		default:
+			return nil, schemas.PaginationMeta{}, fmt.Errorf("invalid search type: %s", hitType)
+		}
+	}
+	if params.Page < 1 {
+		params.Page = 1
+	}
+	if params.Limit < 1 {
+		params.Limit = schemas.DefaultPageLimit
+	}
+
+	hits, total, err := s.repo.Search(params)
+	if err != nil {
+		return nil, schemas.PaginationMeta{}, err
+	}
+
+	for i := range hits {
+		hits[i].Snippet = highlightSnippet(hits[i].Snippet)
+		hits[i].URL = searchHitURL(hits[i])
+	}
+
+	meta := NewPaginationMeta(schemas.ListParams{Page: params.Page, Limit: params.Limit}, total)
+	return hits, meta, nil
+}
+
+// snippetHighlighter turns the match markers of an escaped snippet into <mark> tags
+var snippetHighlighter = strings.NewReplacer(
+	schemas.SearchHighlightStart, "<mark>",
+	schemas.SearchHighlightStop, "</mark>",
+)
+
+// highlightSnippet escapes a snippet of raw course content so it is safe to render as HTML,
+// the matched words are the only markup left
+func highlightSnippet(snippet string) string {
+	return snippetHighlighter.Replace(html.EscapeString(snippet))
+}
+
+func searchHitURL(hit schemas.SearchHit) string {
+	url := fmt.Sprintf("/api/v1/courses/%d", hit.Path.CourseID)
+	if hit.Path.ChapterID != nil {
+		url += fmt.Sprintf("/chapters/%d", *hit.Path.ChapterID)
+	}
+	if hit.Path.LessonID != nil {
+		url += fmt.Sprintf("/lessons/%d", *hit.Path.LessonID)
+	}
+	return url
+}
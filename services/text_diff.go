@@ -0,0 +1,100 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"web/schemas"
+)
+
+// maxDiffEdits is the largest number of inserted and deleted lines diffLines computes.
+// The trace kept for backtracking grows with the square of the edits, so the limit
+// bounds its memory to a few megabytes whatever the size of the texts.
+const maxDiffEdits = 1000
+
+var errDiffTooLarge = errors.New("revisions differ in too many lines to compare")
+
+// diffLines returns a line-level diff that turns oldText into newText. It uses the
+// Myers algorithm, so the result is a shortest edit script with deletions listed
+// before insertions within a changed block. Texts that differ in more than
+// maxDiffEdits lines are rejected with errDiffTooLarge.
+func diffLines(oldText, newText string) ([]schemas.LessonDiffLine, error) {
+	a, b := splitLines(oldText), splitLines(newText)
+	n, m := len(a), len(b)
+
+	// trace[d] holds the furthest x reached on every diagonal k in [-d-1, d+1]
+	// before round d, which is all backtracking needs
+	v := make([]int, 2*(n+m)+3)
+	offset := n + m + 1
+	var trace [][]int
+
+search:
+	for d := 0; d <= n+m; d++ {
+		if d > maxDiffEdits {
+			return nil, errDiffTooLarge
+		}
+		trace = append(trace, append([]int(nil), v[offset-d-1:offset+d+2]...))
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[offset+k] = x
+			if x >= n && y >= m {
+				break search
+			}
+		}
+	}
+
+	// Walk the trace back from the end and collect the edit script in reverse
+	var reversed []schemas.LessonDiffLine
+	x, y := n, m
+	for d := len(trace) - 1; d >= 0; d-- {
+		frontier := func(k int) int { return trace[d][k+d+1] }
+		k := x - y
+
+		prevK := k - 1
+		if k == -d || (k != d && frontier(k-1) < frontier(k+1)) {
+			prevK = k + 1
+		}
+		prevX := frontier(prevK)
+		prevY := prevX - prevK
+
+		for x > prevX && y > prevY {
+			reversed = append(reversed, schemas.LessonDiffLine{Op: schemas.DiffOpEqual, OldLine: x, NewLine: y, Text: a[x-1]})
+			x--
+			y--
+		}
+		if d == 0 {
+			break
+		}
+		if x == prevX {
+			reversed = append(reversed, schemas.LessonDiffLine{Op: schemas.DiffOpInsert, NewLine: y, Text: b[y-1]})
+			y--
+		} else {
+			reversed = append(reversed, schemas.LessonDiffLine{Op: schemas.DiffOpDelete, OldLine: x, Text: a[x-1]})
+			x--
+		}
+	}
+
+	lines := make([]schemas.LessonDiffLine, 0, len(reversed))
+	for i := len(reversed) - 1; i >= 0; i-- {
+		lines = append(lines, reversed[i])
+	}
+	return lines, nil
+}
+
+// splitLines splits text into lines, ignoring a trailing newline and Windows line endings
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	return strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+}
@@ -0,0 +1,125 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+	"web/models"
+	"web/repos"
+	"web/schemas"
+)
+
+// TrashResourceTypes are the resources that are moved to the trash when they are deleted
+var TrashResourceTypes = []string{ResourceCourse, ResourceChapter, ResourceLesson, ResourceAttachment}
+
+// ObjectRemover removes stored objects, implemented by AttachmentService
+type ObjectRemover interface {
+	RemoveObject(objectName string) error
+}
+
+type TrashServiceInterface interface {
+	GetAll(params schemas.TrashParams, actor Actor) ([]schemas.TrashItem, schemas.PaginationMeta, error)
+	Restore(resourceType string, id uint, actor Actor) error
+	Purge() (int, error)
+}
+
+var _ TrashServiceInterface = (*TrashService)(nil)
+
+// TrashService lists and restores deleted courses, chapters, lessons and attachments and
+// deletes them for good once the retention period has passed. The files of deleted
+// attachments are kept in storage until then.
+type TrashService struct {
+	repo      repos.TrashRepositoryInterface
+	policy    PolicyServiceInterface
+	storage   ObjectRemover
+	retention time.Duration
+}
+
+func NewTrashService(repo repos.TrashRepositoryInterface, policy PolicyServiceInterface, storage ObjectRemover, retention time.Duration) *TrashService {
+	return &TrashService{
+		repo:      repo,
+		policy:    policy,
+		storage:   storage,
+		retention: retention,
+	}
+}
+
+// GetAll lists the deleted resources of a type the actor may restore, newest first. Admins
+// list the whole trash of the tenant of params, other users the trash of the courses they
+// manage.
+func (s *TrashService) GetAll(params schemas.TrashParams, actor Actor) ([]schemas.TrashItem, schemas.PaginationMeta, error) {
+	if !isTrashResourceType(params.ResourceType) {
+		return nil, schemas.PaginationMeta{}, errors.New("invalid resource type")
+	}
+
+	if !s.policy.Can(actor, ActionRestore, params.ResourceType, nil) {
+		params.ManagedBy = &actor.UserID
+		params.ManagerRoles = s.policy.MemberRoles(ActionRestore, params.ResourceType)
+	}
+
+	items, total, err := s.repo.GetAll(params)
+	if err != nil {
+		return nil, schemas.PaginationMeta{}, err
+	}
+	for i := range items {
+		items[i].PurgeAt = items[i].DeletedAt.Add(s.retention)
+	}
+
+	return items, NewPaginationMeta(schemas.ListParams{Page: params.Page, Limit: params.Limit}, total), nil
+}
+
+// Restore takes a deleted resource out of the trash together with the content that was
+// deleted with it. Content of a course, chapter or lesson that is still in the trash cannot
+// be restored on its own.
+func (s *TrashService) Restore(resourceType string, id uint, actor Actor) error {
+	if !isTrashResourceType(resourceType) {
+		return errors.New("invalid resource type")
+	}
+
+	item, err := s.repo.GetByID(resourceType, id)
+	if err != nil {
+		return err
+	}
+
+	course := models.Course{ID: item.CourseID, CreatedBy: item.CourseCreatedBy, OrganizationID: item.OrganizationID}
+	if err := s.policy.AuthorizeLoadedCourse(actor, ActionRestore, resourceType, course); err != nil {
+		return err
+	}
+
+	if item.ParentDeleted {
+		return errors.New("parent is deleted")
+	}
+
+	return s.repo.Restore(resourceType, id)
+}
+
+// Purge deletes the content that has been in the trash for longer than the retention period
+// and removes the files of its attachments. It returns the number of removed files, files
+// that cannot be removed are reported in the error.
+func (s *TrashService) Purge() (int, error) {
+	objectNames, err := s.repo.Purge(time.Now().Add(-s.retention))
+	if err != nil {
+		return 0, err
+	}
+
+	removed := 0
+	var removeErrs []error
+	for _, objectName := range objectNames {
+		if err := s.storage.RemoveObject(objectName); err != nil {
+			removeErrs = append(removeErrs, fmt.Errorf("%s: %w", objectName, err))
+			continue
+		}
+		removed++
+	}
+
+	return removed, errors.Join(removeErrs...)
+}
+
+func isTrashResourceType(resourceType string) bool {
+	for _, t := range TrashResourceTypes {
+		if t == resourceType {
+			return true
+		}
+	}
+	return false
+}
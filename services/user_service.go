@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"golang.org/x/crypto/bcrypt"
 	"strings"
+	"web/config"
 	"web/models"
 	"web/repos"
 	"web/schemas"
@@ -13,7 +14,7 @@ import (
 
 type UserServiceInterface interface {
 	RegisterUser(userDTO schemas.RegisterUserRequest) (schemas.UserResponse, error)
-	ClaimUserUserFromToken(claims *KeycloakClaims) (schemas.UserResponse, error)
+	ClaimUserUserFromToken(claims *IdentityClaims) (schemas.UserResponse, error)
 	AdminCreateUser(userDTO schemas.AdminCreateUserRequest, authService *AuthService) (schemas.UserInfoResponse, error)
 	UpdateUser(userID uint, userDTO schemas.UpdateUserRequest) (schemas.UserResponse, error)
 	UpdatePassword(userID uint, passwordDTO schemas.UpdatePasswordRequest) error
@@ -23,11 +24,15 @@ var _ UserServiceInterface = (*UserService)(nil)
 
 type UserService struct {
 	repo repos.UserRepositoryInterface
+	// localPasswords is set when the local provider authenticates with the stored
+	// passwords, other providers keep the passwords of their users themselves
+	localPasswords bool
 }
 
-func NewUserService(repo repos.UserRepositoryInterface) *UserService {
+func NewUserService(appConfig *config.AppConfig, repo repos.UserRepositoryInterface) *UserService {
 	return &UserService{
-		repo: repo,
+		repo:           repo,
+		localPasswords: appConfig.IdentityProvider == config.IdentityProviderLocal,
 	}
 }
 
@@ -95,14 +100,14 @@ func (s *UserService) RegisterUser(userDTO schemas.RegisterUserRequest) (schemas
 	return userResponse, nil
 }
 
-func (s *UserService) ClaimUserUserFromToken(claims *KeycloakClaims) (schemas.UserResponse, error) {
-	if claims.PreferredUsername == "" {
+func (s *UserService) ClaimUserUserFromToken(claims *IdentityClaims) (schemas.UserResponse, error) {
+	if claims.Username == "" {
 		return schemas.UserResponse{}, errors.New("username is required")
 	}
 	if claims.Email == "" {
 		return schemas.UserResponse{}, errors.New("email is required")
 	}
-	user, err := s.repo.GetBySub(claims.Sub)
+	user, err := s.repo.GetBySub(claims.Subject)
 	if err == nil {
 		userResponse := schemas.UserResponse{
 			ID:        user.ID,
@@ -116,8 +121,8 @@ func (s *UserService) ClaimUserUserFromToken(claims *KeycloakClaims) (schemas.Us
 	}
 
 	roles := "ROLE_USER"
-	if len(claims.RealmAccess.Roles) > 0 {
-		roles = strings.Join(claims.RealmAccess.Roles, ",")
+	if len(claims.Roles) > 0 {
+		roles = strings.Join(claims.Roles, ",")
 	}
 
 	randomPassword := make([]byte, 32)
@@ -132,11 +137,11 @@ func (s *UserService) ClaimUserUserFromToken(claims *KeycloakClaims) (schemas.Us
 	}
 
 	user = models.User{
-		Username: claims.PreferredUsername,
+		Username: claims.Username,
 		Email:    claims.Email,
 		Password: string(hashedPassword),
 		Roles:    roles,
-		Sub:      claims.Sub,
+		Sub:      claims.Subject,
 	}
 
 	user, err = s.repo.Create(user)
@@ -170,9 +175,9 @@ func (s *UserService) AdminCreateUser(userDTO schemas.AdminCreateUserRequest, au
 		return schemas.UserInfoResponse{}, errors.New("roles are required")
 	}
 
-	err := authService.RegisterUserInKeycloak(userDTO.Username, userDTO.Email, userDTO.Password, userDTO.Roles)
+	err := authService.RegisterUser(userDTO.Username, userDTO.Email, userDTO.Password, userDTO.Roles)
 	if err != nil {
-		return schemas.UserInfoResponse{}, fmt.Errorf("failed to register user in Keycloak: %w", err)
+		return schemas.UserInfoResponse{}, fmt.Errorf("failed to register user in the identity provider: %w", err)
 	}
 
 	userResponse := schemas.UserInfoResponse{
@@ -228,7 +233,12 @@ func (s *UserService) UpdateUser(userID uint, userDTO schemas.UpdateUserRequest)
 	return userResponse, nil
 }
 
+// UpdatePassword replaces the password after checking the current one. The sessions of the
+// user are revoked with the old password. Only the local provider uses the stored password.
 func (s *UserService) UpdatePassword(userID uint, passwordDTO schemas.UpdatePasswordRequest) error {
+	if !s.localPasswords {
+		return errors.New("password is managed by the identity provider")
+	}
 	if passwordDTO.CurrentPassword == "" {
 		return errors.New("current password is required")
 	}
@@ -239,8 +249,14 @@ func (s *UserService) UpdatePassword(userID uint, passwordDTO schemas.UpdatePass
 		return errors.New("new password must be at least 8 characters")
 	}
 
-	var user models.User
-	user.ID = userID
+	user, err := s.repo.GetByID(userID)
+	if err != nil {
+		return err
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(passwordDTO.CurrentPassword)); err != nil {
+		return errors.New("current password is incorrect")
+	}
 
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(passwordDTO.NewPassword), bcrypt.DefaultCost)
 	if err != nil {
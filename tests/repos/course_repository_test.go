@@ -0,0 +1,41 @@
+package repos_test
+
+import (
+	"strings"
+	"testing"
+	"web/models"
+	"web/repos"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCourseRepository_Clone_SkipsBlocksOfAttachmentsNotCopied(t *testing.T) {
+	db, recorder := dryRunDB(t)
+	attachmentID, trashedID := uint(5), uint(6)
+	source := models.Course{ID: 1, Name: "Go Basics", Chapters: []models.Chapter{{ID: 2, Name: "Chapter 1", Lessons: []models.Lesson{{
+		ID:          3,
+		Name:        "Lesson 1",
+		Attachments: []models.Attachment{{ID: attachmentID, Name: "slides.pdf", URL: "lesson-3/slides.pdf"}},
+		Blocks: []models.LessonBlock{
+			{ID: 7, Type: models.LessonBlockTypeMarkdown, Text: "Intro", Position: 1},
+			{ID: 8, Type: models.LessonBlockTypeAttachment, AttachmentID: &trashedID, Position: 2},
+			{ID: 9, Type: models.LessonBlockTypeAttachment, AttachmentID: &attachmentID, Position: 3},
+		},
+	}}}}}
+
+	// The clone stops at the first scan in dry run mode, after the blocks are written
+	_, _ = repos.NewCourseRepository(db).Clone(source, repos.CloneOptions{Name: "Go Basics (copy)", CreatedBy: 4})
+
+	var blockInserts []string
+	for _, sql := range recorder.statements {
+		if strings.HasPrefix(sql, `INSERT INTO "lesson_block"`) {
+			blockInserts = append(blockInserts, sql)
+		}
+	}
+	// The block of the trashed attachment is skipped and the others close the gap
+	if assert.Len(t, blockInserts, 2) {
+		assert.Contains(t, blockInserts[0], "VALUES (0,1,'markdown','Intro',")
+		assert.Contains(t, blockInserts[1], "VALUES (0,2,'attachment',")
+		assert.NotContains(t, blockInserts[1], "NULL,4)")
+	}
+}
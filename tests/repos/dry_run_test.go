@@ -0,0 +1,74 @@
+package repos_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// sqlRecorder keeps the statements gorm builds, which in dry run mode are never sent
+type sqlRecorder struct {
+	logger.Interface
+	statements []string
+}
+
+func (r *sqlRecorder) Trace(_ context.Context, _ time.Time, fc func() (string, int64), _ error) {
+	sql, _ := fc()
+	r.statements = append(r.statements, sql)
+}
+
+// dryRunConnPool stands in for the database connection, in dry run mode gorm only needs
+// it to begin and end transactions
+type dryRunConnPool struct{}
+
+func (*dryRunConnPool) PrepareContext(context.Context, string) (*sql.Stmt, error) {
+	return nil, errors.New("dry run")
+}
+
+func (*dryRunConnPool) ExecContext(context.Context, string, ...interface{}) (sql.Result, error) {
+	return nil, errors.New("dry run")
+}
+
+func (*dryRunConnPool) QueryContext(context.Context, string, ...interface{}) (*sql.Rows, error) {
+	return nil, errors.New("dry run")
+}
+
+func (*dryRunConnPool) QueryRowContext(context.Context, string, ...interface{}) *sql.Row {
+	return nil
+}
+
+func (*dryRunConnPool) BeginTx(context.Context, *sql.TxOptions) (gorm.ConnPool, error) {
+	return &dryRunTx{}, nil
+}
+
+// dryRunTx is a transaction of dryRunConnPool
+type dryRunTx struct {
+	dryRunConnPool
+}
+
+func (*dryRunTx) Commit() error {
+	return nil
+}
+
+func (*dryRunTx) Rollback() error {
+	return nil
+}
+
+// dryRunDB returns a database that records the SQL of every query instead of running it
+func dryRunDB(t *testing.T) (*gorm.DB, *sqlRecorder) {
+	recorder := &sqlRecorder{Interface: logger.Discard}
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: &dryRunConnPool{}}), &gorm.Config{
+		DryRun:               true,
+		DisableAutomaticPing: true,
+		Logger:               recorder,
+	})
+	assert.NoError(t, err)
+	return db, recorder
+}
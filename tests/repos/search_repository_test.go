@@ -0,0 +1,39 @@
+package repos_test
+
+import (
+	"strings"
+	"testing"
+	"web/repos"
+	"web/schemas"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSearchRepository_Search_EnrolledLearnerOnlySeesPublishedCourses(t *testing.T) {
+	db, recorder := dryRunDB(t)
+
+	// Scanning fails in dry run mode, only the recorded SQL matters
+	hits, total, _ := repos.NewSearchRepository(db).Search(schemas.SearchParams{
+		Query:  "goroutines",
+		Types:  []string{schemas.SearchTypeChapter, schemas.SearchTypeLesson},
+		UserID: 3,
+		Page:   1,
+		Limit:  10,
+	})
+
+	assert.Empty(t, hits)
+	assert.Zero(t, total)
+	assert.NotEmpty(t, recorder.statements)
+	for _, sql := range recorder.statements {
+		// Every chapter and lesson select lets owners and members through whatever the
+		// status, and enrolled learners only while the course is published
+		selects := strings.Split(sql, "UNION ALL")
+		assert.Len(t, selects, 2)
+		for _, selectSQL := range selects {
+			assert.Contains(t, selectSQL, "course.created_by = 3 OR EXISTS (\n\tSELECT 1 FROM course_member")
+
+			enrollment := selectSQL[strings.Index(selectSQL, "FROM enrollment"):]
+			assert.Contains(t, enrollment, "AND course.status = 'published'")
+		}
+	}
+}
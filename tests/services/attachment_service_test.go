@@ -0,0 +1,147 @@
+package services_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"web/config"
+	"web/mocks/repos"
+	"web/models"
+	"web/services"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newFakeMinioConfig points the MinIO client at a server that reports the bucket as existing,
+// so the attachment service can be created without a running MinIO
+func newFakeMinioConfig(t *testing.T) *config.AppConfig {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/xml")
+		_, _ = w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?><LocationConstraint>us-east-1</LocationConstraint>`))
+	}))
+	t.Cleanup(server.Close)
+
+	return &config.AppConfig{
+		MinioEndpoint:  strings.TrimPrefix(server.URL, "http://"),
+		MinioAccessKey: "access",
+		MinioSecretKey: "secret",
+		MinioBucket:    "attachments",
+	}
+}
+
+func TestAttachmentService_HasAccessToLesson(t *testing.T) {
+	ownerID := uint(2)
+	orgID, otherOrgID := uint(10), uint(20)
+
+	testCases := []struct {
+		name           string
+		actor          services.Actor
+		lessonID       uint
+		mockSetup      func(lessonRepo *mocks.LessonRepositoryInterface, enrollmentRepo *mocks.EnrollmentRepositoryInterface, courseRepo *mocks.CourseRepositoryInterface, memberRepo *mocks.CourseMemberRepositoryInterface)
+		expectedAccess bool
+		expectedError  string
+	}{
+		{
+			name:     "Admin",
+			actor:    services.Actor{UserID: 8, IsAdmin: true, OrganizationID: &orgID},
+			lessonID: 4,
+			mockSetup: func(lessonRepo *mocks.LessonRepositoryInterface, enrollmentRepo *mocks.EnrollmentRepositoryInterface, courseRepo *mocks.CourseRepositoryInterface, memberRepo *mocks.CourseMemberRepositoryInterface) {
+				lessonRepo.On("GetCourseID", uint(4)).Return(uint(1), nil)
+				courseRepo.On("GetByID", uint(1)).Return(models.Course{ID: 1, CreatedBy: &ownerID, OrganizationID: &orgID}, nil)
+			},
+			expectedAccess: true,
+		},
+		{
+			name:     "Teacher",
+			actor:    services.Actor{UserID: 6, IsTeacher: true, OrganizationID: &orgID},
+			lessonID: 4,
+			mockSetup: func(lessonRepo *mocks.LessonRepositoryInterface, enrollmentRepo *mocks.EnrollmentRepositoryInterface, courseRepo *mocks.CourseRepositoryInterface, memberRepo *mocks.CourseMemberRepositoryInterface) {
+				lessonRepo.On("GetCourseID", uint(4)).Return(uint(1), nil)
+				courseRepo.On("GetByID", uint(1)).Return(models.Course{ID: 1, CreatedBy: &ownerID, OrganizationID: &orgID}, nil)
+			},
+			expectedAccess: true,
+		},
+		{
+			name:     "Course Owner",
+			actor:    services.Actor{UserID: ownerID, OrganizationID: &orgID},
+			lessonID: 4,
+			mockSetup: func(lessonRepo *mocks.LessonRepositoryInterface, enrollmentRepo *mocks.EnrollmentRepositoryInterface, courseRepo *mocks.CourseRepositoryInterface, memberRepo *mocks.CourseMemberRepositoryInterface) {
+				lessonRepo.On("GetCourseID", uint(4)).Return(uint(1), nil)
+				courseRepo.On("GetByID", uint(1)).Return(models.Course{ID: 1, CreatedBy: &ownerID, OrganizationID: &orgID}, nil)
+			},
+			expectedAccess: true,
+		},
+		{
+			name:     "Enrolled Learner",
+			actor:    services.Actor{UserID: 3, OrganizationID: &orgID},
+			lessonID: 4,
+			mockSetup: func(lessonRepo *mocks.LessonRepositoryInterface, enrollmentRepo *mocks.EnrollmentRepositoryInterface, courseRepo *mocks.CourseRepositoryInterface, memberRepo *mocks.CourseMemberRepositoryInterface) {
+				lessonRepo.On("GetCourseID", uint(4)).Return(uint(1), nil)
+				courseRepo.On("GetByID", uint(1)).Return(models.Course{ID: 1, Status: models.CourseStatusPublished, CreatedBy: &ownerID, OrganizationID: &orgID}, nil)
+				memberRepo.On("GetRole", uint(1), uint(3)).Return("", nil)
+				enrollmentRepo.On("Exists", uint(3), uint(1)).Return(true, nil)
+			},
+			expectedAccess: true,
+		},
+		{
+			name:     "Not Enrolled",
+			actor:    services.Actor{UserID: 4, OrganizationID: &orgID},
+			lessonID: 4,
+			mockSetup: func(lessonRepo *mocks.LessonRepositoryInterface, enrollmentRepo *mocks.EnrollmentRepositoryInterface, courseRepo *mocks.CourseRepositoryInterface, memberRepo *mocks.CourseMemberRepositoryInterface) {
+				lessonRepo.On("GetCourseID", uint(4)).Return(uint(1), nil)
+				courseRepo.On("GetByID", uint(1)).Return(models.Course{ID: 1, Status: models.CourseStatusPublished, CreatedBy: &ownerID, OrganizationID: &orgID}, nil)
+				memberRepo.On("GetRole", uint(1), uint(4)).Return("", nil)
+				enrollmentRepo.On("Exists", uint(4), uint(1)).Return(false, nil)
+			},
+			expectedAccess: false,
+		},
+		{
+			name:     "Course In Another Organization",
+			actor:    services.Actor{UserID: 6, IsTeacher: true, OrganizationID: &otherOrgID},
+			lessonID: 4,
+			mockSetup: func(lessonRepo *mocks.LessonRepositoryInterface, enrollmentRepo *mocks.EnrollmentRepositoryInterface, courseRepo *mocks.CourseRepositoryInterface, memberRepo *mocks.CourseMemberRepositoryInterface) {
+				lessonRepo.On("GetCourseID", uint(4)).Return(uint(1), nil)
+				courseRepo.On("GetByID", uint(1)).Return(models.Course{ID: 1, CreatedBy: &ownerID, OrganizationID: &orgID}, nil)
+			},
+			expectedError: "lesson not found",
+		},
+		{
+			name:     "Unknown Lesson",
+			actor:    services.Actor{UserID: 3, OrganizationID: &orgID},
+			lessonID: 999,
+			mockSetup: func(lessonRepo *mocks.LessonRepositoryInterface, enrollmentRepo *mocks.EnrollmentRepositoryInterface, courseRepo *mocks.CourseRepositoryInterface, memberRepo *mocks.CourseMemberRepositoryInterface) {
+				lessonRepo.On("GetCourseID", uint(999)).Return(uint(0), errors.New("lesson not found"))
+			},
+			expectedError: "lesson not found",
+		},
+	}
+
+	appConfig := newFakeMinioConfig(t)
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			lessonRepo := mocks.NewLessonRepositoryInterface(t)
+			enrollmentRepo := mocks.NewEnrollmentRepositoryInterface(t)
+			courseRepo := mocks.NewCourseRepositoryInterface(t)
+			memberRepo := mocks.NewCourseMemberRepositoryInterface(t)
+			tc.mockSetup(lessonRepo, enrollmentRepo, courseRepo, memberRepo)
+
+			enrollmentService := services.NewEnrollmentService(enrollmentRepo, courseRepo, memberRepo)
+			service, err := services.NewAttachmentService(appConfig, nil, lessonRepo, enrollmentService)
+			require.NoError(t, err)
+
+			hasAccess, err := service.HasAccessToLesson(tc.actor, tc.lessonID)
+
+			if tc.expectedError != "" {
+				assert.EqualError(t, err, tc.expectedError)
+				assert.False(t, hasAccess)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedAccess, hasAccess)
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package services_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+	"web/mocks/repos"
+	"web/models"
+	"web/schemas"
+	"web/services"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuditService_Record(t *testing.T) {
+	repo := new(mocks.AuditLogRepositoryInterface)
+	service := services.NewAuditService(repo)
+
+	actorID, courseID := uint(1), uint(7)
+	entry := models.AuditLog{
+		ActorID:      &actorID,
+		Action:       services.ActionUpdate,
+		ResourceType: services.ResourceCourse,
+		ResourceID:   &courseID,
+		Before:       json.RawMessage(`{"id":7,"name":"Go"}`),
+		After:        json.RawMessage(`{"id":7,"name":"Go Basics"}`),
+		RequestID:    "4f8c2a9e1b7d3c6a",
+		Method:       "PUT",
+		Path:         "/api/v1/courses/7",
+	}
+	repo.On("Create", entry).Return(entry, nil)
+
+	assert.NoError(t, service.Record(entry))
+	repo.AssertExpectations(t)
+
+	assert.EqualError(t, service.Record(models.AuditLog{ResourceType: services.ResourceCourse}), "audit action and resource type are required")
+	assert.EqualError(t, service.Record(models.AuditLog{Action: services.ActionDelete}), "audit action and resource type are required")
+	repo.AssertNumberOfCalls(t, "Create", 1)
+}
+
+func TestAuditService_Snapshot(t *testing.T) {
+	repo := new(mocks.AuditLogRepositoryInterface)
+	service := services.NewAuditService(repo)
+
+	repo.On("Snapshot", services.ResourceLesson, uint(3)).Return(json.RawMessage(`{"id":3}`), nil)
+	repo.On("Snapshot", services.ResourceLesson, uint(4)).Return(json.RawMessage(nil), nil)
+	repo.On("Snapshot", "quiz", uint(5)).Return(json.RawMessage(nil), errors.New(`unknown audit resource type "quiz"`))
+
+	snapshot, err := service.Snapshot(services.ResourceLesson, 3)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"id":3}`, string(snapshot))
+
+	// Resources that do not exist have no state
+	snapshot, err = service.Snapshot(services.ResourceLesson, 4)
+	assert.NoError(t, err)
+	assert.Nil(t, snapshot)
+
+	_, err = service.Snapshot("quiz", 5)
+	assert.Error(t, err)
+}
+
+func TestAuditService_GetAll(t *testing.T) {
+	repo := new(mocks.AuditLogRepositoryInterface)
+	service := services.NewAuditService(repo)
+
+	from := time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(24 * time.Hour)
+	params := schemas.AuditLogParams{ResourceType: services.ResourceCourse, From: &from, To: &to, Page: 2, Limit: 2}
+	repo.On("GetAll", params).Return([]models.AuditLog{{ID: 3}, {ID: 2}}, int64(5), nil)
+
+	entries, meta, err := service.GetAll(params)
+	assert.NoError(t, err)
+	assert.Len(t, entries, 2)
+	assert.Equal(t, schemas.PaginationMeta{Page: 2, Limit: 2, Total: 5, TotalPages: 3, HasNext: true}, meta)
+
+	_, _, err = service.GetAll(schemas.AuditLogParams{From: &to, To: &from, Page: 1, Limit: 20})
+	assert.EqualError(t, err, "invalid time range")
+	repo.AssertNumberOfCalls(t, "GetAll", 1)
+}
@@ -0,0 +1,311 @@
+package services_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+	"time"
+	"web/config"
+	"web/mocks/repos"
+	"web/models"
+	"web/schemas"
+	"web/services"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// fakeObjectStorage keeps stored objects in memory
+type fakeObjectStorage struct {
+	objects map[string][]byte
+	err     error
+}
+
+func newFakeObjectStorage() *fakeObjectStorage {
+	return &fakeObjectStorage{objects: map[string][]byte{}}
+}
+
+func (s *fakeObjectStorage) StoreObject(objectName string, data []byte, contentType string) error {
+	if s.err != nil {
+		return s.err
+	}
+	s.objects[objectName] = data
+	return nil
+}
+
+func (s *fakeObjectStorage) GetObject(objectName string) (io.ReadCloser, error) {
+	data, ok := s.objects[objectName]
+	if !ok {
+		return nil, errors.New("file not found in MinIO")
+	}
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+func (s *fakeObjectStorage) RemoveObject(objectName string) error {
+	delete(s.objects, objectName)
+	return nil
+}
+
+func certificateFromCreate(certificate models.Certificate) models.Certificate {
+	certificate.ID = 1
+	certificate.User = models.User{ID: certificate.UserID, Username: "johndoe"}
+	certificate.Course = models.Course{ID: *certificate.CourseID, Name: certificate.CourseName}
+	return certificate
+}
+
+func TestCertificateService_ClaimCertificate(t *testing.T) {
+	testCases := []struct {
+		name          string
+		percent       int
+		summary       schemas.ProgressSummary
+		mockSetup     func(certificateRepo *mocks.CertificateRepositoryInterface)
+		expectedError error
+	}{
+		{
+			name:    "Completed Course",
+			percent: 100,
+			summary: schemas.ProgressSummary{CompletedLessons: 4, TotalLessons: 4, Percent: 100},
+			mockSetup: func(certificateRepo *mocks.CertificateRepositoryInterface) {
+				var created models.Certificate
+				certificateRepo.On("GetByUserAndCourse", uint(1), uint(1)).Return(models.Certificate{}, errors.New("certificate not found"))
+				certificateRepo.On("Create", mock.MatchedBy(func(certificate models.Certificate) bool {
+					created = certificate
+					return certificate.UserID == 1 && *certificate.CourseID == 1 && certificate.CourseName == "Go (Basics)" && certificate.IssuedBy == nil &&
+						len(certificate.Code) == 19 && certificate.ObjectName == "certificates/"+certificate.Code+".pdf"
+				})).Return(func(certificate models.Certificate) (models.Certificate, error) {
+					return certificateFromCreate(certificate), nil
+				})
+				certificateRepo.On("GetByCode", mock.Anything).Return(func(code string) (models.Certificate, error) {
+					return certificateFromCreate(created), nil
+				})
+			},
+		},
+		{
+			name:    "Configured Threshold Met",
+			percent: 75,
+			summary: schemas.ProgressSummary{CompletedLessons: 3, TotalLessons: 4, Percent: 75},
+			mockSetup: func(certificateRepo *mocks.CertificateRepositoryInterface) {
+				certificateRepo.On("GetByUserAndCourse", uint(1), uint(1)).Return(models.Certificate{}, errors.New("certificate not found"))
+				certificateRepo.On("Create", mock.Anything).Return(func(certificate models.Certificate) (models.Certificate, error) {
+					return certificateFromCreate(certificate), nil
+				})
+				certificateRepo.On("GetByCode", mock.Anything).Return(models.Certificate{ID: 1, Code: "AAAA-BBBB-CCCC-DDDD", ObjectName: "certificates/AAAA-BBBB-CCCC-DDDD.pdf"}, nil)
+			},
+		},
+		{
+			name:          "Requirements Not Met",
+			percent:       100,
+			summary:       schemas.ProgressSummary{CompletedLessons: 3, TotalLessons: 4, Percent: 75},
+			mockSetup:     func(certificateRepo *mocks.CertificateRepositoryInterface) {},
+			expectedError: errors.New("course completion requirements not met"),
+		},
+		{
+			name:          "Course Without Lessons",
+			percent:       100,
+			summary:       schemas.ProgressSummary{},
+			mockSetup:     func(certificateRepo *mocks.CertificateRepositoryInterface) {},
+			expectedError: errors.New("course completion requirements not met"),
+		},
+		{
+			name:    "Already Issued",
+			percent: 100,
+			summary: schemas.ProgressSummary{CompletedLessons: 4, TotalLessons: 4, Percent: 100},
+			mockSetup: func(certificateRepo *mocks.CertificateRepositoryInterface) {
+				certificateRepo.On("GetByUserAndCourse", uint(1), uint(1)).Return(models.Certificate{ID: 1}, nil)
+			},
+			expectedError: errors.New("certificate already issued"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			certificateRepo := mocks.NewCertificateRepositoryInterface(t)
+			courseRepo := mocks.NewCourseRepositoryInterface(t)
+			enrollmentRepo := mocks.NewEnrollmentRepositoryInterface(t)
+			progressRepo := mocks.NewProgressRepositoryInterface(t)
+			storage := newFakeObjectStorage()
+
+			courseRepo.On("GetByID", uint(1)).Return(models.Course{ID: 1, Name: "Go (Basics)"}, nil)
+			progressRepo.On("GetCourseSummaries", uint(1), []uint{1}).Return(map[uint]schemas.ProgressSummary{1: tc.summary}, nil)
+			tc.mockSetup(certificateRepo)
+
+			appConfig := &config.AppConfig{CertificateCompletionPercent: tc.percent}
+			service := services.NewCertificateService(appConfig, certificateRepo, courseRepo, enrollmentRepo, progressRepo, nil, nil, storage)
+
+			certificate, err := service.ClaimCertificate(1, 1)
+
+			if tc.expectedError != nil {
+				assert.Error(t, err)
+				assert.Equal(t, tc.expectedError.Error(), err.Error())
+				assert.Empty(t, storage.objects)
+			} else {
+				assert.NoError(t, err)
+				assert.Len(t, storage.objects, 1)
+				pdf := storage.objects["certificates/"+certificate.Code+".pdf"]
+				assert.True(t, bytes.HasPrefix(pdf, []byte("%PDF-1.4")))
+			}
+		})
+	}
+}
+
+func TestCertificateService_IssueCertificate(t *testing.T) {
+	issuedBy := uint(2)
+
+	t.Run("Not Enrolled", func(t *testing.T) {
+		certificateRepo := mocks.NewCertificateRepositoryInterface(t)
+		courseRepo := mocks.NewCourseRepositoryInterface(t)
+		enrollmentRepo := mocks.NewEnrollmentRepositoryInterface(t)
+		progressRepo := mocks.NewProgressRepositoryInterface(t)
+
+		courseRepo.On("GetByID", uint(1)).Return(models.Course{ID: 1}, nil)
+		enrollmentRepo.On("Exists", uint(3), uint(1)).Return(false, nil)
+
+		service := services.NewCertificateService(&config.AppConfig{}, certificateRepo, courseRepo, enrollmentRepo, progressRepo, nil, nil, newFakeObjectStorage())
+
+		_, err := service.IssueCertificate(1, 3, &issuedBy)
+
+		assert.Error(t, err)
+		assert.Equal(t, "user is not enrolled in this course", err.Error())
+	})
+
+	t.Run("Storage Failure Removes Certificate", func(t *testing.T) {
+		certificateRepo := mocks.NewCertificateRepositoryInterface(t)
+		courseRepo := mocks.NewCourseRepositoryInterface(t)
+		enrollmentRepo := mocks.NewEnrollmentRepositoryInterface(t)
+		progressRepo := mocks.NewProgressRepositoryInterface(t)
+		storage := newFakeObjectStorage()
+		storage.err = errors.New("failed to upload file to MinIO")
+
+		courseRepo.On("GetByID", uint(1)).Return(models.Course{ID: 1}, nil)
+		enrollmentRepo.On("Exists", uint(3), uint(1)).Return(true, nil)
+		certificateRepo.On("GetByUserAndCourse", uint(3), uint(1)).Return(models.Certificate{}, errors.New("certificate not found"))
+		certificateRepo.On("Create", mock.MatchedBy(func(certificate models.Certificate) bool {
+			return certificate.IssuedBy != nil && *certificate.IssuedBy == issuedBy
+		})).Return(func(certificate models.Certificate) (models.Certificate, error) {
+			return certificateFromCreate(certificate), nil
+		})
+		certificateRepo.On("GetByCode", mock.Anything).Return(models.Certificate{ID: 1, Code: "AAAA-BBBB-CCCC-DDDD"}, nil)
+		certificateRepo.On("Delete", uint(1)).Return(nil)
+
+		service := services.NewCertificateService(&config.AppConfig{}, certificateRepo, courseRepo, enrollmentRepo, progressRepo, nil, nil, storage)
+
+		_, err := service.IssueCertificate(1, 3, &issuedBy)
+
+		assert.Error(t, err)
+		assert.Equal(t, "failed to upload file to MinIO", err.Error())
+	})
+}
+
+func TestCertificateService_VerifyCertificate(t *testing.T) {
+	issuedAt := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	certificateRepo := mocks.NewCertificateRepositoryInterface(t)
+	certificateRepo.On("GetByCode", "AAAA-BBBB-CCCC-DDDD").Return(models.Certificate{
+		Code:       "AAAA-BBBB-CCCC-DDDD",
+		User:       models.User{Username: "johndoe"},
+		CourseName: "Introduction to Go Programming",
+		IssuedAt:   issuedAt,
+	}, nil)
+	certificateRepo.On("GetByCode", "0000-0000-0000-0000").Return(models.Certificate{}, errors.New("certificate not found"))
+
+	service := services.NewCertificateService(&config.AppConfig{}, certificateRepo, nil, nil, nil, nil, nil, nil)
+
+	verification, err := service.VerifyCertificate(" aaaa-bbbb-cccc-dddd ")
+	assert.NoError(t, err)
+	assert.True(t, verification.Valid)
+	assert.Equal(t, "johndoe", verification.Username)
+	assert.Equal(t, "Introduction to Go Programming", verification.CourseName)
+	assert.Equal(t, issuedAt, verification.IssuedAt)
+
+	verification, err = service.VerifyCertificate("0000-0000-0000-0000")
+	assert.NoError(t, err)
+	assert.False(t, verification.Valid)
+}
+
+func TestRenderCertificatePDF(t *testing.T) {
+	pdf := services.RenderCertificatePDF("Zoë (admin)", "Go \\ Basics", "AAAA-BBBB-CCCC-DDDD", time.Now(), "/api/v1/certificates/AAAA-BBBB-CCCC-DDDD/verify")
+
+	assert.True(t, bytes.HasPrefix(pdf, []byte("%PDF-1.4\n")))
+	assert.True(t, bytes.HasSuffix(pdf, []byte("%%EOF\n")))
+	assert.Contains(t, string(pdf), "(Zo\\353 \\(admin\\)) Tj")
+	assert.Contains(t, string(pdf), "(Go \\\\ Basics) Tj")
+}
+
+func TestCertificateService_DownloadCertificate(t *testing.T) {
+	ownerID, teacherID, issuerID, orgID, otherOrgID := uint(10), uint(20), uint(2), uint(100), uint(200)
+	courseID := uint(1)
+	certificates := map[string]models.Certificate{
+		"AAAA-AAAA-AAAA-AAAA": {
+			Code: "AAAA-AAAA-AAAA-AAAA", UserID: 30, CourseID: &courseID, ObjectName: "certificates/a.pdf",
+			Course: models.Course{ID: courseID, CreatedBy: &ownerID, OrganizationID: &orgID},
+		},
+		// The course of this certificate was purged
+		"BBBB-BBBB-BBBB-BBBB": {Code: "BBBB-BBBB-BBBB-BBBB", UserID: 30, IssuedBy: &issuerID, ObjectName: "certificates/b.pdf"},
+	}
+
+	testCases := []struct {
+		name          string
+		actor         services.Actor
+		code          string
+		expectedError string
+	}{
+		{name: "Holder", actor: services.Actor{UserID: 30, OrganizationID: &orgID}, code: "AAAA-AAAA-AAAA-AAAA"},
+		{name: "Course Owner", actor: services.Actor{UserID: ownerID, IsTeacher: true, OrganizationID: &orgID}, code: "AAAA-AAAA-AAAA-AAAA"},
+		{name: "Organization Admin", actor: services.Actor{UserID: 3, IsAdmin: true, OrganizationID: &orgID}, code: "AAAA-AAAA-AAAA-AAAA"},
+		{
+			name:          "Teacher Outside The Course",
+			actor:         services.Actor{UserID: teacherID, IsTeacher: true, OrganizationID: &orgID},
+			code:          "AAAA-AAAA-AAAA-AAAA",
+			expectedError: "insufficient permissions to download this certificate",
+		},
+		{
+			name:          "Admin Of Another Organization",
+			actor:         services.Actor{UserID: 4, IsAdmin: true, OrganizationID: &otherOrgID},
+			code:          "AAAA-AAAA-AAAA-AAAA",
+			expectedError: "certificate not found",
+		},
+		{name: "Purged Course, Admin Of The Issuer", actor: services.Actor{UserID: 3, IsAdmin: true, OrganizationID: &orgID}, code: "BBBB-BBBB-BBBB-BBBB"},
+		{
+			name:          "Purged Course, Admin Of Another Organization",
+			actor:         services.Actor{UserID: 4, IsAdmin: true, OrganizationID: &otherOrgID},
+			code:          "BBBB-BBBB-BBBB-BBBB",
+			expectedError: "certificate not found",
+		},
+		{
+			name:          "Purged Course, Course Owner",
+			actor:         services.Actor{UserID: ownerID, IsTeacher: true, OrganizationID: &orgID},
+			code:          "BBBB-BBBB-BBBB-BBBB",
+			expectedError: "insufficient permissions to download this certificate",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			certificateRepo := mocks.NewCertificateRepositoryInterface(t)
+			userRepo := new(mocks.UserRepositoryInterface)
+			memberRepo := new(mocks.CourseMemberRepositoryInterface)
+			storage := newFakeObjectStorage()
+			storage.objects["certificates/a.pdf"] = []byte("%PDF-1.4")
+			storage.objects["certificates/b.pdf"] = []byte("%PDF-1.4")
+
+			certificateRepo.On("GetByCode", tc.code).Return(certificates[tc.code], nil)
+			userRepo.On("GetByID", issuerID).Return(models.User{ID: issuerID, OrganizationID: &orgID}, nil)
+			memberRepo.On("GetRole", courseID, mock.Anything).Return("", nil)
+			policy := services.NewPolicyService(services.PolicyRules, new(mocks.CourseRepositoryInterface), new(mocks.LessonRepositoryInterface), memberRepo)
+
+			service := services.NewCertificateService(&config.AppConfig{}, certificateRepo, nil, nil, nil, userRepo, policy, storage)
+
+			certificate, object, err := service.DownloadCertificate(tc.actor, tc.code)
+
+			if tc.expectedError != "" {
+				assert.EqualError(t, err, tc.expectedError)
+				assert.Nil(t, object)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.code, certificate.Code)
+				object.Close()
+			}
+		})
+	}
+}
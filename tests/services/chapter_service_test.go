@@ -30,7 +30,7 @@ func TestChapterService_GetChaptersByCourseID(t *testing.T) {
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
 			// Call the service method
-			_, err := service.GetChaptersByCourseID(tc.courseID)
+			_, _, err := service.GetChaptersByCourseID(tc.courseID, schemas.ListParams{})
 
 			// Assert the results
 			if tc.expectedError != "" {
@@ -0,0 +1,295 @@
+package services_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"io"
+	"testing"
+	"web/mocks/repos"
+	"web/models"
+	"web/repos"
+	"web/schemas"
+	"web/services"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func archiveTestCourse() models.Course {
+	ownerID := uint(7)
+	slidesID := uint(14)
+	return models.Course{
+		ID: 1, Name: "Go Basics", Description: "Learn Go", Status: models.CourseStatusDraft, CreatedBy: &ownerID,
+		Chapters: []models.Chapter{{
+			ID: 2, Name: "Chapter 1", Order: 1,
+			Lessons: []models.Lesson{{
+				ID: 3, Name: "Lesson 1", Order: 1, Content: "Hello\n\n[Slides](/api/v1/attachments/download/14)",
+				Blocks: []models.LessonBlock{
+					{ID: 20, Position: 1, Type: models.LessonBlockTypeMarkdown, Text: "Hello"},
+					{ID: 21, Position: 2, Type: models.LessonBlockTypeAttachment, Title: "Slides", AttachmentID: &slidesID},
+				},
+				Attachments: []models.Attachment{
+					{ID: 14, Name: "slides.pdf", URL: "lesson-3/slides.pdf"},
+					{ID: 15, Name: "notes.txt", URL: "lesson-3/notes.txt"},
+				},
+			}},
+		}},
+	}
+}
+
+// readArchive returns the files of a zip archive by name
+func readArchive(t *testing.T, data []byte) map[string][]byte {
+	reader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	assert.NoError(t, err)
+
+	files := map[string][]byte{}
+	for _, file := range reader.File {
+		content, err := file.Open()
+		assert.NoError(t, err)
+		files[file.Name], err = io.ReadAll(content)
+		assert.NoError(t, err)
+		content.Close()
+	}
+	return files
+}
+
+// writeArchive builds a zip archive from file names and contents
+func writeArchive(t *testing.T, files map[string][]byte) []byte {
+	var buf bytes.Buffer
+	writer := zip.NewWriter(&buf)
+	for name, content := range files {
+		fileWriter, err := writer.Create(name)
+		assert.NoError(t, err)
+		_, err = fileWriter.Write(content)
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, writer.Close())
+	return buf.Bytes()
+}
+
+func TestCourseArchiveService_GetExportableCourse(t *testing.T) {
+	testCases := []struct {
+		name          string
+		actor         services.Actor
+		expectedError string
+	}{
+		{name: "Owner", actor: services.Actor{UserID: 7, IsTeacher: true}},
+		{name: "Admin", actor: services.Actor{UserID: 1, IsAdmin: true}},
+		{name: "Other Teacher On Draft", actor: services.Actor{UserID: 9, IsTeacher: true}, expectedError: "insufficient permissions to export this course"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			courseRepo := new(mocks.CourseRepositoryInterface)
+			service := services.NewCourseArchiveService(courseRepo, newFakeObjectStorage())
+			courseRepo.On("GetTree", uint(1)).Return(archiveTestCourse(), nil)
+
+			course, err := service.GetExportableCourse(1, tc.actor)
+
+			if tc.expectedError != "" {
+				assert.EqualError(t, err, tc.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, uint(1), course.ID)
+		})
+	}
+}
+
+func TestCourseArchiveService_WriteCourseArchive(t *testing.T) {
+	storage := newFakeObjectStorage()
+	storage.objects["lesson-3/slides.pdf"] = []byte("%PDF slides")
+	// lesson-3/notes.txt is missing from storage
+	service := services.NewCourseArchiveService(new(mocks.CourseRepositoryInterface), storage)
+
+	var buf bytes.Buffer
+	err := service.WriteCourseArchive(&buf, archiveTestCourse())
+
+	assert.NoError(t, err)
+	files := readArchive(t, buf.Bytes())
+	assert.Equal(t, []byte("%PDF slides"), files["attachments/14/slides.pdf"])
+
+	var manifest schemas.CourseArchiveManifest
+	assert.NoError(t, json.Unmarshal(files[schemas.CourseArchiveManifestName], &manifest))
+	assert.Equal(t, schemas.CourseArchiveFormatVersion, manifest.FormatVersion)
+	assert.Equal(t, "Go Basics", manifest.Course.Name)
+	lesson := manifest.Course.Chapters[0].Lessons[0]
+	assert.Len(t, lesson.Blocks, 2)
+	assert.Equal(t, uint(14), *lesson.Blocks[1].AttachmentID)
+	assert.Equal(t, []schemas.CourseArchiveAttachment{
+		{ID: 14, Name: "slides.pdf", File: "attachments/14/slides.pdf", Size: 11},
+		{ID: 15, Name: "notes.txt"},
+	}, lesson.Attachments)
+}
+
+func TestCourseArchiveService_ImportCourse(t *testing.T) {
+	storage := newFakeObjectStorage()
+	storage.objects["lesson-3/slides.pdf"] = []byte("%PDF slides")
+	storage.objects["lesson-3/notes.txt"] = []byte("notes")
+	courseRepo := new(mocks.CourseRepositoryInterface)
+	service := services.NewCourseArchiveService(courseRepo, storage)
+
+	var buf bytes.Buffer
+	assert.NoError(t, service.WriteCourseArchive(&buf, archiveTestCourse()))
+	storage.objects = map[string][]byte{}
+
+	idMap := schemas.CourseIDMap{
+		Chapters:    map[uint]uint{2: 102},
+		Lessons:     map[uint]uint{3: 103},
+		Attachments: map[uint]uint{14: 114, 15: 115},
+	}
+	courseRepo.On("ExistsByName", "Go Basics", (*uint)(nil)).Return(false, nil)
+	courseRepo.On("Import", mock.Anything, mock.MatchedBy(func(options repos.CloneOptions) bool {
+		return options.Name == "Go Basics" && options.CreatedBy == 9
+	})).Return(func(source models.Course, options repos.CloneOptions) (models.Course, schemas.CourseIDMap, error) {
+		lesson := source.Chapters[0].Lessons[0]
+		assert.Equal(t, uint(3), lesson.ID)
+		assert.Len(t, lesson.Blocks, 2)
+		assert.Equal(t, uint(14), *lesson.Blocks[1].AttachmentID)
+		assert.Equal(t, "attachments/14/slides.pdf", lesson.Attachments[0].URL)
+
+		err := options.CopyObjects([]repos.ObjectCopy{
+			{Source: "attachments/14/slides.pdf", Destination: "lesson-103/slides.pdf"},
+			{Source: "attachments/15/notes.txt", Destination: "lesson-103/notes.txt"},
+		})
+		return models.Course{ID: 101, Name: options.Name, Status: models.CourseStatusDraft}, idMap, err
+	})
+
+	response, err := service.ImportCourse(bytes.NewReader(buf.Bytes()), int64(buf.Len()), services.Actor{UserID: 9, IsTeacher: true}, schemas.ImportCourseRequest{})
+
+	assert.NoError(t, err)
+	assert.Empty(t, response.Conflicts)
+	assert.Equal(t, uint(101), response.Course.ID)
+	assert.Equal(t, idMap, *response.IDMap)
+	assert.Equal(t, []byte("%PDF slides"), storage.objects["lesson-103/slides.pdf"])
+	assert.Equal(t, []byte("notes"), storage.objects["lesson-103/notes.txt"])
+	courseRepo.AssertExpectations(t)
+}
+
+func TestCourseArchiveService_ImportCourse_Conflicts(t *testing.T) {
+	attachmentID := uint(14)
+	manifest := schemas.CourseArchiveManifest{
+		FormatVersion: schemas.CourseArchiveFormatVersion,
+		Course: schemas.CourseArchiveCourse{
+			ID: 1, Name: "Go Basics",
+			Chapters: []schemas.CourseArchiveChapter{{
+				ID: 2, Name: "Chapter 1",
+				Lessons: []schemas.CourseArchiveLesson{{
+					ID: 3, Name: "Lesson 1",
+					Blocks: []schemas.LessonBlockRequest{
+						{Type: models.LessonBlockTypeMarkdown, Position: 1, Text: "Hello"},
+						{Type: models.LessonBlockTypeAttachment, Position: 2, AttachmentID: &attachmentID},
+					},
+					Attachments: []schemas.CourseArchiveAttachment{{ID: 14, Name: "slides.pdf", File: "attachments/14/slides.pdf"}},
+				}},
+			}},
+		},
+	}
+	manifestData, err := json.Marshal(manifest)
+	assert.NoError(t, err)
+	archive := writeArchive(t, map[string][]byte{schemas.CourseArchiveManifestName: manifestData})
+
+	expectedConflicts := []string{schemas.ImportConflictMissingFile, schemas.ImportConflictUnknownAttachment, schemas.ImportConflictCourseName}
+	conflictTypes := func(conflicts []schemas.ImportConflict) []string {
+		types := make([]string, 0, len(conflicts))
+		for _, conflict := range conflicts {
+			types = append(types, conflict.Type)
+		}
+		return types
+	}
+
+	t.Run("Fail", func(t *testing.T) {
+		courseRepo := new(mocks.CourseRepositoryInterface)
+		service := services.NewCourseArchiveService(courseRepo, newFakeObjectStorage())
+		courseRepo.On("ExistsByName", "Go Basics", (*uint)(nil)).Return(true, nil)
+		courseRepo.On("ExistsByName", "Go Basics (imported)", (*uint)(nil)).Return(false, nil)
+
+		response, err := service.ImportCourse(bytes.NewReader(archive), int64(len(archive)), services.Actor{UserID: 1, IsAdmin: true}, schemas.ImportCourseRequest{})
+
+		assert.EqualError(t, err, "course archive has conflicts")
+		assert.Equal(t, expectedConflicts, conflictTypes(response.Conflicts))
+		assert.Equal(t, "imported as Go Basics (imported)", response.Conflicts[2].Resolution)
+		assert.Nil(t, response.Course)
+		courseRepo.AssertNotCalled(t, "Import", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Resolve", func(t *testing.T) {
+		courseRepo := new(mocks.CourseRepositoryInterface)
+		service := services.NewCourseArchiveService(courseRepo, newFakeObjectStorage())
+		courseRepo.On("ExistsByName", "Go Basics", (*uint)(nil)).Return(true, nil)
+		courseRepo.On("ExistsByName", "Go Basics (imported)", (*uint)(nil)).Return(true, nil)
+		courseRepo.On("ExistsByName", "Go Basics (imported 2)", (*uint)(nil)).Return(false, nil)
+		courseRepo.On("Import", mock.MatchedBy(func(source models.Course) bool {
+			lesson := source.Chapters[0].Lessons[0]
+			return len(lesson.Attachments) == 0 && len(lesson.Blocks) == 1 && lesson.Content == "Hello"
+		}), mock.MatchedBy(func(options repos.CloneOptions) bool {
+			return options.Name == "Go Basics (imported 2)"
+		})).Return(models.Course{ID: 101, Name: "Go Basics (imported 2)"}, schemas.CourseIDMap{}, nil)
+
+		response, err := service.ImportCourse(bytes.NewReader(archive), int64(len(archive)), services.Actor{UserID: 1, IsAdmin: true},
+			schemas.ImportCourseRequest{OnConflict: schemas.ImportOnConflictResolve})
+
+		assert.NoError(t, err)
+		assert.Equal(t, expectedConflicts, conflictTypes(response.Conflicts))
+		assert.Equal(t, "Go Basics (imported 2)", response.Course.Name)
+		courseRepo.AssertExpectations(t)
+	})
+}
+
+func TestCourseArchiveService_ImportCourse_InvalidArchive(t *testing.T) {
+	invalidBlock, _ := json.Marshal(schemas.CourseArchiveManifest{
+		FormatVersion: schemas.CourseArchiveFormatVersion,
+		Course: schemas.CourseArchiveCourse{Name: "Go", Chapters: []schemas.CourseArchiveChapter{{
+			ID: 2, Name: "Chapter 1",
+			Lessons: []schemas.CourseArchiveLesson{{ID: 3, Name: "Lesson 1", Blocks: []schemas.LessonBlockRequest{{Type: "poll"}}}},
+		}}},
+	})
+	duplicateLesson, _ := json.Marshal(schemas.CourseArchiveManifest{
+		FormatVersion: schemas.CourseArchiveFormatVersion,
+		Course: schemas.CourseArchiveCourse{Name: "Go", Chapters: []schemas.CourseArchiveChapter{{
+			ID: 2, Name: "Chapter 1",
+			Lessons: []schemas.CourseArchiveLesson{{ID: 3, Name: "Lesson 1"}, {ID: 3, Name: "Lesson 2"}},
+		}}},
+	})
+
+	testCases := []struct {
+		name          string
+		archive       []byte
+		actor         services.Actor
+		expectedError string
+	}{
+		{name: "Not A Zip", archive: []byte("not a zip"), expectedError: "invalid course archive"},
+		{name: "No Manifest", archive: writeArchive(t, map[string][]byte{"readme.txt": []byte("hi")}), expectedError: "course archive has no manifest"},
+		{name: "Invalid Manifest", archive: writeArchive(t, map[string][]byte{"manifest.json": []byte("{")}), expectedError: "invalid course archive manifest"},
+		{
+			name:          "Unsupported Version",
+			archive:       writeArchive(t, map[string][]byte{"manifest.json": []byte(`{"format_version": 2}`)}),
+			expectedError: "unsupported course archive format version 2",
+		},
+		{name: "Invalid Block", archive: writeArchive(t, map[string][]byte{"manifest.json": invalidBlock}), expectedError: "lesson 3 block 1: invalid block type"},
+		{name: "Duplicate Lesson", archive: writeArchive(t, map[string][]byte{"manifest.json": duplicateLesson}), expectedError: "duplicate lesson ID 3 in manifest"},
+		{
+			name:          "Student",
+			archive:       writeArchive(t, map[string][]byte{"manifest.json": duplicateLesson}),
+			actor:         services.Actor{UserID: 5},
+			expectedError: "insufficient permissions to import courses",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			courseRepo := new(mocks.CourseRepositoryInterface)
+			service := services.NewCourseArchiveService(courseRepo, newFakeObjectStorage())
+			actor := tc.actor
+			if actor.UserID == 0 {
+				actor = services.Actor{UserID: 1, IsTeacher: true}
+			}
+
+			_, err := service.ImportCourse(bytes.NewReader(tc.archive), int64(len(tc.archive)), actor, schemas.ImportCourseRequest{})
+
+			assert.EqualError(t, err, tc.expectedError)
+			courseRepo.AssertNotCalled(t, "Import", mock.Anything, mock.Anything)
+		})
+	}
+}
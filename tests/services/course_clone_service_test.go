@@ -0,0 +1,159 @@
+package services_test
+
+import (
+	"errors"
+	"testing"
+	"web/mocks/repos"
+	"web/models"
+	"web/repos"
+	"web/schemas"
+	"web/services"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// fakeObjectCopier records copied and removed objects and fails copies to failOn
+type fakeObjectCopier struct {
+	failOn  string
+	copied  []string
+	removed []string
+}
+
+func (s *fakeObjectCopier) CopyObject(sourceName, destinationName string) error {
+	if sourceName == s.failOn {
+		return errors.New("copy failed")
+	}
+	s.copied = append(s.copied, destinationName)
+	return nil
+}
+
+func (s *fakeObjectCopier) RemoveObject(objectName string) error {
+	s.removed = append(s.removed, objectName)
+	return nil
+}
+
+func TestCourseCloneService_CloneCourse(t *testing.T) {
+	ownerID := uint(7)
+	sourceCourse := func(status string) models.Course {
+		return models.Course{
+			ID: 1, Name: "Go Basics", Status: status, CreatedBy: &ownerID,
+			Chapters: []models.Chapter{{ID: 2, Name: "Chapter 1", Lessons: []models.Lesson{{ID: 3, Name: "Lesson 1"}}}},
+		}
+	}
+
+	testCases := []struct {
+		name          string
+		actor         services.Actor
+		status        string
+		request       schemas.CloneCourseRequest
+		expectedName  string
+		expectedError string
+	}{
+		{
+			name:         "Admin Clones Draft With Default Name",
+			actor:        services.Actor{UserID: 1, IsAdmin: true},
+			status:       models.CourseStatusDraft,
+			expectedName: "Go Basics (copy)",
+		},
+		{
+			name:         "Owner Clones Own Draft",
+			actor:        services.Actor{UserID: ownerID, IsTeacher: true},
+			status:       models.CourseStatusDraft,
+			request:      schemas.CloneCourseRequest{Name: "  Go Basics 2026  "},
+			expectedName: "Go Basics 2026",
+		},
+		{
+			name:         "Teacher Clones Published Course",
+			actor:        services.Actor{UserID: 9, IsTeacher: true},
+			status:       models.CourseStatusPublished,
+			expectedName: "Go Basics (copy)",
+		},
+		{
+			name:          "Teacher Cannot Clone Foreign Draft",
+			actor:         services.Actor{UserID: 9, IsTeacher: true},
+			status:        models.CourseStatusDraft,
+			expectedError: "insufficient permissions to clone this course",
+		},
+		{
+			name:          "Student Cannot Clone Published Course",
+			actor:         services.Actor{UserID: 9},
+			status:        models.CourseStatusPublished,
+			expectedError: "insufficient permissions to clone this course",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			courseRepo := new(mocks.CourseRepositoryInterface)
+			service := services.NewCourseCloneService(courseRepo, &fakeObjectCopier{})
+
+			courseRepo.On("GetTree", uint(1)).Return(sourceCourse(tc.status), nil)
+			if tc.expectedError == "" {
+				courseRepo.On("Clone", mock.Anything, mock.MatchedBy(func(options repos.CloneOptions) bool {
+					return options.Name == tc.expectedName && options.CreatedBy == tc.actor.UserID
+				})).Return(models.Course{
+					ID: 10, Name: tc.expectedName, Status: models.CourseStatusDraft, CreatedBy: &tc.actor.UserID,
+					Chapters: []models.Chapter{{ID: 11, Name: "Chapter 1", Lessons: []models.Lesson{{
+						ID: 12, Name: "Lesson 1",
+						Blocks:      []models.LessonBlock{{ID: 13}},
+						Attachments: []models.Attachment{{ID: 14, Name: "slides.pdf"}},
+					}}}},
+				}, nil)
+			}
+
+			tree, err := service.CloneCourse(1, tc.actor, tc.request)
+
+			if tc.expectedError != "" {
+				assert.EqualError(t, err, tc.expectedError)
+				courseRepo.AssertNotCalled(t, "Clone", mock.Anything, mock.Anything)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, uint(10), tree.ID)
+			assert.Equal(t, tc.expectedName, tree.Name)
+			assert.Equal(t, models.CourseStatusDraft, tree.Status)
+			assert.Len(t, tree.Chapters, 1)
+			assert.Len(t, tree.Chapters[0].Lessons, 1)
+			lesson := tree.Chapters[0].Lessons[0]
+			assert.Equal(t, 1, lesson.BlocksCount)
+			assert.Equal(t, []schemas.AttachmentTreeResponse{{ID: 14, Name: "slides.pdf"}}, lesson.Attachments)
+			courseRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCourseCloneService_CloneCourse_CopyFailureRemovesCopiedObjects(t *testing.T) {
+	ownerID := uint(7)
+	courseRepo := new(mocks.CourseRepositoryInterface)
+	storage := &fakeObjectCopier{failOn: "lesson-3/c.pdf"}
+	service := services.NewCourseCloneService(courseRepo, storage)
+
+	courseRepo.On("GetTree", uint(1)).Return(models.Course{ID: 1, Name: "Go Basics", CreatedBy: &ownerID}, nil)
+	courseRepo.On("Clone", mock.Anything, mock.Anything).
+		Return(func(source models.Course, options repos.CloneOptions) (models.Course, error) {
+			// The repository calls CopyObjects before committing the transaction
+			return models.Course{}, options.CopyObjects([]repos.ObjectCopy{
+				{Source: "lesson-3/a.pdf", Destination: "lesson-12/a.pdf"},
+				{Source: "lesson-3/b.pdf", Destination: "lesson-12/b.pdf"},
+				{Source: "lesson-3/c.pdf", Destination: "lesson-12/c.pdf"},
+			})
+		})
+
+	_, err := service.CloneCourse(1, services.Actor{UserID: ownerID, IsTeacher: true}, schemas.CloneCourseRequest{})
+
+	assert.ErrorContains(t, err, "failed to copy attachment lesson-3/c.pdf")
+	assert.Equal(t, []string{"lesson-12/a.pdf", "lesson-12/b.pdf"}, storage.copied)
+	assert.Equal(t, storage.copied, storage.removed)
+}
+
+func TestCourseCloneService_CloneCourse_NotFound(t *testing.T) {
+	courseRepo := new(mocks.CourseRepositoryInterface)
+	service := services.NewCourseCloneService(courseRepo, &fakeObjectCopier{})
+
+	courseRepo.On("GetTree", uint(5)).Return(models.Course{}, errors.New("course not found"))
+
+	_, err := service.CloneCourse(5, services.Actor{UserID: 1, IsAdmin: true}, schemas.CloneCourseRequest{})
+
+	assert.EqualError(t, err, "course not found")
+}
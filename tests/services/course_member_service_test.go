@@ -0,0 +1,133 @@
+package services_test
+
+import (
+	"errors"
+	"testing"
+	"web/mocks/repos"
+	"web/models"
+	"web/schemas"
+	"web/services"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCourseMemberService_InviteMember(t *testing.T) {
+	ownerID, otherOrgID := uint(10), uint(200)
+
+	testCases := []struct {
+		name          string
+		request       schemas.InviteCourseMemberRequest
+		expectCreate  bool
+		expectedError string
+	}{
+		{
+			name:         "Invite By ID",
+			request:      schemas.InviteCourseMemberRequest{UserID: 20, Role: models.CourseMemberRoleCoInstructor},
+			expectCreate: true,
+		},
+		{
+			name:         "Invite By Email",
+			request:      schemas.InviteCourseMemberRequest{Email: "jane@example.com", Role: models.CourseMemberRoleViewer},
+			expectCreate: true,
+		},
+		{
+			name:          "Owner Role Cannot Be Given",
+			request:       schemas.InviteCourseMemberRequest{UserID: 20, Role: models.CourseMemberRoleOwner},
+			expectedError: "invalid member role",
+		},
+		{
+			name:          "Missing User",
+			request:       schemas.InviteCourseMemberRequest{Role: models.CourseMemberRoleViewer},
+			expectedError: "user ID or email is required",
+		},
+		{
+			name:          "Unknown User",
+			request:       schemas.InviteCourseMemberRequest{UserID: 99, Role: models.CourseMemberRoleViewer},
+			expectedError: "user not found",
+		},
+		{
+			name:          "Already A Member",
+			request:       schemas.InviteCourseMemberRequest{UserID: ownerID, Role: models.CourseMemberRoleViewer},
+			expectedError: "user is already a member of this course",
+		},
+		{
+			name:          "User Of Another Organization",
+			request:       schemas.InviteCourseMemberRequest{UserID: 30, Role: models.CourseMemberRoleViewer},
+			expectedError: "user does not belong to the organization of this course",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			memberRepo := new(mocks.CourseMemberRepositoryInterface)
+			courseRepo := new(mocks.CourseRepositoryInterface)
+			userRepo := new(mocks.UserRepositoryInterface)
+			service := services.NewCourseMemberService(memberRepo, courseRepo, userRepo)
+
+			courseRepo.On("GetByID", uint(1)).Return(models.Course{ID: 1, CreatedBy: &ownerID}, nil).Maybe()
+			userRepo.On("GetByID", uint(20)).Return(models.User{ID: 20, Username: "jane"}, nil).Maybe()
+			userRepo.On("GetByID", ownerID).Return(models.User{ID: ownerID, Username: "owner"}, nil).Maybe()
+			userRepo.On("GetByID", uint(99)).Return(models.User{}, errors.New("user not found")).Maybe()
+			userRepo.On("GetByID", uint(30)).Return(models.User{ID: 30, OrganizationID: &otherOrgID}, nil).Maybe()
+			userRepo.On("GetByEmail", "jane@example.com").Return(models.User{ID: 20, Username: "jane"}, nil).Maybe()
+			memberRepo.On("GetRole", uint(1), ownerID).Return(models.CourseMemberRoleOwner, nil).Maybe()
+			memberRepo.On("GetRole", uint(1), uint(20)).Return("", nil).Maybe()
+			memberRepo.On("Create", mock.MatchedBy(func(member models.CourseMember) bool {
+				return member.CourseID == 1 && member.UserID == 20 && member.Role == tc.request.Role &&
+					member.InvitedBy != nil && *member.InvitedBy == ownerID
+			})).Return(models.CourseMember{ID: 5, CourseID: 1, UserID: 20, Role: tc.request.Role}, nil).Maybe()
+
+			member, err := service.InviteMember(1, services.Actor{UserID: ownerID, IsTeacher: true}, tc.request)
+
+			if tc.expectedError != "" {
+				assert.EqualError(t, err, tc.expectedError)
+				memberRepo.AssertNotCalled(t, "Create", mock.Anything)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, uint(20), member.UserID)
+			assert.Equal(t, "jane", member.Username)
+			assert.Equal(t, tc.request.Role, member.Role)
+			memberRepo.AssertNumberOfCalls(t, "Create", 1)
+		})
+	}
+}
+
+func TestCourseMemberService_UpdateMemberRole(t *testing.T) {
+	memberRepo := new(mocks.CourseMemberRepositoryInterface)
+	service := services.NewCourseMemberService(memberRepo, new(mocks.CourseRepositoryInterface), new(mocks.UserRepositoryInterface))
+
+	memberRepo.On("Get", uint(1), uint(10)).Return(models.CourseMember{CourseID: 1, UserID: 10, Role: models.CourseMemberRoleOwner}, nil)
+	memberRepo.On("Get", uint(1), uint(20)).Return(models.CourseMember{CourseID: 1, UserID: 20, Role: models.CourseMemberRoleViewer}, nil)
+	memberRepo.On("Get", uint(1), uint(30)).Return(models.CourseMember{}, errors.New("course member not found"))
+	memberRepo.On("UpdateRole", uint(1), uint(20), models.CourseMemberRoleTeachingAssistant).Return(nil)
+
+	member, err := service.UpdateMemberRole(1, 20, schemas.UpdateCourseMemberRequest{Role: models.CourseMemberRoleTeachingAssistant})
+	assert.NoError(t, err)
+	assert.Equal(t, models.CourseMemberRoleTeachingAssistant, member.Role)
+
+	_, err = service.UpdateMemberRole(1, 10, schemas.UpdateCourseMemberRequest{Role: models.CourseMemberRoleViewer})
+	assert.EqualError(t, err, "the course owner cannot be changed")
+
+	_, err = service.UpdateMemberRole(1, 30, schemas.UpdateCourseMemberRequest{Role: models.CourseMemberRoleViewer})
+	assert.EqualError(t, err, "course member not found")
+
+	_, err = service.UpdateMemberRole(1, 20, schemas.UpdateCourseMemberRequest{Role: "editor"})
+	assert.EqualError(t, err, "invalid member role")
+
+	memberRepo.AssertNumberOfCalls(t, "UpdateRole", 1)
+}
+
+func TestCourseMemberService_RemoveMember(t *testing.T) {
+	memberRepo := new(mocks.CourseMemberRepositoryInterface)
+	service := services.NewCourseMemberService(memberRepo, new(mocks.CourseRepositoryInterface), new(mocks.UserRepositoryInterface))
+
+	memberRepo.On("Get", uint(1), uint(10)).Return(models.CourseMember{CourseID: 1, UserID: 10, Role: models.CourseMemberRoleOwner}, nil)
+	memberRepo.On("Get", uint(1), uint(20)).Return(models.CourseMember{CourseID: 1, UserID: 20, Role: models.CourseMemberRoleCoInstructor}, nil)
+	memberRepo.On("Delete", uint(1), uint(20)).Return(nil)
+
+	assert.NoError(t, service.RemoveMember(1, 20))
+	assert.EqualError(t, service.RemoveMember(1, 10), "the course owner cannot be removed")
+	memberRepo.AssertNumberOfCalls(t, "Delete", 1)
+}
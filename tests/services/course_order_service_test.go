@@ -0,0 +1,285 @@
+package services_test
+
+import (
+	"errors"
+	"testing"
+	"web/mocks/repos"
+	"web/models"
+	"web/schemas"
+	"web/services"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCourseOrderService_ReorderChapters(t *testing.T) {
+	testCases := []struct {
+		name          string
+		chapterIDs    []uint
+		repoError     error
+		expectedError string
+	}{
+		{
+			name:       "Success",
+			chapterIDs: []uint{3, 1, 2},
+		},
+		{
+			name:          "Empty List",
+			chapterIDs:    []uint{},
+			expectedError: "chapter order must not be empty",
+		},
+		{
+			name:          "Duplicate ID",
+			chapterIDs:    []uint{3, 1, 3},
+			expectedError: "chapter order lists chapter 3 more than once",
+		},
+		{
+			name:          "List Does Not Match Course",
+			chapterIDs:    []uint{3, 1},
+			repoError:     errors.New("chapter order must list every chapter of the course exactly once"),
+			expectedError: "chapter order must list every chapter of the course exactly once",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			chapterRepo := new(mocks.ChapterRepositoryInterface)
+			service := services.NewCourseOrderService(new(mocks.CourseRepositoryInterface), chapterRepo, new(mocks.LessonRepositoryInterface), new(mocks.CourseMemberRepositoryInterface))
+
+			chapterRepo.On("Reorder", uint(1), tc.chapterIDs).Return([]models.Chapter{
+				{ID: 3, Name: "Three", Order: 1},
+				{ID: 1, Name: "One", Order: 2},
+				{ID: 2, Name: "Two", Order: 3},
+			}, tc.repoError).Maybe()
+
+			items, err := service.ReorderChapters(1, schemas.ReorderChaptersRequest{ChapterIDs: tc.chapterIDs})
+
+			if tc.expectedError != "" {
+				assert.EqualError(t, err, tc.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, []schemas.OrderedItemResponse{
+				{ID: 3, Name: "Three", Order: 1},
+				{ID: 1, Name: "One", Order: 2},
+				{ID: 2, Name: "Two", Order: 3},
+			}, items)
+		})
+	}
+}
+
+func TestCourseOrderService_ReorderLessons(t *testing.T) {
+	chapterRepo := new(mocks.ChapterRepositoryInterface)
+	lessonRepo := new(mocks.LessonRepositoryInterface)
+	service := services.NewCourseOrderService(new(mocks.CourseRepositoryInterface), chapterRepo, lessonRepo, new(mocks.CourseMemberRepositoryInterface))
+
+	chapterRepo.On("GetByID", uint(2), uint(1), noTenant).Return(models.Chapter{ID: 2, CourseID: 1}, nil)
+	chapterRepo.On("GetByID", uint(9), uint(1), noTenant).Return(models.Chapter{}, errors.New("chapter not found"))
+	lessonRepo.On("Reorder", uint(2), []uint{5, 4}).Return([]models.Lesson{{ID: 5, Order: 1}, {ID: 4, Order: 2}}, nil)
+
+	items, err := service.ReorderLessons(1, 2, schemas.ReorderLessonsRequest{LessonIDs: []uint{5, 4}}, noTenant)
+	assert.NoError(t, err)
+	assert.Equal(t, []schemas.OrderedItemResponse{{ID: 5, Order: 1}, {ID: 4, Order: 2}}, items)
+
+	// The chapter must belong to the course
+	_, err = service.ReorderLessons(1, 9, schemas.ReorderLessonsRequest{LessonIDs: []uint{5, 4}}, noTenant)
+	assert.EqualError(t, err, "chapter not found")
+	lessonRepo.AssertNumberOfCalls(t, "Reorder", 1)
+}
+
+func TestCourseOrderService_MoveLesson(t *testing.T) {
+	testCases := []struct {
+		name          string
+		request       schemas.MoveLessonRequest
+		actor         services.Actor
+		lessonError   error
+		moveError     error
+		expectedError string
+	}{
+		{
+			name:    "Success",
+			request: schemas.MoveLessonRequest{ChapterID: 3, Position: 1},
+		},
+		{
+			name:    "Append",
+			request: schemas.MoveLessonRequest{ChapterID: 3},
+		},
+		{
+			name:          "Missing Target Chapter",
+			request:       schemas.MoveLessonRequest{Position: 1},
+			expectedError: "target chapter ID is required",
+		},
+		{
+			name:          "Negative Position",
+			request:       schemas.MoveLessonRequest{ChapterID: 3, Position: -1},
+			expectedError: "invalid lesson position",
+		},
+		{
+			name:          "Lesson Not In Chapter",
+			request:       schemas.MoveLessonRequest{ChapterID: 3},
+			lessonError:   errors.New("lesson not found"),
+			expectedError: "lesson not found",
+		},
+		{
+			name:          "Target Chapter Of Another Course",
+			request:       schemas.MoveLessonRequest{ChapterID: 7},
+			moveError:     errors.New("target chapter not found in this course"),
+			expectedError: "target chapter not found in this course",
+		},
+		{
+			name:    "Other Course Owned By Teacher",
+			request: schemas.MoveLessonRequest{CourseID: 5, ChapterID: 7},
+			actor:   services.Actor{UserID: 10, IsTeacher: true},
+		},
+		{
+			name:    "Other Course As Admin",
+			request: schemas.MoveLessonRequest{CourseID: 6, ChapterID: 7},
+			actor:   services.Actor{UserID: 1, IsAdmin: true},
+		},
+		{
+			name:          "Other Course Not Owned",
+			request:       schemas.MoveLessonRequest{CourseID: 6, ChapterID: 7},
+			actor:         services.Actor{UserID: 10, IsTeacher: true},
+			expectedError: "insufficient permissions to move content between these courses",
+		},
+		{
+			name:          "Other Course Not Found",
+			request:       schemas.MoveLessonRequest{CourseID: 9, ChapterID: 7},
+			actor:         services.Actor{UserID: 10, IsTeacher: true},
+			expectedError: "target course not found",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			courseRepo := moveTestCourses()
+			lessonRepo := new(mocks.LessonRepositoryInterface)
+			service := services.NewCourseOrderService(courseRepo, new(mocks.ChapterRepositoryInterface), lessonRepo, moveTestMembers())
+
+			lessonRepo.On("GetByID", uint(1), uint(2), uint(4), tc.actor.TenantFilter()).Return(models.Lesson{ID: 4, ChapterID: 2}, tc.lessonError).Maybe()
+			toCourseID := tc.request.CourseID
+			if toCourseID == 0 {
+				toCourseID = 1
+			}
+			lessonRepo.On("Move", uint(4), toCourseID, tc.request.ChapterID, tc.request.Position).
+				Return([]models.Lesson{{ID: 4, Order: 1}, {ID: 8, Order: 2}}, tc.moveError).Maybe()
+
+			items, err := service.MoveLesson(1, 2, 4, tc.actor, tc.request)
+
+			if tc.expectedError != "" {
+				assert.EqualError(t, err, tc.expectedError)
+				if tc.moveError == nil {
+					lessonRepo.AssertNotCalled(t, "Move", uint(4), toCourseID, tc.request.ChapterID, tc.request.Position)
+				}
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, []schemas.OrderedItemResponse{{ID: 4, Order: 1}, {ID: 8, Order: 2}}, items)
+			lessonRepo.AssertCalled(t, "Move", uint(4), toCourseID, tc.request.ChapterID, tc.request.Position)
+		})
+	}
+}
+
+func TestCourseOrderService_MoveChapter(t *testing.T) {
+	testCases := []struct {
+		name          string
+		request       schemas.MoveChapterRequest
+		actor         services.Actor
+		expectedError string
+	}{
+		{
+			name:    "Other Course Owned By Teacher",
+			request: schemas.MoveChapterRequest{CourseID: 5, Position: 2},
+			actor:   services.Actor{UserID: 10, IsTeacher: true},
+		},
+		{
+			name:    "Other Course As Admin",
+			request: schemas.MoveChapterRequest{CourseID: 6},
+			actor:   services.Actor{UserID: 1, IsAdmin: true},
+		},
+		{
+			name:    "Co-Instructor Of Both Courses",
+			request: schemas.MoveChapterRequest{CourseID: 6},
+			actor:   services.Actor{UserID: 11},
+		},
+		{
+			name:          "Viewer Of Target Course",
+			request:       schemas.MoveChapterRequest{CourseID: 5},
+			actor:         services.Actor{UserID: 11},
+			expectedError: "insufficient permissions to move content between these courses",
+		},
+		{
+			name:    "Same Course",
+			request: schemas.MoveChapterRequest{CourseID: 1, Position: 1},
+			actor:   services.Actor{UserID: 11, IsTeacher: true},
+		},
+		{
+			name:          "Missing Target Course",
+			request:       schemas.MoveChapterRequest{Position: 1},
+			actor:         services.Actor{UserID: 10, IsTeacher: true},
+			expectedError: "target course ID is required",
+		},
+		{
+			name:          "Negative Position",
+			request:       schemas.MoveChapterRequest{CourseID: 5, Position: -1},
+			actor:         services.Actor{UserID: 10, IsTeacher: true},
+			expectedError: "invalid chapter position",
+		},
+		{
+			name:          "Other Course Not Owned",
+			request:       schemas.MoveChapterRequest{CourseID: 6},
+			actor:         services.Actor{UserID: 10, IsTeacher: true},
+			expectedError: "insufficient permissions to move content between these courses",
+		},
+		{
+			name:          "Other Course Not Found",
+			request:       schemas.MoveChapterRequest{CourseID: 9},
+			actor:         services.Actor{UserID: 10, IsTeacher: true},
+			expectedError: "target course not found",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			chapterRepo := new(mocks.ChapterRepositoryInterface)
+			service := services.NewCourseOrderService(moveTestCourses(), chapterRepo, new(mocks.LessonRepositoryInterface), moveTestMembers())
+
+			chapterRepo.On("GetByID", uint(2), uint(1), tc.actor.TenantFilter()).Return(models.Chapter{ID: 2, CourseID: 1}, nil).Maybe()
+			chapterRepo.On("Move", uint(2), tc.request.CourseID, tc.request.Position).
+				Return([]models.Chapter{{ID: 8, Name: "Eight", Order: 1}, {ID: 2, Name: "Two", Order: 2}}, nil).Maybe()
+
+			items, err := service.MoveChapter(1, 2, tc.actor, tc.request)
+
+			if tc.expectedError != "" {
+				assert.EqualError(t, err, tc.expectedError)
+				chapterRepo.AssertNotCalled(t, "Move", uint(2), tc.request.CourseID, tc.request.Position)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, []schemas.OrderedItemResponse{{ID: 8, Name: "Eight", Order: 1}, {ID: 2, Name: "Two", Order: 2}}, items)
+		})
+	}
+}
+
+// moveTestCourses returns a course repository with courses 1 and 5 created by user 10,
+// course 6 created by user 20 and no course 9
+func moveTestCourses() *mocks.CourseRepositoryInterface {
+	teacherID, otherTeacherID := uint(10), uint(20)
+	courseRepo := new(mocks.CourseRepositoryInterface)
+	courseRepo.On("GetByID", uint(1)).Return(models.Course{ID: 1, CreatedBy: &teacherID}, nil).Maybe()
+	courseRepo.On("GetByID", uint(5)).Return(models.Course{ID: 5, CreatedBy: &teacherID}, nil).Maybe()
+	courseRepo.On("GetByID", uint(6)).Return(models.Course{ID: 6, CreatedBy: &otherTeacherID}, nil).Maybe()
+	courseRepo.On("GetByID", uint(9)).Return(models.Course{}, errors.New("course not found")).Maybe()
+	return courseRepo
+}
+
+// moveTestMembers returns a member repository where user 11 co-instructs courses 1 and 6
+// and views course 5
+func moveTestMembers() *mocks.CourseMemberRepositoryInterface {
+	memberRepo := new(mocks.CourseMemberRepositoryInterface)
+	memberRepo.On("GetRole", uint(1), uint(11)).Return(models.CourseMemberRoleCoInstructor, nil).Maybe()
+	memberRepo.On("GetRole", uint(6), uint(11)).Return(models.CourseMemberRoleCoInstructor, nil).Maybe()
+	memberRepo.On("GetRole", uint(5), uint(11)).Return(models.CourseMemberRoleViewer, nil).Maybe()
+	memberRepo.On("GetRole", mock.Anything, mock.Anything).Return("", nil).Maybe()
+	return memberRepo
+}
@@ -17,7 +17,7 @@ func TestCourseService_GetAllCourses(t *testing.T) {
 
 	mockRepo := new(mocks.CourseRepositoryInterface)
 
-	service := services.NewCourseService(mockRepo)
+	service := services.NewCourseService(mockRepo, nil)
 
 	expectedCourses := []schemas.CourseResponseWithChaptersCount{
 		{
@@ -34,12 +34,14 @@ func TestCourseService_GetAllCourses(t *testing.T) {
 		},
 	}
 
-	mockRepo.On("GetAll").Return(expectedCourses, nil)
+	params := schemas.ListParams{Page: 1, Limit: 20, SortBy: "created_at", SortDesc: true}
+	mockRepo.On("GetAll", params).Return(expectedCourses, int64(2), nil)
 
-	courses, err := service.GetAllCourses()
+	courses, meta, err := service.GetAllCourses(params)
 
 	assert.NoError(t, err)
 	assert.Equal(t, expectedCourses, courses)
+	assert.Equal(t, schemas.PaginationMeta{Page: 1, Limit: 20, Total: 2, TotalPages: 1, HasNext: false, Sort: "-created_at"}, meta)
 	mockRepo.AssertExpectations(t)
 }
 
@@ -47,7 +49,7 @@ func TestCourseService_GetCourseByID(t *testing.T) {
 
 	mockRepo := new(mocks.CourseRepositoryInterface)
 
-	service := services.NewCourseService(mockRepo)
+	service := services.NewCourseService(mockRepo, nil)
 
 	testCases := []struct {
 		name           string
@@ -118,7 +120,7 @@ func TestCourseService_CreateCourse(t *testing.T) {
 
 	mockRepo := new(mocks.CourseRepositoryInterface)
 
-	service := services.NewCourseService(mockRepo)
+	service := services.NewCourseService(mockRepo, nil)
 
 	testCases := []struct {
 		name           string
@@ -196,3 +198,117 @@ func TestCourseService_CreateCourse(t *testing.T) {
 
 	mockRepo.AssertExpectations(t)
 }
+
+func TestCourseService_ChangeCourseStatus(t *testing.T) {
+	ownerID := uint(10)
+
+	testCases := []struct {
+		name          string
+		course        models.Course
+		actor         services.Actor
+		status        string
+		expectUpdate  bool
+		expectedError error
+	}{
+		{
+			name:         "Owner Submits For Review",
+			course:       models.Course{ID: 1, Status: models.CourseStatusDraft, CreatedBy: &ownerID},
+			actor:        services.Actor{UserID: ownerID, IsTeacher: true},
+			status:       models.CourseStatusInReview,
+			expectUpdate: true,
+		},
+		{
+			name:          "Other Teacher Cannot Submit",
+			course:        models.Course{ID: 1, Status: models.CourseStatusDraft, CreatedBy: &ownerID},
+			actor:         services.Actor{UserID: 11, IsTeacher: true},
+			status:        models.CourseStatusInReview,
+			expectedError: errors.New("insufficient permissions to change course status"),
+		},
+		{
+			name:         "Co-Instructor Submits For Review",
+			course:       models.Course{ID: 1, Status: models.CourseStatusDraft, CreatedBy: &ownerID},
+			actor:        services.Actor{UserID: 12},
+			status:       models.CourseStatusInReview,
+			expectUpdate: true,
+		},
+		{
+			name:          "Teaching Assistant Cannot Submit",
+			course:        models.Course{ID: 1, Status: models.CourseStatusDraft, CreatedBy: &ownerID},
+			actor:         services.Actor{UserID: 13, IsTeacher: true},
+			status:        models.CourseStatusInReview,
+			expectedError: errors.New("insufficient permissions to change course status"),
+		},
+		{
+			name:          "Co-Instructor Cannot Publish",
+			course:        models.Course{ID: 1, Status: models.CourseStatusInReview, CreatedBy: &ownerID},
+			actor:         services.Actor{UserID: 12, IsTeacher: true},
+			status:        models.CourseStatusPublished,
+			expectedError: errors.New("insufficient permissions to change course status"),
+		},
+		{
+			name:          "Teacher Cannot Publish",
+			course:        models.Course{ID: 1, Status: models.CourseStatusInReview, CreatedBy: &ownerID},
+			actor:         services.Actor{UserID: ownerID, IsTeacher: true},
+			status:        models.CourseStatusPublished,
+			expectedError: errors.New("insufficient permissions to change course status"),
+		},
+		{
+			name:         "Admin Publishes",
+			course:       models.Course{ID: 1, Status: models.CourseStatusInReview, CreatedBy: &ownerID},
+			actor:        services.Actor{UserID: 1, IsAdmin: true},
+			status:       models.CourseStatusPublished,
+			expectUpdate: true,
+		},
+		{
+			name:          "Draft Cannot Skip Review",
+			course:        models.Course{ID: 1, Status: models.CourseStatusDraft, CreatedBy: &ownerID},
+			actor:         services.Actor{UserID: 1, IsAdmin: true},
+			status:        models.CourseStatusPublished,
+			expectedError: errors.New("cannot change course status from draft to published"),
+		},
+		{
+			name:          "Already In Status",
+			course:        models.Course{ID: 1, Status: models.CourseStatusDraft, CreatedBy: &ownerID},
+			actor:         services.Actor{UserID: 1, IsAdmin: true},
+			status:        models.CourseStatusDraft,
+			expectedError: errors.New("course is already draft"),
+		},
+		{
+			name:          "Invalid Status",
+			course:        models.Course{ID: 1, Status: models.CourseStatusDraft, CreatedBy: &ownerID},
+			actor:         services.Actor{UserID: 1, IsAdmin: true},
+			status:        "deleted",
+			expectedError: errors.New("invalid course status"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockRepo := new(mocks.CourseRepositoryInterface)
+			memberRepo := new(mocks.CourseMemberRepositoryInterface)
+			service := services.NewCourseService(mockRepo, memberRepo)
+
+			mockRepo.On("GetByID", uint(1)).Return(tc.course, nil).Maybe()
+			memberRepo.On("GetRole", uint(1), uint(12)).Return(models.CourseMemberRoleCoInstructor, nil).Maybe()
+			memberRepo.On("GetRole", uint(1), uint(13)).Return(models.CourseMemberRoleTeachingAssistant, nil).Maybe()
+			memberRepo.On("GetRole", uint(1), mock.Anything).Return("", nil).Maybe()
+			if tc.expectUpdate {
+				mockRepo.On("UpdateStatus", tc.course, mock.MatchedBy(func(history models.CourseStatusHistory) bool {
+					return history.CourseID == 1 && history.FromStatus == tc.course.Status &&
+						history.ToStatus == tc.status && history.ChangedBy != nil && *history.ChangedBy == tc.actor.UserID
+				})).Return(models.Course{ID: 1, Status: tc.status, CreatedBy: &ownerID}, nil)
+			}
+
+			course, err := service.ChangeCourseStatus(1, tc.actor, schemas.ChangeCourseStatusRequest{Status: tc.status})
+
+			if tc.expectedError != nil {
+				assert.Error(t, err)
+				assert.Equal(t, tc.expectedError.Error(), err.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.status, course.Status)
+			}
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
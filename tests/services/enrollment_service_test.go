@@ -0,0 +1,243 @@
+package services_test
+
+import (
+	"errors"
+	"testing"
+	"web/mocks/repos"
+	"web/models"
+	"web/schemas"
+	"web/services"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestEnrollmentService_Enroll(t *testing.T) {
+	testCases := []struct {
+		name          string
+		userID        uint
+		courseID      uint
+		mockSetup     func(enrollmentRepo *mocks.EnrollmentRepositoryInterface, courseRepo *mocks.CourseRepositoryInterface)
+		expectedError error
+	}{
+		{
+			name:     "Success",
+			userID:   1,
+			courseID: 1,
+			mockSetup: func(enrollmentRepo *mocks.EnrollmentRepositoryInterface, courseRepo *mocks.CourseRepositoryInterface) {
+				courseRepo.On("GetByID", uint(1)).Return(models.Course{ID: 1, Name: "Test Course", Status: models.CourseStatusPublished}, nil)
+				enrollmentRepo.On("Exists", uint(1), uint(1)).Return(false, nil)
+				enrollmentRepo.On("Create", mock.MatchedBy(func(enrollment models.Enrollment) bool {
+					return enrollment.UserID == 1 && enrollment.CourseID == 1
+				})).Return(models.Enrollment{ID: 1, UserID: 1, CourseID: 1}, nil)
+			},
+			expectedError: nil,
+		},
+		{
+			name:     "Already Enrolled",
+			userID:   1,
+			courseID: 1,
+			mockSetup: func(enrollmentRepo *mocks.EnrollmentRepositoryInterface, courseRepo *mocks.CourseRepositoryInterface) {
+				courseRepo.On("GetByID", uint(1)).Return(models.Course{ID: 1, Status: models.CourseStatusPublished}, nil)
+				enrollmentRepo.On("Exists", uint(1), uint(1)).Return(true, nil)
+			},
+			expectedError: errors.New("already enrolled in this course"),
+		},
+		{
+			name:     "Course Not Published",
+			userID:   1,
+			courseID: 2,
+			mockSetup: func(enrollmentRepo *mocks.EnrollmentRepositoryInterface, courseRepo *mocks.CourseRepositoryInterface) {
+				courseRepo.On("GetByID", uint(2)).Return(models.Course{ID: 2, Status: models.CourseStatusDraft}, nil)
+			},
+			expectedError: errors.New("course is not published"),
+		},
+		{
+			name:     "Course Not Found",
+			userID:   1,
+			courseID: 999,
+			mockSetup: func(enrollmentRepo *mocks.EnrollmentRepositoryInterface, courseRepo *mocks.CourseRepositoryInterface) {
+				courseRepo.On("GetByID", uint(999)).Return(models.Course{}, errors.New("course not found"))
+			},
+			expectedError: errors.New("course not found"),
+		},
+		{
+			name:     "Invalid Course ID",
+			userID:   1,
+			courseID: 0,
+			mockSetup: func(enrollmentRepo *mocks.EnrollmentRepositoryInterface, courseRepo *mocks.CourseRepositoryInterface) {
+			},
+			expectedError: errors.New("course ID is required"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			enrollmentRepo := mocks.NewEnrollmentRepositoryInterface(t)
+			courseRepo := mocks.NewCourseRepositoryInterface(t)
+			tc.mockSetup(enrollmentRepo, courseRepo)
+
+			service := services.NewEnrollmentService(enrollmentRepo, courseRepo, nil)
+
+			enrollment, err := service.Enroll(tc.userID, tc.courseID)
+
+			if tc.expectedError != nil {
+				assert.Error(t, err)
+				assert.Equal(t, tc.expectedError.Error(), err.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.courseID, enrollment.CourseID)
+				assert.Equal(t, "Test Course", enrollment.CourseName)
+			}
+		})
+	}
+}
+
+func TestEnrollmentService_HasAccessToCourse(t *testing.T) {
+	ownerID := uint(2)
+	orgID, otherOrgID := uint(10), uint(20)
+
+	testCases := []struct {
+		name           string
+		actor          services.Actor
+		mockSetup      func(enrollmentRepo *mocks.EnrollmentRepositoryInterface, courseRepo *mocks.CourseRepositoryInterface, memberRepo *mocks.CourseMemberRepositoryInterface)
+		expectedAccess bool
+		expectedError  string
+	}{
+		{
+			name:  "Owner",
+			actor: services.Actor{UserID: ownerID},
+			mockSetup: func(enrollmentRepo *mocks.EnrollmentRepositoryInterface, courseRepo *mocks.CourseRepositoryInterface, memberRepo *mocks.CourseMemberRepositoryInterface) {
+				courseRepo.On("GetByID", uint(1)).Return(models.Course{ID: 1, CreatedBy: &ownerID}, nil)
+			},
+			expectedAccess: true,
+		},
+		{
+			name:  "Teacher",
+			actor: services.Actor{UserID: 6, IsTeacher: true},
+			mockSetup: func(enrollmentRepo *mocks.EnrollmentRepositoryInterface, courseRepo *mocks.CourseRepositoryInterface, memberRepo *mocks.CourseMemberRepositoryInterface) {
+				courseRepo.On("GetByID", uint(1)).Return(models.Course{ID: 1, CreatedBy: &ownerID}, nil)
+			},
+			expectedAccess: true,
+		},
+		{
+			name:  "Course Member",
+			actor: services.Actor{UserID: 5},
+			mockSetup: func(enrollmentRepo *mocks.EnrollmentRepositoryInterface, courseRepo *mocks.CourseRepositoryInterface, memberRepo *mocks.CourseMemberRepositoryInterface) {
+				courseRepo.On("GetByID", uint(1)).Return(models.Course{ID: 1, CreatedBy: &ownerID}, nil)
+				memberRepo.On("GetRole", uint(1), uint(5)).Return(models.CourseMemberRoleViewer, nil)
+			},
+			expectedAccess: true,
+		},
+		{
+			name:  "Course Member Of Draft Course",
+			actor: services.Actor{UserID: 5},
+			mockSetup: func(enrollmentRepo *mocks.EnrollmentRepositoryInterface, courseRepo *mocks.CourseRepositoryInterface, memberRepo *mocks.CourseMemberRepositoryInterface) {
+				courseRepo.On("GetByID", uint(1)).Return(models.Course{ID: 1, Status: models.CourseStatusDraft, CreatedBy: &ownerID}, nil)
+				memberRepo.On("GetRole", uint(1), uint(5)).Return(models.CourseMemberRoleViewer, nil)
+			},
+			expectedAccess: true,
+		},
+		{
+			name:  "Enrolled",
+			actor: services.Actor{UserID: 3},
+			mockSetup: func(enrollmentRepo *mocks.EnrollmentRepositoryInterface, courseRepo *mocks.CourseRepositoryInterface, memberRepo *mocks.CourseMemberRepositoryInterface) {
+				courseRepo.On("GetByID", uint(1)).Return(models.Course{ID: 1, Status: models.CourseStatusPublished, CreatedBy: &ownerID}, nil)
+				memberRepo.On("GetRole", uint(1), uint(3)).Return("", nil)
+				enrollmentRepo.On("Exists", uint(3), uint(1)).Return(true, nil)
+			},
+			expectedAccess: true,
+		},
+		{
+			name:  "Enrolled In Course Back In Draft",
+			actor: services.Actor{UserID: 3},
+			mockSetup: func(enrollmentRepo *mocks.EnrollmentRepositoryInterface, courseRepo *mocks.CourseRepositoryInterface, memberRepo *mocks.CourseMemberRepositoryInterface) {
+				courseRepo.On("GetByID", uint(1)).Return(models.Course{ID: 1, Status: models.CourseStatusDraft, CreatedBy: &ownerID}, nil)
+				memberRepo.On("GetRole", uint(1), uint(3)).Return("", nil)
+			},
+			expectedAccess: false,
+		},
+		{
+			name:  "Enrolled In Archived Course",
+			actor: services.Actor{UserID: 3},
+			mockSetup: func(enrollmentRepo *mocks.EnrollmentRepositoryInterface, courseRepo *mocks.CourseRepositoryInterface, memberRepo *mocks.CourseMemberRepositoryInterface) {
+				courseRepo.On("GetByID", uint(1)).Return(models.Course{ID: 1, Status: models.CourseStatusArchived, CreatedBy: &ownerID}, nil)
+				memberRepo.On("GetRole", uint(1), uint(3)).Return("", nil)
+			},
+			expectedAccess: false,
+		},
+		{
+			name:  "Not Enrolled",
+			actor: services.Actor{UserID: 4},
+			mockSetup: func(enrollmentRepo *mocks.EnrollmentRepositoryInterface, courseRepo *mocks.CourseRepositoryInterface, memberRepo *mocks.CourseMemberRepositoryInterface) {
+				courseRepo.On("GetByID", uint(1)).Return(models.Course{ID: 1, Status: models.CourseStatusPublished, CreatedBy: &ownerID}, nil)
+				memberRepo.On("GetRole", uint(1), uint(4)).Return("", nil)
+				enrollmentRepo.On("Exists", uint(4), uint(1)).Return(false, nil)
+			},
+			expectedAccess: false,
+		},
+		{
+			name:  "Teacher Of Another Organization",
+			actor: services.Actor{UserID: 6, IsTeacher: true, OrganizationID: &otherOrgID},
+			mockSetup: func(enrollmentRepo *mocks.EnrollmentRepositoryInterface, courseRepo *mocks.CourseRepositoryInterface, memberRepo *mocks.CourseMemberRepositoryInterface) {
+				courseRepo.On("GetByID", uint(1)).Return(models.Course{ID: 1, CreatedBy: &ownerID, OrganizationID: &orgID}, nil)
+			},
+			expectedError: "course not found",
+		},
+		{
+			name:  "Admin Without Organization",
+			actor: services.Actor{UserID: 8, IsAdmin: true},
+			mockSetup: func(enrollmentRepo *mocks.EnrollmentRepositoryInterface, courseRepo *mocks.CourseRepositoryInterface, memberRepo *mocks.CourseMemberRepositoryInterface) {
+				courseRepo.On("GetByID", uint(1)).Return(models.Course{ID: 1, CreatedBy: &ownerID, OrganizationID: &orgID}, nil)
+			},
+			expectedError: "course not found",
+		},
+		{
+			name:  "Platform Admin",
+			actor: services.Actor{UserID: 7, IsAdmin: true, PlatformAdmin: true},
+			mockSetup: func(enrollmentRepo *mocks.EnrollmentRepositoryInterface, courseRepo *mocks.CourseRepositoryInterface, memberRepo *mocks.CourseMemberRepositoryInterface) {
+				courseRepo.On("GetByID", uint(1)).Return(models.Course{ID: 1, CreatedBy: &ownerID, OrganizationID: &orgID}, nil)
+			},
+			expectedAccess: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			enrollmentRepo := mocks.NewEnrollmentRepositoryInterface(t)
+			courseRepo := mocks.NewCourseRepositoryInterface(t)
+			memberRepo := mocks.NewCourseMemberRepositoryInterface(t)
+			tc.mockSetup(enrollmentRepo, courseRepo, memberRepo)
+
+			service := services.NewEnrollmentService(enrollmentRepo, courseRepo, memberRepo)
+
+			hasAccess, err := service.HasAccessToCourse(tc.actor, 1)
+
+			if tc.expectedError != "" {
+				assert.EqualError(t, err, tc.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedAccess, hasAccess)
+		})
+	}
+}
+
+func TestEnrollmentService_GetUserEnrollments(t *testing.T) {
+	enrollmentRepo := mocks.NewEnrollmentRepositoryInterface(t)
+	service := services.NewEnrollmentService(enrollmentRepo, nil, nil)
+
+	expected := []schemas.EnrollmentResponse{
+		{ID: 1, CourseID: 1, CourseName: "Test Course 1"},
+		{ID: 2, CourseID: 2, CourseName: "Test Course 2"},
+	}
+	enrollmentRepo.On("GetByUserID", uint(1)).Return(expected, nil)
+
+	enrollments, err := service.GetUserEnrollments(1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, expected, enrollments)
+
+	_, err = service.GetUserEnrollments(0)
+	assert.EqualError(t, err, "user ID is required")
+}
@@ -0,0 +1,376 @@
+package services_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+	"web/config"
+	"web/mocks/repos"
+	"web/services"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeJWKSServer serves a JWKS with configurable cache headers and answers conditional
+// requests for its ETag with 304 Not Modified
+type fakeJWKSServer struct {
+	*httptest.Server
+	mu          sync.Mutex
+	keys        []map[string]string
+	header      http.Header
+	etag        string
+	failing     bool
+	requests    int
+	notModified int
+}
+
+func newFakeJWKSServer(t *testing.T, keys ...map[string]string) *fakeJWKSServer {
+	server := &fakeJWKSServer{keys: keys, header: http.Header{}}
+	server.Server = httptest.NewServer(http.HandlerFunc(server.serve))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func (s *fakeJWKSServer) serve(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.requests++
+	if s.failing {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	for name, values := range s.header {
+		w.Header()[name] = values
+	}
+	if s.etag != "" {
+		w.Header().Set("ETag", s.etag)
+		if r.Header.Get("If-None-Match") == s.etag {
+			s.notModified++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{"keys": s.keys})
+}
+
+func (s *fakeJWKSServer) setKeys(keys ...map[string]string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.keys = keys
+}
+
+func (s *fakeJWKSServer) setFailing(failing bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failing = failing
+}
+
+func (s *fakeJWKSServer) requestCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests
+}
+
+func rsaJWK(kid string, key *rsa.PublicKey) map[string]string {
+	return map[string]string{
+		"kid": kid,
+		"kty": "RSA",
+		"alg": "RS256",
+		"use": "sig",
+		"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+		"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+	}
+}
+
+func ecJWK(kid string, key *ecdsa.PublicKey) map[string]string {
+	size := (key.Curve.Params().BitSize + 7) / 8
+	return map[string]string{
+		"kid": kid,
+		"kty": "EC",
+		"alg": "ES256",
+		"use": "sig",
+		"crv": key.Curve.Params().Name,
+		"x":   base64.RawURLEncoding.EncodeToString(key.X.FillBytes(make([]byte, size))),
+		"y":   base64.RawURLEncoding.EncodeToString(key.Y.FillBytes(make([]byte, size))),
+	}
+}
+
+func generateTestKeys(t *testing.T) (*rsa.PrivateKey, *ecdsa.PrivateKey) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	return rsaKey, ecKey
+}
+
+// testJWKSOptions never let the keys expire or a fetch be rate limited unless a test says so
+func testJWKSOptions() services.JWKSCacheOptions {
+	return services.JWKSCacheOptions{
+		MinRefetchInterval: time.Hour,
+		DefaultTTL:         time.Hour,
+		MinTTL:             0,
+		MaxTTL:             24 * time.Hour,
+	}
+}
+
+func TestJWKSCache_RSAAndECKeys(t *testing.T) {
+	rsaKey, ecKey := generateTestKeys(t)
+	encKey := rsaJWK("enc-1", &rsaKey.PublicKey)
+	encKey["use"] = "enc"
+	server := newFakeJWKSServer(t,
+		rsaJWK("rsa-1", &rsaKey.PublicKey),
+		ecJWK("ec-1", &ecKey.PublicKey),
+		encKey,
+		map[string]string{"kid": "oct-1", "kty": "oct", "k": "c2VjcmV0"},
+	)
+	cache := services.NewJWKSCache(server.URL, testJWKSOptions())
+
+	key, err := cache.Key(context.Background(), "rsa-1")
+	require.NoError(t, err)
+	assert.True(t, rsaKey.PublicKey.Equal(key))
+
+	key, err = cache.Key(context.Background(), "ec-1")
+	require.NoError(t, err)
+	assert.True(t, ecKey.PublicKey.Equal(key))
+
+	// Encryption keys and unsupported key types never verify tokens
+	_, err = cache.Key(context.Background(), "enc-1")
+	assert.EqualError(t, err, "key with ID enc-1 not found")
+	_, err = cache.Key(context.Background(), "oct-1")
+	assert.EqualError(t, err, "key with ID oct-1 not found")
+
+	assert.Equal(t, 1, server.requestCount())
+}
+
+func TestJWKSCache_ConcurrentLookupsShareOneFetch(t *testing.T) {
+	rsaKey, _ := generateTestKeys(t)
+	server := newFakeJWKSServer(t, rsaJWK("rsa-1", &rsaKey.PublicKey))
+	cache := services.NewJWKSCache(server.URL, testJWKSOptions())
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := cache.Key(context.Background(), "rsa-1")
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, 1, server.requestCount())
+}
+
+func TestJWKSCache_KeyRotation(t *testing.T) {
+	oldKey, newKey := generateTestKeys(t)
+	server := newFakeJWKSServer(t, rsaJWK("old", &oldKey.PublicKey))
+	options := testJWKSOptions()
+	options.MinRefetchInterval = 50 * time.Millisecond
+	cache := services.NewJWKSCache(server.URL, options)
+
+	_, err := cache.Key(context.Background(), "old")
+	require.NoError(t, err)
+
+	server.setKeys(ecJWK("new", &newKey.PublicKey))
+
+	// Unknown kids refetch the JWKS at most once per MinRefetchInterval
+	_, err = cache.Key(context.Background(), "new")
+	assert.EqualError(t, err, "key with ID new not found")
+	_, err = cache.Key(context.Background(), "new")
+	assert.EqualError(t, err, "key with ID new not found")
+	assert.Equal(t, 1, server.requestCount())
+
+	time.Sleep(60 * time.Millisecond)
+	key, err := cache.Key(context.Background(), "new")
+	require.NoError(t, err)
+	assert.True(t, newKey.PublicKey.Equal(key))
+	assert.Equal(t, 2, server.requestCount())
+
+	// Keys removed from the JWKS are no longer accepted
+	_, err = cache.Key(context.Background(), "old")
+	assert.EqualError(t, err, "key with ID old not found")
+}
+
+func TestJWKSCache_CacheHeaders(t *testing.T) {
+	testCases := []struct {
+		name             string
+		header           http.Header
+		expectedRequests int
+	}{
+		{
+			name:             "No Headers Use The Default Lifetime",
+			header:           http.Header{},
+			expectedRequests: 1,
+		},
+		{
+			name:             "Max Age",
+			header:           http.Header{"Cache-Control": {"public, max-age=600"}},
+			expectedRequests: 1,
+		},
+		{
+			name:             "Max Age Used Up By Age",
+			header:           http.Header{"Cache-Control": {"max-age=600"}, "Age": {"600"}},
+			expectedRequests: 2,
+		},
+		{
+			name:             "No Store",
+			header:           http.Header{"Cache-Control": {"no-store"}},
+			expectedRequests: 2,
+		},
+		{
+			name:             "No Cache",
+			header:           http.Header{"Cache-Control": {"no-cache"}},
+			expectedRequests: 2,
+		},
+		{
+			name:             "Expires In The Future",
+			header:           http.Header{"Expires": {time.Now().Add(time.Hour).UTC().Format(http.TimeFormat)}},
+			expectedRequests: 1,
+		},
+		{
+			name:             "Expires In The Past",
+			header:           http.Header{"Expires": {time.Now().Add(-time.Hour).UTC().Format(http.TimeFormat)}},
+			expectedRequests: 2,
+		},
+	}
+
+	rsaKey, _ := generateTestKeys(t)
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := newFakeJWKSServer(t, rsaJWK("rsa-1", &rsaKey.PublicKey))
+			server.header = tc.header
+			options := testJWKSOptions()
+			options.MinRefetchInterval = 0
+			cache := services.NewJWKSCache(server.URL, options)
+
+			_, err := cache.Key(context.Background(), "rsa-1")
+			require.NoError(t, err)
+			_, err = cache.Key(context.Background(), "rsa-1")
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.expectedRequests, server.requestCount())
+		})
+	}
+}
+
+func TestJWKSCache_ConditionalRefresh(t *testing.T) {
+	rsaKey, _ := generateTestKeys(t)
+	server := newFakeJWKSServer(t, rsaJWK("rsa-1", &rsaKey.PublicKey))
+	server.etag = `"v1"`
+	cache := services.NewJWKSCache(server.URL, testJWKSOptions())
+
+	require.NoError(t, cache.Refresh(context.Background()))
+	require.NoError(t, cache.Refresh(context.Background()))
+
+	// The 304 response keeps the cached keys
+	key, err := cache.Key(context.Background(), "rsa-1")
+	require.NoError(t, err)
+	assert.True(t, rsaKey.PublicKey.Equal(key))
+	assert.Equal(t, 2, server.requestCount())
+	assert.Equal(t, 1, server.notModified)
+}
+
+func TestJWKSCache_ServesCachedKeysWhileTheServerFails(t *testing.T) {
+	rsaKey, _ := generateTestKeys(t)
+	server := newFakeJWKSServer(t, rsaJWK("rsa-1", &rsaKey.PublicKey))
+	server.header.Set("Cache-Control", "no-store")
+	options := testJWKSOptions()
+	options.MinRefetchInterval = 0
+	cache := services.NewJWKSCache(server.URL, options)
+
+	_, err := cache.Key(context.Background(), "rsa-1")
+	require.NoError(t, err)
+
+	server.setFailing(true)
+
+	key, err := cache.Key(context.Background(), "rsa-1")
+	require.NoError(t, err)
+	assert.True(t, rsaKey.PublicKey.Equal(key))
+
+	_, err = cache.Key(context.Background(), "rsa-2")
+	assert.ErrorContains(t, err, "failed to get JWKS: unexpected JWKS response: 500")
+}
+
+func TestJWKSCache_BackgroundRefresh(t *testing.T) {
+	rsaKey, ecKey := generateTestKeys(t)
+	server := newFakeJWKSServer(t, rsaJWK("rsa-1", &rsaKey.PublicKey))
+	server.header.Set("Cache-Control", "max-age=0")
+	options := testJWKSOptions()
+	options.MinTTL = 50 * time.Millisecond
+	cache := services.NewJWKSCache(server.URL, options)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go cache.Run(ctx)
+
+	assert.Eventually(t, func() bool { return server.requestCount() >= 3 }, 2*time.Second, 10*time.Millisecond)
+
+	// A rotated key is picked up by the background refresh without a lookup triggering it
+	server.setKeys(ecJWK("ec-1", &ecKey.PublicKey))
+	fetched := server.requestCount()
+	assert.Eventually(t, func() bool { return server.requestCount() > fetched }, 2*time.Second, 10*time.Millisecond)
+	cancel()
+
+	key, err := cache.Key(context.Background(), "ec-1")
+	require.NoError(t, err)
+	assert.True(t, ecKey.PublicKey.Equal(key))
+}
+
+func TestAuthService_ValidateToken_JWKS(t *testing.T) {
+	rsaKey, ecKey := generateTestKeys(t)
+	server := newFakeJWKSServer(t, rsaJWK("rsa-1", &rsaKey.PublicKey), ecJWK("ec-1", &ecKey.PublicKey))
+	appConfig := &config.AppConfig{KeycloakURL: server.URL, KeycloakRealm: "courses", KeycloakClientID: "course-api"}
+	service := services.NewAuthService(appConfig, services.NewKeycloakProvider(appConfig),
+		new(mocks.UserRepositoryInterface), new(mocks.OrganizationRepositoryInterface))
+
+	claims := services.KeycloakClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    server.URL + "/realms/courses",
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		},
+		PreferredUsername: "jdoe",
+	}
+	claims.RealmAccess.Roles = []string{"teacher"}
+	claims.ResourceAccess = map[string]struct {
+		Roles []string `json:"roles"`
+	}{"course-api": {Roles: []string{"admin"}}, "account": {Roles: []string{"manage-account"}}}
+	sign := func(method jwt.SigningMethod, kid string, key interface{}) string {
+		token := jwt.NewWithClaims(method, claims)
+		token.Header["kid"] = kid
+		signed, err := token.SignedString(key)
+		require.NoError(t, err)
+		return signed
+	}
+
+	validated, err := service.ValidateToken(sign(jwt.SigningMethodES256, "ec-1", ecKey))
+	require.NoError(t, err)
+	assert.Equal(t, "jdoe", validated.Username)
+	assert.Equal(t, []string{"teacher", "admin"}, validated.Roles)
+
+	validated, err = service.ValidateToken(sign(jwt.SigningMethodRS256, "rsa-1", rsaKey))
+	require.NoError(t, err)
+	assert.Equal(t, "jdoe", validated.Username)
+
+	// The kid decides the key, a token cannot pick a key of another type
+	_, err = service.ValidateToken(sign(jwt.SigningMethodES256, "rsa-1", ecKey))
+	assert.Error(t, err)
+
+	_, err = service.ValidateToken(sign(jwt.SigningMethodHS256, "rsa-1", []byte("secret")))
+	assert.ErrorContains(t, err, "unexpected signing method")
+
+	assert.Equal(t, 1, server.requestCount())
+}
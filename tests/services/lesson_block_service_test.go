@@ -0,0 +1,184 @@
+package services_test
+
+import (
+	"errors"
+	"testing"
+	"web/mocks/repos"
+	"web/models"
+	"web/schemas"
+	"web/services"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func testLessonBlocks() []models.LessonBlock {
+	return []models.LessonBlock{
+		{ID: 1, LessonID: 3, Position: 1, Type: models.LessonBlockTypeMarkdown, Text: "Intro"},
+		{ID: 2, LessonID: 3, Position: 2, Type: models.LessonBlockTypeCode, Text: "fmt.Println(1)", Language: "go"},
+	}
+}
+
+// blocksSaved returns the blocks the mocked repository stores, with IDs assigned to new blocks
+func blocksSaved(lesson models.Lesson) []models.LessonBlock {
+	saved := make([]models.LessonBlock, len(lesson.Blocks))
+	copy(saved, lesson.Blocks)
+	for i := range saved {
+		if saved[i].ID == 0 {
+			saved[i].ID = 10
+		}
+	}
+	return saved
+}
+
+func TestLessonBlockService_CreateBlock_Validation(t *testing.T) {
+	attachmentID := uint(5)
+
+	testCases := []struct {
+		name          string
+		request       schemas.LessonBlockRequest
+		expectedError string
+	}{
+		{"Invalid Type", schemas.LessonBlockRequest{Type: "table"}, "invalid block type"},
+		{"Empty Markdown", schemas.LessonBlockRequest{Type: models.LessonBlockTypeMarkdown, Text: "  "}, "markdown block text is required"},
+		{"Code Without Language", schemas.LessonBlockRequest{Type: models.LessonBlockTypeCode, Text: "x := 1"}, "code block language is required"},
+		{"Code With Invalid Language", schemas.LessonBlockRequest{Type: models.LessonBlockTypeCode, Text: "x := 1", Language: "go lang"}, "invalid code block language"},
+		{"Callout With Invalid Variant", schemas.LessonBlockRequest{Type: models.LessonBlockTypeCallout, Text: "Careful", Variant: "loud"}, "invalid callout variant"},
+		{"Video Without URL", schemas.LessonBlockRequest{Type: models.LessonBlockTypeVideo}, "video block URL is required"},
+		{"Video With Invalid URL", schemas.LessonBlockRequest{Type: models.LessonBlockTypeVideo, URL: "javascript:alert(1)"}, "video block URL must be an http or https URL"},
+		{"Attachment Without ID", schemas.LessonBlockRequest{Type: models.LessonBlockTypeAttachment}, "attachment block requires an attachment ID"},
+		{"Attachment Of Other Lesson", schemas.LessonBlockRequest{Type: models.LessonBlockTypeAttachment, AttachmentID: &attachmentID}, "attachment does not belong to this lesson"},
+		{"Negative Position", schemas.LessonBlockRequest{Type: models.LessonBlockTypeMarkdown, Text: "Hi", Position: -1}, "invalid block position"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			lessonRepo := new(mocks.LessonRepositoryInterface)
+			attachmentRepo := new(mocks.AttachmentRepositoryInterface)
+			service := services.NewLessonBlockService(lessonRepo, attachmentRepo)
+
+			lessonRepo.On("GetByID", uint(1), uint(2), uint(3), noTenant).Return(models.Lesson{ID: 3}, nil)
+			lessonRepo.On("GetBlocks", uint(3)).Return(testLessonBlocks(), nil)
+			attachmentRepo.On("GetByID", uint(5), noTenant).Return(models.Attachment{ID: 5, LessonID: 4}, nil).Maybe()
+
+			_, err := service.CreateBlock(1, 2, 3, tc.request, 7, noTenant)
+
+			assert.Error(t, err)
+			assert.Equal(t, tc.expectedError, err.Error())
+			lessonRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+// Attachments are looked up in the organization of the actor, so a block cannot reference
+// the attachment of another tenant
+func TestLessonBlockService_CreateBlock_AttachmentOfOtherOrganization(t *testing.T) {
+	orgID, attachmentID := uint(100), uint(5)
+	tenant := &schemas.TenantFilter{OrganizationID: &orgID}
+	lessonRepo := new(mocks.LessonRepositoryInterface)
+	attachmentRepo := new(mocks.AttachmentRepositoryInterface)
+	service := services.NewLessonBlockService(lessonRepo, attachmentRepo)
+
+	lessonRepo.On("GetByID", uint(1), uint(2), uint(3), tenant).Return(models.Lesson{ID: 3}, nil)
+	lessonRepo.On("GetBlocks", uint(3)).Return(testLessonBlocks(), nil)
+	attachmentRepo.On("GetByID", attachmentID, tenant).Return(models.Attachment{}, errors.New("attachment not found"))
+
+	_, err := service.CreateBlock(1, 2, 3, schemas.LessonBlockRequest{Type: models.LessonBlockTypeAttachment, AttachmentID: &attachmentID}, 7, tenant)
+
+	assert.EqualError(t, err, "attachment not found")
+	lessonRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestLessonBlockService_CreateBlock(t *testing.T) {
+	lessonRepo := new(mocks.LessonRepositoryInterface)
+	service := services.NewLessonBlockService(lessonRepo, new(mocks.AttachmentRepositoryInterface))
+
+	lessonRepo.On("GetByID", uint(1), uint(2), uint(3), noTenant).Return(models.Lesson{ID: 3, Name: "Lesson"}, nil)
+	lessonRepo.On("GetBlocks", uint(3)).Return(testLessonBlocks(), nil)
+
+	var stored models.Lesson
+	lessonRepo.On("Update", mock.AnythingOfType("models.Lesson"), mock.MatchedBy(func(revision models.LessonRevision) bool {
+		return revision.CreatedBy != nil && *revision.CreatedBy == 7
+	})).Run(func(args mock.Arguments) {
+		stored = args.Get(0).(models.Lesson)
+	}).Return(func(lesson models.Lesson, revision models.LessonRevision) models.LessonRevision {
+		return models.LessonRevision{Revision: 2, Blocks: blocksSaved(lesson)}
+	}, nil)
+
+	block, err := service.CreateBlock(1, 2, 3, schemas.LessonBlockRequest{
+		Type:     models.LessonBlockTypeCallout,
+		Text:     "Mind the gap",
+		Position: 2,
+	}, 7, noTenant)
+
+	assert.NoError(t, err)
+	assert.Equal(t, uint(10), block.ID)
+	assert.Equal(t, 2, block.Position)
+	assert.Equal(t, models.CalloutVariantInfo, block.Variant)
+
+	assert.Len(t, stored.Blocks, 3)
+	assert.Equal(t, []uint{1, 0, 2}, []uint{stored.Blocks[0].ID, stored.Blocks[1].ID, stored.Blocks[2].ID})
+	assert.Equal(t, []int{1, 2, 3}, []int{stored.Blocks[0].Position, stored.Blocks[1].Position, stored.Blocks[2].Position})
+	assert.Equal(t, "Intro\n\n> **Info**\n>\n> Mind the gap\n\n```go\nfmt.Println(1)\n```", stored.Content)
+	lessonRepo.AssertExpectations(t)
+}
+
+func TestLessonBlockService_ReorderBlocks(t *testing.T) {
+	testCases := []struct {
+		name          string
+		blockIDs      []uint
+		expectedError error
+	}{
+		{name: "Success", blockIDs: []uint{2, 1}},
+		{name: "Missing Block", blockIDs: []uint{2}, expectedError: errors.New("block order must list every block of the lesson exactly once")},
+		{name: "Duplicate Block", blockIDs: []uint{2, 2}, expectedError: errors.New("block order must list every block of the lesson exactly once")},
+		{name: "Unknown Block", blockIDs: []uint{2, 9}, expectedError: errors.New("block order must list every block of the lesson exactly once")},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			lessonRepo := new(mocks.LessonRepositoryInterface)
+			service := services.NewLessonBlockService(lessonRepo, new(mocks.AttachmentRepositoryInterface))
+
+			lessonRepo.On("GetByID", uint(1), uint(2), uint(3), noTenant).Return(models.Lesson{ID: 3}, nil)
+			lessonRepo.On("GetBlocks", uint(3)).Return(testLessonBlocks(), nil)
+			if tc.expectedError == nil {
+				lessonRepo.On("Update", mock.MatchedBy(func(lesson models.Lesson) bool {
+					return len(lesson.Blocks) == 2 && lesson.Blocks[0].ID == 2 && lesson.Blocks[0].Position == 1 &&
+						lesson.Blocks[1].ID == 1 && lesson.Blocks[1].Position == 2
+				}), mock.Anything).Return(func(lesson models.Lesson, revision models.LessonRevision) models.LessonRevision {
+					return models.LessonRevision{Blocks: blocksSaved(lesson)}
+				}, nil)
+			}
+
+			blocks, err := service.ReorderBlocks(1, 2, 3, schemas.ReorderLessonBlocksRequest{BlockIDs: tc.blockIDs}, 7, noTenant)
+
+			if tc.expectedError != nil {
+				assert.Error(t, err)
+				assert.Equal(t, tc.expectedError.Error(), err.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, uint(2), blocks[0].ID)
+				assert.Equal(t, uint(1), blocks[1].ID)
+			}
+			lessonRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestLessonBlockService_DeleteBlock(t *testing.T) {
+	lessonRepo := new(mocks.LessonRepositoryInterface)
+	service := services.NewLessonBlockService(lessonRepo, new(mocks.AttachmentRepositoryInterface))
+
+	lessonRepo.On("GetByID", uint(1), uint(2), uint(3), noTenant).Return(models.Lesson{ID: 3}, nil)
+	lessonRepo.On("GetBlocks", uint(3)).Return(testLessonBlocks(), nil)
+	lessonRepo.On("Update", mock.MatchedBy(func(lesson models.Lesson) bool {
+		return len(lesson.Blocks) == 1 && lesson.Blocks[0].ID == 2 && lesson.Content == "```go\nfmt.Println(1)\n```"
+	}), mock.Anything).Return(models.LessonRevision{}, nil)
+
+	assert.NoError(t, service.DeleteBlock(1, 2, 3, 1, 7, noTenant))
+
+	err := service.DeleteBlock(1, 2, 3, 99, 7, noTenant)
+	assert.Error(t, err)
+	assert.Equal(t, "block not found", err.Error())
+}
@@ -0,0 +1,74 @@
+package services_test
+
+import (
+	"strings"
+	"testing"
+	"web/schemas"
+	"web/services"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLessonRenderer_RenderMarkdown(t *testing.T) {
+	renderer := services.NewLessonRenderer(10)
+
+	rendered := renderer.RenderMarkdown("# Getting started\n\nGo is a *compiled* language.\n\n## Variables\n\n" +
+		"Use `var` to declare a variable.\n\n```go\nvar x int\n```\n\n### Short `:=` form\n")
+
+	assert.Contains(t, rendered.HTML, `<h1 id="getting-started">Getting started</h1>`)
+	assert.Contains(t, rendered.HTML, "<em>compiled</em>")
+	assert.Contains(t, rendered.HTML, `<code class="language-go">var x int`)
+	assert.Equal(t, []schemas.LessonTOCEntry{
+		{Level: 1, Title: "Getting started", Anchor: "getting-started"},
+		{Level: 2, Title: "Variables", Anchor: "variables"},
+		{Level: 3, Title: "Short := form", Anchor: "short--form"},
+	}, rendered.TOC)
+	assert.Equal(t, "Go is a compiled language. Use var to declare a variable.", rendered.Excerpt)
+}
+
+func TestLessonRenderer_Sanitizes(t *testing.T) {
+	renderer := services.NewLessonRenderer(10)
+
+	rendered := renderer.RenderMarkdown("Hello <script>alert(1)</script><b onclick=\"steal()\">world</b>\n\n" +
+		"[click](javascript:alert(1)) [file](/api/v1/attachments/download/4)\n\n<iframe src=\"https://evil.example\"></iframe>")
+
+	assert.NotContains(t, rendered.HTML, "<script")
+	assert.NotContains(t, rendered.HTML, "onclick")
+	assert.NotContains(t, rendered.HTML, "javascript:")
+	assert.NotContains(t, rendered.HTML, "<iframe")
+	assert.Contains(t, rendered.HTML, "<b>world</b>")
+	assert.Contains(t, rendered.HTML, `href="/api/v1/attachments/download/4"`)
+	assert.Equal(t, "Hello world click file", rendered.Excerpt)
+}
+
+func TestLessonRenderer_Excerpt(t *testing.T) {
+	renderer := services.NewLessonRenderer(10)
+
+	rendered := renderer.RenderMarkdown(strings.Repeat("lorem ipsum ", 50))
+
+	assert.LessOrEqual(t, len([]rune(rendered.Excerpt)), 200)
+	assert.True(t, strings.HasSuffix(rendered.Excerpt, "ipsum…") || strings.HasSuffix(rendered.Excerpt, "lorem…"))
+	assert.Empty(t, renderer.RenderMarkdown("").Excerpt)
+	assert.Empty(t, renderer.RenderMarkdown("").TOC)
+}
+
+func TestLessonRenderer_CachesPerRevision(t *testing.T) {
+	renderer := services.NewLessonRenderer(2)
+
+	first := renderer.Render(1, 1, "first")
+	assert.Equal(t, "<p>first</p>\n", first.HTML)
+
+	// A revision never changes, so the cached result is returned
+	assert.Equal(t, first, renderer.Render(1, 1, "changed"))
+
+	// A new revision is rendered again
+	assert.Equal(t, "<p>second</p>\n", renderer.Render(1, 2, "second").HTML)
+
+	// The least recently used revision is evicted once the cache is full
+	renderer.Render(2, 1, "other lesson")
+	assert.Equal(t, "<p>changed</p>\n", renderer.Render(1, 1, "changed").HTML)
+
+	// Content without a revision is never cached
+	assert.Equal(t, "<p>a</p>\n", renderer.Render(1, 0, "a").HTML)
+	assert.Equal(t, "<p>b</p>\n", renderer.Render(1, 0, "b").HTML)
+}
@@ -0,0 +1,214 @@
+package services_test
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"web/mocks/repos"
+	"web/models"
+	"web/schemas"
+	"web/services"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestLessonRevisionService_DiffRevisions(t *testing.T) {
+	lessonRepo := new(mocks.LessonRepositoryInterface)
+	service := services.NewLessonRevisionService(lessonRepo)
+
+	lessonRepo.On("GetByID", uint(1), uint(2), uint(3), noTenant).Return(models.Lesson{ID: 3}, nil)
+	lessonRepo.On("GetRevision", uint(3), 1).Return(models.LessonRevision{
+		LessonID: 3, Revision: 1, Name: "Intro", Description: "Overview", Order: 1,
+		Content: "first line\nsecond line\nthird line\n",
+	}, nil)
+	lessonRepo.On("GetRevision", uint(3), 2).Return(models.LessonRevision{
+		LessonID: 3, Revision: 2, Name: "Intro", Description: "Overview", Order: 2,
+		Content: "first line\nchanged line\nthird line\nfourth line\n",
+	}, nil)
+
+	diff, err := service.DiffRevisions(1, 2, 3, 1, 2, noTenant)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, diff.FromRevision)
+	assert.Equal(t, 2, diff.ToRevision)
+	assert.Len(t, diff.Changes, 2)
+
+	content := diff.Changes[0]
+	assert.Equal(t, "content", content.Field)
+	assert.Equal(t, 2, content.Added)
+	assert.Equal(t, 1, content.Removed)
+	assert.Equal(t, []schemas.LessonDiffLine{
+		{Op: schemas.DiffOpEqual, OldLine: 1, NewLine: 1, Text: "first line"},
+		{Op: schemas.DiffOpDelete, OldLine: 2, Text: "second line"},
+		{Op: schemas.DiffOpInsert, NewLine: 2, Text: "changed line"},
+		{Op: schemas.DiffOpEqual, OldLine: 3, NewLine: 3, Text: "third line"},
+		{Op: schemas.DiffOpInsert, NewLine: 4, Text: "fourth line"},
+	}, content.Lines)
+
+	order := diff.Changes[1]
+	assert.Equal(t, "order", order.Field)
+	assert.Equal(t, []schemas.LessonDiffLine{
+		{Op: schemas.DiffOpDelete, OldLine: 1, Text: "1"},
+		{Op: schemas.DiffOpInsert, NewLine: 1, Text: "2"},
+	}, order.Lines)
+
+	lessonRepo.AssertExpectations(t)
+}
+
+func TestLessonRevisionService_DiffRevisions_EmptyContent(t *testing.T) {
+	lessonRepo := new(mocks.LessonRepositoryInterface)
+	service := services.NewLessonRevisionService(lessonRepo)
+
+	lessonRepo.On("GetByID", uint(1), uint(2), uint(3), noTenant).Return(models.Lesson{ID: 3}, nil)
+	lessonRepo.On("GetRevision", uint(3), 1).Return(models.LessonRevision{LessonID: 3, Revision: 1, Name: "Intro"}, nil)
+	lessonRepo.On("GetRevision", uint(3), 2).Return(models.LessonRevision{LessonID: 3, Revision: 2, Name: "Intro", Content: "a\nb"}, nil)
+
+	diff, err := service.DiffRevisions(1, 2, 3, 1, 2, noTenant)
+
+	assert.NoError(t, err)
+	assert.Len(t, diff.Changes, 1)
+	assert.Equal(t, 2, diff.Changes[0].Added)
+	assert.Equal(t, 0, diff.Changes[0].Removed)
+}
+
+func TestLessonRevisionService_DiffRevisions_TooManyChanges(t *testing.T) {
+	lessonRepo := new(mocks.LessonRepositoryInterface)
+	service := services.NewLessonRevisionService(lessonRepo)
+
+	// 600 removed and 600 added lines exceed the limit of 1000 changed lines
+	var oldContent, newContent strings.Builder
+	for i := 0; i < 600; i++ {
+		fmt.Fprintf(&oldContent, "old line %d\n", i)
+		fmt.Fprintf(&newContent, "new line %d\n", i)
+	}
+
+	lessonRepo.On("GetByID", uint(1), uint(2), uint(3), noTenant).Return(models.Lesson{ID: 3}, nil)
+	lessonRepo.On("GetRevision", uint(3), 1).Return(models.LessonRevision{LessonID: 3, Revision: 1, Name: "Intro", Content: oldContent.String()}, nil)
+	lessonRepo.On("GetRevision", uint(3), 2).Return(models.LessonRevision{LessonID: 3, Revision: 2, Name: "Intro", Content: newContent.String()}, nil)
+
+	_, err := service.DiffRevisions(1, 2, 3, 1, 2, noTenant)
+
+	assert.EqualError(t, err, "revisions differ in too many lines to compare")
+}
+
+func TestLessonRevisionService_RestoreRevision(t *testing.T) {
+	current := models.Lesson{ID: 3, Name: "Intro", Description: "Overview", Content: "new text", Order: 1}
+	old := models.LessonRevision{LessonID: 3, Revision: 1, Name: "Intro", Description: "Overview", Content: "old text", Order: 1}
+
+	testCases := []struct {
+		name          string
+		revision      int
+		mockSetup     func(lessonRepo *mocks.LessonRepositoryInterface)
+		expectedError error
+	}{
+		{
+			name:     "Success",
+			revision: 1,
+			mockSetup: func(lessonRepo *mocks.LessonRepositoryInterface) {
+				lessonRepo.On("GetByID", uint(1), uint(2), uint(3), noTenant).Return(current, nil)
+				lessonRepo.On("GetRevision", uint(3), 1).Return(old, nil)
+				lessonRepo.On("Update", mock.MatchedBy(func(lesson models.Lesson) bool {
+					return lesson.ID == 3 && lesson.Content == "old text"
+				}), mock.MatchedBy(func(revision models.LessonRevision) bool {
+					return revision.RestoredFrom != nil && *revision.RestoredFrom == 1 &&
+						revision.CreatedBy != nil && *revision.CreatedBy == 7
+				})).Return(models.LessonRevision{LessonID: 3, Revision: 3, Content: "old text", RestoredFrom: intPtr(1)}, nil)
+			},
+		},
+		{
+			name:     "Already Current",
+			revision: 2,
+			mockSetup: func(lessonRepo *mocks.LessonRepositoryInterface) {
+				lessonRepo.On("GetByID", uint(1), uint(2), uint(3), noTenant).Return(current, nil)
+				lessonRepo.On("GetRevision", uint(3), 2).Return(models.LessonRevision{
+					LessonID: 3, Revision: 2, Name: "Intro", Description: "Overview", Content: "new text", Order: 1,
+				}, nil)
+			},
+			expectedError: errors.New("lesson already matches revision 2"),
+		},
+		{
+			name:     "Revision Not Found",
+			revision: 9,
+			mockSetup: func(lessonRepo *mocks.LessonRepositoryInterface) {
+				lessonRepo.On("GetByID", uint(1), uint(2), uint(3), noTenant).Return(current, nil)
+				lessonRepo.On("GetRevision", uint(3), 9).Return(models.LessonRevision{}, errors.New("revision not found"))
+			},
+			expectedError: errors.New("revision not found"),
+		},
+		{
+			name:     "Lesson Not Found",
+			revision: 1,
+			mockSetup: func(lessonRepo *mocks.LessonRepositoryInterface) {
+				lessonRepo.On("GetByID", uint(1), uint(2), uint(3), noTenant).Return(models.Lesson{}, errors.New("lesson not found"))
+			},
+			expectedError: errors.New("lesson not found"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			lessonRepo := new(mocks.LessonRepositoryInterface)
+			service := services.NewLessonRevisionService(lessonRepo)
+			tc.mockSetup(lessonRepo)
+
+			revision, err := service.RestoreRevision(1, 2, 3, tc.revision, 7, noTenant)
+
+			if tc.expectedError != nil {
+				assert.Error(t, err)
+				assert.Equal(t, tc.expectedError.Error(), err.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, 3, revision.Revision)
+				assert.Equal(t, "old text", revision.Content)
+			}
+			lessonRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestLessonRevisionService_RestoreRevisionAfterUpdateWithoutContent(t *testing.T) {
+	lessonRepo := new(mocks.LessonRepositoryInterface)
+	lessonService := services.NewLessonService(lessonRepo, nil, nil)
+	revisionService := services.NewLessonRevisionService(lessonRepo)
+
+	original := models.Lesson{ID: 3, Name: "Intro", Description: "Overview", Content: "lesson body", Order: 1}
+	lessonRepo.On("GetByID", uint(1), uint(2), uint(3), noTenant).Return(original, nil).Once()
+
+	var saved models.Lesson
+	lessonRepo.On("Update", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		saved = args.Get(0).(models.Lesson)
+	}).Return(models.LessonRevision{}, nil).Once()
+
+	// A PUT that only renames the lesson
+	err := lessonService.UpdateLesson(1, 2, 3, schemas.LessonRequest{Name: "Renamed"}, 7, noTenant)
+	assert.NoError(t, err)
+	assert.Equal(t, "Renamed", saved.Name)
+	assert.Equal(t, "Overview", saved.Description)
+	assert.Equal(t, "lesson body", saved.Content)
+	assert.Equal(t, 1, saved.Order)
+	assert.Nil(t, saved.Blocks)
+
+	// Restore the revision of that PUT after the content changed again
+	revision := models.LessonRevision{
+		LessonID: 3, Revision: 2, Name: saved.Name, Description: saved.Description, Content: saved.Content, Order: saved.Order,
+	}
+	current := saved
+	current.Content = "rewritten body"
+	lessonRepo.On("GetByID", uint(1), uint(2), uint(3), noTenant).Return(current, nil).Once()
+	lessonRepo.On("GetRevision", uint(3), 2).Return(revision, nil)
+	lessonRepo.On("Update", mock.MatchedBy(func(lesson models.Lesson) bool {
+		return lesson.Content == "lesson body" && lesson.Description == "Overview" && lesson.Order == 1
+	}), mock.Anything).Return(models.LessonRevision{LessonID: 3, Revision: 4, Content: "lesson body", RestoredFrom: intPtr(2)}, nil).Once()
+
+	restored, err := revisionService.RestoreRevision(1, 2, 3, 2, 7, noTenant)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "lesson body", restored.Content)
+	lessonRepo.AssertExpectations(t)
+}
+
+func intPtr(value int) *int {
+	return &value
+}
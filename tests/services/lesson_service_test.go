@@ -38,7 +38,7 @@ func TestLessonService_GetLessonsByChapterID(t *testing.T) {
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
 			// Call the service method
-			_, err := service.GetLessonsByChapterID(tc.courseID, tc.chapterID)
+			_, _, err := service.GetLessonsByChapterID(tc.courseID, tc.chapterID, schemas.ListParams{})
 
 			// Assert the results
 			if tc.expectedError != "" {
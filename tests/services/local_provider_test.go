@@ -0,0 +1,244 @@
+package services_test
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"testing"
+	"time"
+	"web/config"
+	"web/mocks/repos"
+	"web/models"
+	"web/services"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const localAuthTestSecret = "0123456789abcdef0123456789abcdef"
+
+func newLocalTestProvider(t *testing.T) (*services.LocalProvider, *mocks.UserRepositoryInterface, *mocks.RefreshTokenRepositoryInterface) {
+	appConfig := &config.AppConfig{
+		LocalAuthSecret:          localAuthTestSecret,
+		LocalAuthIssuer:          "course-api",
+		LocalAuthAccessTokenTTL:  15 * time.Minute,
+		LocalAuthRefreshTokenTTL: 24 * time.Hour,
+	}
+	userRepo := new(mocks.UserRepositoryInterface)
+	refreshTokenRepo := new(mocks.RefreshTokenRepositoryInterface)
+	provider, err := services.NewLocalProvider(appConfig, userRepo, refreshTokenRepo)
+	require.NoError(t, err)
+	return provider, userRepo, refreshTokenRepo
+}
+
+func localTestUser(t *testing.T, password string) models.User {
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.MinCost)
+	require.NoError(t, err)
+	return models.User{ID: 5, Username: "jdoe", Email: "jdoe@example.com", Password: string(hashedPassword), Roles: "teacher, admin", Sub: "sub-5"}
+}
+
+func activeSession(id uint) models.RefreshToken {
+	return models.RefreshToken{ID: id, UserID: 5, ExpiresAt: time.Now().Add(time.Hour)}
+}
+
+func refreshTokenHash(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(hash[:])
+}
+
+func TestLocalProvider_Login(t *testing.T) {
+	provider, userRepo, refreshTokenRepo := newLocalTestProvider(t)
+	user := localTestUser(t, "secret")
+	userRepo.On("GetByUsername", "jdoe").Return(user, nil)
+	userRepo.On("GetByUsername", "unknown").Return(models.User{}, errors.New("user not found"))
+	refreshTokenRepo.On("Create", mock.MatchedBy(func(token models.RefreshToken) bool {
+		return token.UserID == 5 && len(token.TokenHash) == 64 && token.ExpiresAt.After(time.Now().Add(23*time.Hour))
+	})).Return(models.RefreshToken{ID: 7}, nil)
+	refreshTokenRepo.On("GetByID", uint(7)).Return(activeSession(7), nil)
+
+	tokens, err := provider.Login("jdoe", "secret")
+	require.NoError(t, err)
+	assert.Equal(t, "Bearer", tokens.TokenType)
+	assert.Equal(t, 900, tokens.ExpiresIn)
+	assert.NotEmpty(t, tokens.RefreshToken)
+
+	claims, err := provider.ValidateToken(tokens.AccessToken)
+	require.NoError(t, err)
+	assert.Equal(t, "sub-5", claims.Subject)
+	assert.Equal(t, "jdoe", claims.Username)
+	assert.Equal(t, "jdoe@example.com", claims.Email)
+	assert.Equal(t, []string{"teacher", "admin"}, claims.Roles)
+
+	// The stored hash belongs to the returned refresh token
+	created := refreshTokenRepo.Calls[0].Arguments.Get(0).(models.RefreshToken)
+	assert.Equal(t, refreshTokenHash(tokens.RefreshToken), created.TokenHash)
+
+	_, err = provider.Login("jdoe", "wrong")
+	assert.EqualError(t, err, "invalid username or password")
+
+	_, err = provider.Login("unknown", "secret")
+	assert.EqualError(t, err, "invalid username or password")
+
+	refreshTokenRepo.AssertNumberOfCalls(t, "Create", 1)
+}
+
+func TestLocalProvider_LoginAssignsMissingSubject(t *testing.T) {
+	provider, userRepo, refreshTokenRepo := newLocalTestProvider(t)
+	user := localTestUser(t, "secret")
+	user.Sub = ""
+	userRepo.On("GetByUsername", "jdoe").Return(user, nil)
+	userRepo.On("UpdateSub", uint(5), mock.AnythingOfType("string")).Return(nil)
+	refreshTokenRepo.On("Create", mock.Anything).Return(models.RefreshToken{ID: 7}, nil)
+	refreshTokenRepo.On("GetByID", uint(7)).Return(activeSession(7), nil)
+
+	tokens, err := provider.Login("jdoe", "secret")
+	require.NoError(t, err)
+
+	claims, err := provider.ValidateToken(tokens.AccessToken)
+	require.NoError(t, err)
+	assert.Equal(t, userRepo.Calls[1].Arguments.String(1), claims.Subject)
+	assert.Len(t, claims.Subject, 36)
+}
+
+func TestLocalProvider_ValidateToken(t *testing.T) {
+	provider, _, refreshTokenRepo := newLocalTestProvider(t)
+	revokedAt := time.Now()
+	refreshTokenRepo.On("GetByID", uint(7)).Return(activeSession(7), nil)
+	refreshTokenRepo.On("GetByID", uint(8)).Return(models.RefreshToken{ID: 8, UserID: 5, ExpiresAt: time.Now().Add(time.Hour), RevokedAt: &revokedAt}, nil)
+	refreshTokenRepo.On("GetByID", uint(9)).Return(models.RefreshToken{}, errors.New("refresh token not found"))
+
+	signSession := func(method jwt.SigningMethod, key interface{}, issuer string, expiresIn time.Duration, sessionID uint) string {
+		token := jwt.NewWithClaims(method, jwt.MapClaims{
+			"iss": issuer,
+			"sub": "sub-5",
+			"exp": time.Now().Add(expiresIn).Unix(),
+			"sid": sessionID,
+		})
+		signed, err := token.SignedString(key)
+		require.NoError(t, err)
+		return signed
+	}
+	sign := func(method jwt.SigningMethod, key interface{}, issuer string, expiresIn time.Duration) string {
+		return signSession(method, key, issuer, expiresIn, 7)
+	}
+
+	_, err := provider.ValidateToken(sign(jwt.SigningMethodHS256, []byte(localAuthTestSecret), "course-api", time.Minute))
+	assert.NoError(t, err)
+
+	// Access tokens end with their session, also when they are not introspected
+	_, err = provider.ValidateToken(signSession(jwt.SigningMethodHS256, []byte(localAuthTestSecret), "course-api", time.Minute, 8))
+	assert.EqualError(t, err, "session is no longer active")
+
+	_, err = provider.ValidateToken(signSession(jwt.SigningMethodHS256, []byte(localAuthTestSecret), "course-api", time.Minute, 9))
+	assert.EqualError(t, err, "session is no longer active")
+
+	_, err = provider.ValidateToken(sign(jwt.SigningMethodHS256, []byte("another-secret-of-thirty-two-chars"), "course-api", time.Minute))
+	assert.ErrorContains(t, err, "signature is invalid")
+
+	_, err = provider.ValidateToken(sign(jwt.SigningMethodHS512, []byte(localAuthTestSecret), "course-api", time.Minute))
+	assert.ErrorContains(t, err, "signing method HS512 is invalid")
+
+	_, err = provider.ValidateToken(sign(jwt.SigningMethodHS256, []byte(localAuthTestSecret), "other", time.Minute))
+	assert.ErrorContains(t, err, "token has invalid issuer")
+
+	_, err = provider.ValidateToken(sign(jwt.SigningMethodHS256, []byte(localAuthTestSecret), "course-api", -time.Minute))
+	assert.ErrorContains(t, err, "token is expired")
+}
+
+func TestLocalProvider_RefreshToken(t *testing.T) {
+	provider, userRepo, refreshTokenRepo := newLocalTestProvider(t)
+	user := localTestUser(t, "secret")
+	userRepo.On("GetByID", uint(5)).Return(user, nil)
+
+	revokedAt := time.Now().Add(-time.Minute)
+	refreshTokenRepo.On("GetByHash", refreshTokenHash("active")).Return(models.RefreshToken{ID: 7, UserID: 5, ExpiresAt: time.Now().Add(time.Hour)}, nil)
+	refreshTokenRepo.On("GetByHash", refreshTokenHash("revoked")).Return(models.RefreshToken{ID: 8, UserID: 5, ExpiresAt: time.Now().Add(time.Hour), RevokedAt: &revokedAt}, nil)
+	refreshTokenRepo.On("GetByHash", refreshTokenHash("expired")).Return(models.RefreshToken{ID: 9, UserID: 5, ExpiresAt: time.Now().Add(-time.Minute)}, nil)
+	refreshTokenRepo.On("GetByHash", refreshTokenHash("unknown")).Return(models.RefreshToken{}, errors.New("refresh token not found"))
+	refreshTokenRepo.On("Rotate", uint(7), refreshTokenHash("active"), mock.AnythingOfType("string"), mock.AnythingOfType("time.Time")).Return(nil).Once()
+	refreshTokenRepo.On("GetByID", uint(7)).Return(activeSession(7), nil)
+
+	tokens, err := provider.RefreshToken("active")
+	require.NoError(t, err)
+	assert.NotEqual(t, "active", tokens.RefreshToken)
+	refreshTokenRepo.AssertCalled(t, "Rotate", uint(7), refreshTokenHash("active"), refreshTokenHash(tokens.RefreshToken), mock.AnythingOfType("time.Time"))
+
+	claims, err := provider.ValidateToken(tokens.AccessToken)
+	require.NoError(t, err)
+	assert.Equal(t, "sub-5", claims.Subject)
+
+	// A refresh token that was rotated concurrently cannot be used again
+	refreshTokenRepo.On("Rotate", uint(7), refreshTokenHash("active"), mock.AnythingOfType("string"), mock.AnythingOfType("time.Time")).Return(errors.New("refresh token not found"))
+	_, err = provider.RefreshToken("active")
+	assert.EqualError(t, err, "invalid refresh token")
+
+	for _, token := range []string{"revoked", "expired", "unknown"} {
+		_, err := provider.RefreshToken(token)
+		assert.EqualError(t, err, "invalid refresh token", token)
+	}
+}
+
+func TestLocalProvider_Revocation(t *testing.T) {
+	provider, userRepo, refreshTokenRepo := newLocalTestProvider(t)
+	userRepo.On("GetByUsername", "jdoe").Return(localTestUser(t, "secret"), nil)
+	refreshTokenRepo.On("Create", mock.Anything).Return(models.RefreshToken{ID: 7}, nil)
+
+	tokens, err := provider.Login("jdoe", "secret")
+	require.NoError(t, err)
+
+	refreshTokenRepo.On("GetByID", uint(7)).Return(models.RefreshToken{ID: 7, UserID: 5, ExpiresAt: time.Now().Add(time.Hour)}, nil).Once()
+	active, err := provider.IntrospectToken(tokens.AccessToken)
+	require.NoError(t, err)
+	assert.True(t, active)
+
+	refreshTokenRepo.On("GetByHash", refreshTokenHash(tokens.RefreshToken)).Return(models.RefreshToken{ID: 7, UserID: 5}, nil)
+	refreshTokenRepo.On("Revoke", uint(7)).Return(nil)
+	require.NoError(t, provider.RevokeToken(tokens.RefreshToken))
+	refreshTokenRepo.AssertCalled(t, "Revoke", uint(7))
+
+	// Access tokens of a revoked session are no longer active
+	revokedAt := time.Now()
+	refreshTokenRepo.On("GetByID", uint(7)).Return(models.RefreshToken{ID: 7, UserID: 5, ExpiresAt: time.Now().Add(time.Hour), RevokedAt: &revokedAt}, nil)
+	active, err = provider.IntrospectToken(tokens.AccessToken)
+	require.NoError(t, err)
+	assert.False(t, active)
+
+	_, err = provider.ValidateToken(tokens.AccessToken)
+	assert.EqualError(t, err, "session is no longer active")
+
+	// Revoking an unknown token is not an error
+	refreshTokenRepo.On("GetByHash", refreshTokenHash("unknown")).Return(models.RefreshToken{}, errors.New("refresh token not found"))
+	assert.NoError(t, provider.RevokeToken("unknown"))
+
+	active, err = provider.IntrospectToken("not-a-token")
+	require.NoError(t, err)
+	assert.False(t, active)
+}
+
+func TestLocalProvider_CreateUserAndAssignRoles(t *testing.T) {
+	provider, userRepo, _ := newLocalTestProvider(t)
+	userRepo.On("GetByUsername", "jdoe").Return(models.User{}, errors.New("user not found"))
+	userRepo.On("GetByEmail", "jdoe@example.com").Return(models.User{}, errors.New("user not found"))
+	userRepo.On("Create", mock.MatchedBy(func(user models.User) bool {
+		return user.Username == "jdoe" && bcrypt.CompareHashAndPassword([]byte(user.Password), []byte("secret")) == nil
+	})).Return(func(user models.User) models.User {
+		user.ID = 5
+		return user
+	}, nil)
+
+	subject, err := provider.CreateUser("jdoe", "jdoe@example.com", "secret")
+	require.NoError(t, err)
+	assert.Len(t, subject, 36)
+
+	userRepo.On("GetBySub", subject).Return(models.User{ID: 5, Roles: "teacher", Sub: subject}, nil)
+	userRepo.On("UpdateRoles", uint(5), "teacher,admin").Return(nil)
+	require.NoError(t, provider.AssignRoles(subject, []string{"admin", "teacher"}))
+	userRepo.AssertCalled(t, "UpdateRoles", uint(5), "teacher,admin")
+
+	userRepo.On("GetByUsername", "taken").Return(models.User{ID: 6}, nil)
+	_, err = provider.CreateUser("taken", "taken@example.com", "secret")
+	assert.EqualError(t, err, "username already exists")
+}
@@ -0,0 +1,164 @@
+package services_test
+
+import (
+	"crypto/rsa"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"web/config"
+	"web/services"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newFakeOIDCServer serves a discovery document naming itself as the issuer, with its JWKS
+// and a token endpoint that accepts the password "secret"
+func newFakeOIDCServer(t *testing.T, key *rsa.PublicKey, discovery map[string]string) *httptest.Server {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	document := map[string]string{
+		"issuer":         server.URL,
+		"jwks_uri":       server.URL + "/jwks",
+		"token_endpoint": server.URL + "/token",
+	}
+	for name, value := range discovery {
+		document[name] = value
+	}
+
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(document)
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"keys": []map[string]string{rsaJWK("rsa-1", key)}})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		if r.PostFormValue("password") != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"access_token": "access", "refresh_token": "refresh", "token_type": "Bearer", "expires_in": 300,
+		})
+	})
+	return server
+}
+
+func TestOIDCProvider_Discovery(t *testing.T) {
+	rsaKey, _ := generateTestKeys(t)
+	server := newFakeOIDCServer(t, &rsaKey.PublicKey, nil)
+
+	provider, err := services.NewOIDCProvider(server.URL, "course-api", "", "")
+	require.NoError(t, err)
+
+	tokens, err := provider.Login("jdoe", "secret")
+	require.NoError(t, err)
+	assert.Equal(t, "access", tokens.AccessToken)
+	assert.Equal(t, "refresh", tokens.RefreshToken)
+	assert.Equal(t, 300, tokens.ExpiresIn)
+
+	_, err = provider.Login("jdoe", "wrong")
+	assert.ErrorContains(t, err, "authentication failed")
+
+	// The provider does not announce an introspection endpoint
+	_, err = provider.IntrospectToken("access")
+	assert.EqualError(t, err, "token introspection is not supported by the identity provider")
+
+	_, err = provider.CreateUser("jdoe", "jdoe@example.com", "secret")
+	assert.EqualError(t, err, "user provisioning is not supported by the identity provider")
+}
+
+func TestOIDCProvider_DiscoveryRequiresMatchingIssuer(t *testing.T) {
+	rsaKey, _ := generateTestKeys(t)
+	server := newFakeOIDCServer(t, &rsaKey.PublicKey, map[string]string{"issuer": "https://other.example.com"})
+
+	_, err := services.NewOIDCProvider(server.URL, "course-api", "", "")
+	assert.ErrorContains(t, err, "OIDC discovery document names issuer https://other.example.com")
+}
+
+func TestNewIdentityProvider_OIDCRequiresIntrospectionEndpoint(t *testing.T) {
+	rsaKey, _ := generateTestKeys(t)
+	server := newFakeOIDCServer(t, &rsaKey.PublicKey, nil)
+	appConfig := func(tokenValidation string) *config.AppConfig {
+		return &config.AppConfig{
+			IdentityProvider: config.IdentityProviderOIDC,
+			OIDCIssuerURL:    server.URL,
+			OIDCClientID:     "course-api",
+			TokenValidation:  tokenValidation,
+		}
+	}
+
+	for _, tokenValidation := range []string{config.TokenValidationIntrospect, config.TokenValidationIntrospectMutations} {
+		_, err := services.NewIdentityProvider(appConfig(tokenValidation), nil, nil)
+		assert.EqualError(t, err, "OIDC discovery document has no introspection_endpoint, set TOKEN_VALIDATION=local to validate tokens without it")
+	}
+
+	_, err := services.NewIdentityProvider(appConfig(config.TokenValidationLocal), nil, nil)
+	assert.NoError(t, err)
+
+	server = newFakeOIDCServer(t, &rsaKey.PublicKey, map[string]string{"introspection_endpoint": "https://idp.example.com/introspect"})
+	_, err = services.NewIdentityProvider(appConfig(config.TokenValidationIntrospect), nil, nil)
+	assert.NoError(t, err)
+}
+
+func TestOIDCProvider_ValidateToken(t *testing.T) {
+	rsaKey, _ := generateTestKeys(t)
+	server := newFakeOIDCServer(t, &rsaKey.PublicKey, nil)
+
+	provider, err := services.NewOIDCProvider(server.URL, "course-api", "", "realm_access.roles")
+	require.NoError(t, err)
+
+	sign := func(claims jwt.MapClaims) string {
+		token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+		token.Header["kid"] = "rsa-1"
+		signed, err := token.SignedString(rsaKey)
+		require.NoError(t, err)
+		return signed
+	}
+	claims := func(overrides jwt.MapClaims) jwt.MapClaims {
+		claims := jwt.MapClaims{
+			"iss":          server.URL,
+			"sub":          "user-1",
+			"aud":          []string{"course-api"},
+			"exp":          time.Now().Add(time.Hour).Unix(),
+			"email":        "jdoe@example.com",
+			"realm_access": map[string]interface{}{"roles": []string{"teacher", "admin"}},
+			"groups":       []string{"/organizations/springfield"},
+		}
+		for name, value := range overrides {
+			claims[name] = value
+		}
+		return claims
+	}
+
+	validated, err := provider.ValidateToken(sign(claims(nil)))
+	require.NoError(t, err)
+	assert.Equal(t, "user-1", validated.Subject)
+	assert.Equal(t, "jdoe@example.com", validated.Username)
+	assert.Equal(t, []string{"teacher", "admin"}, validated.Roles)
+	assert.Equal(t, []string{"/organizations/springfield"}, validated.Groups)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), validated.ExpiresAt, time.Minute)
+
+	// A token for another audience is accepted when the client is the authorized party
+	_, err = provider.ValidateToken(sign(claims(jwt.MapClaims{"aud": "account", "azp": "course-api"})))
+	assert.NoError(t, err)
+
+	_, err = provider.ValidateToken(sign(claims(jwt.MapClaims{"aud": "account"})))
+	assert.EqualError(t, err, "invalid token audience: expected course-api")
+
+	_, err = provider.ValidateToken(sign(claims(jwt.MapClaims{"iss": server.URL + "/other"})))
+	assert.EqualError(t, err, "invalid token issuer: expected "+server.URL)
+
+	_, err = provider.ValidateToken(sign(claims(jwt.MapClaims{"exp": time.Now().Add(-time.Minute).Unix()})))
+	assert.ErrorContains(t, err, "token is expired")
+
+	withoutExpiry := claims(nil)
+	delete(withoutExpiry, "exp")
+	_, err = provider.ValidateToken(sign(withoutExpiry))
+	assert.ErrorContains(t, err, "exp claim is required")
+}
@@ -0,0 +1,203 @@
+package services_test
+
+import (
+	"errors"
+	"testing"
+	"web/config"
+	"web/mocks/repos"
+	"web/models"
+	"web/schemas"
+	"web/services"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestOrganizationService_Create(t *testing.T) {
+	testCases := []struct {
+		name          string
+		request       schemas.CreateOrganizationRequest
+		expectedError string
+	}{
+		{
+			name:    "Valid Organization",
+			request: schemas.CreateOrganizationRequest{Name: " Springfield High ", Slug: "springfield-high"},
+		},
+		{
+			name:          "Missing Name",
+			request:       schemas.CreateOrganizationRequest{Name: " ", Slug: "springfield-high"},
+			expectedError: "organization name is required",
+		},
+		{
+			name:          "Invalid Slug",
+			request:       schemas.CreateOrganizationRequest{Name: "Springfield High", Slug: "Springfield High"},
+			expectedError: "invalid organization slug",
+		},
+		{
+			name:          "Slug Already Exists",
+			request:       schemas.CreateOrganizationRequest{Name: "Shelbyville High", Slug: "shelbyville-high"},
+			expectedError: "organization slug already exists",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := new(mocks.OrganizationRepositoryInterface)
+			service := services.NewOrganizationService(repo, new(mocks.UserRepositoryInterface))
+
+			repo.On("ExistsBySlug", "springfield-high").Return(false, nil).Maybe()
+			repo.On("ExistsBySlug", "shelbyville-high").Return(true, nil).Maybe()
+			repo.On("Create", models.Organization{Name: "Springfield High", Slug: "springfield-high"}).
+				Return(models.Organization{ID: 1, Name: "Springfield High", Slug: "springfield-high"}, nil).Maybe()
+
+			organization, err := service.Create(tc.request)
+
+			if tc.expectedError != "" {
+				assert.EqualError(t, err, tc.expectedError)
+				repo.AssertNotCalled(t, "Create", mock.Anything)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, uint(1), organization.ID)
+		})
+	}
+}
+
+func TestOrganizationService_Update(t *testing.T) {
+	repo := new(mocks.OrganizationRepositoryInterface)
+	service := services.NewOrganizationService(repo, new(mocks.UserRepositoryInterface))
+
+	repo.On("GetByID", uint(1)).Return(models.Organization{ID: 1, Name: "Springfield", Slug: "springfield"}, nil)
+	repo.On("ExistsBySlug", "shelbyville").Return(true, nil)
+	repo.On("Update", models.Organization{ID: 1, Name: "Springfield High", Slug: "springfield"}).
+		Return(models.Organization{ID: 1, Name: "Springfield High", Slug: "springfield"}, nil)
+
+	// Keeping the slug does not conflict with the organization itself
+	organization, err := service.Update(1, schemas.UpdateOrganizationRequest{Name: "Springfield High", Slug: "springfield"})
+	assert.NoError(t, err)
+	assert.Equal(t, "Springfield High", organization.Name)
+	repo.AssertNotCalled(t, "ExistsBySlug", "springfield")
+
+	_, err = service.Update(1, schemas.UpdateOrganizationRequest{Name: "Springfield High", Slug: "shelbyville"})
+	assert.EqualError(t, err, "organization slug already exists")
+}
+
+func TestOrganizationService_Delete(t *testing.T) {
+	repo := new(mocks.OrganizationRepositoryInterface)
+	service := services.NewOrganizationService(repo, new(mocks.UserRepositoryInterface))
+
+	repo.On("GetByID", uint(1)).Return(models.Organization{ID: 1}, nil)
+	repo.On("GetByID", uint(2)).Return(models.Organization{ID: 2}, nil)
+	repo.On("GetByID", uint(9)).Return(models.Organization{}, errors.New("organization not found"))
+	repo.On("IsEmpty", uint(1)).Return(true, nil)
+	repo.On("IsEmpty", uint(2)).Return(false, nil)
+	repo.On("Delete", uint(1)).Return(nil)
+
+	assert.NoError(t, service.Delete(1))
+	assert.EqualError(t, service.Delete(2), "organization still has users or courses")
+	assert.EqualError(t, service.Delete(9), "organization not found")
+	repo.AssertNotCalled(t, "Delete", uint(2))
+}
+
+func TestOrganizationService_AssignAndRemoveUser(t *testing.T) {
+	orgID, otherOrgID := uint(1), uint(2)
+	repo := new(mocks.OrganizationRepositoryInterface)
+	userRepo := new(mocks.UserRepositoryInterface)
+	service := services.NewOrganizationService(repo, userRepo)
+
+	repo.On("GetByID", orgID).Return(models.Organization{ID: orgID}, nil)
+	repo.On("GetByID", uint(9)).Return(models.Organization{}, errors.New("organization not found"))
+	userRepo.On("UpdateOrganization", uint(5), &orgID).Return(nil)
+	userRepo.On("UpdateOrganization", uint(5), (*uint)(nil)).Return(nil)
+	userRepo.On("GetByID", uint(5)).Return(models.User{ID: 5, OrganizationID: &orgID}, nil)
+	userRepo.On("GetByID", uint(6)).Return(models.User{ID: 6, OrganizationID: &otherOrgID}, nil)
+
+	assert.NoError(t, service.AssignUser(orgID, 5))
+	assert.EqualError(t, service.AssignUser(9, 5), "organization not found")
+
+	assert.NoError(t, service.RemoveUser(orgID, 5))
+	assert.EqualError(t, service.RemoveUser(orgID, 6), "user does not belong to this organization")
+	userRepo.AssertNotCalled(t, "UpdateOrganization", uint(6), mock.Anything)
+}
+
+func TestActor_InOrganization(t *testing.T) {
+	orgID, otherOrgID := uint(1), uint(2)
+
+	assert.True(t, services.Actor{IsAdmin: true, PlatformAdmin: true}.InOrganization(&orgID))
+	assert.False(t, services.Actor{IsAdmin: true}.InOrganization(&orgID))
+	assert.True(t, services.Actor{}.InOrganization(nil))
+	assert.False(t, services.Actor{}.InOrganization(&orgID))
+	assert.True(t, services.Actor{OrganizationID: &orgID}.InOrganization(&orgID))
+	assert.False(t, services.Actor{OrganizationID: &orgID}.InOrganization(&otherOrgID))
+	assert.False(t, services.Actor{IsAdmin: true, OrganizationID: &orgID}.InOrganization(nil))
+}
+
+func TestAuthService_NewActor_PlatformAdmin(t *testing.T) {
+	orgID, otherOrgID := uint(1), uint(2)
+	service := services.NewAuthService(&config.AppConfig{}, nil, nil, nil)
+
+	platformAdmin := service.NewActor(models.User{ID: 1, OrganizationID: &orgID}, &services.IdentityClaims{Roles: []string{services.RolePlatformAdmin}})
+	assert.True(t, platformAdmin.IsAdmin)
+	assert.True(t, platformAdmin.IsPlatformAdmin())
+	assert.True(t, platformAdmin.InOrganization(&otherOrgID))
+	assert.Nil(t, platformAdmin.TenantFilter())
+
+	// An admin whose token names no organization only administers the default tenant
+	admin := service.NewActor(models.User{ID: 2}, &services.IdentityClaims{Roles: []string{"admin"}})
+	assert.True(t, admin.IsAdmin)
+	assert.False(t, admin.IsPlatformAdmin())
+	assert.False(t, admin.InOrganization(&orgID))
+	assert.Equal(t, &schemas.TenantFilter{}, admin.TenantFilter())
+}
+
+func TestOrganizationService_RemovedAdminCannotReachOtherOrganizations(t *testing.T) {
+	orgID, otherOrgID := uint(1), uint(2)
+	userRepo := new(mocks.UserRepositoryInterface)
+	userRepo.On("GetByID", uint(5)).Return(models.User{ID: 5, OrganizationID: &orgID}, nil)
+	userRepo.On("UpdateOrganization", uint(5), (*uint)(nil)).Return(nil)
+	service := services.NewOrganizationService(new(mocks.OrganizationRepositoryInterface), userRepo)
+
+	assert.NoError(t, service.RemoveUser(orgID, 5))
+
+	authService := services.NewAuthService(&config.AppConfig{}, nil, userRepo, nil)
+	actor := authService.NewActor(models.User{ID: 5}, &services.IdentityClaims{Roles: []string{"admin"}})
+	assert.False(t, actor.IsPlatformAdmin())
+	assert.False(t, actor.InOrganization(&orgID))
+	assert.False(t, actor.InOrganization(&otherOrgID))
+	assert.Equal(t, &schemas.TenantFilter{}, actor.TenantFilter())
+}
+
+func TestAuthService_SyncOrganization(t *testing.T) {
+	orgID, otherOrgID := uint(1), uint(2)
+	orgRepo := new(mocks.OrganizationRepositoryInterface)
+	userRepo := new(mocks.UserRepositoryInterface)
+	service := services.NewAuthService(&config.AppConfig{}, nil, userRepo, orgRepo)
+
+	orgRepo.On("GetBySlug", "springfield").Return(models.Organization{ID: orgID, Slug: "springfield"}, nil)
+	orgRepo.On("GetBySlug", "unknown").Return(models.Organization{}, errors.New("organization not found"))
+	userRepo.On("UpdateOrganization", uint(5), &orgID).Return(nil)
+
+	// The organization claim wins over the groups
+	user, err := service.SyncOrganization(models.User{ID: 5, OrganizationID: &otherOrgID},
+		&services.IdentityClaims{Organization: "springfield", Groups: []string{"/organizations/shelbyville"}})
+	assert.NoError(t, err)
+	assert.Equal(t, &orgID, user.OrganizationID)
+
+	// An organization group is used when the claim is missing
+	user, err = service.SyncOrganization(models.User{ID: 5}, &services.IdentityClaims{Groups: []string{"/staff", "/organizations/springfield"}})
+	assert.NoError(t, err)
+	assert.Equal(t, &orgID, user.OrganizationID)
+
+	// Users already in the organization are not updated again
+	_, err = service.SyncOrganization(models.User{ID: 6, OrganizationID: &orgID}, &services.IdentityClaims{Organization: "springfield"})
+	assert.NoError(t, err)
+	userRepo.AssertNotCalled(t, "UpdateOrganization", uint(6), mock.Anything)
+
+	// Tokens without an organization keep the assignment of the admins
+	user, err = service.SyncOrganization(models.User{ID: 7, OrganizationID: &otherOrgID}, &services.IdentityClaims{})
+	assert.NoError(t, err)
+	assert.Equal(t, &otherOrgID, user.OrganizationID)
+
+	_, err = service.SyncOrganization(models.User{ID: 5}, &services.IdentityClaims{Organization: "unknown"})
+	assert.EqualError(t, err, "organization not found")
+}
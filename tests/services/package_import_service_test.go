@@ -0,0 +1,289 @@
+package services_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+	"web/mocks/repos"
+	"web/models"
+	"web/repos"
+	"web/schemas"
+	"web/services"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const scormManifest = `<?xml version="1.0" encoding="UTF-8"?>
+<manifest identifier="vendor-course" version="1.0"
+    xmlns="http://www.imsproject.org/xsd/imscp_rootv1p1p2"
+    xmlns:adlcp="http://www.adlnet.org/xsd/adlcp_rootv1p2">
+  <metadata>
+    <schema>ADL SCORM</schema>
+    <schemaversion>1.2</schemaversion>
+  </metadata>
+  <organizations default="ORG-1">
+    <organization identifier="ORG-1">
+      <title>Safety Training</title>
+      <item identifier="ITEM-1">
+        <title>Module 1</title>
+        <item identifier="ITEM-1-1" identifierref="RES-1"><title>Welcome</title></item>
+        <item identifier="ITEM-1-2" identifierref="RES-MISSING"><title>Broken</title></item>
+      </item>
+      <item identifier="ITEM-2" identifierref="RES-2"><title>Summary</title></item>
+    </organization>
+  </organizations>
+  <resources>
+    <resource identifier="RES-1" type="webcontent" adlcp:scormtype="sco" href="module1/index.html">
+      <file href="module1/index.html"/>
+      <file href="module1/images/logo.png"/>
+      <file href="module1/missing.css"/>
+      <dependency identifierref="SHARED"/>
+    </resource>
+    <resource identifier="RES-2" type="webcontent" adlcp:scormtype="asset" href="summary.pdf">
+      <file href="summary.pdf"/>
+    </resource>
+    <resource identifier="SHARED" type="webcontent" adlcp:scormtype="asset">
+      <file href="shared/api.js"/>
+    </resource>
+  </resources>
+</manifest>`
+
+const commonCartridgeManifest = `<?xml version="1.0" encoding="UTF-8"?>
+<manifest identifier="cc" xmlns="http://www.imsglobal.org/xsd/imsccv1p1/imscp_v1p1">
+  <metadata>
+    <schema>IMS Common Cartridge</schema>
+    <schemaversion>1.1.0</schemaversion>
+  </metadata>
+  <organizations>
+    <organization identifier="ORG" structure="rooted-hierarchy">
+      <item identifier="LearningModules">
+        <item identifier="MOD-1">
+          <title>Week 1</title>
+          <item identifier="I-1" identifierref="R-PAGE"><title>Reading</title></item>
+          <item identifier="I-2" identifierref="R-LINK"><title>Go website</title></item>
+          <item identifier="I-3" identifierref="R-QUIZ"><title>Quiz</title></item>
+        </item>
+      </item>
+    </organization>
+  </organizations>
+  <resources>
+    <resource identifier="R-PAGE" type="webcontent" href="web_resources/reading%20one.html">
+      <file href="web_resources/reading one.html"/>
+    </resource>
+    <resource identifier="R-LINK" type="imswl_xmlv1p1">
+      <file href="links/go.xml"/>
+    </resource>
+    <resource identifier="R-QUIZ" type="imsqti_xmlv1p2/imscc_xmlv1p1/assessment">
+      <file href="quiz/assessment.xml"/>
+    </resource>
+  </resources>
+</manifest>`
+
+// runPackageImport starts an import and waits until the job finishes. Completed jobs record
+// their course through auditRepo.
+func runPackageImport(t *testing.T, courseRepo *mocks.CourseRepositoryInterface, auditRepo *mocks.AuditLogRepositoryInterface, storage *fakeObjectStorage, fileName string, packageData []byte) models.ImportJob {
+	jobRepo := new(mocks.ImportJobRepositoryInterface)
+	service := services.NewPackageImportService(jobRepo, courseRepo, services.NewAuditService(auditRepo), storage)
+
+	userID := uint(7)
+	finished := make(chan models.ImportJob, 1)
+	jobRepo.On("Create", mock.Anything).Return(func(job models.ImportJob) (models.ImportJob, error) {
+		job.ID = 1
+		return job, nil
+	})
+	jobRepo.On("Update", mock.Anything).Return(func(job models.ImportJob) (models.ImportJob, error) {
+		if job.Status == models.ImportJobStatusCompleted || job.Status == models.ImportJobStatusFailed {
+			finished <- job
+		}
+		return job, nil
+	})
+
+	auditEntry := models.AuditLog{Action: services.ActionImport, ResourceType: services.ResourceCourse, ActorID: &userID, Method: "POST", Path: "/api/v1/imports/packages"}
+	job, err := service.StartImport(bytes.NewReader(packageData), fileName, services.Actor{UserID: userID, IsTeacher: true}, auditEntry)
+	assert.NoError(t, err)
+	assert.Equal(t, models.ImportJobStatusPending, job.Status)
+
+	select {
+	case job = <-finished:
+	case <-time.After(5 * time.Second):
+		t.Fatal("import job did not finish")
+	}
+	return job
+}
+
+func warningMessages(job models.ImportJob) []string {
+	messages := make([]string, 0, len(job.Warnings))
+	for _, warning := range job.Warnings {
+		messages = append(messages, warning.Item+": "+warning.Message)
+	}
+	return messages
+}
+
+func TestPackageImportService_SCORM12(t *testing.T) {
+	packageData := writeArchive(t, map[string][]byte{
+		"imsmanifest.xml":         []byte(scormManifest),
+		"module1/index.html":      []byte("<html>welcome</html>"),
+		"module1/images/logo.png": []byte("png"),
+		"shared/api.js":           []byte("js"),
+		"summary.pdf":             []byte("%PDF"),
+	})
+
+	courseRepo := new(mocks.CourseRepositoryInterface)
+	storage := newFakeObjectStorage()
+	var imported models.Course
+	courseRepo.On("Import", mock.Anything, mock.MatchedBy(func(options repos.CloneOptions) bool {
+		return options.Name == "Safety Training" && options.CreatedBy == 7 && options.KeepAttachmentPaths
+	})).Return(func(source models.Course, options repos.CloneOptions) (models.Course, schemas.CourseIDMap, error) {
+		imported = source
+		err := options.CopyObjects([]repos.ObjectCopy{{Source: "module1/index.html", Destination: "lesson-30/module1/index.html"}})
+		return models.Course{ID: 20}, schemas.CourseIDMap{}, err
+	})
+
+	auditRepo := new(mocks.AuditLogRepositoryInterface)
+	auditRepo.On("Snapshot", services.ResourceCourse, uint(20)).Return(json.RawMessage(`{"id":20}`), nil)
+	auditRepo.On("Create", mock.MatchedBy(func(entry models.AuditLog) bool {
+		return entry.Action == services.ActionImport && entry.ResourceType == services.ResourceCourse &&
+			entry.ResourceID != nil && *entry.ResourceID == 20 && *entry.ActorID == 7 &&
+			entry.Path == "/api/v1/imports/packages" && string(entry.After) == `{"id":20}`
+	})).Return(models.AuditLog{ID: 1}, nil)
+
+	job := runPackageImport(t, courseRepo, auditRepo, storage, "safety.zip", packageData)
+
+	assert.Equal(t, models.ImportJobStatusCompleted, job.Status, job.Error)
+	assert.Equal(t, models.ImportJobKindSCORM12, job.Kind)
+	assert.Equal(t, uint(20), *job.CourseID)
+	assert.NotNil(t, job.FinishedAt)
+	assert.Equal(t, []byte("<html>welcome</html>"), storage.objects["lesson-30/module1/index.html"])
+	assert.Equal(t, []string{
+		"ITEM-1-1: SCORM runtime tracking is not supported, the SCO is imported as static content",
+		"ITEM-1-1: the file module1/missing.css is missing from the package",
+		"ITEM-1-2: the item refers to the unknown resource RES-MISSING and is skipped",
+	}, warningMessages(job))
+
+	assert.Len(t, imported.Chapters, 2)
+	module := imported.Chapters[0]
+	assert.Equal(t, "Module 1", module.Name)
+	assert.Len(t, module.Lessons, 1)
+	welcome := module.Lessons[0]
+	assert.Equal(t, "Welcome", welcome.Name)
+	var attachmentNames []string
+	for _, attachment := range welcome.Attachments {
+		attachmentNames = append(attachmentNames, attachment.Name)
+	}
+	assert.Equal(t, []string{"module1/index.html", "module1/images/logo.png", "shared/api.js"}, attachmentNames)
+	assert.Len(t, welcome.Blocks, 1)
+	assert.Equal(t, models.LessonBlockTypeAttachment, welcome.Blocks[0].Type)
+	assert.Equal(t, welcome.Attachments[0].ID, *welcome.Blocks[0].AttachmentID)
+
+	// Top-level lessons are collected in a chapter named after the organization
+	assert.Equal(t, "Safety Training", imported.Chapters[1].Name)
+	assert.Equal(t, "Summary", imported.Chapters[1].Lessons[0].Name)
+}
+
+func TestPackageImportService_CommonCartridge(t *testing.T) {
+	packageData := writeArchive(t, map[string][]byte{
+		"imsmanifest.xml":                []byte(commonCartridgeManifest),
+		"web_resources/reading one.html": []byte("<p>read</p>"),
+		"links/go.xml":                   []byte(`<webLink><title>The Go website</title><url href="https://go.dev"/></webLink>`),
+		"quiz/assessment.xml":            []byte("<questestinterop/>"),
+	})
+
+	courseRepo := new(mocks.CourseRepositoryInterface)
+	var imported models.Course
+	courseRepo.On("Import", mock.Anything, mock.Anything).
+		Return(func(source models.Course, options repos.CloneOptions) (models.Course, schemas.CourseIDMap, error) {
+			imported = source
+			return models.Course{ID: 21}, schemas.CourseIDMap{}, nil
+		})
+
+	auditRepo := new(mocks.AuditLogRepositoryInterface)
+	auditRepo.On("Snapshot", services.ResourceCourse, uint(21)).Return(json.RawMessage(`{"id":21}`), nil)
+	auditRepo.On("Create", mock.Anything).Return(models.AuditLog{ID: 1}, nil)
+
+	job := runPackageImport(t, courseRepo, auditRepo, newFakeObjectStorage(), "go-course.zip", packageData)
+
+	assert.Equal(t, models.ImportJobStatusCompleted, job.Status, job.Error)
+	assert.Equal(t, models.ImportJobKindCommonCartridge, job.Kind)
+	assert.Equal(t, []string{"I-3: assessments are not supported"}, warningMessages(job))
+
+	// The course is named after the package file when the organization has no title
+	assert.Equal(t, "go-course", imported.Name)
+	assert.Len(t, imported.Chapters, 1)
+	week := imported.Chapters[0]
+	assert.Equal(t, "Week 1", week.Name)
+	assert.Len(t, week.Lessons, 2)
+	assert.Equal(t, "web_resources/reading one.html", week.Lessons[0].Attachments[0].URL)
+	assert.Equal(t, "[The Go website](https://go.dev)", week.Lessons[1].Content)
+	assert.Empty(t, week.Lessons[1].Attachments)
+}
+
+func TestPackageImportService_InvalidPackages(t *testing.T) {
+	testCases := []struct {
+		name          string
+		files         map[string][]byte
+		expectedError string
+	}{
+		{
+			name:          "No Manifest",
+			files:         map[string][]byte{"index.html": []byte("hi")},
+			expectedError: "package has no imsmanifest.xml",
+		},
+		{
+			name: "SCORM 2004",
+			files: map[string][]byte{"imsmanifest.xml": []byte(`<manifest><metadata><schema>ADL SCORM</schema>` +
+				`<schemaversion>2004 4th Edition</schemaversion></metadata></manifest>`)},
+			expectedError: "unsupported SCORM version 2004 4th Edition, only SCORM 1.2 is supported",
+		},
+		{
+			name: "Nothing Importable",
+			files: map[string][]byte{"imsmanifest.xml": []byte(`<manifest><metadata><schema>ADL SCORM</schema>` +
+				`<schemaversion>1.2</schemaversion></metadata><organizations><organization identifier="O">` +
+				`<item identifier="I" identifierref="R"/></organization></organizations>` +
+				`<resources><resource identifier="R" type="webcontent" href="../escape.html"/></resources></manifest>`)},
+			expectedError: "package has no importable content",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			courseRepo := new(mocks.CourseRepositoryInterface)
+
+			// Failed imports create no course, so nothing is recorded in the audit log
+			auditRepo := new(mocks.AuditLogRepositoryInterface)
+			job := runPackageImport(t, courseRepo, auditRepo, newFakeObjectStorage(), "package.zip", writeArchive(t, tc.files))
+
+			assert.Equal(t, models.ImportJobStatusFailed, job.Status)
+			assert.Equal(t, tc.expectedError, job.Error)
+			courseRepo.AssertNotCalled(t, "Import", mock.Anything, mock.Anything)
+			auditRepo.AssertNotCalled(t, "Create", mock.Anything)
+		})
+	}
+}
+
+func TestPackageImportService_StartImport_Validation(t *testing.T) {
+	service := services.NewPackageImportService(new(mocks.ImportJobRepositoryInterface), new(mocks.CourseRepositoryInterface), nil, newFakeObjectStorage())
+
+	_, err := service.StartImport(bytes.NewReader(nil), "course.zip", services.Actor{UserID: 3}, models.AuditLog{})
+	assert.EqualError(t, err, "insufficient permissions to import packages")
+
+	_, err = service.StartImport(bytes.NewReader(nil), "course.tar.gz", services.Actor{UserID: 3, IsTeacher: true}, models.AuditLog{})
+	assert.EqualError(t, err, "package must be a zip file")
+}
+
+func TestPackageImportService_GetJob(t *testing.T) {
+	ownerID := uint(7)
+	jobRepo := new(mocks.ImportJobRepositoryInterface)
+	service := services.NewPackageImportService(jobRepo, new(mocks.CourseRepositoryInterface), nil, newFakeObjectStorage())
+	jobRepo.On("GetByID", uint(1)).Return(models.ImportJob{ID: 1, CreatedBy: &ownerID}, nil)
+
+	_, err := service.GetJob(1, services.Actor{UserID: ownerID, IsTeacher: true})
+	assert.NoError(t, err)
+
+	_, err = service.GetJob(1, services.Actor{UserID: 1, IsAdmin: true})
+	assert.NoError(t, err)
+
+	_, err = service.GetJob(1, services.Actor{UserID: 9, IsTeacher: true})
+	assert.EqualError(t, err, "insufficient permissions to view this import job")
+}
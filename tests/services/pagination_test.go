@@ -0,0 +1,49 @@
+package services_test
+
+import (
+	"testing"
+	"web/schemas"
+	"web/services"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewPaginationMeta(t *testing.T) {
+	testCases := []struct {
+		name     string
+		params   schemas.ListParams
+		total    int64
+		expected schemas.PaginationMeta
+	}{
+		{
+			name:     "First Page With More",
+			params:   schemas.ListParams{Page: 1, Limit: 20, SortBy: "order"},
+			total:    45,
+			expected: schemas.PaginationMeta{Page: 1, Limit: 20, Total: 45, TotalPages: 3, HasNext: true, Sort: "order"},
+		},
+		{
+			name:     "Last Page",
+			params:   schemas.ListParams{Page: 3, Limit: 20, SortBy: "name", SortDesc: true},
+			total:    45,
+			expected: schemas.PaginationMeta{Page: 3, Limit: 20, Total: 45, TotalPages: 3, HasNext: false, Sort: "-name"},
+		},
+		{
+			name:     "Exact Page Boundary",
+			params:   schemas.ListParams{Page: 2, Limit: 10},
+			total:    20,
+			expected: schemas.PaginationMeta{Page: 2, Limit: 10, Total: 20, TotalPages: 2, HasNext: false},
+		},
+		{
+			name:     "Empty Result",
+			params:   schemas.ListParams{Page: 1, Limit: 10},
+			total:    0,
+			expected: schemas.PaginationMeta{Page: 1, Limit: 10, Total: 0, TotalPages: 0, HasNext: false},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, services.NewPaginationMeta(tc.params, tc.total))
+		})
+	}
+}
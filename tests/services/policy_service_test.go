@@ -0,0 +1,246 @@
+package services_test
+
+import (
+	"errors"
+	"testing"
+	"web/mocks/repos"
+	"web/models"
+	"web/schemas"
+	"web/services"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// policyTestActors are the kinds of callers every route is checked for. The admin is a
+// platform admin, the owner created the course of the route.
+var policyTestActors = map[string]services.Actor{
+	"admin":   {UserID: 1, IsAdmin: true, PlatformAdmin: true},
+	"owner":   {UserID: 10, IsTeacher: true},
+	"teacher": {UserID: 20, IsTeacher: true},
+	"learner": {UserID: 30},
+}
+
+// noTenant is the tenant filter of platform admins, who reach every organization
+var noTenant *schemas.TenantFilter
+
+func TestPolicyService_Routes(t *testing.T) {
+	courseOwnerID := uint(10)
+	everyone := []string{"admin", "owner", "teacher", "learner"}
+	staff := []string{"admin", "owner", "teacher"}
+	adminAndOwner := []string{"admin", "owner"}
+
+	testCases := []struct {
+		route    string
+		action   string
+		resource string
+		owner    *uint
+		allowed  []string
+	}{
+		{"GET /courses (unpublished courses)", services.ActionReadUnpublished, services.ResourceCourse, nil, staff},
+		{"POST /courses", services.ActionCreate, services.ResourceCourse, nil, staff},
+		{"PUT /courses/:id", services.ActionUpdate, services.ResourceCourse, &courseOwnerID, adminAndOwner},
+		{"DELETE /courses/:id", services.ActionDelete, services.ResourceCourse, &courseOwnerID, adminAndOwner},
+		{"POST /courses/:id/status", services.ActionChangeStatus, services.ResourceCourse, &courseOwnerID, adminAndOwner},
+		{"GET /courses/:id/status-history", services.ActionReadHistory, services.ResourceCourse, &courseOwnerID, staff},
+		{"POST /courses/:id/clone", services.ActionCopy, services.ResourceCourse, &courseOwnerID, staff},
+		{"GET /courses/:id/export", services.ActionCopy, services.ResourceCourse, &courseOwnerID, staff},
+		{"POST /courses/import", services.ActionImport, services.ResourceCourse, nil, staff},
+		{"POST /imports/packages", services.ActionImport, services.ResourceCourse, nil, staff},
+		{"GET /imports/packages/:jobId", services.ActionImport, services.ResourceCourse, nil, staff},
+
+		{"GET /courses/:id/members", services.ActionRead, services.ResourceCourseMember, &courseOwnerID, adminAndOwner},
+		{"POST /courses/:id/members", services.ActionCreate, services.ResourceCourseMember, &courseOwnerID, adminAndOwner},
+		{"PUT /courses/:id/members/:userId", services.ActionUpdate, services.ResourceCourseMember, &courseOwnerID, adminAndOwner},
+		{"DELETE /courses/:id/members/:userId", services.ActionDelete, services.ResourceCourseMember, &courseOwnerID, adminAndOwner},
+
+		{"POST /courses/:id/chapters", services.ActionCreate, services.ResourceChapter, &courseOwnerID, adminAndOwner},
+		{"PUT /courses/:id/chapters/:chapterId", services.ActionUpdate, services.ResourceChapter, &courseOwnerID, adminAndOwner},
+		{"DELETE /courses/:id/chapters/:chapterId", services.ActionDelete, services.ResourceChapter, &courseOwnerID, adminAndOwner},
+		{"PUT /courses/:id/chapters/order", services.ActionUpdate, services.ResourceChapter, &courseOwnerID, adminAndOwner},
+		{"POST /courses/:id/chapters/:chapterId/move", services.ActionUpdate, services.ResourceChapter, &courseOwnerID, adminAndOwner},
+
+		{"POST .../lessons", services.ActionCreate, services.ResourceLesson, &courseOwnerID, adminAndOwner},
+		{"PUT .../lessons/:lessonId", services.ActionUpdate, services.ResourceLesson, &courseOwnerID, adminAndOwner},
+		{"DELETE .../lessons/:lessonId", services.ActionDelete, services.ResourceLesson, &courseOwnerID, adminAndOwner},
+		{"PUT .../lessons/order", services.ActionUpdate, services.ResourceLesson, &courseOwnerID, adminAndOwner},
+		{"POST .../lessons/:lessonId/move", services.ActionUpdate, services.ResourceLesson, &courseOwnerID, adminAndOwner},
+		{"POST, PUT and DELETE .../lessons/:lessonId/blocks", services.ActionUpdate, services.ResourceLesson, &courseOwnerID, adminAndOwner},
+		{"GET .../lessons/:lessonId/revisions", services.ActionReadHistory, services.ResourceLesson, &courseOwnerID, staff},
+		{"POST .../lessons/:lessonId/revisions/:revision/restore", services.ActionUpdate, services.ResourceLesson, &courseOwnerID, adminAndOwner},
+
+		{"POST .../lessons/:lessonId/attachments", services.ActionCreate, services.ResourceAttachment, &courseOwnerID, adminAndOwner},
+		{"DELETE .../lessons/:lessonId/attachments/:attachmentId", services.ActionDelete, services.ResourceAttachment, &courseOwnerID, adminAndOwner},
+		{"POST /attachments/upload/:lessonId", services.ActionCreate, services.ResourceAttachment, &courseOwnerID, adminAndOwner},
+		{"DELETE /attachments/delete/:id", services.ActionDelete, services.ResourceAttachment, nil, []string{"admin"}},
+
+		{"POST .../lessons/:lessonId/quizzes", services.ActionCreate, services.ResourceQuiz, &courseOwnerID, adminAndOwner},
+		{"PUT .../lessons/:lessonId/quizzes/:quizId", services.ActionUpdate, services.ResourceQuiz, &courseOwnerID, adminAndOwner},
+		{"DELETE .../lessons/:lessonId/quizzes/:quizId", services.ActionDelete, services.ResourceQuiz, &courseOwnerID, adminAndOwner},
+		{"GET .../lessons/:lessonId/quizzes/:quizId (answers)", services.ActionReadAnswers, services.ResourceQuiz, &courseOwnerID, staff},
+		{"POST .../quizzes/:quizId/attempts/:attemptId/review", services.ActionReview, services.ResourceQuiz, &courseOwnerID, adminAndOwner},
+
+		{"POST /courses/:id/certificates", services.ActionCreate, services.ResourceCertificate, &courseOwnerID, adminAndOwner},
+		{"GET /certificates/:code/download", services.ActionRead, services.ResourceCertificate, &courseOwnerID, adminAndOwner},
+
+		{"POST /users/admin/create", services.ActionCreate, services.ResourceUser, nil, []string{"admin"}},
+		{"GET /audit-logs", services.ActionRead, services.ResourceAuditLog, nil, []string{"admin"}},
+		{"GET /search (unpublished courses)", services.ActionReadUnpublished, services.ResourceCourse, nil, staff},
+		{"POST /courses/:id/enrollment", services.ActionEnroll, services.ResourceCourse, &courseOwnerID, everyone},
+
+		{"POST /trash/courses/:itemId/restore", services.ActionRestore, services.ResourceCourse, &courseOwnerID, adminAndOwner},
+		{"POST /trash/chapters/:itemId/restore", services.ActionRestore, services.ResourceChapter, &courseOwnerID, adminAndOwner},
+		{"POST /trash/lessons/:itemId/restore", services.ActionRestore, services.ResourceLesson, &courseOwnerID, adminAndOwner},
+		{"POST /trash/attachments/:itemId/restore", services.ActionRestore, services.ResourceAttachment, &courseOwnerID, adminAndOwner},
+
+		{"GET /organizations", services.ActionRead, services.ResourceOrganization, nil, []string{"admin"}},
+		{"POST /organizations", services.ActionCreate, services.ResourceOrganization, nil, []string{"admin"}},
+		{"PUT /organizations/:orgId", services.ActionUpdate, services.ResourceOrganization, nil, []string{"admin"}},
+		{"DELETE /organizations/:orgId", services.ActionDelete, services.ResourceOrganization, nil, []string{"admin"}},
+	}
+
+	service := services.NewPolicyService(services.PolicyRules, new(mocks.CourseRepositoryInterface), new(mocks.LessonRepositoryInterface), new(mocks.CourseMemberRepositoryInterface))
+
+	for _, tc := range testCases {
+		t.Run(tc.route, func(t *testing.T) {
+			for name, actor := range policyTestActors {
+				assert.Equal(t, contains(tc.allowed, name), service.Can(actor, tc.action, tc.resource, tc.owner), name)
+			}
+		})
+	}
+}
+
+func TestPolicyService_UnknownRuleIsDenied(t *testing.T) {
+	service := services.NewPolicyService(services.PolicyRules, new(mocks.CourseRepositoryInterface), new(mocks.LessonRepositoryInterface), new(mocks.CourseMemberRepositoryInterface))
+
+	assert.False(t, service.Can(policyTestActors["admin"], "publish", services.ResourceLesson, nil))
+	assert.EqualError(t, service.Authorize(policyTestActors["teacher"], services.ActionChangeStatus, services.ResourceCourse, nil),
+		"insufficient permissions for change_status on course")
+}
+
+func TestPolicyService_AuthorizeCourse(t *testing.T) {
+	ownerID := uint(10)
+	courseRepo := new(mocks.CourseRepositoryInterface)
+	courseRepo.On("GetByID", uint(1)).Return(models.Course{ID: 1, CreatedBy: &ownerID}, nil)
+	courseRepo.On("GetByID", uint(9)).Return(models.Course{}, errors.New("course not found"))
+	memberRepo := new(mocks.CourseMemberRepositoryInterface)
+	memberRepo.On("GetRole", uint(1), uint(20)).Return("", nil)
+	service := services.NewPolicyService(services.PolicyRules, courseRepo, new(mocks.LessonRepositoryInterface), memberRepo)
+
+	assert.NoError(t, service.AuthorizeCourse(policyTestActors["owner"], services.ActionUpdate, services.ResourceLesson, 1))
+	assert.EqualError(t, service.AuthorizeCourse(policyTestActors["teacher"], services.ActionUpdate, services.ResourceLesson, 1),
+		"insufficient permissions for update on lesson")
+	assert.EqualError(t, service.AuthorizeCourse(policyTestActors["owner"], services.ActionUpdate, services.ResourceLesson, 9),
+		"course not found")
+
+	// Admins are allowed without looking up the owner
+	assert.NoError(t, service.AuthorizeCourse(policyTestActors["admin"], services.ActionDelete, services.ResourceCourse, 5))
+	courseRepo.AssertNotCalled(t, "GetByID", uint(5))
+}
+
+func TestPolicyService_Organizations(t *testing.T) {
+	ownerID, orgID, otherOrgID := uint(10), uint(100), uint(200)
+	courseRepo := new(mocks.CourseRepositoryInterface)
+	courseRepo.On("GetByID", uint(1)).Return(models.Course{ID: 1, CreatedBy: &ownerID, OrganizationID: &orgID}, nil)
+	memberRepo := new(mocks.CourseMemberRepositoryInterface)
+	service := services.NewPolicyService(services.PolicyRules, courseRepo, new(mocks.LessonRepositoryInterface), memberRepo)
+
+	orgAdmin := services.Actor{UserID: 2, IsAdmin: true, OrganizationID: &orgID}
+	otherOrgAdmin := services.Actor{UserID: 3, IsAdmin: true, OrganizationID: &otherOrgID}
+	otherOrgLearner := services.Actor{UserID: 30, OrganizationID: &otherOrgID}
+	defaultTenantOwner := services.Actor{UserID: ownerID, IsTeacher: true}
+
+	// Admins of an organization manage its courses but not the organizations
+	assert.NoError(t, service.AuthorizeCourse(orgAdmin, services.ActionDelete, services.ResourceCourse, 1))
+	assert.False(t, service.Can(orgAdmin, services.ActionCreate, services.ResourceOrganization, nil))
+	assert.False(t, service.Can(orgAdmin, services.ActionCreate, services.ResourceUser, nil))
+
+	// Courses of other organizations are hidden, even from their admins and the creator
+	assert.EqualError(t, service.AuthorizeCourse(otherOrgAdmin, services.ActionDelete, services.ResourceCourse, 1), "course not found")
+	assert.EqualError(t, service.AuthorizeCourse(otherOrgLearner, services.ActionEnroll, services.ResourceCourse, 1), "course not found")
+	assert.EqualError(t, service.AuthorizeCourse(defaultTenantOwner, services.ActionUpdate, services.ResourceCourse, 1), "course not found")
+	memberRepo.AssertNotCalled(t, "GetRole", uint(1), uint(3))
+
+	// Platform admins reach every organization
+	assert.NoError(t, service.AuthorizeCourse(policyTestActors["admin"], services.ActionDelete, services.ResourceCourse, 1))
+}
+
+func TestPolicyService_AuthorizeLesson(t *testing.T) {
+	ownerID := uint(10)
+	courseRepo := new(mocks.CourseRepositoryInterface)
+	lessonRepo := new(mocks.LessonRepositoryInterface)
+	courseRepo.On("GetByID", uint(1)).Return(models.Course{ID: 1, CreatedBy: &ownerID}, nil)
+	lessonRepo.On("GetCourseID", uint(4)).Return(uint(1), nil)
+	lessonRepo.On("GetCourseID", uint(8)).Return(uint(0), errors.New("lesson not found"))
+	memberRepo := new(mocks.CourseMemberRepositoryInterface)
+	memberRepo.On("GetRole", uint(1), uint(30)).Return("", nil)
+	service := services.NewPolicyService(services.PolicyRules, courseRepo, lessonRepo, memberRepo)
+
+	assert.NoError(t, service.AuthorizeLesson(policyTestActors["owner"], services.ActionCreate, services.ResourceAttachment, 4))
+	assert.EqualError(t, service.AuthorizeLesson(policyTestActors["learner"], services.ActionCreate, services.ResourceAttachment, 4),
+		"insufficient permissions for create on attachment")
+	assert.EqualError(t, service.AuthorizeLesson(policyTestActors["owner"], services.ActionCreate, services.ResourceAttachment, 8),
+		"lesson not found")
+}
+
+func TestPolicyService_CourseMembers(t *testing.T) {
+	creatorID := uint(10)
+	courseRepo := new(mocks.CourseRepositoryInterface)
+	courseRepo.On("GetByID", uint(1)).Return(models.Course{ID: 1, CreatedBy: &creatorID}, nil)
+	memberRepo := new(mocks.CourseMemberRepositoryInterface)
+	memberRepo.On("GetRole", uint(1), uint(40)).Return(models.CourseMemberRoleCoInstructor, nil)
+	memberRepo.On("GetRole", uint(1), uint(41)).Return(models.CourseMemberRoleTeachingAssistant, nil)
+	memberRepo.On("GetRole", uint(1), uint(42)).Return(models.CourseMemberRoleViewer, nil)
+	memberRepo.On("GetRole", uint(1), uint(43)).Return(models.CourseMemberRoleOwner, nil)
+	memberRepo.On("GetRole", uint(1), uint(30)).Return("", nil)
+	service := services.NewPolicyService(services.PolicyRules, courseRepo, new(mocks.LessonRepositoryInterface), memberRepo)
+
+	members := map[string]services.Actor{
+		"co_instructor":      {UserID: 40},
+		"teaching_assistant": {UserID: 41},
+		"viewer":             {UserID: 42},
+		"transferred owner":  {UserID: 43},
+		"learner":            {UserID: 30},
+	}
+	allMembers := []string{"co_instructor", "teaching_assistant", "viewer", "transferred owner"}
+
+	testCases := []struct {
+		action   string
+		resource string
+		allowed  []string
+	}{
+		{services.ActionUpdate, services.ResourceCourse, []string{"co_instructor", "transferred owner"}},
+		{services.ActionDelete, services.ResourceCourse, []string{"transferred owner"}},
+		{services.ActionChangeStatus, services.ResourceCourse, []string{"co_instructor", "transferred owner"}},
+		{services.ActionReadHistory, services.ResourceCourse, allMembers},
+		{services.ActionCreate, services.ResourceChapter, []string{"co_instructor", "transferred owner"}},
+		{services.ActionUpdate, services.ResourceLesson, []string{"co_instructor", "transferred owner"}},
+		{services.ActionReadHistory, services.ResourceLesson, allMembers},
+		{services.ActionCreate, services.ResourceAttachment, []string{"co_instructor", "transferred owner"}},
+		{services.ActionDelete, services.ResourceQuiz, []string{"co_instructor", "transferred owner"}},
+		{services.ActionReadAnswers, services.ResourceQuiz, []string{"co_instructor", "teaching_assistant", "transferred owner"}},
+		{services.ActionReview, services.ResourceQuiz, []string{"co_instructor", "teaching_assistant", "transferred owner"}},
+		{services.ActionCreate, services.ResourceCertificate, []string{"co_instructor", "teaching_assistant", "transferred owner"}},
+		{services.ActionRead, services.ResourceCertificate, []string{"co_instructor", "teaching_assistant", "transferred owner"}},
+		{services.ActionRead, services.ResourceCourseMember, allMembers},
+		{services.ActionCreate, services.ResourceCourseMember, []string{"transferred owner"}},
+		{services.ActionDelete, services.ResourceCourseMember, []string{"transferred owner"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.action+" "+tc.resource, func(t *testing.T) {
+			for name, actor := range members {
+				err := service.AuthorizeCourse(actor, tc.action, tc.resource, 1)
+				assert.Equal(t, contains(tc.allowed, name), err == nil, name)
+			}
+		})
+	}
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
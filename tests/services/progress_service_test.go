@@ -0,0 +1,142 @@
+package services_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+	"web/mocks/repos"
+	"web/models"
+	"web/schemas"
+	"web/services"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestProgressService_UpdateLessonProgress(t *testing.T) {
+	completedAt := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name           string
+		request        schemas.UpdateLessonProgressRequest
+		mockSetup      func(progressRepo *mocks.ProgressRepositoryInterface, lessonRepo *mocks.LessonRepositoryInterface)
+		expectedError  error
+		expectedStatus string
+	}{
+		{
+			name:    "In Progress",
+			request: schemas.UpdateLessonProgressRequest{Percent: 40, LastPosition: 120},
+			mockSetup: func(progressRepo *mocks.ProgressRepositoryInterface, lessonRepo *mocks.LessonRepositoryInterface) {
+				lessonRepo.On("GetByID", uint(1), uint(1), uint(1), noTenant).Return(models.Lesson{ID: 1}, nil)
+				progressRepo.On("GetByUserAndLesson", uint(1), uint(1)).Return(models.LessonProgress{}, errors.New("progress not found"))
+				progressRepo.On("Upsert", mock.MatchedBy(func(progress models.LessonProgress) bool {
+					return progress.Status == models.ProgressStatusInProgress && progress.Percent == 40 && progress.CompletedAt == nil
+				})).Return(func(progress models.LessonProgress) (models.LessonProgress, error) {
+					return progress, nil
+				})
+			},
+			expectedStatus: models.ProgressStatusInProgress,
+		},
+		{
+			name:    "Completed Keeps First Completion Time",
+			request: schemas.UpdateLessonProgressRequest{Status: models.ProgressStatusCompleted},
+			mockSetup: func(progressRepo *mocks.ProgressRepositoryInterface, lessonRepo *mocks.LessonRepositoryInterface) {
+				lessonRepo.On("GetByID", uint(1), uint(1), uint(1), noTenant).Return(models.Lesson{ID: 1}, nil)
+				progressRepo.On("GetByUserAndLesson", uint(1), uint(1)).Return(models.LessonProgress{CompletedAt: &completedAt}, nil)
+				progressRepo.On("Upsert", mock.MatchedBy(func(progress models.LessonProgress) bool {
+					return progress.Percent == 100 && progress.CompletedAt != nil && progress.CompletedAt.Equal(completedAt)
+				})).Return(func(progress models.LessonProgress) (models.LessonProgress, error) {
+					return progress, nil
+				})
+			},
+			expectedStatus: models.ProgressStatusCompleted,
+		},
+		{
+			name:    "Position Report Keeps Completed Lesson Completed",
+			request: schemas.UpdateLessonProgressRequest{Percent: 30, LastPosition: 45},
+			mockSetup: func(progressRepo *mocks.ProgressRepositoryInterface, lessonRepo *mocks.LessonRepositoryInterface) {
+				lessonRepo.On("GetByID", uint(1), uint(1), uint(1), noTenant).Return(models.Lesson{ID: 1}, nil)
+				progressRepo.On("GetByUserAndLesson", uint(1), uint(1)).Return(models.LessonProgress{Status: models.ProgressStatusCompleted, Percent: 100, CompletedAt: &completedAt}, nil)
+				progressRepo.On("Upsert", mock.MatchedBy(func(progress models.LessonProgress) bool {
+					return progress.Status == models.ProgressStatusCompleted && progress.Percent == 30 && progress.LastPosition == 45 &&
+						progress.CompletedAt != nil && progress.CompletedAt.Equal(completedAt)
+				})).Return(func(progress models.LessonProgress) (models.LessonProgress, error) {
+					return progress, nil
+				})
+			},
+			expectedStatus: models.ProgressStatusCompleted,
+		},
+		{
+			name:    "Explicit Status Reopens Completed Lesson",
+			request: schemas.UpdateLessonProgressRequest{Status: models.ProgressStatusInProgress, Percent: 30},
+			mockSetup: func(progressRepo *mocks.ProgressRepositoryInterface, lessonRepo *mocks.LessonRepositoryInterface) {
+				lessonRepo.On("GetByID", uint(1), uint(1), uint(1), noTenant).Return(models.Lesson{ID: 1}, nil)
+				progressRepo.On("GetByUserAndLesson", uint(1), uint(1)).Return(models.LessonProgress{Status: models.ProgressStatusCompleted, Percent: 100, CompletedAt: &completedAt}, nil)
+				progressRepo.On("Upsert", mock.MatchedBy(func(progress models.LessonProgress) bool {
+					return progress.Status == models.ProgressStatusInProgress && progress.CompletedAt == nil
+				})).Return(func(progress models.LessonProgress) (models.LessonProgress, error) {
+					return progress, nil
+				})
+			},
+			expectedStatus: models.ProgressStatusInProgress,
+		},
+		{
+			name:    "Invalid Percent",
+			request: schemas.UpdateLessonProgressRequest{Percent: 120},
+			mockSetup: func(progressRepo *mocks.ProgressRepositoryInterface, lessonRepo *mocks.LessonRepositoryInterface) {
+			},
+			expectedError: errors.New("percent must be between 0 and 100"),
+		},
+		{
+			name:    "Invalid Status",
+			request: schemas.UpdateLessonProgressRequest{Status: "paused"},
+			mockSetup: func(progressRepo *mocks.ProgressRepositoryInterface, lessonRepo *mocks.LessonRepositoryInterface) {
+			},
+			expectedError: errors.New("invalid progress status"),
+		},
+		{
+			name:    "Lesson Not Found",
+			request: schemas.UpdateLessonProgressRequest{Percent: 10},
+			mockSetup: func(progressRepo *mocks.ProgressRepositoryInterface, lessonRepo *mocks.LessonRepositoryInterface) {
+				lessonRepo.On("GetByID", uint(1), uint(1), uint(1), noTenant).Return(models.Lesson{}, errors.New("lesson not found"))
+			},
+			expectedError: errors.New("lesson not found"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			progressRepo := mocks.NewProgressRepositoryInterface(t)
+			lessonRepo := mocks.NewLessonRepositoryInterface(t)
+			tc.mockSetup(progressRepo, lessonRepo)
+
+			service := services.NewProgressService(progressRepo, lessonRepo)
+
+			progress, err := service.UpdateLessonProgress(1, 1, 1, 1, tc.request, noTenant)
+
+			if tc.expectedError != nil {
+				assert.Error(t, err)
+				assert.Equal(t, tc.expectedError.Error(), err.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expectedStatus, progress.Status)
+			}
+		})
+	}
+}
+
+func TestProgressService_AttachCourseProgress(t *testing.T) {
+	progressRepo := mocks.NewProgressRepositoryInterface(t)
+	service := services.NewProgressService(progressRepo, nil)
+
+	courses := []schemas.CourseResponseWithChaptersCount{{ID: 1}, {ID: 2}}
+	progressRepo.On("GetCourseSummaries", uint(1), []uint{1, 2}).Return(map[uint]schemas.ProgressSummary{
+		1: {CompletedLessons: 1, TotalLessons: 4, Percent: 25},
+	}, nil)
+
+	err := service.AttachCourseProgress(1, courses)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 25, courses[0].Progress.Percent)
+	assert.Equal(t, 0, courses[1].Progress.TotalLessons)
+}
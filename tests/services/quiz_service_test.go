@@ -0,0 +1,585 @@
+package services_test
+
+import (
+	"errors"
+	"testing"
+	"web/mocks/repos"
+	"web/models"
+	"web/schemas"
+	"web/services"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func float64Ptr(v float64) *float64 {
+	return &v
+}
+
+func testQuiz() models.Quiz {
+	return models.Quiz{
+		ID:           1,
+		LessonID:     1,
+		Title:        "Chapter 1 Quiz",
+		PassingScore: 60,
+		Questions: []models.QuizQuestion{
+			{
+				ID:     1,
+				Type:   models.QuestionTypeSingleChoice,
+				Points: 1,
+				Options: []models.QuizOption{
+					{ID: 1, IsCorrect: true},
+					{ID: 2},
+				},
+			},
+			{
+				ID:     2,
+				Type:   models.QuestionTypeMultipleChoice,
+				Points: 2,
+				Options: []models.QuizOption{
+					{ID: 3, IsCorrect: true},
+					{ID: 4, IsCorrect: true},
+					{ID: 5},
+				},
+			},
+			{
+				ID:            3,
+				Type:          models.QuestionTypeNumeric,
+				Points:        2,
+				CorrectNumber: float64Ptr(3.14),
+				Tolerance:     0.01,
+			},
+		},
+	}
+}
+
+func TestGradeQuizAttempt(t *testing.T) {
+	testCases := []struct {
+		name             string
+		quiz             models.Quiz
+		request          schemas.SubmitQuizAttemptRequest
+		expectedError    error
+		expectedScore    int
+		expectedMaxScore int
+		expectedPassed   bool
+		expectedPending  bool
+	}{
+		{
+			name: "All Correct",
+			quiz: testQuiz(),
+			request: schemas.SubmitQuizAttemptRequest{Answers: []schemas.QuizAnswerRequest{
+				{QuestionID: 1, OptionIDs: []uint{1}},
+				{QuestionID: 2, OptionIDs: []uint{3, 4}},
+				{QuestionID: 3, Number: float64Ptr(3.141)},
+			}},
+			expectedScore:    5,
+			expectedMaxScore: 5,
+			expectedPassed:   true,
+		},
+		{
+			name: "Partial Multiple Choice Is Wrong",
+			quiz: testQuiz(),
+			request: schemas.SubmitQuizAttemptRequest{Answers: []schemas.QuizAnswerRequest{
+				{QuestionID: 1, OptionIDs: []uint{1}},
+				{QuestionID: 2, OptionIDs: []uint{3}},
+				{QuestionID: 3, Number: float64Ptr(3.2)},
+			}},
+			expectedScore:    1,
+			expectedMaxScore: 5,
+			expectedPassed:   false,
+		},
+		{
+			name: "Unanswered Questions Count Towards Max Score",
+			quiz: testQuiz(),
+			request: schemas.SubmitQuizAttemptRequest{Answers: []schemas.QuizAnswerRequest{
+				{QuestionID: 2, OptionIDs: []uint{3, 4}},
+				{QuestionID: 3, Number: float64Ptr(3.14)},
+			}},
+			expectedScore:    4,
+			expectedMaxScore: 5,
+			expectedPassed:   true,
+		},
+		{
+			name: "Free Text Marks Pending Review",
+			quiz: models.Quiz{
+				ID: 1,
+				Questions: []models.QuizQuestion{
+					{ID: 1, Type: models.QuestionTypeFreeText, Points: 5},
+				},
+			},
+			request: schemas.SubmitQuizAttemptRequest{Answers: []schemas.QuizAnswerRequest{
+				{QuestionID: 1, Text: "Variables hold values"},
+			}},
+			expectedScore:    0,
+			expectedMaxScore: 5,
+			expectedPassed:   false,
+			expectedPending:  true,
+		},
+		{
+			name: "Pending Review Is Not Passed",
+			quiz: func() models.Quiz {
+				quiz := testQuiz()
+				quiz.Questions = append(quiz.Questions, models.QuizQuestion{ID: 4, Type: models.QuestionTypeFreeText, Points: 5})
+				return quiz
+			}(),
+			request: schemas.SubmitQuizAttemptRequest{Answers: []schemas.QuizAnswerRequest{
+				{QuestionID: 1, OptionIDs: []uint{1}},
+				{QuestionID: 2, OptionIDs: []uint{3, 4}},
+				{QuestionID: 3, Number: float64Ptr(3.14)},
+				{QuestionID: 4, Text: "Variables hold values"},
+			}},
+			expectedScore:    5,
+			expectedMaxScore: 10,
+			expectedPassed:   false,
+			expectedPending:  true,
+		},
+		{
+			name: "Unanswered Free Text Counts Towards Max Score",
+			quiz: models.Quiz{
+				ID:           1,
+				PassingScore: 50,
+				Questions: []models.QuizQuestion{
+					{ID: 1, Type: models.QuestionTypeFreeText, Points: 5},
+				},
+			},
+			request:          schemas.SubmitQuizAttemptRequest{},
+			expectedScore:    0,
+			expectedMaxScore: 5,
+			expectedPassed:   false,
+		},
+		{
+			name: "Unknown Question",
+			quiz: testQuiz(),
+			request: schemas.SubmitQuizAttemptRequest{Answers: []schemas.QuizAnswerRequest{
+				{QuestionID: 99, OptionIDs: []uint{1}},
+			}},
+			expectedError: errors.New("question 99 does not belong to this quiz"),
+		},
+		{
+			name: "Option From Another Question",
+			quiz: testQuiz(),
+			request: schemas.SubmitQuizAttemptRequest{Answers: []schemas.QuizAnswerRequest{
+				{QuestionID: 1, OptionIDs: []uint{3}},
+			}},
+			expectedError: errors.New("option 3 does not belong to question 1"),
+		},
+		{
+			name: "Multiple Options On Single Choice",
+			quiz: testQuiz(),
+			request: schemas.SubmitQuizAttemptRequest{Answers: []schemas.QuizAnswerRequest{
+				{QuestionID: 1, OptionIDs: []uint{1, 2}},
+			}},
+			expectedError: errors.New("question 1 accepts a single option"),
+		},
+		{
+			name: "Duplicate Answer",
+			quiz: testQuiz(),
+			request: schemas.SubmitQuizAttemptRequest{Answers: []schemas.QuizAnswerRequest{
+				{QuestionID: 1, OptionIDs: []uint{1}},
+				{QuestionID: 1, OptionIDs: []uint{2}},
+			}},
+			expectedError: errors.New("question 1 is answered more than once"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			attempt, err := services.GradeQuizAttempt(tc.quiz, tc.request)
+
+			if tc.expectedError != nil {
+				assert.Error(t, err)
+				assert.Equal(t, tc.expectedError.Error(), err.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expectedScore, attempt.Score)
+				assert.Equal(t, tc.expectedMaxScore, attempt.MaxScore)
+				assert.Equal(t, tc.expectedPassed, attempt.Passed)
+				assert.Equal(t, tc.expectedPending, attempt.PendingReview)
+			}
+		})
+	}
+}
+
+// reviewTestQuiz is testQuiz with a free text question worth 5 points
+func reviewTestQuiz() models.Quiz {
+	quiz := testQuiz()
+	quiz.Questions = append(quiz.Questions, models.QuizQuestion{ID: 4, Type: models.QuestionTypeFreeText, Points: 5})
+	return quiz
+}
+
+// pendingTestAttempt is an attempt of reviewTestQuiz with full marks on the objective
+// questions and a free text answer to review
+func pendingTestAttempt() models.QuizAttempt {
+	correct := true
+	return models.QuizAttempt{
+		ID:            7,
+		QuizID:        1,
+		Score:         5,
+		MaxScore:      10,
+		PendingReview: true,
+		Answers: []models.QuizAnswer{
+			{ID: 1, QuestionID: 1, IsCorrect: &correct, PointsAwarded: 1},
+			{ID: 2, QuestionID: 2, IsCorrect: &correct, PointsAwarded: 2},
+			{ID: 3, QuestionID: 3, IsCorrect: &correct, PointsAwarded: 2},
+			{ID: 4, QuestionID: 4, TextAnswer: "Variables hold values"},
+		},
+	}
+}
+
+func TestReviewQuizAttempt(t *testing.T) {
+	testCases := []struct {
+		name            string
+		attempt         func() models.QuizAttempt
+		request         schemas.ReviewQuizAttemptRequest
+		expectedError   error
+		expectedScore   int
+		expectedPassed  bool
+		expectedCorrect bool
+	}{
+		{
+			name:            "Full Points Pass",
+			attempt:         pendingTestAttempt,
+			request:         schemas.ReviewQuizAttemptRequest{Answers: []schemas.QuizAnswerReview{{QuestionID: 4, PointsAwarded: 5}}},
+			expectedScore:   10,
+			expectedPassed:  true,
+			expectedCorrect: true,
+		},
+		{
+			name:           "Partial Points Below Passing Score",
+			attempt:        pendingTestAttempt,
+			request:        schemas.ReviewQuizAttemptRequest{Answers: []schemas.QuizAnswerReview{{QuestionID: 4, PointsAwarded: 0}}},
+			expectedScore:  5,
+			expectedPassed: false,
+		},
+		{
+			name: "Review Again Replaces The Points",
+			attempt: func() models.QuizAttempt {
+				attempt := pendingTestAttempt()
+				correct := true
+				attempt.Score, attempt.PendingReview, attempt.Passed = 10, false, true
+				attempt.Answers[3].IsCorrect, attempt.Answers[3].PointsAwarded = &correct, 5
+				return attempt
+			},
+			request:        schemas.ReviewQuizAttemptRequest{Answers: []schemas.QuizAnswerReview{{QuestionID: 4, PointsAwarded: 1}}},
+			expectedScore:  6,
+			expectedPassed: true,
+		},
+		{
+			name:          "Objective Question",
+			attempt:       pendingTestAttempt,
+			request:       schemas.ReviewQuizAttemptRequest{Answers: []schemas.QuizAnswerReview{{QuestionID: 1, PointsAwarded: 1}}},
+			expectedError: errors.New("question 1 has no free text answer to review"),
+		},
+		{
+			name:          "Too Many Points",
+			attempt:       pendingTestAttempt,
+			request:       schemas.ReviewQuizAttemptRequest{Answers: []schemas.QuizAnswerReview{{QuestionID: 4, PointsAwarded: 6}}},
+			expectedError: errors.New("points of question 4 must be between 0 and 5"),
+		},
+		{
+			name:          "Unknown Question",
+			attempt:       pendingTestAttempt,
+			request:       schemas.ReviewQuizAttemptRequest{Answers: []schemas.QuizAnswerReview{{QuestionID: 99}}},
+			expectedError: errors.New("question 99 does not belong to this quiz"),
+		},
+		{
+			name:          "No Reviews",
+			attempt:       pendingTestAttempt,
+			expectedError: errors.New("at least one answer review is required"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			attempt, err := services.ReviewQuizAttempt(reviewTestQuiz(), tc.attempt(), tc.request)
+
+			if tc.expectedError != nil {
+				assert.Error(t, err)
+				assert.Equal(t, tc.expectedError.Error(), err.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expectedScore, attempt.Score)
+				assert.Equal(t, tc.expectedPassed, attempt.Passed)
+				assert.False(t, attempt.PendingReview)
+				assert.Equal(t, tc.expectedCorrect, *attempt.Answers[3].IsCorrect)
+			}
+		})
+	}
+}
+
+func TestQuizService_ReviewAttempt(t *testing.T) {
+	quizRepo := mocks.NewQuizRepositoryInterface(t)
+	lessonRepo := mocks.NewLessonRepositoryInterface(t)
+	lessonRepo.On("GetByID", uint(1), uint(1), uint(1), noTenant).Return(models.Lesson{ID: 1}, nil)
+	quizRepo.On("GetByID", uint(1), uint(1)).Return(reviewTestQuiz(), nil)
+	quizRepo.On("GetAttempt", uint(1), uint(7)).Return(pendingTestAttempt(), nil)
+	quizRepo.On("GetAttempt", uint(1), uint(8)).Return(models.QuizAttempt{}, errors.New("quiz attempt not found"))
+	quizRepo.On("UpdateAttemptReview", mock.MatchedBy(func(attempt models.QuizAttempt) bool {
+		return attempt.ID == 7 && attempt.Score == 10 && attempt.Passed && !attempt.PendingReview &&
+			attempt.Answers[3].PointsAwarded == 5
+	})).Return(nil)
+
+	service := services.NewQuizService(quizRepo, lessonRepo)
+	request := schemas.ReviewQuizAttemptRequest{Answers: []schemas.QuizAnswerReview{{QuestionID: 4, PointsAwarded: 5}}}
+
+	attempt, err := service.ReviewAttempt(1, 1, 1, 1, 7, request, noTenant)
+	assert.NoError(t, err)
+	assert.Equal(t, 100, attempt.Percent)
+	assert.True(t, attempt.Passed)
+	assert.False(t, attempt.PendingReview)
+
+	_, err = service.ReviewAttempt(1, 1, 1, 1, 8, request, noTenant)
+	assert.EqualError(t, err, "quiz attempt not found")
+}
+
+func TestQuizService_CreateQuiz(t *testing.T) {
+	validQuestion := schemas.QuizQuestionRequest{
+		Type: models.QuestionTypeSingleChoice,
+		Text: "Which keyword declares a variable?",
+		Options: []schemas.QuizOptionRequest{
+			{Text: "var", IsCorrect: true},
+			{Text: "let"},
+		},
+	}
+
+	testCases := []struct {
+		name          string
+		request       schemas.QuizRequest
+		mockSetup     func(quizRepo *mocks.QuizRepositoryInterface, lessonRepo *mocks.LessonRepositoryInterface)
+		expectedError error
+		expectedID    uint
+	}{
+		{
+			name:    "Success",
+			request: schemas.QuizRequest{Title: "Quiz", PassingScore: 70, Questions: []schemas.QuizQuestionRequest{validQuestion}},
+			mockSetup: func(quizRepo *mocks.QuizRepositoryInterface, lessonRepo *mocks.LessonRepositoryInterface) {
+				lessonRepo.On("GetByID", uint(1), uint(1), uint(1), noTenant).Return(models.Lesson{ID: 1}, nil)
+				quizRepo.On("Create", mock.MatchedBy(func(quiz models.Quiz) bool {
+					return quiz.LessonID == 1 && len(quiz.Questions) == 1 &&
+						quiz.Questions[0].Points == 1 && quiz.Questions[0].Order == 1 &&
+						len(quiz.Questions[0].Options) == 2
+				})).Return(uint(1), nil)
+			},
+			expectedID: 1,
+		},
+		{
+			name:          "Missing Title",
+			request:       schemas.QuizRequest{Questions: []schemas.QuizQuestionRequest{validQuestion}},
+			mockSetup:     func(quizRepo *mocks.QuizRepositoryInterface, lessonRepo *mocks.LessonRepositoryInterface) {},
+			expectedError: errors.New("quiz title is required"),
+		},
+		{
+			name:          "No Questions",
+			request:       schemas.QuizRequest{Title: "Quiz"},
+			mockSetup:     func(quizRepo *mocks.QuizRepositoryInterface, lessonRepo *mocks.LessonRepositoryInterface) {},
+			expectedError: errors.New("quiz must have at least one question"),
+		},
+		{
+			name: "Single Choice With Two Correct Options",
+			request: schemas.QuizRequest{Title: "Quiz", Questions: []schemas.QuizQuestionRequest{{
+				Type: models.QuestionTypeSingleChoice,
+				Text: "Pick one",
+				Options: []schemas.QuizOptionRequest{
+					{Text: "a", IsCorrect: true},
+					{Text: "b", IsCorrect: true},
+				},
+			}}},
+			mockSetup:     func(quizRepo *mocks.QuizRepositoryInterface, lessonRepo *mocks.LessonRepositoryInterface) {},
+			expectedError: errors.New("question 1: exactly one correct option is required"),
+		},
+		{
+			name: "Numeric Without Correct Number",
+			request: schemas.QuizRequest{Title: "Quiz", Questions: []schemas.QuizQuestionRequest{{
+				Type: models.QuestionTypeNumeric,
+				Text: "6 * 7 = ?",
+			}}},
+			mockSetup:     func(quizRepo *mocks.QuizRepositoryInterface, lessonRepo *mocks.LessonRepositoryInterface) {},
+			expectedError: errors.New("question 1: correct number is required"),
+		},
+		{
+			name:    "Lesson Not Found",
+			request: schemas.QuizRequest{Title: "Quiz", Questions: []schemas.QuizQuestionRequest{validQuestion}},
+			mockSetup: func(quizRepo *mocks.QuizRepositoryInterface, lessonRepo *mocks.LessonRepositoryInterface) {
+				lessonRepo.On("GetByID", uint(1), uint(1), uint(1), noTenant).Return(models.Lesson{}, errors.New("lesson not found"))
+			},
+			expectedError: errors.New("lesson not found"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			quizRepo := mocks.NewQuizRepositoryInterface(t)
+			lessonRepo := mocks.NewLessonRepositoryInterface(t)
+			tc.mockSetup(quizRepo, lessonRepo)
+
+			service := services.NewQuizService(quizRepo, lessonRepo)
+
+			id, err := service.CreateQuiz(1, 1, 1, tc.request, noTenant)
+
+			if tc.expectedError != nil {
+				assert.Error(t, err)
+				assert.Equal(t, tc.expectedError.Error(), err.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expectedID, id)
+			}
+		})
+	}
+}
+
+func TestQuizService_UpdateQuiz(t *testing.T) {
+	existing := reviewTestQuiz()
+	updatedQuestions := func() []schemas.QuizQuestionRequest {
+		return []schemas.QuizQuestionRequest{
+			{ID: 1, Type: models.QuestionTypeSingleChoice, Text: "Which keyword declares a variable?", Options: []schemas.QuizOptionRequest{
+				{ID: 1, Text: "var", IsCorrect: true},
+				{Text: "const"},
+			}},
+			{ID: 4, Type: models.QuestionTypeFreeText, Text: "Explain goroutines", Points: 5},
+		}
+	}
+	// settledAttempt matches pendingTestAttempt once its free text answer is settled
+	settledAttempt := mock.MatchedBy(func(attempt models.QuizAttempt) bool {
+		return attempt.ID == 7 && !attempt.PendingReview && attempt.Score == 5 && attempt.Passed &&
+			attempt.Answers[3].IsCorrect != nil && !*attempt.Answers[3].IsCorrect && attempt.Answers[3].PointsAwarded == 0
+	})
+
+	testCases := []struct {
+		name          string
+		questions     func() []schemas.QuizQuestionRequest
+		expectUpdate  bool
+		expectSettled bool
+		expectedError error
+	}{
+		{
+			name:         "Keeps Question IDs",
+			questions:    updatedQuestions,
+			expectUpdate: true,
+		},
+		{
+			name: "Removed Free Text Question Settles Pending Answers",
+			questions: func() []schemas.QuizQuestionRequest {
+				return updatedQuestions()[:1]
+			},
+			expectUpdate:  true,
+			expectSettled: true,
+		},
+		{
+			name: "Free Text Question Changed To Numeric Settles Pending Answers",
+			questions: func() []schemas.QuizQuestionRequest {
+				questions := updatedQuestions()
+				questions[1].Type = models.QuestionTypeNumeric
+				questions[1].CorrectNumber = float64Ptr(42)
+				return questions
+			},
+			expectUpdate:  true,
+			expectSettled: true,
+		},
+		{
+			name: "Unknown Question ID",
+			questions: func() []schemas.QuizQuestionRequest {
+				questions := updatedQuestions()
+				questions[1].ID = 99
+				return questions
+			},
+			expectedError: errors.New("question 2: question 99 does not belong to this quiz"),
+		},
+		{
+			name: "Option Of Another Question",
+			questions: func() []schemas.QuizQuestionRequest {
+				questions := updatedQuestions()
+				questions[0].Options[1].ID = 3
+				return questions
+			},
+			expectedError: errors.New("question 1: option 3 does not belong to this question"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			quizRepo := mocks.NewQuizRepositoryInterface(t)
+			lessonRepo := mocks.NewLessonRepositoryInterface(t)
+			lessonRepo.On("GetByID", uint(1), uint(1), uint(1), noTenant).Return(models.Lesson{ID: 1}, nil)
+			quizRepo.On("GetByID", uint(1), uint(1)).Return(existing, nil)
+			if tc.expectUpdate {
+				quizRepo.On("Update", mock.MatchedBy(func(quiz models.Quiz) bool {
+					return quiz.Questions[0].ID == 1 && quiz.Questions[0].Options[0].ID == 1 && quiz.Questions[0].Options[1].ID == 0
+				})).Return(nil)
+				quizRepo.On("GetPendingAttempts", uint(1)).Return([]models.QuizAttempt{pendingTestAttempt()}, nil)
+			}
+			if tc.expectSettled {
+				quizRepo.On("UpdateAttemptReview", settledAttempt).Return(nil)
+			}
+
+			service := services.NewQuizService(quizRepo, lessonRepo)
+			request := schemas.QuizRequest{Title: "Quiz", PassingScore: 50, Questions: tc.questions()}
+
+			err := service.UpdateQuiz(1, 1, 1, 1, request, noTenant)
+
+			if tc.expectedError != nil {
+				assert.EqualError(t, err, tc.expectedError.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+			if !tc.expectSettled {
+				quizRepo.AssertNotCalled(t, "UpdateAttemptReview", mock.Anything)
+			}
+		})
+	}
+}
+
+func TestReviewQuizAttempt_SettlesStaleAnswers(t *testing.T) {
+	// The attempt answered two free text questions, question 4 no longer is one
+	pendingAttempt := func() models.QuizAttempt {
+		attempt := pendingTestAttempt()
+		attempt.MaxScore = 15
+		attempt.Answers = append(attempt.Answers, models.QuizAnswer{ID: 5, QuestionID: 5, TextAnswer: "Channels connect goroutines"})
+		return attempt
+	}
+	freeText := models.QuizQuestion{ID: 5, Type: models.QuestionTypeFreeText, Points: 5}
+
+	removed := testQuiz()
+	removed.Questions = append(removed.Questions, freeText)
+	changed := reviewTestQuiz()
+	changed.Questions[3].Type = models.QuestionTypeNumeric
+	changed.Questions = append(changed.Questions, freeText)
+
+	for name, quiz := range map[string]models.Quiz{"Question Removed": removed, "Question Type Changed": changed} {
+		t.Run(name, func(t *testing.T) {
+			request := schemas.ReviewQuizAttemptRequest{Answers: []schemas.QuizAnswerReview{{QuestionID: 5, PointsAwarded: 5}}}
+
+			reviewed, err := services.ReviewQuizAttempt(quiz, pendingAttempt(), request)
+
+			assert.NoError(t, err)
+			assert.False(t, reviewed.PendingReview)
+			assert.Equal(t, 10, reviewed.Score)
+			assert.True(t, reviewed.Passed)
+			assert.False(t, *reviewed.Answers[3].IsCorrect)
+			assert.Equal(t, 0, reviewed.Answers[3].PointsAwarded)
+			assert.True(t, *reviewed.Answers[4].IsCorrect)
+
+			_, err = services.ReviewQuizAttempt(quiz, pendingAttempt(), schemas.ReviewQuizAttemptRequest{Answers: []schemas.QuizAnswerReview{{QuestionID: 4, PointsAwarded: 5}}})
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestQuizService_GetQuizByID_HidesAnswers(t *testing.T) {
+	quizRepo := mocks.NewQuizRepositoryInterface(t)
+	lessonRepo := mocks.NewLessonRepositoryInterface(t)
+	lessonRepo.On("GetByID", uint(1), uint(1), uint(1), noTenant).Return(models.Lesson{ID: 1}, nil)
+	quizRepo.On("GetByID", uint(1), uint(1)).Return(testQuiz(), nil)
+
+	service := services.NewQuizService(quizRepo, lessonRepo)
+
+	quiz, err := service.GetQuizByID(1, 1, 1, 1, false, noTenant)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, quiz.QuestionsCount)
+	for _, question := range quiz.Questions {
+		assert.Nil(t, question.CorrectNumber)
+		for _, option := range question.Options {
+			assert.Nil(t, option.IsCorrect)
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package services_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"web/mocks/repos"
+	"web/schemas"
+	"web/services"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func uintPtr(v uint) *uint {
+	return &v
+}
+
+func TestSearchService_Search(t *testing.T) {
+	testCases := []struct {
+		name          string
+		params        schemas.SearchParams
+		mockSetup     func(searchRepo *mocks.SearchRepositoryInterface)
+		expectedError error
+		expectedURLs  []string
+		expectedTotal int64
+	}{
+		{
+			name:   "Hits Link To Their Path",
+			params: schemas.SearchParams{Query: "  goroutines ", UserID: 1},
+			mockSetup: func(searchRepo *mocks.SearchRepositoryInterface) {
+				searchRepo.On("Search", mock.MatchedBy(func(params schemas.SearchParams) bool {
+					return params.Query == "goroutines" && params.Page == 1 && params.Limit == schemas.DefaultPageLimit
+				})).Return([]schemas.SearchHit{
+					{Type: schemas.SearchTypeLesson, ID: 3, Path: schemas.SearchPath{CourseID: 1, ChapterID: uintPtr(2), LessonID: uintPtr(3)}},
+					{Type: schemas.SearchTypeChapter, ID: 2, Path: schemas.SearchPath{CourseID: 1, ChapterID: uintPtr(2)}},
+					{Type: schemas.SearchTypeCourse, ID: 1, Path: schemas.SearchPath{CourseID: 1}},
+				}, int64(3), nil)
+			},
+			expectedURLs: []string{
+				"/api/v1/courses/1/chapters/2/lessons/3",
+				"/api/v1/courses/1/chapters/2",
+				"/api/v1/courses/1",
+			},
+			expectedTotal: 3,
+		},
+		{
+			name:          "Empty Query",
+			params:        schemas.SearchParams{Query: "   "},
+			mockSetup:     func(searchRepo *mocks.SearchRepositoryInterface) {},
+			expectedError: errors.New("search query is required"),
+		},
+		{
+			name:          "Query Too Long",
+			params:        schemas.SearchParams{Query: strings.Repeat("a", 201)},
+			mockSetup:     func(searchRepo *mocks.SearchRepositoryInterface) {},
+			expectedError: errors.New("search query must not exceed 200 characters"),
+		},
+		{
+			name:          "Invalid Type",
+			params:        schemas.SearchParams{Query: "go", Types: []string{"quiz"}},
+			mockSetup:     func(searchRepo *mocks.SearchRepositoryInterface) {},
+			expectedError: errors.New("invalid search type: quiz"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			searchRepo := mocks.NewSearchRepositoryInterface(t)
+			tc.mockSetup(searchRepo)
+
+			service := services.NewSearchService(searchRepo)
+
+			hits, meta, err := service.Search(tc.params)
+
+			if tc.expectedError != nil {
+				assert.Error(t, err)
+				assert.Equal(t, tc.expectedError.Error(), err.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expectedTotal, meta.Total)
+				for i, url := range tc.expectedURLs {
+					assert.Equal(t, url, hits[i].URL)
+				}
+			}
+		})
+	}
+}
+
+func TestSearchService_SearchEscapesSnippets(t *testing.T) {
+	searchRepo := mocks.NewSearchRepositoryInterface(t)
+	searchRepo.On("Search", mock.Anything).Return([]schemas.SearchHit{
+		{
+			Type:    schemas.SearchTypeLesson,
+			ID:      3,
+			Snippet: `<script>alert("x")</script> Start ` + schemas.SearchHighlightStart + "goroutines" + schemas.SearchHighlightStop + " & channels",
+			Path:    schemas.SearchPath{CourseID: 1, ChapterID: uintPtr(2), LessonID: uintPtr(3)},
+		},
+	}, int64(1), nil)
+
+	service := services.NewSearchService(searchRepo)
+
+	hits, _, err := service.Search(schemas.SearchParams{Query: "goroutines", UserID: 1})
+
+	assert.NoError(t, err)
+	assert.Equal(t, `&lt;script&gt;alert(&#34;x&#34;)&lt;/script&gt; Start <mark>goroutines</mark> &amp; channels`, hits[0].Snippet)
+}
@@ -0,0 +1,162 @@
+package services_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+	"web/config"
+	"web/mocks/repos"
+	"web/services"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeIntrospectionServer answers token introspection requests, tokens listed in revoked
+// are reported inactive
+type fakeIntrospectionServer struct {
+	*httptest.Server
+	mu       sync.Mutex
+	revoked  map[string]bool
+	requests int
+}
+
+func newFakeIntrospectionServer(t *testing.T) *fakeIntrospectionServer {
+	server := &fakeIntrospectionServer{revoked: map[string]bool{}}
+	server.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		server.mu.Lock()
+		defer server.mu.Unlock()
+
+		server.requests++
+		active := !server.revoked[r.PostFormValue("token")]
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]bool{"active": active})
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func (s *fakeIntrospectionServer) revoke(token string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.revoked[token] = true
+}
+
+func (s *fakeIntrospectionServer) requestCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests
+}
+
+func newTokenValidationService(server *fakeIntrospectionServer, strategy string, cacheTTL time.Duration) *services.AuthService {
+	appConfig := &config.AppConfig{
+		KeycloakURL:           server.URL,
+		KeycloakRealm:         "courses",
+		TokenValidation:       strategy,
+		IntrospectionCacheTTL: cacheTTL,
+	}
+	return services.NewAuthService(appConfig, services.NewKeycloakProvider(appConfig),
+		new(mocks.UserRepositoryInterface), new(mocks.OrganizationRepositoryInterface))
+}
+
+func tokenClaims(expiresIn time.Duration) *services.IdentityClaims {
+	return &services.IdentityClaims{ExpiresAt: time.Now().Add(expiresIn)}
+}
+
+func TestAuthService_IsTokenActive_Local(t *testing.T) {
+	server := newFakeIntrospectionServer(t)
+	server.revoke("token-a")
+	service := newTokenValidationService(server, config.TokenValidationLocal, 0)
+
+	for _, method := range []string{http.MethodGet, http.MethodPost, http.MethodDelete} {
+		active, err := service.IsTokenActive("token-a", tokenClaims(time.Hour), method)
+		require.NoError(t, err)
+		assert.True(t, active, method)
+	}
+	assert.Equal(t, 0, server.requestCount())
+}
+
+func TestAuthService_IsTokenActive_IntrospectMutations(t *testing.T) {
+	server := newFakeIntrospectionServer(t)
+	server.revoke("token-a")
+	service := newTokenValidationService(server, config.TokenValidationIntrospectMutations, 0)
+
+	testCases := []struct {
+		method         string
+		expectedActive bool
+	}{
+		{http.MethodGet, true},
+		{http.MethodHead, true},
+		{http.MethodOptions, true},
+		{http.MethodPost, false},
+		{http.MethodPut, false},
+		{http.MethodPatch, false},
+		{http.MethodDelete, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.method, func(t *testing.T) {
+			active, err := service.IsTokenActive("token-a", tokenClaims(time.Hour), tc.method)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedActive, active)
+		})
+	}
+	assert.Equal(t, 4, server.requestCount())
+}
+
+func TestAuthService_IsTokenActive_IntrospectionCache(t *testing.T) {
+	server := newFakeIntrospectionServer(t)
+	service := newTokenValidationService(server, config.TokenValidationIntrospect, 50*time.Millisecond)
+
+	// Results are reused per token until the TTL passes
+	for i := 0; i < 3; i++ {
+		active, err := service.IsTokenActive("token-a", tokenClaims(time.Hour), http.MethodGet)
+		require.NoError(t, err)
+		assert.True(t, active)
+	}
+	assert.Equal(t, 1, server.requestCount())
+
+	_, err := service.IsTokenActive("token-b", tokenClaims(time.Hour), http.MethodGet)
+	require.NoError(t, err)
+	assert.Equal(t, 2, server.requestCount())
+
+	// A revocation is seen once the cached result expires
+	server.revoke("token-a")
+	time.Sleep(60 * time.Millisecond)
+	active, err := service.IsTokenActive("token-a", tokenClaims(time.Hour), http.MethodGet)
+	require.NoError(t, err)
+	assert.False(t, active)
+	assert.Equal(t, 3, server.requestCount())
+
+	// Inactive results are cached as well
+	active, err = service.IsTokenActive("token-a", tokenClaims(time.Hour), http.MethodGet)
+	require.NoError(t, err)
+	assert.False(t, active)
+	assert.Equal(t, 3, server.requestCount())
+}
+
+func TestAuthService_IsTokenActive_CacheEndsWithTheToken(t *testing.T) {
+	server := newFakeIntrospectionServer(t)
+	service := newTokenValidationService(server, config.TokenValidationIntrospect, time.Hour)
+
+	_, err := service.IsTokenActive("token-a", tokenClaims(-time.Second), http.MethodGet)
+	require.NoError(t, err)
+	_, err = service.IsTokenActive("token-a", tokenClaims(-time.Second), http.MethodGet)
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, server.requestCount())
+}
+
+func TestAuthService_IsTokenActive_CacheDisabled(t *testing.T) {
+	server := newFakeIntrospectionServer(t)
+	service := newTokenValidationService(server, config.TokenValidationIntrospect, 0)
+
+	for i := 0; i < 3; i++ {
+		_, err := service.IsTokenActive("token-a", tokenClaims(time.Hour), http.MethodGet)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 3, server.requestCount())
+}
@@ -0,0 +1,224 @@
+package services_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+	"web/mocks/repos"
+	"web/models"
+	"web/schemas"
+	"web/services"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// fakeObjectRemover records the removed objects and fails for the objects listed in failing
+type fakeObjectRemover struct {
+	removed []string
+	failing map[string]bool
+}
+
+func (f *fakeObjectRemover) RemoveObject(objectName string) error {
+	if f.failing[objectName] {
+		return errors.New("storage unavailable")
+	}
+	f.removed = append(f.removed, objectName)
+	return nil
+}
+
+func newTrashTestService(repo *mocks.TrashRepositoryInterface, memberRepo *mocks.CourseMemberRepositoryInterface, storage services.ObjectRemover) *services.TrashService {
+	policy := services.NewPolicyService(services.PolicyRules, new(mocks.CourseRepositoryInterface), new(mocks.LessonRepositoryInterface), memberRepo)
+	return services.NewTrashService(repo, policy, storage, 30*24*time.Hour)
+}
+
+func TestTrashService_GetAll(t *testing.T) {
+	deletedAt := time.Date(2025, 7, 1, 12, 0, 0, 0, time.UTC)
+	orgID := uint(100)
+
+	testCases := []struct {
+		name         string
+		resourceType string
+		actor        services.Actor
+		managedBy    *uint
+		managerRoles []string
+	}{
+		{
+			name:         "Admins List The Whole Trash Of Their Tenant",
+			resourceType: services.ResourceCourse,
+			actor:        services.Actor{UserID: 1, IsAdmin: true, OrganizationID: &orgID},
+		},
+		{
+			name:         "Teachers List The Courses They Own",
+			resourceType: services.ResourceCourse,
+			actor:        services.Actor{UserID: 10, IsTeacher: true},
+			managedBy:    uintPtr(10),
+			managerRoles: []string{models.CourseMemberRoleOwner},
+		},
+		{
+			name:         "Co-Instructors List The Chapters They Edit",
+			resourceType: services.ResourceChapter,
+			actor:        services.Actor{UserID: 40},
+			managedBy:    uintPtr(40),
+			managerRoles: []string{models.CourseMemberRoleOwner, models.CourseMemberRoleCoInstructor},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := new(mocks.TrashRepositoryInterface)
+			service := newTrashTestService(repo, new(mocks.CourseMemberRepositoryInterface), &fakeObjectRemover{})
+
+			tenant := &schemas.TenantFilter{OrganizationID: tc.actor.OrganizationID}
+			repo.On("GetAll", schemas.TrashParams{
+				ResourceType: tc.resourceType,
+				Tenant:       tenant,
+				ManagedBy:    tc.managedBy,
+				ManagerRoles: tc.managerRoles,
+				Page:         1,
+				Limit:        20,
+			}).Return([]schemas.TrashItem{{ResourceType: tc.resourceType, ID: 3, DeletedAt: deletedAt}}, int64(1), nil)
+
+			items, meta, err := service.GetAll(schemas.TrashParams{ResourceType: tc.resourceType, Tenant: tenant, Page: 1, Limit: 20}, tc.actor)
+
+			assert.NoError(t, err)
+			assert.Len(t, items, 1)
+			assert.Equal(t, deletedAt.Add(30*24*time.Hour), items[0].PurgeAt)
+			assert.Equal(t, int64(1), meta.Total)
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestTrashService_GetAll_InvalidResourceType(t *testing.T) {
+	repo := new(mocks.TrashRepositoryInterface)
+	service := newTrashTestService(repo, new(mocks.CourseMemberRepositoryInterface), &fakeObjectRemover{})
+
+	_, _, err := service.GetAll(schemas.TrashParams{ResourceType: services.ResourceQuiz}, services.Actor{UserID: 1, IsAdmin: true})
+
+	assert.EqualError(t, err, "invalid resource type")
+	repo.AssertNotCalled(t, "GetAll", mock.Anything)
+}
+
+func TestTrashService_Restore(t *testing.T) {
+	ownerID, orgID, otherOrgID := uint(10), uint(100), uint(200)
+
+	testCases := []struct {
+		name          string
+		resourceType  string
+		item          schemas.TrashItem
+		getErr        error
+		actor         services.Actor
+		expectedError string
+	}{
+		{
+			name:         "Owner Restores A Course",
+			resourceType: services.ResourceCourse,
+			item:         schemas.TrashItem{ID: 1, CourseID: 1, CourseCreatedBy: &ownerID},
+			actor:        services.Actor{UserID: ownerID, IsTeacher: true},
+		},
+		{
+			name:         "Co-Instructor Restores A Lesson",
+			resourceType: services.ResourceLesson,
+			item:         schemas.TrashItem{ID: 7, CourseID: 1, CourseCreatedBy: &ownerID},
+			actor:        services.Actor{UserID: 40},
+		},
+		{
+			name:          "Co-Instructor Cannot Restore A Course",
+			resourceType:  services.ResourceCourse,
+			item:          schemas.TrashItem{ID: 1, CourseID: 1, CourseCreatedBy: &ownerID},
+			actor:         services.Actor{UserID: 40},
+			expectedError: "insufficient permissions for restore on course",
+		},
+		{
+			name:          "Other Teacher Cannot Restore",
+			resourceType:  services.ResourceChapter,
+			item:          schemas.TrashItem{ID: 3, CourseID: 1, CourseCreatedBy: &ownerID},
+			actor:         services.Actor{UserID: 20, IsTeacher: true},
+			expectedError: "insufficient permissions for restore on chapter",
+		},
+		{
+			name:          "Admin Of Another Organization Cannot Restore",
+			resourceType:  services.ResourceCourse,
+			item:          schemas.TrashItem{ID: 1, CourseID: 1, CourseCreatedBy: &ownerID, OrganizationID: &orgID},
+			actor:         services.Actor{UserID: 2, IsAdmin: true, OrganizationID: &otherOrgID},
+			expectedError: "course not found",
+		},
+		{
+			name:          "Parent Still Deleted",
+			resourceType:  services.ResourceAttachment,
+			item:          schemas.TrashItem{ID: 5, CourseID: 1, CourseCreatedBy: &ownerID, ParentDeleted: true},
+			actor:         services.Actor{UserID: ownerID, IsTeacher: true},
+			expectedError: "parent is deleted",
+		},
+		{
+			name:          "Not In The Trash",
+			resourceType:  services.ResourceLesson,
+			getErr:        errors.New("lesson not found"),
+			actor:         services.Actor{UserID: ownerID, IsTeacher: true},
+			expectedError: "lesson not found",
+		},
+		{
+			name:          "Invalid Resource Type",
+			resourceType:  services.ResourceQuiz,
+			actor:         services.Actor{UserID: 1, IsAdmin: true},
+			expectedError: "invalid resource type",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := new(mocks.TrashRepositoryInterface)
+			memberRepo := new(mocks.CourseMemberRepositoryInterface)
+			memberRepo.On("GetRole", uint(1), uint(40)).Return(models.CourseMemberRoleCoInstructor, nil).Maybe()
+			memberRepo.On("GetRole", uint(1), uint(20)).Return("", nil).Maybe()
+			service := newTrashTestService(repo, memberRepo, &fakeObjectRemover{})
+
+			repo.On("GetByID", tc.resourceType, mock.AnythingOfType("uint")).Return(tc.item, tc.getErr).Maybe()
+			repo.On("Restore", tc.resourceType, mock.AnythingOfType("uint")).Return(nil).Maybe()
+
+			err := service.Restore(tc.resourceType, tc.item.ID, tc.actor)
+
+			if tc.expectedError != "" {
+				assert.EqualError(t, err, tc.expectedError)
+				repo.AssertNotCalled(t, "Restore", mock.Anything, mock.Anything)
+				return
+			}
+			assert.NoError(t, err)
+			repo.AssertCalled(t, "Restore", tc.resourceType, tc.item.ID)
+		})
+	}
+}
+
+func TestTrashService_Purge(t *testing.T) {
+	repo := new(mocks.TrashRepositoryInterface)
+	storage := &fakeObjectRemover{failing: map[string]bool{"lesson-2/broken.pdf": true}}
+	service := newTrashTestService(repo, new(mocks.CourseMemberRepositoryInterface), storage)
+
+	before := time.Now()
+	repo.On("Purge", mock.MatchedBy(func(deletedBefore time.Time) bool {
+		// Only content deleted more than the 30 days of retention ago is purged
+		cutoff := before.Add(-30 * 24 * time.Hour)
+		return !deletedBefore.Before(cutoff) && deletedBefore.Before(cutoff.Add(time.Minute))
+	})).Return([]string{"lesson-1/slides.pdf", "lesson-2/broken.pdf", "org-100/lesson-3/notes.txt"}, nil)
+
+	removed, err := service.Purge()
+
+	assert.Equal(t, 2, removed)
+	assert.ErrorContains(t, err, "lesson-2/broken.pdf: storage unavailable")
+	assert.Equal(t, []string{"lesson-1/slides.pdf", "org-100/lesson-3/notes.txt"}, storage.removed)
+}
+
+func TestTrashService_Purge_RepositoryError(t *testing.T) {
+	repo := new(mocks.TrashRepositoryInterface)
+	storage := &fakeObjectRemover{}
+	service := newTrashTestService(repo, new(mocks.CourseMemberRepositoryInterface), storage)
+
+	repo.On("Purge", mock.Anything).Return(nil, errors.New("database unavailable"))
+
+	removed, err := service.Purge()
+
+	assert.Equal(t, 0, removed)
+	assert.EqualError(t, err, "database unavailable")
+	assert.Empty(t, storage.removed)
+}
@@ -0,0 +1,49 @@
+package services_test
+
+import (
+	"testing"
+	"web/config"
+	"web/mocks/repos"
+	"web/models"
+	"web/schemas"
+	"web/services"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestUserService_UpdatePassword(t *testing.T) {
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte("oldpassword"), bcrypt.MinCost)
+	assert.NoError(t, err)
+
+	userRepo := new(mocks.UserRepositoryInterface)
+	userRepo.On("GetByID", uint(5)).Return(models.User{ID: 5, Password: string(hashedPassword)}, nil)
+	userRepo.On("UpdatePassword", uint(5), mock.MatchedBy(func(hash string) bool {
+		return bcrypt.CompareHashAndPassword([]byte(hash), []byte("newpassword")) == nil
+	})).Return(nil)
+	service := services.NewUserService(&config.AppConfig{IdentityProvider: config.IdentityProviderLocal}, userRepo)
+
+	err = service.UpdatePassword(5, schemas.UpdatePasswordRequest{CurrentPassword: "wrongpassword", NewPassword: "newpassword"})
+	assert.EqualError(t, err, "current password is incorrect")
+	userRepo.AssertNotCalled(t, "UpdatePassword", mock.Anything, mock.Anything)
+
+	err = service.UpdatePassword(5, schemas.UpdatePasswordRequest{CurrentPassword: "oldpassword", NewPassword: "newpassword"})
+	assert.NoError(t, err)
+	userRepo.AssertNumberOfCalls(t, "UpdatePassword", 1)
+}
+
+func TestUserService_UpdatePassword_ExternalProvider(t *testing.T) {
+	for _, provider := range []string{config.IdentityProviderKeycloak, config.IdentityProviderOIDC} {
+		t.Run(provider, func(t *testing.T) {
+			userRepo := new(mocks.UserRepositoryInterface)
+			service := services.NewUserService(&config.AppConfig{IdentityProvider: provider}, userRepo)
+
+			err := service.UpdatePassword(5, schemas.UpdatePasswordRequest{CurrentPassword: "oldpassword", NewPassword: "newpassword"})
+
+			assert.EqualError(t, err, "password is managed by the identity provider")
+			userRepo.AssertNotCalled(t, "GetByID", mock.Anything)
+			userRepo.AssertNotCalled(t, "UpdatePassword", mock.Anything, mock.Anything)
+		})
+	}
+}